@@ -168,20 +168,15 @@ local function substitute_payload(conf, result)
       ngx.say(cjson.encode(e))
       ngx.exit(ngx.status)
     end
+  elseif type(data) ~= "string" then
+    ngx.log(ngx.DEBUG, "request body is empty")
+    result[conf.substitute.input] = data
   else
-    if not header["content-type"] then
-      ngx.log(ngx.DEBUG, "request body type not specified")
-      result[conf.substitute.input] = data
-    else
-      ngx.log(ngx.DEBUG, "request body type is not supported: " .. header["content-type"])
-      ngx.status = 415 -- Unsupported Media Type
-      local e = {
-        message=string.format("request body type is not supported: %s", header["content-type"]),
-        code=ngx.status,
-      }
-      ngx.say(cjson.encode(e))
-      ngx.exit(ngx.status)
-    end
+    -- binary and text bodies, e.g. an uploaded image or CSV file, are base64-encoded with their content type
+    local content_type = header["content-type"] or "application/octet-stream"
+    ngx.log(ngx.DEBUG, "request body is " .. content_type)
+    result[conf.substitute.input] = ngx.encode_base64(data)
+    result[conf.substitute.input_content_type] = content_type
   end
   ngx.log(ngx.DEBUG, "after substitute: payload: " .. cjson.encode(result))
   return result
@@ -254,6 +249,16 @@ local function insert_http_context_to_payload(conf, result)
   end
 end
 
+-- insert the Idempotency-Key header into payload, so that retried requests run the function only once
+local function insert_idempotency_key_to_payload(conf, result)
+  local key = ngx.req.get_headers()["idempotency-key"]
+  if key then
+    ngx.log(ngx.DEBUG, "insert idempotency key [" .. key .. "] to payload")
+    result[conf.substitute.idempotency_key] = key
+  end
+  return result
+end
+
 local function tranform_request(conf)
 
   transform_header(conf)
@@ -276,6 +281,9 @@ local function tranform_request(conf)
   -- insert special prefixed headers into payload
   result = insert_header_to_payload(conf, result)
 
+  -- insert idempotency key into payload
+  result = insert_idempotency_key_to_payload(conf, result)
+
   -- insert http context into payload
   result = insert_http_context_to_payload(conf, result)
 
@@ -298,6 +306,9 @@ end
 -- transform response
 ----------------------------------------------------------
 
+-- set by the function manager on blocking runs returning a binary or text output
+local OUTPUT_CONTENT_TYPE_HEADER = "x-dispatch-output-content-type"
+
 function DispatchTransformerHandler:header_filter(conf)
   DispatchTransformerHandler.super.header_filter(self)
 
@@ -309,17 +320,30 @@ function DispatchTransformerHandler:header_filter(conf)
   if conf.enable.output and is_json_body(ngx.header) then
     -- clear content-length header as the body content changed
     ngx.header["content-length"] = nil
+
+    -- binary and text outputs are returned as is, with their content type
+    local output_content_type = ngx.header[OUTPUT_CONTENT_TYPE_HEADER]
+    if output_content_type then
+      ctx.rt_output_content_type = output_content_type
+      ngx.header["content-type"] = output_content_type
+      ngx.header[OUTPUT_CONTENT_TYPE_HEADER] = nil
+    end
   end
 end
 
-local function substitute_json_response(field, data)
+local function substitute_json_response(field, data, output_content_type)
   local ok = false
   ok, data = parse_json(data)
   if not ok then
       return false
   end
   local result = nil
-  if data[field] then
+  if output_content_type then
+    result = ngx.decode_base64(data[field] or "")
+    if not result then
+      return false
+    end
+  elseif data[field] then
     result = cjson.encode(data[field])
   end
   return true, result
@@ -333,7 +357,7 @@ local function substitute_response(conf)
     ngx.log(ngx.DEBUG, "response body:" .. data)
 
     local ok = false
-    ok, data = substitute_json_response(conf.substitute.output, data)
+    ok, data = substitute_json_response(conf.substitute.output, data, ctx.rt_output_content_type)
     if ok then
       ngx.log(ngx.DEBUG, "response body after transform:" .. (data or ""))
       ngx.log(ngx.DEBUG, "response transform done")
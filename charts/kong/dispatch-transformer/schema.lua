@@ -57,8 +57,10 @@ return {
       schema = {
         fields = {
           input  = { type = "string", default = "input" },
+          input_content_type = { type = "string", default = "inputContentType" },
           output = { type = "string", default = "output" },
           http_context = { type = "string", default = "httpContext" },
+          idempotency_key = { type = "string", default = "idempotencyKey" },
         }
       }
     },
@@ -21,8 +21,10 @@ import (
 	"github.com/vmware/dispatch/pkg/entity-store"
 	"github.com/vmware/dispatch/pkg/event-manager"
 	"github.com/vmware/dispatch/pkg/event-manager/drivers"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions"
 	"github.com/vmware/dispatch/pkg/events"
 	"github.com/vmware/dispatch/pkg/events/transport"
@@ -126,11 +128,18 @@ func main() {
 	fnClient := client.NewFunctionsClient(eventmanager.Flags.FunctionManager, client.AuthWithToken("cookie"), "")
 	secretsClient := client.NewSecretsClient(eventmanager.Flags.SecretStore, client.AuthWithToken("cookie"), "")
 
-	subManager, err := subscriptions.NewManager(eventTransport, fnClient)
+	eventLog := eventlog.NewLog(store, eventTransport, eventmanager.Flags.EventRetention)
+	eventLog.Start()
+	defer eventLog.Shutdown()
+
+	subManager, err := subscriptions.NewManager(eventTransport, fnClient, eventLog, eventmanager.Flags.RedeliveryTimeout)
 	if err != nil {
 		log.Fatalf("Error creating SubscriptionManager: %v", err)
 	}
 
+	scheduler := schedules.NewScheduler(store, eventTransport, fnClient, eventLog)
+	defer scheduler.Shutdown()
+
 	k8sBackend, err := drivers.NewK8sBackend(
 		secretsClient,
 		drivers.ConfigOpts{
@@ -150,8 +159,10 @@ func main() {
 	// event controller
 	eventController := eventmanager.NewEventController(
 		subManager,
+		scheduler,
 		k8sBackend,
 		store,
+		eventLog,
 		eventmanager.EventControllerConfig{},
 	)
 
@@ -160,10 +171,12 @@ func main() {
 
 	// handler
 	handlers := &eventmanager.Handlers{
-		Store:         store,
-		Transport:     eventTransport,
-		Watcher:       eventController.Watcher(),
-		SecretsClient: secretsClient,
+		Store:               store,
+		Transport:           eventTransport,
+		Watcher:             eventController.Watcher(),
+		SecretsClient:       secretsClient,
+		EventLog:            eventLog,
+		SubscriptionManager: subManager,
 	}
 
 	handlers.ConfigureHandlers(api)
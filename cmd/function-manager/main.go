@@ -18,6 +18,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/vmware/dispatch/pkg/blobstore"
 	"github.com/vmware/dispatch/pkg/client"
 
 	"github.com/vmware/dispatch/pkg/config"
@@ -25,15 +26,19 @@ import (
 	"github.com/vmware/dispatch/pkg/function-manager"
 	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi"
 	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
 	"github.com/vmware/dispatch/pkg/functions"
 	"github.com/vmware/dispatch/pkg/functions/injectors"
 	"github.com/vmware/dispatch/pkg/functions/kubeless"
+	"github.com/vmware/dispatch/pkg/functions/kubernetes"
+	fnmiddleware "github.com/vmware/dispatch/pkg/functions/middleware"
 	"github.com/vmware/dispatch/pkg/functions/noop"
 	"github.com/vmware/dispatch/pkg/functions/openfaas"
 	"github.com/vmware/dispatch/pkg/functions/openwhisk"
 	"github.com/vmware/dispatch/pkg/functions/riff"
 	"github.com/vmware/dispatch/pkg/functions/runner"
 	"github.com/vmware/dispatch/pkg/functions/validator"
+	"github.com/vmware/dispatch/pkg/invocation"
 	"github.com/vmware/dispatch/pkg/middleware"
 	"github.com/vmware/dispatch/pkg/utils"
 )
@@ -88,6 +93,22 @@ var drivers = map[string]func() functions.FaaSDriver{
 		}
 		return faas
 	},
+	"kubernetes": func() functions.FaaSDriver {
+		faas, err := kubernetes.New(&kubernetes.Config{
+			K8sConfig:            config.Global.Function.Kubernetes.K8sConfig,
+			FuncNamespace:        config.Global.Function.Kubernetes.FuncNamespace,
+			ImagePullSecret:      config.Global.Function.Kubernetes.ImagePullSecret,
+			FuncDefaultRequests:  config.Global.Function.Kubernetes.FuncDefaultRequests,
+			FuncDefaultLimits:    config.Global.Function.Kubernetes.FuncDefaultLimits,
+			Replicas:             config.Global.Function.Kubernetes.Replicas,
+			MaxReplicas:          config.Global.Function.Kubernetes.MaxReplicas,
+			TargetCPUUtilization: config.Global.Function.Kubernetes.TargetCPUUtilization,
+		})
+		if err != nil {
+			log.Fatalf("Error starting Kubernetes driver: %+v", err)
+		}
+		return faas
+	},
 	"noop": func() functions.FaaSDriver {
 		faas, err := noop.New(&noop.Config{})
 		if err != nil {
@@ -173,21 +194,41 @@ func main() {
 		log.Fatalln(err)
 	}
 
+	blobs, err := blobstore.New(functionmanager.FunctionManagerFlags.BlobStore, functionmanager.FunctionManagerFlags.BlobStoreDir,
+		blobstore.S3Config{
+			Endpoint:  functionmanager.FunctionManagerFlags.S3Endpoint,
+			Bucket:    functionmanager.FunctionManagerFlags.S3Bucket,
+			Region:    functionmanager.FunctionManagerFlags.S3Region,
+			AccessKey: functionmanager.FunctionManagerFlags.S3AccessKey,
+			SecretKey: functionmanager.FunctionManagerFlags.S3SecretKey,
+		})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if blobs != nil {
+		es = functionmanager.NewPayloadStore(es, blobs, functionmanager.FunctionManagerFlags.PayloadThreshold)
+	}
+
 	faas := drivers[config.Global.Function.Faas]()
 	defer utils.Close(faas)
 
 	c := &functionmanager.ControllerConfig{
-		ResyncPeriod: time.Duration(config.Global.Function.ResyncPeriod) * time.Second,
+		ResyncPeriod:    time.Duration(config.Global.Function.ResyncPeriod) * time.Second,
+		Workers:         functionmanager.FunctionManagerFlags.Workers,
+		ReservedWorkers: functionmanager.FunctionManagerFlags.ReservedWorkers,
 	}
 
 	secretsClient := client.NewSecretsClient(functionmanager.FunctionManagerFlags.SecretStore, client.AuthWithToken("cookie"), "")
 	servicesClient := client.NewServicesClient(functionmanager.FunctionManagerFlags.ServiceManager, client.AuthWithToken("cookie"), "")
 
+	middlewares := fnmiddleware.New(functionmanager.FunctionManagerFlags.HookHosts)
 	r := runner.New(&runner.Config{
 		Faas:            faas,
 		Validator:       validator.New(),
 		SecretInjector:  injectors.NewSecretInjector(secretsClient),
 		ServiceInjector: injectors.NewServiceInjector(secretsClient, servicesClient),
+		ConfigInjector:  injectors.NewConfigInjector(es),
+		Middlewares:     middlewares,
 	})
 
 	var imageGetter functionmanager.ImageGetter
@@ -202,11 +243,33 @@ func main() {
 	}
 	imageBuilder := functions.NewDockerImageBuilder(config.Global.Registry.RegistryURI, registryAuth, dc)
 
-	controller := functionmanager.NewController(c, es, faas, r, imageGetter, imageBuilder)
+	logs := functionmanager.NewLogBuffer(functionmanager.FunctionManagerFlags.LogBufferRuns, functionmanager.FunctionManagerFlags.LogBufferLines)
+
+	limiter := quotas.NewLimiter(es)
+
+	eventsClient := client.NewEventsClient(functionmanager.FunctionManagerFlags.EventManager, client.AuthWithToken("cookie"), "")
+	callbacks := functionmanager.NewCallbacks(es, eventsClient, secretsClient)
+
+	var invocations *functionmanager.Invocations
+	if functionmanager.FunctionManagerFlags.InvocationKey != "" {
+		key, err := invocation.LoadPrivateKey(functionmanager.FunctionManagerFlags.InvocationKey)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		invocations = functionmanager.NewInvocations(key, functionmanager.FunctionManagerFlags.InvocationURL)
+		invocations.TTL = functionmanager.FunctionManagerFlags.InvocationTTL
+	}
+
+	cancellations := functionmanager.NewCancellations()
+
+	controller := functionmanager.NewController(c, es, faas, r, imageGetter, imageBuilder, logs, limiter, callbacks, invocations, cancellations)
 	defer controller.Shutdown()
 	controller.Start()
 
-	handlers := functionmanager.NewHandlers(controller.Watcher(), es)
+	handlers := functionmanager.NewHandlers(controller.Watcher(), es, logs, limiter, middlewares, functionmanager.FunctionManagerFlags.IdempotencyWindow)
+	handlers.Invocations = invocations
+	handlers.Callbacks = callbacks
+	handlers.Cancellations = cancellations
 	handlers.ConfigureHandlers(api)
 
 	healthChecker := func() error {
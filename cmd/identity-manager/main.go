@@ -21,6 +21,7 @@ import (
 	iam "github.com/vmware/dispatch/pkg/identity-manager"
 	"github.com/vmware/dispatch/pkg/identity-manager/gen/restapi"
 	"github.com/vmware/dispatch/pkg/identity-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/invocation"
 	"github.com/vmware/dispatch/pkg/middleware"
 	"github.com/vmware/dispatch/pkg/utils"
 )
@@ -105,6 +106,13 @@ func main() {
 	controller.Start()
 
 	handlers := identitymanager.NewHandlers(controller.Watcher(), es, enforcer)
+	if identitymanager.IdentityManagerFlags.InvocationPublicKey != "" {
+		key, err := invocation.LoadPublicKey(identitymanager.IdentityManagerFlags.InvocationPublicKey)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		handlers.InvocationKey = key
+	}
 	handlers.ConfigureHandlers(api)
 
 	healthChecker := func() error {
@@ -35,17 +35,19 @@ const (
 var dispatchTransformer = Plugin{
 	Name: "dispatch-transformer",
 	Config: map[string]interface{}{
-		"config.substitute.input":            "input",
-		"config.substitute.output":           "output",
-		"config.substitute.http_context":     "httpContext",
-		"config.enable.input":                true,
-		"config.enable.output":               true,
-		"config.enable.http_context":         true,
-		"config.http_method":                 "POST",
-		"config.add.header":                  []string{"cookie:cookie"},
-		"config.add.internal_header":         []string{},
-		"config.header_prefix_for_insertion": "x-dispatch-",
-		"config.insert_to_body.header":       "blocking:true",
+		"config.substitute.input":              "input",
+		"config.substitute.input_content_type": "inputContentType",
+		"config.substitute.output":             "output",
+		"config.substitute.http_context":       "httpContext",
+		"config.substitute.idempotency_key":    "idempotencyKey",
+		"config.enable.input":                  true,
+		"config.enable.output":                 true,
+		"config.enable.http_context":           true,
+		"config.http_method":                   "POST",
+		"config.add.header":                    []string{"cookie:cookie"},
+		"config.add.internal_header":           []string{},
+		"config.header_prefix_for_insertion":   "x-dispatch-",
+		"config.insert_to_body.header":         "blocking:true",
 	},
 }
 
@@ -130,3 +130,33 @@ func TestGatewayPostRequest(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec4.Code)
 
 }
+
+func TestGatewayBinaryPayload(t *testing.T) {
+	fnClient := &mocks.FunctionsClient{}
+	fnClient.On("RunFunction", mock.Anything, mock.Anything, mock.MatchedBy(func(run *v1.Run) bool {
+		return run.InputContentType == "image/png" && run.Input == "iVBORw=="
+	})).Return(
+		&v1.Run{Output: "YSxiCjEsMgo=", OutputContentType: "text/csv"}, nil,
+	)
+	gw, err := NewGateway(fnClient)
+	assert.NoError(t, err)
+
+	api := &gateway.API{
+		ID:        uuid.NewV4().String(),
+		CreatedAt: int(time.Now().Unix()),
+		Name:      "api",
+		Function:  "function1",
+		URIs:      []string{"/convert"},
+		Methods:   []string{"POST"},
+		Enabled:   true,
+	}
+	gw.AddAPI(context.Background(), api)
+
+	req := httptest.NewRequest("POST", "http://localhost:8080/convert", bytes.NewBuffer([]byte{0x89, 0x50, 0x4e, 0x47}))
+	req.Header.Add("Content-type", "image/png")
+	rec := httptest.NewRecorder()
+	gw.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-type"))
+	assert.Equal(t, "a,b\n1,2\n", rec.Body.String())
+}
@@ -6,6 +6,7 @@
 package local
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"github.com/vmware/dispatch/pkg/api-manager/gateway"
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/functions"
 )
 
 // Serve sets the handler and starts the API Gateway HTTP server
@@ -61,7 +63,7 @@ func (g *Gateway) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	input, err := getInput(req)
+	input, contentType, err := getInput(req)
 	if err != nil {
 		writeErrorResp(rw, 400, err.Error())
 		return
@@ -73,10 +75,12 @@ func (g *Gateway) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	run := v1.Run{
-		Blocking:     blocking,
-		FunctionName: api.Function,
-		Input:        input,
-		HTTPContext:  getContext(req, api.Function),
+		Blocking:         blocking,
+		FunctionName:     api.Function,
+		Input:            input,
+		InputContentType: contentType,
+		HTTPContext:      getContext(req, api.Function),
+		IdempotencyKey:   req.Header.Get("Idempotency-Key"),
 	}
 	resp, err := g.fnClient.RunFunction(req.Context(), api.OrganizationID, &run)
 	if err != nil {
@@ -96,6 +100,18 @@ func (g *Gateway) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		writeEmptyResp(rw, 200)
 		return
 	}
+	if resp.OutputContentType != "" {
+		// binary and text outputs are returned as is, with their content type
+		str, _ := resp.Output.(string)
+		data, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			writeErrorResp(rw, 502, fmt.Sprintf("invalid %s output: %s", resp.OutputContentType, err))
+			return
+		}
+		rw.Header().Add("Content-type", resp.OutputContentType)
+		rw.Write(data)
+		return
+	}
 	rw.Header().Add("Content-type", "application/json")
 	enc := json.NewEncoder(rw)
 	enc.Encode(resp.Output)
@@ -172,14 +188,15 @@ func addCORS(rw http.ResponseWriter) {
 	rw.Header().Add("Access-Control-Allow-Headers", "*")
 }
 
-// getInput processes the request and generates an input for the function.
-func getInput(req *http.Request) (interface{}, error) {
+// getInput processes the request and generates an input for the function. JSON, form and query inputs are returned as
+// JSON with an empty content type, any other body is returned base64-encoded with its content type.
+func getInput(req *http.Request) (interface{}, string, error) {
 	if req.Method == http.MethodGet {
-		return processValues(req.URL.Query()), nil
+		return processValues(req.URL.Query()), "", nil
 	}
 
 	if req.ContentLength == 0 {
-		return map[string]interface{}{}, nil
+		return map[string]interface{}{}, "", nil
 	}
 
 	contentType := req.Header.Get("Content-type")
@@ -188,27 +205,26 @@ func getInput(req *http.Request) (interface{}, error) {
 		req.ParseMultipartForm(
 			int64(10 << 20), // 10 MB
 		)
-		return processValues(req.Form), nil
+		return processValues(req.Form), "", nil
 	}
 
-	if isJSONContentType(contentType) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	} else if functions.IsJSONContentType(contentType) {
 		var input interface{}
 		dec := json.NewDecoder(req.Body)
 		err := dec.Decode(&input)
 		if err != nil {
-			return nil, errors.New("request body is not json")
+			return nil, "", errors.New("request body is not json")
 		}
-		return input, nil
+		return input, "", nil
 	}
 
-	if contentType != "" {
-		return nil, fmt.Errorf("request body type is not supported: %s", contentType)
-	}
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return body, nil
+	return base64.StdEncoding.EncodeToString(body), contentType, nil
 }
 
 func getContext(req *http.Request, funcName string) map[string]interface{} {
@@ -261,27 +277,6 @@ func isFormContentType(contentType string) bool {
 	return false
 }
 
-// isJSONContentType checks if provided string is a proper JSON content type
-func isJSONContentType(contentType string) bool {
-	if contentType == "" {
-		return false
-	}
-
-	for _, v := range strings.Split(contentType, ",") {
-		t, _, err := mime.ParseMediaType(v)
-		if err != nil {
-			break
-		}
-		if t == "application/json" {
-			return true
-		}
-		if strings.HasPrefix(t, "application/") && strings.HasSuffix(t, "+json") {
-			return true
-		}
-	}
-	return false
-}
-
 // cleanHost returns host without port, if one is set
 func cleanHost(host string) string {
 	if host == "" {
@@ -55,29 +55,37 @@ func TestGetInput(t *testing.T) {
 	req1 := httptest.NewRequest("POST", "http://example.com/hello", bytes.NewBuffer([]byte(`{"key":"value"}`)))
 	req1.Host = "example.com"
 	req1.Header.Add("Content-type", "application/json")
-	body, err := getInput(req1)
+	body, contentType, err := getInput(req1)
 	assert.NoError(t, err)
+	assert.Empty(t, contentType)
 	assert.Equal(t, "value", body.(map[string]interface{})["key"])
 
 	req2 := httptest.NewRequest("POST", "http://example.com/hello", bytes.NewBuffer(nil))
 	req2.Host = "example.com"
-	body, err = getInput(req2)
+	body, _, err = getInput(req2)
 	assert.NoError(t, err)
 	assert.Equal(t, map[string]interface{}{}, body)
 
 	req3 := httptest.NewRequest("POST", "http://example.com/hello", bytes.NewBuffer([]byte(`name=VMware&place=Palo Alto`)))
 	req3.Host = "example.com"
 	req3.Header.Add("Content-type", "application/x-www-form-urlencoded")
-	body, err = getInput(req3)
+	body, _, err = getInput(req3)
 	assert.NoError(t, err)
 	assert.Equal(t, "Palo Alto", body.(map[string]interface{})["place"])
 
 	req4 := httptest.NewRequest("POST", "http://example.com/hello", bytes.NewBuffer([]byte(`blabla`)))
 	req4.Host = "example.com"
 	req4.Header.Add("Content-type", "text/plain")
-	body, err = getInput(req4)
-	assert.Error(t, err)
+	body, contentType, err = getInput(req4)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", contentType)
+	assert.Equal(t, "YmxhYmxh", body)
 
+	req5 := httptest.NewRequest("POST", "http://example.com/hello", bytes.NewBuffer([]byte(`{"key":`)))
+	req5.Host = "example.com"
+	req5.Header.Add("Content-type", "application/json")
+	_, _, err = getInput(req5)
+	assert.Error(t, err)
 }
 
 func TestMatchString(t *testing.T) {
@@ -98,24 +106,6 @@ func TestMatchString(t *testing.T) {
 	}
 }
 
-func TestIsJSONContentType(t *testing.T) {
-	testCases := []struct {
-		In  string
-		Out bool
-	}{
-		{"", false},
-		{"multipart/form-data; boundary=---------------------------974767299852498929531610575", false},
-		{"text/html; charset=utf-8", false},
-		{"application/json", true},
-		{"application/cloudevents+json", true},
-	}
-
-	for _, c := range testCases {
-		result := isJSONContentType(c.In)
-		assert.Equal(t, result, c.Out)
-	}
-}
-
 func TestCleanHost(t *testing.T) {
 	testCases := []struct {
 		In  string
@@ -0,0 +1,234 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// Batch runs of a function over a list of inputs
+// swagger:model Batch
+type Batch struct {
+
+	// maximum number of runs of the batch executed at the same time, 10 by default
+	Concurrency int64 `json:"concurrency,omitempty"`
+
+	// created time
+	// Read Only: true
+	CreatedTime int64 `json:"createdTime,omitempty"`
+
+	// number of runs in error
+	// Read Only: true
+	Failed int64 `json:"failed,omitempty"`
+
+	// finished time
+	// Read Only: true
+	FinishedTime int64 `json:"finishedTime,omitempty"`
+
+	// name of the function run over the inputs
+	// Pattern: ^[\w\d\-]+$
+	FunctionName string `json:"functionName,omitempty"`
+
+	// inputs of the runs, one run per input, not returned
+	Inputs []interface{} `json:"inputs"`
+
+	// name
+	// Read Only: true
+	Name strfmt.UUID `json:"name,omitempty"`
+
+	// reason
+	Reason []string `json:"reason"`
+
+	// status
+	Status Status `json:"status,omitempty"`
+
+	// number of READY runs
+	// Read Only: true
+	Succeeded int64 `json:"succeeded,omitempty"`
+
+	// tags
+	Tags []*Tag `json:"tags"`
+
+	// number of inputs
+	// Read Only: true
+	Total int64 `json:"total,omitempty"`
+}
+
+// Validate validates this batch
+func (m *Batch) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateConcurrency(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateFunctionName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateInputs(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateReason(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTags(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Batch) validateConcurrency(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Concurrency) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("concurrency", "body", int64(m.Concurrency), 1, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("concurrency", "body", int64(m.Concurrency), 100, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Batch) validateFunctionName(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.FunctionName) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("functionName", "body", string(m.FunctionName), `^[\w\d\-]+$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Batch) validateInputs(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Inputs) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Batch) validateName(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Name) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("name", "body", "uuid", m.Name.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Batch) validateReason(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Reason) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Batch) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	if err := m.Status.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("status")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Batch) validateTags(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Tags) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Tags); i++ {
+
+		if swag.IsZero(m.Tags[i]) { // not required
+			continue
+		}
+
+		if m.Tags[i] != nil {
+
+			if err := m.Tags[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("tags" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Batch) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Batch) UnmarshalBinary(b []byte) error {
+	var res Batch
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
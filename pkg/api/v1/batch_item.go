@@ -0,0 +1,131 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// BatchItem result of the run of a batch input
+// swagger:model BatchItem
+type BatchItem struct {
+
+	// error
+	Error *InvocationError `json:"error,omitempty"`
+
+	// index of the input in the batch
+	Index int64 `json:"index,omitempty"`
+
+	// output
+	Output interface{} `json:"output,omitempty"`
+
+	// content type of a binary or text output, which is a base64-encoded string, empty for JSON
+	OutputContentType string `json:"outputContentType,omitempty"`
+
+	// name of the run of the input
+	Run strfmt.UUID `json:"run,omitempty"`
+
+	// status
+	Status Status `json:"status,omitempty"`
+}
+
+// Validate validates this batch item
+func (m *BatchItem) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateError(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateRun(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *BatchItem) validateError(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Error) { // not required
+		return nil
+	}
+
+	if m.Error != nil {
+
+		if err := m.Error.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("error")
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+func (m *BatchItem) validateRun(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Run) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("run", "body", "uuid", m.Run.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *BatchItem) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	if err := m.Status.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("status")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *BatchItem) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *BatchItem) UnmarshalBinary(b []byte) error {
+	var res BatchItem
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
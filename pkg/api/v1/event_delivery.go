@@ -0,0 +1,72 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+)
+
+// NO TESTS
+
+// EventDelivery is the outcome of the delivery of an event to a subscription
+// swagger:model EventDelivery
+type EventDelivery struct {
+
+	// error running the function, if the delivery failed
+	// Read Only: true
+	Error string `json:"error,omitempty"`
+
+	// name of the function of the subscription
+	// Read Only: true
+	Function string `json:"function,omitempty"`
+
+	// true if the event was delivered by a replay
+	// Read Only: true
+	Replay bool `json:"replay,omitempty"`
+
+	// name of the function run
+	// Read Only: true
+	Run string `json:"run,omitempty"`
+
+	// name of the subscription
+	// Read Only: true
+	Subscription string `json:"subscription,omitempty"`
+
+	// time of the delivery
+	// Read Only: true
+	Time int64 `json:"time,omitempty"`
+}
+
+// Validate validates this event delivery
+func (m *EventDelivery) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *EventDelivery) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *EventDelivery) UnmarshalBinary(b []byte) error {
+	var res EventDelivery
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
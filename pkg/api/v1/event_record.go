@@ -0,0 +1,180 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"encoding/json"
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// EventRecord is an event recorded by the event manager, with the outcomes of its deliveries
+// swagger:model EventRecord
+type EventRecord struct {
+
+	// deliveries of the event to subscriptions, in order
+	// Read Only: true
+	Deliveries []*EventDelivery `json:"deliveries"`
+
+	// event
+	Event *CloudEvent `json:"event,omitempty"`
+
+	// api for events emitted through the API, schedule for events emitted by schedules, transport for events published to the event transport by others, e.g. ingested by event drivers
+	// Read Only: true
+	Origin string `json:"origin,omitempty"`
+
+	// recorded time
+	// Read Only: true
+	RecordedTime int64 `json:"recordedTime,omitempty"`
+
+	// topic the event was published to
+	// Read Only: true
+	Topic string `json:"topic,omitempty"`
+}
+
+// Validate validates this event record
+func (m *EventRecord) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateDeliveries(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateEvent(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateOrigin(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *EventRecord) validateDeliveries(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Deliveries) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Deliveries); i++ {
+
+		if swag.IsZero(m.Deliveries[i]) { // not required
+			continue
+		}
+
+		if m.Deliveries[i] != nil {
+
+			if err := m.Deliveries[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("deliveries" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+func (m *EventRecord) validateEvent(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Event) { // not required
+		return nil
+	}
+
+	if m.Event != nil {
+
+		if err := m.Event.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("event")
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+var eventRecordOriginPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["api","schedule","transport"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		eventRecordOriginPropEnum = append(eventRecordOriginPropEnum, v)
+	}
+}
+
+const (
+
+	// EventRecordOriginAPI captures enum value "api"
+	EventRecordOriginAPI string = "api"
+
+	// EventRecordOriginSchedule captures enum value "schedule"
+	EventRecordOriginSchedule string = "schedule"
+
+	// EventRecordOriginTransport captures enum value "transport"
+	EventRecordOriginTransport string = "transport"
+)
+
+// prop value enum
+func (m *EventRecord) validateOriginEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, eventRecordOriginPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *EventRecord) validateOrigin(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Origin) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateOriginEnum("origin", "body", m.Origin); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *EventRecord) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *EventRecord) UnmarshalBinary(b []byte) error {
+	var res EventRecord
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,99 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// EventReplay replays recorded events to a subscription, the events of a time range or of a list of IDs
+// swagger:model EventReplay
+type EventReplay struct {
+
+	// number of events accepted for replay, i.e. matching the filters of the subscription, they are delivered in the background
+	// Read Only: true
+	Accepted int64 `json:"accepted,omitempty"`
+
+	// IDs of the events to replay
+	EventIds []string `json:"eventIds"`
+
+	// replay the events recorded since the given Unix time
+	Since int64 `json:"since,omitempty"`
+
+	// name of the subscription the events are replayed to
+	// Required: true
+	// Pattern: ^[\w\d][\w\d\-]*$
+	Subscription *string `json:"subscription"`
+
+	// replay the events recorded before the given Unix time
+	Until int64 `json:"until,omitempty"`
+}
+
+// Validate validates this event replay
+func (m *EventReplay) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateEventIds(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSubscription(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *EventReplay) validateEventIds(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.EventIds) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *EventReplay) validateSubscription(formats strfmt.Registry) error {
+
+	if err := validate.Required("subscription", "body", m.Subscription); err != nil {
+		return err
+	}
+
+	if err := FieldPatternName.Validate("subscription", *m.Subscription); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *EventReplay) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *EventReplay) UnmarshalBinary(b []byte) error {
+	var res EventReplay
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -61,6 +61,12 @@ type Function struct {
 	// Pattern: ^[\w\d][\w\d\-]*$
 	Name *string `json:"name"`
 
+	// event published when a run fails, with the error of the run
+	OnFailure *FunctionEvent `json:"onFailure,omitempty"`
+
+	// event published when a run succeeds, with the output of the run
+	OnSuccess *FunctionEvent `json:"onSuccess,omitempty"`
+
 	// reason
 	Reason []string `json:"reason"`
 
@@ -73,6 +79,15 @@ type Function struct {
 	// services
 	Services []string `json:"services"`
 
+	// environment configuration, injected in the function context and the container environment
+	Config map[string]string `json:"config,omitempty"`
+
+	// names of the config maps merged into the configuration
+	ConfigMaps []string `json:"configMaps"`
+
+	// middlewares applied to the runs of the function, the first one being the outermost
+	Middlewares []*FunctionMiddleware `json:"middlewares"`
+
 	// status
 	Status Status `json:"status,omitempty"`
 
@@ -117,6 +132,16 @@ func (m *Function) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateOnFailure(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateOnSuccess(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateSchema(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -132,6 +157,16 @@ func (m *Function) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateConfigMaps(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateMiddlewares(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateStatus(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -218,6 +253,46 @@ func (m *Function) validateName(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Function) validateOnFailure(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.OnFailure) { // not required
+		return nil
+	}
+
+	if m.OnFailure != nil {
+
+		if err := m.OnFailure.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("onFailure")
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Function) validateOnSuccess(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.OnSuccess) { // not required
+		return nil
+	}
+
+	if m.OnSuccess != nil {
+
+		if err := m.OnSuccess.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("onSuccess")
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
 func (m *Function) validateSchema(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Schema) { // not required
@@ -256,6 +331,43 @@ func (m *Function) validateServices(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Function) validateConfigMaps(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ConfigMaps) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Function) validateMiddlewares(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Middlewares) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Middlewares); i++ {
+
+		if swag.IsZero(m.Middlewares[i]) { // not required
+			continue
+		}
+
+		if m.Middlewares[i] != nil {
+
+			if err := m.Middlewares[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("middlewares" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
 func (m *Function) validateStatus(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Status) { // not required
@@ -0,0 +1,184 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// FunctionBuild records a build of the image of a function
+// swagger:model FunctionBuild
+type FunctionBuild struct {
+
+	// whether an image built from the same content was reused instead of building a new one
+	// Read Only: true
+	Cached bool `json:"cached,omitempty"`
+
+	// hash of the source, image and handler the function image is built from
+	// Read Only: true
+	ContentHash string `json:"contentHash,omitempty"`
+
+	// created time
+	// Read Only: true
+	CreatedTime int64 `json:"createdTime,omitempty"`
+
+	// function image built
+	// Read Only: true
+	FunctionImageURL string `json:"functionImageURL,omitempty"`
+
+	// name of the function built
+	// Read Only: true
+	FunctionName string `json:"functionName,omitempty"`
+
+	// id
+	// Read Only: true
+	ID strfmt.UUID `json:"id,omitempty"`
+
+	// kind
+	// Read Only: true
+	// Pattern: ^[\w\d\-]+$
+	Kind string `json:"kind,omitempty"`
+
+	// output of the docker build
+	// Read Only: true
+	Logs string `json:"logs,omitempty"`
+
+	// modified time
+	// Read Only: true
+	ModifiedTime int64 `json:"modifiedTime,omitempty"`
+
+	// name
+	// Read Only: true
+	// Pattern: ^[\w\d][\w\d\-]*$
+	Name string `json:"name,omitempty"`
+
+	// reason
+	// Read Only: true
+	Reason []string `json:"reason"`
+
+	// status
+	Status Status `json:"status,omitempty"`
+}
+
+// Validate validates this function build
+func (m *FunctionBuild) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateID(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateKind(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateReason(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *FunctionBuild) validateID(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ID) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("id", "body", "uuid", m.ID.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *FunctionBuild) validateKind(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Kind) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("kind", "body", string(m.Kind), `^[\w\d\-]+$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *FunctionBuild) validateName(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Name) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("name", "body", string(m.Name), `^[\w\d][\w\d\-]*$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *FunctionBuild) validateReason(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Reason) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *FunctionBuild) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	if err := m.Status.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("status")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *FunctionBuild) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *FunctionBuild) UnmarshalBinary(b []byte) error {
+	var res FunctionBuild
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
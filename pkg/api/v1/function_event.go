@@ -0,0 +1,83 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// FunctionEvent event published with the result of the runs of a function
+// swagger:model FunctionEvent
+type FunctionEvent struct {
+
+	// fields of the event data mapped to dot-separated paths in the run (name, functionName, input, output or error), the output or error of the run if empty
+	Data map[string]string `json:"data,omitempty"`
+
+	// type of the event
+	// Required: true
+	// Max Length: 128
+	// Pattern: ^[\w\d\-\.]+$
+	EventType *string `json:"eventType"`
+
+	// source of the event, dispatch/functions/<function name> if empty
+	Source string `json:"source,omitempty"`
+}
+
+// Validate validates this function event
+func (m *FunctionEvent) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateEventType(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *FunctionEvent) validateEventType(formats strfmt.Registry) error {
+
+	if err := validate.Required("eventType", "body", m.EventType); err != nil {
+		return err
+	}
+
+	if err := validate.MaxLength("eventType", "body", string(*m.EventType), 128); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("eventType", "body", string(*m.EventType), `^[\w\d\-\.]+$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *FunctionEvent) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *FunctionEvent) UnmarshalBinary(b []byte) error {
+	var res FunctionEvent
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
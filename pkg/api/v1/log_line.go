@@ -0,0 +1,60 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+)
+
+// NO TESTS
+
+// LogLine a single line of function run output
+// swagger:model LogLine
+type LogLine struct {
+
+	// function name
+	FunctionName string `json:"functionName,omitempty"`
+
+	// run name
+	RunName string `json:"runName,omitempty"`
+
+	// stream the line was written to (stdout or stderr)
+	Stream string `json:"stream,omitempty"`
+
+	// text
+	Text string `json:"text,omitempty"`
+}
+
+// Validate validates this log line
+func (m *LogLine) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *LogLine) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *LogLine) UnmarshalBinary(b []byte) error {
+	var res LogLine
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,198 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// Quota limits the function runs of an organization, or of one of its functions
+// swagger:model Quota
+type Quota struct {
+
+	// number of runs currently executing
+	// Read Only: true
+	ConcurrentRuns int64 `json:"concurrentRuns,omitempty"`
+
+	// created time
+	// Read Only: true
+	CreatedTime int64 `json:"createdTime,omitempty"`
+
+	// function the quota applies to, all the functions of the organization if empty
+	// Pattern: ^[\w\d\-]*$
+	Function string `json:"function,omitempty"`
+
+	// id
+	// Read Only: true
+	ID strfmt.UUID `json:"id,omitempty"`
+
+	// kind
+	// Read Only: true
+	// Pattern: ^[\w\d\-]+$
+	Kind string `json:"kind,omitempty"`
+
+	// maximum number of runs executing at the same time, unlimited if 0
+	MaxConcurrentRuns int64 `json:"maxConcurrentRuns,omitempty"`
+
+	// maximum number of runs waiting for one of the maxConcurrentRuns to finish
+	MaxQueuedRuns int64 `json:"maxQueuedRuns,omitempty"`
+
+	// modified time
+	// Read Only: true
+	ModifiedTime int64 `json:"modifiedTime,omitempty"`
+
+	// name
+	// Required: true
+	// Pattern: ^[\w\d][\w\d\-]*$
+	Name *string `json:"name"`
+
+	// number of runs currently waiting to execute
+	// Read Only: true
+	QueuedRuns int64 `json:"queuedRuns,omitempty"`
+
+	// maximum rate of new runs, unlimited if 0
+	RunsPerSecond float64 `json:"runsPerSecond,omitempty"`
+
+	// tags
+	Tags []*Tag `json:"tags"`
+}
+
+// Validate validates this quota
+func (m *Quota) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateFunction(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateID(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateKind(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTags(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Quota) validateFunction(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Function) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("function", "body", string(m.Function), `^[\w\d\-]*$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Quota) validateID(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ID) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("id", "body", "uuid", m.ID.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Quota) validateKind(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Kind) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("kind", "body", string(m.Kind), `^[\w\d\-]+$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Quota) validateName(formats strfmt.Registry) error {
+
+	if err := validate.Required("name", "body", m.Name); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("name", "body", string(*m.Name), `^[\w\d][\w\d\-]*$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Quota) validateTags(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Tags) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Tags); i++ {
+
+		if swag.IsZero(m.Tags[i]) { // not required
+			continue
+		}
+
+		if m.Tags[i] != nil {
+
+			if err := m.Tags[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("tags" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Quota) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Quota) UnmarshalBinary(b []byte) error {
+	var res Quota
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
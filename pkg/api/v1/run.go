@@ -6,6 +6,7 @@
 package v1
 
 import (
+	"encoding/json"
 	"strconv"
 
 	strfmt "github.com/go-openapi/strfmt"
@@ -24,6 +25,13 @@ type Run struct {
 	// blocking
 	Blocking bool `json:"blocking,omitempty"`
 
+	// callback
+	Callback *RunCallback `json:"callback,omitempty"`
+
+	// whether the run was cancelled before it finished
+	// Read Only: true
+	Cancelled bool `json:"cancelled,omitempty"`
+
 	// error
 	Error *InvocationError `json:"error,omitempty"`
 
@@ -53,9 +61,15 @@ type Run struct {
 	// Read Only: true
 	HTTPContext map[string]interface{} `json:"httpContext,omitempty"`
 
+	// idempotency key, runs of the same function with the same key are only executed once
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+
 	// input
 	Input interface{} `json:"input,omitempty"`
 
+	// content type of a binary or text input, which is a base64-encoded string, empty for JSON
+	InputContentType string `json:"inputContentType,omitempty"`
+
 	// logs
 	Logs *Logs `json:"logs,omitempty"`
 
@@ -67,9 +81,24 @@ type Run struct {
 	// Read Only: true
 	Output interface{} `json:"output,omitempty"`
 
+	// content type of a binary or text output, which is a base64-encoded string, empty for JSON
+	// Read Only: true
+	OutputContentType string `json:"outputContentType,omitempty"`
+
+	// name of the run whose invocation token created this run
+	// Read Only: true
+	ParentRun strfmt.UUID `json:"parentRun,omitempty"`
+
+	// priority class of the run, high for blocking runs, low for runs of events and schedules and normal otherwise if empty
+	Priority string `json:"priority,omitempty"`
+
 	// reason
 	Reason []string `json:"reason"`
 
+	// name of the run this run replays
+	// Read Only: true
+	ReplayOf strfmt.UUID `json:"replayOf,omitempty"`
+
 	// secrets
 	Secrets []string `json:"secrets"`
 
@@ -81,12 +110,21 @@ type Run struct {
 
 	// tags
 	Tags []*Tag `json:"tags"`
+
+	// ID of the trace of the run, empty when tracing is disabled
+	// Read Only: true
+	TraceID string `json:"traceId,omitempty"`
 }
 
 // Validate validates this run
 func (m *Run) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCallback(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateError(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -112,11 +150,26 @@ func (m *Run) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateParentRun(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validatePriority(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateReason(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validateReplayOf(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateSecrets(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -143,6 +196,26 @@ func (m *Run) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Run) validateCallback(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Callback) { // not required
+		return nil
+	}
+
+	if m.Callback != nil {
+
+		if err := m.Callback.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("callback")
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
 func (m *Run) validateError(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Error) { // not required
@@ -229,6 +302,65 @@ func (m *Run) validateName(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Run) validateParentRun(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ParentRun) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("parentRun", "body", "uuid", m.ParentRun.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var runPriorityPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["high","normal","low"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		runPriorityPropEnum = append(runPriorityPropEnum, v)
+	}
+}
+
+const (
+
+	// RunPriorityHigh captures enum value "high"
+	RunPriorityHigh string = "high"
+
+	// RunPriorityNormal captures enum value "normal"
+	RunPriorityNormal string = "normal"
+
+	// RunPriorityLow captures enum value "low"
+	RunPriorityLow string = "low"
+)
+
+// prop value enum
+func (m *Run) validatePriorityEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, runPriorityPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Run) validatePriority(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Priority) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validatePriorityEnum("priority", "body", m.Priority); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *Run) validateReason(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Reason) { // not required
@@ -238,6 +370,19 @@ func (m *Run) validateReason(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Run) validateReplayOf(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ReplayOf) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("replayOf", "body", "uuid", m.ReplayOf.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *Run) validateSecrets(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Secrets) { // not required
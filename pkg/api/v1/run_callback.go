@@ -0,0 +1,82 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// RunCallback callback invoked with the run when it completes
+// swagger:model RunCallback
+type RunCallback struct {
+
+	// type of an event published with the run, instead of calling url
+	// Max Length: 128
+	// Pattern: ^[\w\d\-\.]+$
+	EventType string `json:"eventType,omitempty"`
+
+	// secret signing the request to url with HMAC-SHA256, never returned
+	Secret string `json:"secret,omitempty"`
+
+	// HTTP URL called with the run
+	URL string `json:"url,omitempty"`
+}
+
+// Validate validates this run callback
+func (m *RunCallback) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateEventType(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *RunCallback) validateEventType(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.EventType) { // not required
+		return nil
+	}
+
+	if err := validate.MaxLength("eventType", "body", string(m.EventType), 128); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("eventType", "body", string(m.EventType), `^[\w\d\-\.]+$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *RunCallback) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *RunCallback) UnmarshalBinary(b []byte) error {
+	var res RunCallback
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,76 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+)
+
+// NO TESTS
+
+// RunStats aggregates the runs of a function over a time window, durations are in milliseconds
+// swagger:model RunStats
+type RunStats struct {
+
+	// number of runs
+	// Read Only: true
+	Count int64 `json:"count,omitempty"`
+
+	// number of runs which failed
+	// Read Only: true
+	ErrorCount int64 `json:"errorCount,omitempty"`
+
+	// ratio of runs which failed
+	// Read Only: true
+	ErrorRate float64 `json:"errorRate,omitempty"`
+
+	// name of the function
+	// Read Only: true
+	FunctionName string `json:"functionName,omitempty"`
+
+	// median duration of the finished runs
+	// Read Only: true
+	P50Duration int64 `json:"p50Duration,omitempty"`
+
+	// 95th percentile of the duration of the finished runs
+	// Read Only: true
+	P95Duration int64 `json:"p95Duration,omitempty"`
+
+	// 99th percentile of the duration of the finished runs
+	// Read Only: true
+	P99Duration int64 `json:"p99Duration,omitempty"`
+}
+
+// Validate validates this run stats
+func (m *RunStats) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *RunStats) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *RunStats) UnmarshalBinary(b []byte) error {
+	var res RunStats
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,352 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"encoding/json"
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// Schedule schedule
+// swagger:model Schedule
+type Schedule struct {
+
+	// what to do when a fire time is reached while the function run of the previous fire is still running
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+
+	// created time
+	// Read Only: true
+	CreatedTime int64 `json:"createdTime,omitempty"`
+
+	// cron expression (minute hour day-of-month month day-of-week)
+	// Required: true
+	Cron *string `json:"cron"`
+
+	// event type to emit on every fire, either function or event type is required
+	// Max Length: 128
+	// Pattern: ^[\w\d\-\.]*$
+	EventType string `json:"eventType,omitempty"`
+
+	// function to run on every fire, either function or event type is required
+	// Pattern: ^[\w\d\-]*$
+	Function string `json:"function,omitempty"`
+
+	// id
+	// Read Only: true
+	ID strfmt.UUID `json:"id,omitempty"`
+
+	// input of the function run, or data of the event
+	Input interface{} `json:"input,omitempty"`
+
+	// kind
+	// Read Only: true
+	// Pattern: ^[\w\d\-]+$
+	Kind string `json:"kind,omitempty"`
+
+	// last fire time
+	// Read Only: true
+	LastFireTime int64 `json:"lastFireTime,omitempty"`
+
+	// name of the function run started by the last fire
+	// Read Only: true
+	LastRun string `json:"lastRun,omitempty"`
+
+	// modified time
+	// Read Only: true
+	ModifiedTime int64 `json:"modifiedTime,omitempty"`
+
+	// name
+	// Required: true
+	// Pattern: ^[\w\d][\w\d\-]*$
+	Name *string `json:"name"`
+
+	// next fire time
+	// Read Only: true
+	NextFireTime int64 `json:"nextFireTime,omitempty"`
+
+	// reason
+	Reason []string `json:"reason"`
+
+	// secrets
+	Secrets []string `json:"secrets"`
+
+	// status
+	Status Status `json:"status,omitempty"`
+
+	// tags
+	Tags []*Tag `json:"tags"`
+
+	// timezone the cron expression is evaluated in, e.g. America/Los_Angeles, defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Validate validates this schedule
+func (m *Schedule) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateConcurrencyPolicy(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateCron(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateEventType(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateFunction(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateID(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateKind(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateReason(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSecrets(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTags(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+var scheduleConcurrencyPolicyPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["Allow","Forbid","Replace"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		scheduleConcurrencyPolicyPropEnum = append(scheduleConcurrencyPolicyPropEnum, v)
+	}
+}
+
+const (
+
+	// ScheduleConcurrencyPolicyAllow captures enum value "Allow"
+	ScheduleConcurrencyPolicyAllow string = "Allow"
+
+	// ScheduleConcurrencyPolicyForbid captures enum value "Forbid"
+	ScheduleConcurrencyPolicyForbid string = "Forbid"
+
+	// ScheduleConcurrencyPolicyReplace captures enum value "Replace"
+	ScheduleConcurrencyPolicyReplace string = "Replace"
+)
+
+// prop value enum
+func (m *Schedule) validateConcurrencyPolicyEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, scheduleConcurrencyPolicyPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Schedule) validateConcurrencyPolicy(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ConcurrencyPolicy) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateConcurrencyPolicyEnum("concurrencyPolicy", "body", m.ConcurrencyPolicy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Schedule) validateCron(formats strfmt.Registry) error {
+
+	if err := validate.Required("cron", "body", m.Cron); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Schedule) validateEventType(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.EventType) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("eventType", "body", string(m.EventType), `^[\w\d\-\.]*$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Schedule) validateFunction(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Function) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("function", "body", string(m.Function), `^[\w\d\-]*$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Schedule) validateID(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ID) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("id", "body", "uuid", m.ID.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Schedule) validateKind(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Kind) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("kind", "body", string(m.Kind), `^[\w\d\-]+$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Schedule) validateName(formats strfmt.Registry) error {
+
+	if err := validate.Required("name", "body", m.Name); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("name", "body", string(*m.Name), `^[\w\d][\w\d\-]*$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Schedule) validateReason(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Reason) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Schedule) validateSecrets(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Secrets) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Schedule) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	if err := m.Status.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("status")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Schedule) validateTags(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Tags) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Tags); i++ {
+
+		if swag.IsZero(m.Tags[i]) { // not required
+			continue
+		}
+
+		if m.Tags[i] != nil {
+
+			if err := m.Tags[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("tags" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Schedule) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Schedule) UnmarshalBinary(b []byte) error {
+	var res Schedule
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
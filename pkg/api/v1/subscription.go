@@ -6,6 +6,7 @@
 package v1
 
 import (
+	"encoding/json"
 	"strconv"
 
 	strfmt "github.com/go-openapi/strfmt"
@@ -21,16 +22,27 @@ import (
 // swagger:model Subscription
 type Subscription struct {
 
+	// when events are acknowledged: once the run of the function is accepted (the default if empty), or once the run succeeded
+	Ack string `json:"ack,omitempty"`
+
 	// created time
 	// Read Only: true
 	CreatedTime int64 `json:"createdTime,omitempty"`
 
-	// event type
+	// event type of the dead-letter topic, which receives the events that could not be delivered
+	// Read Only: true
+	DeadLetterEventType string `json:"deadLetterEventType,omitempty"`
+
+	// event type, or a pattern where the word * matches one word and # zero or more words, e.g. vm.*
 	// Required: true
 	// Max Length: 128
-	// Pattern: ^[\w\d\-\.]+$
+	// Min Length: 1
+	// Pattern: ^([\w\d\-]*|\*|#)(\.([\w\d\-]*|\*|#))*$
 	EventType *string `json:"eventType"`
 
+	// conditions on the attributes, extensions or data of events, all must match to run the function, e.g. data.vm.powerState == "off"
+	Filters []string `json:"filters"`
+
 	// function
 	// Required: true
 	// Pattern: ^[\w\d\-]+$
@@ -45,6 +57,10 @@ type Subscription struct {
 	// Pattern: ^[\w\d\-]+$
 	Kind string `json:"kind,omitempty"`
 
+	// number of events which matched the filters since the event manager started
+	// Read Only: true
+	MatchedEvents int64 `json:"matchedEvents,omitempty"`
+
 	// modified time
 	// Read Only: true
 	ModifiedTime int64 `json:"modifiedTime,omitempty"`
@@ -63,17 +79,31 @@ type Subscription struct {
 
 	// tags
 	Tags []*Tag `json:"tags"`
+
+	// number of events which did not match the filters since the event manager started
+	// Read Only: true
+	UnmatchedEvents int64 `json:"unmatchedEvents,omitempty"`
 }
 
 // Validate validates this subscription
 func (m *Subscription) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateAck(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateEventType(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validateFilters(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateFunction(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -115,21 +145,77 @@ func (m *Subscription) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+var subscriptionAckPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["accepted","succeeded"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		subscriptionAckPropEnum = append(subscriptionAckPropEnum, v)
+	}
+}
+
+const (
+
+	// SubscriptionAckAccepted captures enum value "accepted"
+	SubscriptionAckAccepted string = "accepted"
+
+	// SubscriptionAckSucceeded captures enum value "succeeded"
+	SubscriptionAckSucceeded string = "succeeded"
+)
+
+// prop value enum
+func (m *Subscription) validateAckEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, subscriptionAckPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Subscription) validateAck(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Ack) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateAckEnum("ack", "body", m.Ack); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *Subscription) validateEventType(formats strfmt.Registry) error {
 	if err := validate.Required("eventType", "body", m.EventType); err != nil {
 		return err
 	}
 
+	if err := validate.MinLength("eventType", "body", string(*m.EventType), 1); err != nil {
+		return err
+	}
+
 	if err := validate.MaxLength("eventType", "body", string(*m.EventType), 128); err != nil {
 		return err
 	}
 
-	if err := validate.Pattern("eventType", "body", string(*m.EventType), `^[\w\d\-\.]+$`); err != nil {
+	if err := validate.Pattern("eventType", "body", string(*m.EventType), `^([\w\d\-]*|\*|#)(\.([\w\d\-]*|\*|#))*$`); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (m *Subscription) validateFilters(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Filters) { // not required
+		return nil
+	}
+
+	return nil
+}
+
 func (m *Subscription) validateFunction(formats strfmt.Registry) error {
 
 	if err := validate.Required("function", "body", m.Function); err != nil {
@@ -0,0 +1,106 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// WorkflowBranch a branch of a parallel state
+// swagger:model WorkflowBranch
+type WorkflowBranch struct {
+
+	// name of the first state
+	// Required: true
+	StartAt *string `json:"startAt"`
+
+	// states
+	// Required: true
+	States []*WorkflowState `json:"states"`
+}
+
+// Validate validates this workflow branch
+func (m *WorkflowBranch) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateStartAt(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStates(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *WorkflowBranch) validateStartAt(formats strfmt.Registry) error {
+
+	if err := validate.Required("startAt", "body", m.StartAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *WorkflowBranch) validateStates(formats strfmt.Registry) error {
+
+	if err := validate.Required("states", "body", m.States); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.States); i++ {
+
+		if swag.IsZero(m.States[i]) { // not required
+			continue
+		}
+
+		if m.States[i] != nil {
+
+			if err := m.States[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("states" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *WorkflowBranch) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *WorkflowBranch) UnmarshalBinary(b []byte) error {
+	var res WorkflowBranch
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,133 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"encoding/json"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// WorkflowChoice a rule of a choice state
+// swagger:model WorkflowChoice
+type WorkflowChoice struct {
+
+	// name of the next state if the rule matches
+	// Required: true
+	Next *string `json:"next"`
+
+	// operator
+	// Required: true
+	Operator *string `json:"operator"`
+
+	// value compared to the variable
+	Value interface{} `json:"value,omitempty"`
+
+	// dot separated path of a field of the state input, e.g. result.status
+	// Required: true
+	Variable *string `json:"variable"`
+}
+
+// Validate validates this workflow choice
+func (m *WorkflowChoice) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateNext(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateOperator(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateVariable(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *WorkflowChoice) validateNext(formats strfmt.Registry) error {
+
+	if err := validate.Required("next", "body", m.Next); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var workflowChoiceOperatorPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["==","!=","<","<=",">",">="]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		workflowChoiceOperatorPropEnum = append(workflowChoiceOperatorPropEnum, v)
+	}
+}
+
+// prop value enum
+func (m *WorkflowChoice) validateOperatorEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, workflowChoiceOperatorPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *WorkflowChoice) validateOperator(formats strfmt.Registry) error {
+
+	if err := validate.Required("operator", "body", m.Operator); err != nil {
+		return err
+	}
+
+	// value enum
+	if err := m.validateOperatorEnum("operator", "body", *m.Operator); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *WorkflowChoice) validateVariable(formats strfmt.Registry) error {
+
+	if err := validate.Required("variable", "body", m.Variable); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *WorkflowChoice) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *WorkflowChoice) UnmarshalBinary(b []byte) error {
+	var res WorkflowChoice
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
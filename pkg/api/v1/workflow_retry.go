@@ -0,0 +1,57 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+)
+
+// NO TESTS
+
+// WorkflowRetry retry policy of a task state
+// swagger:model WorkflowRetry
+type WorkflowRetry struct {
+
+	// multiplier of the interval after each attempt
+	BackoffRate float64 `json:"backoffRate,omitempty"`
+
+	// interval before the first retry in seconds
+	IntervalSeconds int64 `json:"intervalSeconds,omitempty"`
+
+	// maximum number of retries
+	MaxAttempts int64 `json:"maxAttempts,omitempty"`
+}
+
+// Validate validates this workflow retry
+func (m *WorkflowRetry) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *WorkflowRetry) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *WorkflowRetry) UnmarshalBinary(b []byte) error {
+	var res WorkflowRetry
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,209 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// WorkflowRun workflow run
+// swagger:model WorkflowRun
+type WorkflowRun struct {
+
+	// error
+	// Read Only: true
+	Error string `json:"error,omitempty"`
+
+	// executed time
+	// Read Only: true
+	ExecutedTime int64 `json:"executedTime,omitempty"`
+
+	// finished time
+	// Read Only: true
+	FinishedTime int64 `json:"finishedTime,omitempty"`
+
+	// input
+	Input interface{} `json:"input,omitempty"`
+
+	// name
+	// Read Only: true
+	Name strfmt.UUID `json:"name,omitempty"`
+
+	// output
+	// Read Only: true
+	Output interface{} `json:"output,omitempty"`
+
+	// reason
+	Reason []string `json:"reason"`
+
+	// status
+	Status Status `json:"status,omitempty"`
+
+	// steps executed so far, in order
+	// Read Only: true
+	Steps []*WorkflowStep `json:"steps"`
+
+	// tags
+	Tags []*Tag `json:"tags"`
+
+	// workflow name
+	// Read Only: true
+	WorkflowName string `json:"workflowName,omitempty"`
+}
+
+// Validate validates this workflow run
+func (m *WorkflowRun) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateReason(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSteps(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTags(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *WorkflowRun) validateName(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Name) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("name", "body", "uuid", m.Name.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *WorkflowRun) validateReason(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Reason) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *WorkflowRun) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	if err := m.Status.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("status")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *WorkflowRun) validateSteps(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Steps) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Steps); i++ {
+
+		if swag.IsZero(m.Steps[i]) { // not required
+			continue
+		}
+
+		if m.Steps[i] != nil {
+
+			if err := m.Steps[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("steps" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+func (m *WorkflowRun) validateTags(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Tags) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Tags); i++ {
+
+		if swag.IsZero(m.Tags[i]) { // not required
+			continue
+		}
+
+		if m.Tags[i] != nil {
+
+			if err := m.Tags[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("tags" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *WorkflowRun) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *WorkflowRun) UnmarshalBinary(b []byte) error {
+	var res WorkflowRun
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
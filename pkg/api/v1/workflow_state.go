@@ -0,0 +1,277 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	"encoding/json"
+	"strconv"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NO TESTS
+
+// WorkflowState a state of a workflow
+// swagger:model WorkflowState
+type WorkflowState struct {
+
+	// branches executed concurrently by a parallel state
+	Branches []*WorkflowBranch `json:"branches"`
+
+	// state to go to when the state fails after all retries, the error is passed as input
+	Catch string `json:"catch,omitempty"`
+
+	// rules evaluated in order by a choice state, the first matching rule selects the next state
+	Choices []*WorkflowChoice `json:"choices"`
+
+	// next state of a choice state when no rule matches
+	Default string `json:"default,omitempty"`
+
+	// the workflow ends after this state
+	End bool `json:"end,omitempty"`
+
+	// error message of a fail state
+	Error string `json:"error,omitempty"`
+
+	// function executed by a task state
+	// Pattern: ^[\w\d\-]+$
+	Function string `json:"function,omitempty"`
+
+	// name
+	// Required: true
+	// Pattern: ^[\w\d\-]+$
+	Name *string `json:"name"`
+
+	// name of the next state
+	Next string `json:"next,omitempty"`
+
+	// retry
+	Retry *WorkflowRetry `json:"retry,omitempty"`
+
+	// delay of a wait state in seconds
+	Seconds int64 `json:"seconds,omitempty"`
+
+	// type
+	// Required: true
+	Type *string `json:"type"`
+}
+
+// Validate validates this workflow state
+func (m *WorkflowState) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateBranches(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateChoices(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateFunction(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateRetry(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateType(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *WorkflowState) validateBranches(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Branches) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Branches); i++ {
+
+		if swag.IsZero(m.Branches[i]) { // not required
+			continue
+		}
+
+		if m.Branches[i] != nil {
+
+			if err := m.Branches[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("branches" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+func (m *WorkflowState) validateChoices(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Choices) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Choices); i++ {
+
+		if swag.IsZero(m.Choices[i]) { // not required
+			continue
+		}
+
+		if m.Choices[i] != nil {
+
+			if err := m.Choices[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("choices" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+}
+
+func (m *WorkflowState) validateFunction(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Function) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("function", "body", string(m.Function), `^[\w\d\-]+$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *WorkflowState) validateName(formats strfmt.Registry) error {
+
+	if err := validate.Required("name", "body", m.Name); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("name", "body", string(*m.Name), `^[\w\d\-]+$`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *WorkflowState) validateRetry(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Retry) { // not required
+		return nil
+	}
+
+	if m.Retry != nil {
+
+		if err := m.Retry.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("retry")
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+var workflowStateTypePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["task","parallel","choice","wait","succeed","fail"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		workflowStateTypePropEnum = append(workflowStateTypePropEnum, v)
+	}
+}
+
+const (
+
+	// WorkflowStateTypeTask captures enum value "task"
+	WorkflowStateTypeTask string = "task"
+
+	// WorkflowStateTypeParallel captures enum value "parallel"
+	WorkflowStateTypeParallel string = "parallel"
+
+	// WorkflowStateTypeChoice captures enum value "choice"
+	WorkflowStateTypeChoice string = "choice"
+
+	// WorkflowStateTypeWait captures enum value "wait"
+	WorkflowStateTypeWait string = "wait"
+
+	// WorkflowStateTypeSucceed captures enum value "succeed"
+	WorkflowStateTypeSucceed string = "succeed"
+
+	// WorkflowStateTypeFail captures enum value "fail"
+	WorkflowStateTypeFail string = "fail"
+)
+
+// prop value enum
+func (m *WorkflowState) validateTypeEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, workflowStateTypePropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *WorkflowState) validateType(formats strfmt.Registry) error {
+
+	if err := validate.Required("type", "body", m.Type); err != nil {
+		return err
+	}
+
+	// value enum
+	if err := m.validateTypeEnum("type", "body", *m.Type); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *WorkflowState) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *WorkflowState) UnmarshalBinary(b []byte) error {
+	var res WorkflowState
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
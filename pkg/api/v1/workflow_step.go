@@ -0,0 +1,102 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package v1
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+)
+
+// NO TESTS
+
+// WorkflowStep an execution of a workflow state
+// swagger:model WorkflowStep
+type WorkflowStep struct {
+
+	// attempt, starting at 1
+	Attempt int64 `json:"attempt,omitempty"`
+
+	// path of the parallel branch the state belongs to, e.g. fanout.0
+	Branch string `json:"branch,omitempty"`
+
+	// error
+	Error string `json:"error,omitempty"`
+
+	// finished time
+	FinishedTime int64 `json:"finishedTime,omitempty"`
+
+	// name of the function run of a task state
+	FunctionRun string `json:"functionRun,omitempty"`
+
+	// input
+	Input interface{} `json:"input,omitempty"`
+
+	// output
+	Output interface{} `json:"output,omitempty"`
+
+	// started time
+	StartedTime int64 `json:"startedTime,omitempty"`
+
+	// state name
+	State string `json:"state,omitempty"`
+
+	// status
+	Status Status `json:"status,omitempty"`
+
+	// state type
+	Type string `json:"type,omitempty"`
+}
+
+// Validate validates this workflow step
+func (m *WorkflowStep) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *WorkflowStep) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	if err := m.Status.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("status")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *WorkflowStep) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *WorkflowStep) UnmarshalBinary(b []byte) error {
+	var res WorkflowStep
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
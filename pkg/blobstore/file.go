@@ -0,0 +1,83 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package blobstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store keeping blobs as files in a directory
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "error creating blob store directory %s", dir)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid blob key %s", key)
+	}
+	return path, nil
+}
+
+func (s *fileStore) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for blob %s", key)
+	}
+	// write to a temporary file first, so that readers never see a partial blob
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".blob")
+	if err != nil {
+		return errors.Wrapf(err, "error creating file for blob %s", key)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error writing blob %s", key)
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "error writing blob %s", key)
+}
+
+func (s *fileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, errors.Wrapf(err, "error reading blob %s", key)
+}
+
+func (s *fileStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error deleting blob %s", key)
+	}
+	return nil
+}
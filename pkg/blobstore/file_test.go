@@ -0,0 +1,50 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package blobstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "org/runs/missing/input")
+	assert.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, s.Put(ctx, "org/runs/hello world/input", []byte(`{"name":"Jon"}`)))
+	data, err := s.Get(ctx, "org/runs/hello world/input")
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Jon"}`, string(data))
+
+	require.NoError(t, s.Put(ctx, "org/runs/hello world/input", []byte(`{"name":"Jane"}`)))
+	data, err = s.Get(ctx, "org/runs/hello world/input")
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Jane"}`, string(data))
+
+	require.NoError(t, s.Delete(ctx, "org/runs/hello world/input"))
+	_, err = s.Get(ctx, "org/runs/hello world/input")
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, s.Delete(ctx, "org/runs/hello world/input"))
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := NewFileStore(dir)
+	require.NoError(t, err)
+	testStore(t, s)
+
+	assert.Error(t, s.Put(context.Background(), "../outside", []byte("x")))
+}
@@ -0,0 +1,177 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// S3Config configures a store using an S3-compatible object storage API
+type S3Config struct {
+	// Endpoint is the URL of the API, e.g. https://s3.us-west-2.amazonaws.com or http://minio:9000
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// Client is the HTTP client used for requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+type s3Store struct {
+	S3Config
+}
+
+// NewS3Store creates a store keeping blobs as objects of a bucket, using path-style requests signed with AWS
+// signature version 4
+func NewS3Store(config S3Config) Store {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &s3Store{S3Config: config}
+}
+
+func (s *s3Store) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	path := "/" + s.Bucket + "/" + uriEncodePath(key)
+	req, err := http.NewRequest(method, s.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating request for blob %s", key)
+	}
+	req = req.WithContext(ctx)
+	s.sign(req, path, body, time.Now().UTC())
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error sending request for blob %s", key)
+	}
+	return resp, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "error writing blob %s", key)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, err := ioutil.ReadAll(resp.Body)
+		return data, errors.Wrapf(err, "error reading blob %s", key)
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, responseError(resp, "error reading blob %s", key)
+	}
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return responseError(resp, "error deleting blob %s", key)
+	}
+}
+
+// sign adds the AWS signature version 4 authorization header to the request
+func (s *s3Store) sign(req *http.Request, path string, body []byte, now time.Time) {
+	date := now.Format("20060102")
+	timestamp := now.Format("20060102T150405Z")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", timestamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		"",
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + timestamp,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		timestamp,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+func responseError(resp *http.Response, format string, args ...interface{}) error {
+	body, _ := ioutil.ReadAll(resp.Body)
+	return errors.Wrapf(errors.Errorf("%s: %s", resp.Status, body), format, args...)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncodePath encodes a path as required by AWS signature version 4, keeping slashes
+func uriEncodePath(s string) string {
+	var buf bytes.Buffer
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			buf.WriteByte(b)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", b)
+		}
+	}
+	return buf.String()
+}
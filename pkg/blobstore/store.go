@@ -0,0 +1,42 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package blobstore
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// NO TESTS
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs of data under keys, keys are slash separated paths
+type Store interface {
+	// Put stores the data under the key, replacing any existing blob
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the data stored under the key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the blob stored under the key, deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates a store of a kind: "file" keeps blobs in dir, "s3" uses the S3-compatible API configured by s3, and
+// "none" or an empty kind returns a nil store
+func New(kind, dir string, s3 S3Config) (Store, error) {
+	switch kind {
+	case "", "none":
+		return nil, nil
+	case "file":
+		return NewFileStore(dir)
+	case "s3":
+		return NewS3Store(s3), nil
+	default:
+		return nil, errors.Errorf("unknown blob store %s", kind)
+	}
+}
@@ -0,0 +1,163 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/client/batch"
+)
+
+// CreateBatch starts the runs of a function over the inputs of a batch
+func (c *DefaultFunctionsClient) CreateBatch(ctx context.Context, organizationID string, b *v1.Batch) (*v1.Batch, error) {
+	params := batch.AddBatchParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		Body:         b,
+	}
+	response, err := c.client.Batch.AddBatch(&params, c.auth)
+	if err != nil {
+		return nil, createBatchSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func createBatchSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *batch.AddBatchBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *batch.AddBatchUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *batch.AddBatchForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *batch.AddBatchNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *batch.AddBatchDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetBatch gets a batch and its progress
+func (c *DefaultFunctionsClient) GetBatch(ctx context.Context, organizationID string, batchName string) (*v1.Batch, error) {
+	params := batch.GetBatchParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		BatchName:    strfmt.UUID(batchName),
+	}
+	response, err := c.client.Batch.GetBatch(&params, c.auth)
+	if err != nil {
+		return nil, getBatchSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func getBatchSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *batch.GetBatchBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *batch.GetBatchUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *batch.GetBatchForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *batch.GetBatchNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *batch.GetBatchDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetBatchItems gets the result of each input of a batch, only those in error if failed is true
+func (c *DefaultFunctionsClient) GetBatchItems(ctx context.Context, organizationID string, batchName string, failed bool) ([]v1.BatchItem, error) {
+	params := batch.GetBatchItemsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		BatchName:    strfmt.UUID(batchName),
+		Failed:       &failed,
+	}
+	response, err := c.client.Batch.GetBatchItems(&params, c.auth)
+	if err != nil {
+		return nil, getBatchItemsSwaggerError(err)
+	}
+	items := []v1.BatchItem{}
+	for _, item := range response.Payload {
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+func getBatchItemsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *batch.GetBatchItemsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *batch.GetBatchItemsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *batch.GetBatchItemsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *batch.GetBatchItemsNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *batch.GetBatchItemsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ListBatches lists the batches, filtered by function name if set
+func (c *DefaultFunctionsClient) ListBatches(ctx context.Context, organizationID string, functionName *string) ([]v1.Batch, error) {
+	params := batch.GetBatchesParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		FunctionName: functionName,
+	}
+	response, err := c.client.Batch.GetBatches(&params, c.auth)
+	if err != nil {
+		return nil, listBatchesSwaggerError(err)
+	}
+	batches := []v1.Batch{}
+	for _, b := range response.Payload {
+		batches = append(batches, *b)
+	}
+	return batches, nil
+}
+
+func listBatchesSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *batch.GetBatchesBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *batch.GetBatchesUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *batch.GetBatchesForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *batch.GetBatchesDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
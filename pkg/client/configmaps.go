@@ -0,0 +1,191 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/client/configmap"
+)
+
+// CreateConfigMap creates and adds a new config map
+func (c *DefaultFunctionsClient) CreateConfigMap(ctx context.Context, organizationID string, cm *v1.ConfigMap) (*v1.ConfigMap, error) {
+	params := configmap.AddConfigMapParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		Body:         cm,
+	}
+	response, err := c.client.ConfigMap.AddConfigMap(&params, c.auth)
+	if err != nil {
+		return nil, createConfigMapSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func createConfigMapSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *configmap.AddConfigMapBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *configmap.AddConfigMapUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *configmap.AddConfigMapForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *configmap.AddConfigMapConflict:
+		return NewErrorAlreadyExists(v.Payload)
+	case *configmap.AddConfigMapDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// DeleteConfigMap deletes a config map
+func (c *DefaultFunctionsClient) DeleteConfigMap(ctx context.Context, organizationID string, configMapName string) (*v1.ConfigMap, error) {
+	params := configmap.DeleteConfigMapParams{
+		Context:       ctx,
+		XDispatchOrg:  c.getOrgID(organizationID),
+		ConfigMapName: configMapName,
+	}
+	response, err := c.client.ConfigMap.DeleteConfigMap(&params, c.auth)
+	if err != nil {
+		return nil, deleteConfigMapSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func deleteConfigMapSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *configmap.DeleteConfigMapBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *configmap.DeleteConfigMapUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *configmap.DeleteConfigMapForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *configmap.DeleteConfigMapNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *configmap.DeleteConfigMapDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetConfigMap gets a config map by name
+func (c *DefaultFunctionsClient) GetConfigMap(ctx context.Context, organizationID string, configMapName string) (*v1.ConfigMap, error) {
+	params := configmap.GetConfigMapParams{
+		Context:       ctx,
+		XDispatchOrg:  c.getOrgID(organizationID),
+		ConfigMapName: configMapName,
+	}
+	response, err := c.client.ConfigMap.GetConfigMap(&params, c.auth)
+	if err != nil {
+		return nil, getConfigMapSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func getConfigMapSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *configmap.GetConfigMapBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *configmap.GetConfigMapUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *configmap.GetConfigMapForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *configmap.GetConfigMapNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *configmap.GetConfigMapDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ListConfigMaps lists all config maps
+func (c *DefaultFunctionsClient) ListConfigMaps(ctx context.Context, organizationID string) ([]v1.ConfigMap, error) {
+	params := configmap.GetConfigMapsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.ConfigMap.GetConfigMaps(&params, c.auth)
+	if err != nil {
+		return nil, listConfigMapsSwaggerError(err)
+	}
+	configMaps := []v1.ConfigMap{}
+	for _, cm := range response.Payload {
+		configMaps = append(configMaps, *cm)
+	}
+	return configMaps, nil
+}
+
+func listConfigMapsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *configmap.GetConfigMapsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *configmap.GetConfigMapsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *configmap.GetConfigMapsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *configmap.GetConfigMapsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// UpdateConfigMap updates the data of a config map, the functions using it are redeployed
+func (c *DefaultFunctionsClient) UpdateConfigMap(ctx context.Context, organizationID string, cm *v1.ConfigMap) (*v1.ConfigMap, error) {
+	params := configmap.UpdateConfigMapParams{
+		Context:       ctx,
+		XDispatchOrg:  c.getOrgID(organizationID),
+		ConfigMapName: *cm.Name,
+		Body:          cm,
+	}
+	response, err := c.client.ConfigMap.UpdateConfigMap(&params, c.auth)
+	if err != nil {
+		return nil, updateConfigMapSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func updateConfigMapSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *configmap.UpdateConfigMapBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *configmap.UpdateConfigMapUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *configmap.UpdateConfigMapForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *configmap.UpdateConfigMapNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *configmap.UpdateConfigMapDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
@@ -7,6 +7,7 @@ package client
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
 )
@@ -131,6 +132,33 @@ func NewErrorFunctionError(apiError *v1.Error) *ErrorFunctionError {
 	}
 }
 
+// ErrorTooManyRequests represents error of a request rejected by a quota
+type ErrorTooManyRequests struct {
+	baseError
+	// RetryAfter is how long to wait before retrying the request
+	RetryAfter time.Duration
+}
+
+// NewErrorTooManyRequests creates new instance of ErrorTooManyRequests based on Error Model
+func NewErrorTooManyRequests(apiError *v1.Error, retryAfter time.Duration) *ErrorTooManyRequests {
+	return &ErrorTooManyRequests{
+		baseError:  baseErrFromModel(apiError),
+		RetryAfter: retryAfter,
+	}
+}
+
+// ErrorConflict represents error of a request conflicting with the state of the resource
+type ErrorConflict struct {
+	baseError
+}
+
+// NewErrorConflict creates new instance of ErrorConflict based on Error Model
+func NewErrorConflict(apiError *v1.Error) *ErrorConflict {
+	return &ErrorConflict{
+		baseError: baseErrFromModel(apiError),
+	}
+}
+
 func baseErrFromModel(apiError *v1.Error) baseError {
 	message := ""
 	if apiError.Message != nil {
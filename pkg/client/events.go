@@ -8,9 +8,11 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	"github.com/vmware/dispatch/pkg/api/v1"
 	swaggerclient "github.com/vmware/dispatch/pkg/event-manager/gen/client"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/client/drivers"
@@ -23,6 +25,10 @@ type EventsClient interface {
 	// Emit an event
 	EmitEvent(ctx context.Context, organizationID string, emission *v1.Emission) (*v1.Emission, error)
 
+	// Event log
+	ListEvents(ctx context.Context, organizationID string, opts EventOpts) ([]v1.EventRecord, error)
+	ReplayEvents(ctx context.Context, organizationID string, replay *v1.EventReplay) (*v1.EventReplay, error)
+
 	// Subscriptions
 	CreateSubscription(ctx context.Context, organizationID string, subscription *v1.Subscription) (*v1.Subscription, error)
 	DeleteSubscription(ctx context.Context, organizationID string, subscriptionName string) (*v1.Subscription, error)
@@ -30,6 +36,13 @@ type EventsClient interface {
 	ListSubscriptions(ctx context.Context, organizationID string) ([]v1.Subscription, error)
 	UpdateSubscription(ctx context.Context, organizationID string, subscription *v1.Subscription) (*v1.Subscription, error)
 
+	// Schedules
+	CreateSchedule(ctx context.Context, organizationID string, schedule *v1.Schedule) (*v1.Schedule, error)
+	DeleteSchedule(ctx context.Context, organizationID string, scheduleName string) (*v1.Schedule, error)
+	GetSchedule(ctx context.Context, organizationID string, scheduleName string) (*v1.Schedule, error)
+	ListSchedules(ctx context.Context, organizationID string) ([]v1.Schedule, error)
+	UpdateSchedule(ctx context.Context, organizationID string, schedule *v1.Schedule) (*v1.Schedule, error)
+
 	// Event Drivers
 	CreateEventDriver(ctx context.Context, organizationID string, eventDriver *v1.EventDriver) (*v1.EventDriver, error)
 	DeleteEventDriver(ctx context.Context, organizationID string, eventDriverName string) (*v1.EventDriver, error)
@@ -45,6 +58,14 @@ type EventsClient interface {
 	UpdateEventDriverType(ctx context.Context, organizationID string, eventDriverType *v1.EventDriverType) (*v1.EventDriverType, error)
 }
 
+// EventOpts are options for listing recorded events, zero values do not filter
+type EventOpts struct {
+	EventType string
+	Source    string
+	Since     time.Time
+	Until     time.Time
+}
+
 // DefaultEventsClient defines the default client for events API
 type DefaultEventsClient struct {
 	baseClient
@@ -98,6 +119,89 @@ func emitEventSwaggerError(err error) error {
 	}
 }
 
+// ListEvents lists the recorded events
+func (c *DefaultEventsClient) ListEvents(ctx context.Context, organizationID string, opts EventOpts) ([]v1.EventRecord, error) {
+	params := events.GetEventsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	if opts.EventType != "" {
+		params.Type = swag.String(opts.EventType)
+	}
+	if opts.Source != "" {
+		params.Source = swag.String(opts.Source)
+	}
+	if !opts.Since.IsZero() {
+		params.Since = swag.Int64(opts.Since.Unix())
+	}
+	if !opts.Until.IsZero() {
+		params.Until = swag.Int64(opts.Until.Unix())
+	}
+	response, err := c.client.Events.GetEvents(&params, c.auth)
+	if err != nil {
+		return nil, listEventsSwaggerError(err)
+	}
+	records := []v1.EventRecord{}
+	for _, r := range response.Payload {
+		records = append(records, *r)
+	}
+	return records, nil
+}
+
+func listEventsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *events.GetEventsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *events.GetEventsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *events.GetEventsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *events.GetEventsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ReplayEvents delivers recorded events to a subscription again
+func (c *DefaultEventsClient) ReplayEvents(ctx context.Context, organizationID string, replay *v1.EventReplay) (*v1.EventReplay, error) {
+	params := events.ReplayEventsParams{
+		Body:         replay,
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Events.ReplayEvents(&params, c.auth)
+	if err != nil {
+		return nil, replayEventsSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func replayEventsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *events.ReplayEventsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *events.ReplayEventsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *events.ReplayEventsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *events.ReplayEventsNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *events.ReplayEventsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
 // CreateSubscription creates and adds a new subscription
 func (c *DefaultEventsClient) CreateSubscription(ctx context.Context, organizationID string, subscription *v1.Subscription) (*v1.Subscription, error) {
 	params := subscriptions.AddSubscriptionParams{
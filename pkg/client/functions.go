@@ -6,7 +6,9 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -26,20 +28,86 @@ type FunctionsClient interface {
 	RunFunction(ctx context.Context, organizationID string, run *v1.Run) (*v1.Run, error)
 	GetFunctionRun(ctx context.Context, organizationID string, opts FunctionOpts) (*v1.Run, error)
 	ListRuns(ctx context.Context, organizationID string, opts FunctionOpts) ([]v1.Run, error)
+	GetRunStats(ctx context.Context, organizationID string, opts FunctionOpts) ([]v1.RunStats, error)
+	ReplayRun(ctx context.Context, organizationID string, opts FunctionOpts, blocking bool) (*v1.Run, error)
+	CancelRun(ctx context.Context, organizationID string, opts FunctionOpts) (*v1.Run, error)
+	GetRunLogs(ctx context.Context, organizationID string, opts FunctionOpts, handler func(v1.LogLine)) error
+	GetFunctionLogs(ctx context.Context, organizationID string, functionName string, follow bool, handler func(v1.LogLine)) error
 
 	// Function store
 	CreateFunction(ctx context.Context, organizationID string, function *v1.Function) (*v1.Function, error)
 	DeleteFunction(ctx context.Context, organizationID string, functionName string) (*v1.Function, error)
 	GetFunction(ctx context.Context, organizationID string, functionName string) (*v1.Function, error)
+	ListFunctionBuilds(ctx context.Context, organizationID string, functionName string) ([]v1.FunctionBuild, error)
 	ListFunctions(ctx context.Context, organizationID string) ([]v1.Function, error)
 	UpdateFunction(ctx context.Context, organizationID string, function *v1.Function) (*v1.Function, error)
+
+	// Workflows
+	CreateWorkflow(ctx context.Context, organizationID string, workflow *v1.Workflow) (*v1.Workflow, error)
+	DeleteWorkflow(ctx context.Context, organizationID string, workflowName string) (*v1.Workflow, error)
+	GetWorkflow(ctx context.Context, organizationID string, workflowName string) (*v1.Workflow, error)
+	ListWorkflows(ctx context.Context, organizationID string) ([]v1.Workflow, error)
+	RunWorkflow(ctx context.Context, organizationID string, workflowName string, run *v1.WorkflowRun) (*v1.WorkflowRun, error)
+	GetWorkflowRun(ctx context.Context, organizationID string, runName string) (*v1.WorkflowRun, error)
+	ListWorkflowRuns(ctx context.Context, organizationID string, workflowName *string) ([]v1.WorkflowRun, error)
+
+	// Batches
+	CreateBatch(ctx context.Context, organizationID string, batch *v1.Batch) (*v1.Batch, error)
+	GetBatch(ctx context.Context, organizationID string, batchName string) (*v1.Batch, error)
+	GetBatchItems(ctx context.Context, organizationID string, batchName string, failed bool) ([]v1.BatchItem, error)
+	ListBatches(ctx context.Context, organizationID string, functionName *string) ([]v1.Batch, error)
+
+	// Config maps
+	CreateConfigMap(ctx context.Context, organizationID string, configMap *v1.ConfigMap) (*v1.ConfigMap, error)
+	DeleteConfigMap(ctx context.Context, organizationID string, configMapName string) (*v1.ConfigMap, error)
+	GetConfigMap(ctx context.Context, organizationID string, configMapName string) (*v1.ConfigMap, error)
+	ListConfigMaps(ctx context.Context, organizationID string) ([]v1.ConfigMap, error)
+	UpdateConfigMap(ctx context.Context, organizationID string, configMap *v1.ConfigMap) (*v1.ConfigMap, error)
+
+	// Quotas
+	CreateQuota(ctx context.Context, organizationID string, quota *v1.Quota) (*v1.Quota, error)
+	DeleteQuota(ctx context.Context, organizationID string, quotaName string) (*v1.Quota, error)
+	GetQuota(ctx context.Context, organizationID string, quotaName string) (*v1.Quota, error)
+	ListQuotas(ctx context.Context, organizationID string) ([]v1.Quota, error)
+	UpdateQuota(ctx context.Context, organizationID string, quota *v1.Quota) (*v1.Quota, error)
 }
 
+const eventStreamMime = "text/event-stream"
+
 // FunctionOpts are options for retrieving function runs
 type FunctionOpts struct {
 	FunctionName *string
 	RunName      *string
 	Since        time.Time
+	// Until, Status, ErrorType, MinDuration and MaxDuration are only used when listing runs, zero values do not filter
+	Until       time.Time
+	Status      string
+	ErrorType   string
+	MinDuration time.Duration
+	MaxDuration time.Duration
+}
+
+func unixOrNil(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	u := t.Unix()
+	return &u
+}
+
+func millisecondsOrNil(d time.Duration) *int64 {
+	if d <= 0 {
+		return nil
+	}
+	ms := int64(d / time.Millisecond)
+	return &ms
+}
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
 
 // DefaultFunctionsClient defines the default functions client
@@ -53,6 +121,7 @@ type DefaultFunctionsClient struct {
 // NewFunctionsClient is used to create a new functions client
 func NewFunctionsClient(host string, auth runtime.ClientAuthInfoWriter, organizationID string) *DefaultFunctionsClient {
 	transport := DefaultHTTPClient(host, swaggerclient.DefaultBasePath)
+	transport.Consumers[eventStreamMime] = runtime.ByteStreamConsumer()
 	return &DefaultFunctionsClient{
 		baseClient: baseClient{
 			organizationID: organizationID,
@@ -100,6 +169,8 @@ func runSwaggerError(err error) error {
 		return NewErrorNotFound(v.Payload)
 	case *runner.RunFunctionUnprocessableEntity:
 		return NewErrorInvalidInput(v.Payload)
+	case *runner.RunFunctionTooManyRequests:
+		return NewErrorTooManyRequests(v.Payload, time.Duration(v.RetryAfter)*time.Second)
 	case *runner.RunFunctionBadGateway:
 		return NewErrorFunctionError(v.Payload)
 	case *runner.RunFunctionDefault:
@@ -156,6 +227,11 @@ func (c *DefaultFunctionsClient) ListRuns(ctx context.Context, organizationID st
 		XDispatchOrg: c.getOrgID(organizationID),
 		FunctionName: opts.FunctionName,
 		Since:        &s,
+		Until:        unixOrNil(opts.Until),
+		Status:       stringOrNil(opts.Status),
+		ErrorType:    stringOrNil(opts.ErrorType),
+		MinDuration:  millisecondsOrNil(opts.MinDuration),
+		MaxDuration:  millisecondsOrNil(opts.MaxDuration),
 	}
 	response, err := c.client.Runner.GetRuns(&params, c.auth)
 	if err != nil {
@@ -168,6 +244,46 @@ func (c *DefaultFunctionsClient) ListRuns(ctx context.Context, organizationID st
 	return runs, nil
 }
 
+// GetRunStats aggregates the runs of each function, filtered by the function name and time window of opts
+func (c *DefaultFunctionsClient) GetRunStats(ctx context.Context, organizationID string, opts FunctionOpts) ([]v1.RunStats, error) {
+	s := opts.Since.Unix()
+	params := runner.GetRunStatsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		FunctionName: opts.FunctionName,
+		Since:        &s,
+		Until:        unixOrNil(opts.Until),
+	}
+	response, err := c.client.Runner.GetRunStats(&params, c.auth)
+	if err != nil {
+		return nil, getRunStatsSwaggerError(err)
+	}
+	stats := []v1.RunStats{}
+	for _, s := range response.Payload {
+		stats = append(stats, *s)
+	}
+	return stats, nil
+}
+
+func getRunStatsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *runner.GetRunStatsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *runner.GetRunStatsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *runner.GetRunStatsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *runner.GetRunStatsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
 func listRunsSwaggerError(err error) error {
 	if err == nil {
 		return nil
@@ -187,6 +303,192 @@ func listRunsSwaggerError(err error) error {
 	}
 }
 
+// ReplayRun runs again the run named by opts, against the function named by opts if set, otherwise the function of
+// the replayed run
+func (c *DefaultFunctionsClient) ReplayRun(ctx context.Context, organizationID string, opts FunctionOpts, blocking bool) (*v1.Run, error) {
+	params := runner.ReplayRunParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		RunName:      strfmt.UUID(*opts.RunName),
+		FunctionName: opts.FunctionName,
+		Blocking:     &blocking,
+	}
+	ok, accepted, err := c.client.Runner.ReplayRun(&params, c.auth)
+	if err != nil {
+		return nil, replayRunSwaggerError(err)
+	}
+	if ok != nil {
+		return ok.Payload, nil
+	}
+	if accepted != nil {
+		return accepted.Payload, nil
+	}
+	return nil, errors.New("swagger error, returned payload not supported")
+}
+
+func replayRunSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *runner.ReplayRunBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *runner.ReplayRunUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *runner.ReplayRunForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *runner.ReplayRunNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *runner.ReplayRunTooManyRequests:
+		return NewErrorTooManyRequests(v.Payload, time.Duration(v.RetryAfter)*time.Second)
+	case *runner.ReplayRunDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// CancelRun cancels a queued or running function run
+func (c *DefaultFunctionsClient) CancelRun(ctx context.Context, organizationID string, opts FunctionOpts) (*v1.Run, error) {
+	params := runner.CancelRunParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		RunName:      strfmt.UUID(*opts.RunName),
+		FunctionName: opts.FunctionName,
+	}
+	response, err := c.client.Runner.CancelRun(&params, c.auth)
+	if err != nil {
+		return nil, cancelRunSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func cancelRunSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *runner.CancelRunBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *runner.CancelRunUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *runner.CancelRunForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *runner.CancelRunNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *runner.CancelRunConflict:
+		return NewErrorConflict(v.Payload)
+	case *runner.CancelRunDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetRunLogs streams the logs of a function run to handler, a running function has no logs until it returns
+func (c *DefaultFunctionsClient) GetRunLogs(ctx context.Context, organizationID string, opts FunctionOpts, handler func(v1.LogLine)) error {
+	params := runner.GetRunLogsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		FunctionName: opts.FunctionName,
+		RunName:      strfmt.UUID(*opts.RunName),
+	}
+	decoder := &logStreamDecoder{handler: handler}
+	if _, err := c.client.Runner.GetRunLogs(&params, c.auth, decoder); err != nil {
+		return getRunLogsSwaggerError(err)
+	}
+	return decoder.err
+}
+
+func getRunLogsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *runner.GetRunLogsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *runner.GetRunLogsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *runner.GetRunLogsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *runner.GetRunLogsNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *runner.GetRunLogsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetFunctionLogs streams the logs of all runs of a function to handler, if follow is true it blocks until ctx is done
+func (c *DefaultFunctionsClient) GetFunctionLogs(ctx context.Context, organizationID string, functionName string, follow bool, handler func(v1.LogLine)) error {
+	params := runner.GetFunctionLogsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		FunctionName: functionName,
+		Follow:       &follow,
+	}
+	decoder := &logStreamDecoder{handler: handler}
+	if _, err := c.client.Runner.GetFunctionLogs(&params, c.auth, decoder); err != nil {
+		return getFunctionLogsSwaggerError(err)
+	}
+	return decoder.err
+}
+
+func getFunctionLogsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *runner.GetFunctionLogsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *runner.GetFunctionLogsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *runner.GetFunctionLogsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *runner.GetFunctionLogsNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *runner.GetFunctionLogsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// logStreamDecoder decodes the server-sent events of a log stream as they are written
+type logStreamDecoder struct {
+	handler func(v1.LogLine)
+	buf     []byte
+	err     error
+}
+
+func (d *logStreamDecoder) Write(p []byte) (int, error) {
+	d.buf = append(d.buf, p...)
+	for {
+		i := bytes.IndexByte(d.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := d.buf[:i]
+		d.buf = d.buf[i+1:]
+		// only the data field is needed, the event name is repeated in the log line
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		var logLine v1.LogLine
+		if err := json.Unmarshal(bytes.TrimSpace(line[len("data:"):]), &logLine); err != nil {
+			d.err = errors.Wrap(err, "error decoding log line")
+			continue
+		}
+		d.handler(logLine)
+	}
+	return len(p), nil
+}
+
 // CreateFunction creates and adds a new function
 func (c *DefaultFunctionsClient) CreateFunction(ctx context.Context, organizationID string, function *v1.Function) (*v1.Function, error) {
 	params := store.AddFunctionParams{
@@ -292,6 +594,45 @@ func getFunctionSwaggerError(err error) error {
 	}
 }
 
+// ListFunctionBuilds lists the image builds of a function, the latest first
+func (c *DefaultFunctionsClient) ListFunctionBuilds(ctx context.Context, organizationID string, functionName string) ([]v1.FunctionBuild, error) {
+	params := store.GetFunctionBuildsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		FunctionName: functionName,
+	}
+	response, err := c.client.Store.GetFunctionBuilds(&params, c.auth)
+	if err != nil {
+		return nil, listFunctionBuildsSwaggerError(err)
+	}
+	builds := []v1.FunctionBuild{}
+	for _, b := range response.Payload {
+		builds = append(builds, *b)
+	}
+	return builds, nil
+}
+
+func listFunctionBuildsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *store.GetFunctionBuildsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *store.GetFunctionBuildsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *store.GetFunctionBuildsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *store.GetFunctionBuildsNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *store.GetFunctionBuildsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
 // ListFunctions lists all functions
 func (c *DefaultFunctionsClient) ListFunctions(ctx context.Context, organizationID string) ([]v1.Function, error) {
 	params := store.GetFunctionsParams{
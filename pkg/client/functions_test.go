@@ -7,6 +7,8 @@ package client_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -36,3 +38,26 @@ func TestCreateFunction(t *testing.T) {
 	assert.Equal(t, functionResponse, functionBody)
 
 }
+
+func TestGetRunLogs(t *testing.T) {
+	runName := "f98d0a7f-0c1d-4020-a488-cabc501b08e0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/runs/"+runName+"/logs", r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: stdout\ndata: {\"runName\":\"%s\",\"stream\":\"stdout\",\"text\":\"first\"}\n\n", runName)
+		fmt.Fprintf(w, "event: stderr\ndata: {\"runName\":\"%s\",\"stream\":\"stderr\",\"text\":\"second\"}\n\n", runName)
+	}))
+	defer server.Close()
+
+	fclient := client.NewFunctionsClient(server.URL, nil, testOrgID)
+
+	var lines []v1.LogLine
+	err := fclient.GetRunLogs(context.Background(), testOrgID, client.FunctionOpts{RunName: &runName}, func(line v1.LogLine) {
+		lines = append(lines, line)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.LogLine{
+		{RunName: runName, Stream: "stdout", Text: "first"},
+		{RunName: runName, Stream: "stderr", Text: "second"},
+	}, lines)
+}
@@ -12,6 +12,75 @@ type FunctionsClient struct {
 	mock.Mock
 }
 
+// CancelRun provides a mock function with given fields: ctx, organizationID, opts
+func (_m *FunctionsClient) CancelRun(ctx context.Context, organizationID string, opts client.FunctionOpts) (*v1.Run, error) {
+	ret := _m.Called(ctx, organizationID, opts)
+
+	var r0 *v1.Run
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.FunctionOpts) *v1.Run); ok {
+		r0 = rf(ctx, organizationID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Run)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.FunctionOpts) error); ok {
+		r1 = rf(ctx, organizationID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateBatch provides a mock function with given fields: ctx, organizationID, batch
+func (_m *FunctionsClient) CreateBatch(ctx context.Context, organizationID string, batch *v1.Batch) (*v1.Batch, error) {
+	ret := _m.Called(ctx, organizationID, batch)
+
+	var r0 *v1.Batch
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Batch) *v1.Batch); ok {
+		r0 = rf(ctx, organizationID, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.Batch) error); ok {
+		r1 = rf(ctx, organizationID, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateConfigMap provides a mock function with given fields: ctx, organizationID, configMap
+func (_m *FunctionsClient) CreateConfigMap(ctx context.Context, organizationID string, configMap *v1.ConfigMap) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, organizationID, configMap)
+
+	var r0 *v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.ConfigMap) *v1.ConfigMap); ok {
+		r0 = rf(ctx, organizationID, configMap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.ConfigMap) error); ok {
+		r1 = rf(ctx, organizationID, configMap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateFunction provides a mock function with given fields: ctx, organizationID, function
 func (_m *FunctionsClient) CreateFunction(ctx context.Context, organizationID string, function *v1.Function) (*v1.Function, error) {
 	ret := _m.Called(ctx, organizationID, function)
@@ -35,6 +104,75 @@ func (_m *FunctionsClient) CreateFunction(ctx context.Context, organizationID st
 	return r0, r1
 }
 
+// CreateQuota provides a mock function with given fields: ctx, organizationID, quota
+func (_m *FunctionsClient) CreateQuota(ctx context.Context, organizationID string, quota *v1.Quota) (*v1.Quota, error) {
+	ret := _m.Called(ctx, organizationID, quota)
+
+	var r0 *v1.Quota
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Quota) *v1.Quota); ok {
+		r0 = rf(ctx, organizationID, quota)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.Quota) error); ok {
+		r1 = rf(ctx, organizationID, quota)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWorkflow provides a mock function with given fields: ctx, organizationID, workflow
+func (_m *FunctionsClient) CreateWorkflow(ctx context.Context, organizationID string, workflow *v1.Workflow) (*v1.Workflow, error) {
+	ret := _m.Called(ctx, organizationID, workflow)
+
+	var r0 *v1.Workflow
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Workflow) *v1.Workflow); ok {
+		r0 = rf(ctx, organizationID, workflow)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Workflow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.Workflow) error); ok {
+		r1 = rf(ctx, organizationID, workflow)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteConfigMap provides a mock function with given fields: ctx, organizationID, configMapName
+func (_m *FunctionsClient) DeleteConfigMap(ctx context.Context, organizationID string, configMapName string) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, organizationID, configMapName)
+
+	var r0 *v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.ConfigMap); ok {
+		r0 = rf(ctx, organizationID, configMapName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, configMapName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteFunction provides a mock function with given fields: ctx, organizationID, functionName
 func (_m *FunctionsClient) DeleteFunction(ctx context.Context, organizationID string, functionName string) (*v1.Function, error) {
 	ret := _m.Called(ctx, organizationID, functionName)
@@ -58,6 +196,121 @@ func (_m *FunctionsClient) DeleteFunction(ctx context.Context, organizationID st
 	return r0, r1
 }
 
+// DeleteQuota provides a mock function with given fields: ctx, organizationID, quotaName
+func (_m *FunctionsClient) DeleteQuota(ctx context.Context, organizationID string, quotaName string) (*v1.Quota, error) {
+	ret := _m.Called(ctx, organizationID, quotaName)
+
+	var r0 *v1.Quota
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Quota); ok {
+		r0 = rf(ctx, organizationID, quotaName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, quotaName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWorkflow provides a mock function with given fields: ctx, organizationID, workflowName
+func (_m *FunctionsClient) DeleteWorkflow(ctx context.Context, organizationID string, workflowName string) (*v1.Workflow, error) {
+	ret := _m.Called(ctx, organizationID, workflowName)
+
+	var r0 *v1.Workflow
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Workflow); ok {
+		r0 = rf(ctx, organizationID, workflowName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Workflow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, workflowName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatch provides a mock function with given fields: ctx, organizationID, batchName
+func (_m *FunctionsClient) GetBatch(ctx context.Context, organizationID string, batchName string) (*v1.Batch, error) {
+	ret := _m.Called(ctx, organizationID, batchName)
+
+	var r0 *v1.Batch
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Batch); ok {
+		r0 = rf(ctx, organizationID, batchName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, batchName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatchItems provides a mock function with given fields: ctx, organizationID, batchName, failed
+func (_m *FunctionsClient) GetBatchItems(ctx context.Context, organizationID string, batchName string, failed bool) ([]v1.BatchItem, error) {
+	ret := _m.Called(ctx, organizationID, batchName, failed)
+
+	var r0 []v1.BatchItem
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) []v1.BatchItem); ok {
+		r0 = rf(ctx, organizationID, batchName, failed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.BatchItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, organizationID, batchName, failed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConfigMap provides a mock function with given fields: ctx, organizationID, configMapName
+func (_m *FunctionsClient) GetConfigMap(ctx context.Context, organizationID string, configMapName string) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, organizationID, configMapName)
+
+	var r0 *v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.ConfigMap); ok {
+		r0 = rf(ctx, organizationID, configMapName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, configMapName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFunction provides a mock function with given fields: ctx, organizationID, functionName
 func (_m *FunctionsClient) GetFunction(ctx context.Context, organizationID string, functionName string) (*v1.Function, error) {
 	ret := _m.Called(ctx, organizationID, functionName)
@@ -81,6 +334,20 @@ func (_m *FunctionsClient) GetFunction(ctx context.Context, organizationID strin
 	return r0, r1
 }
 
+// GetFunctionLogs provides a mock function with given fields: ctx, organizationID, functionName, follow, handler
+func (_m *FunctionsClient) GetFunctionLogs(ctx context.Context, organizationID string, functionName string, follow bool, handler func(v1.LogLine)) error {
+	ret := _m.Called(ctx, organizationID, functionName, follow, handler)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, func(v1.LogLine)) error); ok {
+		r0 = rf(ctx, organizationID, functionName, follow, handler)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetFunctionRun provides a mock function with given fields: ctx, organizationID, opts
 func (_m *FunctionsClient) GetFunctionRun(ctx context.Context, organizationID string, opts client.FunctionOpts) (*v1.Run, error) {
 	ret := _m.Called(ctx, organizationID, opts)
@@ -104,6 +371,181 @@ func (_m *FunctionsClient) GetFunctionRun(ctx context.Context, organizationID st
 	return r0, r1
 }
 
+// GetQuota provides a mock function with given fields: ctx, organizationID, quotaName
+func (_m *FunctionsClient) GetQuota(ctx context.Context, organizationID string, quotaName string) (*v1.Quota, error) {
+	ret := _m.Called(ctx, organizationID, quotaName)
+
+	var r0 *v1.Quota
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Quota); ok {
+		r0 = rf(ctx, organizationID, quotaName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, quotaName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRunLogs provides a mock function with given fields: ctx, organizationID, opts, handler
+func (_m *FunctionsClient) GetRunLogs(ctx context.Context, organizationID string, opts client.FunctionOpts, handler func(v1.LogLine)) error {
+	ret := _m.Called(ctx, organizationID, opts, handler)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.FunctionOpts, func(v1.LogLine)) error); ok {
+		r0 = rf(ctx, organizationID, opts, handler)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRunStats provides a mock function with given fields: ctx, organizationID, opts
+func (_m *FunctionsClient) GetRunStats(ctx context.Context, organizationID string, opts client.FunctionOpts) ([]v1.RunStats, error) {
+	ret := _m.Called(ctx, organizationID, opts)
+
+	var r0 []v1.RunStats
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.FunctionOpts) []v1.RunStats); ok {
+		r0 = rf(ctx, organizationID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.RunStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.FunctionOpts) error); ok {
+		r1 = rf(ctx, organizationID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflow provides a mock function with given fields: ctx, organizationID, workflowName
+func (_m *FunctionsClient) GetWorkflow(ctx context.Context, organizationID string, workflowName string) (*v1.Workflow, error) {
+	ret := _m.Called(ctx, organizationID, workflowName)
+
+	var r0 *v1.Workflow
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Workflow); ok {
+		r0 = rf(ctx, organizationID, workflowName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Workflow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, workflowName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowRun provides a mock function with given fields: ctx, organizationID, runName
+func (_m *FunctionsClient) GetWorkflowRun(ctx context.Context, organizationID string, runName string) (*v1.WorkflowRun, error) {
+	ret := _m.Called(ctx, organizationID, runName)
+
+	var r0 *v1.WorkflowRun
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.WorkflowRun); ok {
+		r0 = rf(ctx, organizationID, runName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.WorkflowRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, runName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBatches provides a mock function with given fields: ctx, organizationID, functionName
+func (_m *FunctionsClient) ListBatches(ctx context.Context, organizationID string, functionName *string) ([]v1.Batch, error) {
+	ret := _m.Called(ctx, organizationID, functionName)
+
+	var r0 []v1.Batch
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string) []v1.Batch); ok {
+		r0 = rf(ctx, organizationID, functionName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *string) error); ok {
+		r1 = rf(ctx, organizationID, functionName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListConfigMaps provides a mock function with given fields: ctx, organizationID
+func (_m *FunctionsClient) ListConfigMaps(ctx context.Context, organizationID string) ([]v1.ConfigMap, error) {
+	ret := _m.Called(ctx, organizationID)
+
+	var r0 []v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(context.Context, string) []v1.ConfigMap); ok {
+		r0 = rf(ctx, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFunctionBuilds provides a mock function with given fields: ctx, organizationID, functionName
+func (_m *FunctionsClient) ListFunctionBuilds(ctx context.Context, organizationID string, functionName string) ([]v1.FunctionBuild, error) {
+	ret := _m.Called(ctx, organizationID, functionName)
+
+	var r0 []v1.FunctionBuild
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []v1.FunctionBuild); ok {
+		r0 = rf(ctx, organizationID, functionName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.FunctionBuild)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, organizationID, functionName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListFunctions provides a mock function with given fields: ctx, organizationID
 func (_m *FunctionsClient) ListFunctions(ctx context.Context, organizationID string) ([]v1.Function, error) {
 	ret := _m.Called(ctx, organizationID)
@@ -127,6 +569,29 @@ func (_m *FunctionsClient) ListFunctions(ctx context.Context, organizationID str
 	return r0, r1
 }
 
+// ListQuotas provides a mock function with given fields: ctx, organizationID
+func (_m *FunctionsClient) ListQuotas(ctx context.Context, organizationID string) ([]v1.Quota, error) {
+	ret := _m.Called(ctx, organizationID)
+
+	var r0 []v1.Quota
+	if rf, ok := ret.Get(0).(func(context.Context, string) []v1.Quota); ok {
+		r0 = rf(ctx, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRuns provides a mock function with given fields: ctx, organizationID, opts
 func (_m *FunctionsClient) ListRuns(ctx context.Context, organizationID string, opts client.FunctionOpts) ([]v1.Run, error) {
 	ret := _m.Called(ctx, organizationID, opts)
@@ -150,6 +615,75 @@ func (_m *FunctionsClient) ListRuns(ctx context.Context, organizationID string,
 	return r0, r1
 }
 
+// ListWorkflowRuns provides a mock function with given fields: ctx, organizationID, workflowName
+func (_m *FunctionsClient) ListWorkflowRuns(ctx context.Context, organizationID string, workflowName *string) ([]v1.WorkflowRun, error) {
+	ret := _m.Called(ctx, organizationID, workflowName)
+
+	var r0 []v1.WorkflowRun
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string) []v1.WorkflowRun); ok {
+		r0 = rf(ctx, organizationID, workflowName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.WorkflowRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *string) error); ok {
+		r1 = rf(ctx, organizationID, workflowName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWorkflows provides a mock function with given fields: ctx, organizationID
+func (_m *FunctionsClient) ListWorkflows(ctx context.Context, organizationID string) ([]v1.Workflow, error) {
+	ret := _m.Called(ctx, organizationID)
+
+	var r0 []v1.Workflow
+	if rf, ok := ret.Get(0).(func(context.Context, string) []v1.Workflow); ok {
+		r0 = rf(ctx, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Workflow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayRun provides a mock function with given fields: ctx, organizationID, opts, blocking
+func (_m *FunctionsClient) ReplayRun(ctx context.Context, organizationID string, opts client.FunctionOpts, blocking bool) (*v1.Run, error) {
+	ret := _m.Called(ctx, organizationID, opts, blocking)
+
+	var r0 *v1.Run
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.FunctionOpts, bool) *v1.Run); ok {
+		r0 = rf(ctx, organizationID, opts, blocking)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Run)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.FunctionOpts, bool) error); ok {
+		r1 = rf(ctx, organizationID, opts, blocking)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunFunction provides a mock function with given fields: ctx, organizationID, run
 func (_m *FunctionsClient) RunFunction(ctx context.Context, organizationID string, run *v1.Run) (*v1.Run, error) {
 	ret := _m.Called(ctx, organizationID, run)
@@ -173,6 +707,52 @@ func (_m *FunctionsClient) RunFunction(ctx context.Context, organizationID strin
 	return r0, r1
 }
 
+// RunWorkflow provides a mock function with given fields: ctx, organizationID, workflowName, run
+func (_m *FunctionsClient) RunWorkflow(ctx context.Context, organizationID string, workflowName string, run *v1.WorkflowRun) (*v1.WorkflowRun, error) {
+	ret := _m.Called(ctx, organizationID, workflowName, run)
+
+	var r0 *v1.WorkflowRun
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.WorkflowRun) *v1.WorkflowRun); ok {
+		r0 = rf(ctx, organizationID, workflowName, run)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.WorkflowRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *v1.WorkflowRun) error); ok {
+		r1 = rf(ctx, organizationID, workflowName, run)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateConfigMap provides a mock function with given fields: ctx, organizationID, configMap
+func (_m *FunctionsClient) UpdateConfigMap(ctx context.Context, organizationID string, configMap *v1.ConfigMap) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, organizationID, configMap)
+
+	var r0 *v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.ConfigMap) *v1.ConfigMap); ok {
+		r0 = rf(ctx, organizationID, configMap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.ConfigMap) error); ok {
+		r1 = rf(ctx, organizationID, configMap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateFunction provides a mock function with given fields: ctx, organizationID, function
 func (_m *FunctionsClient) UpdateFunction(ctx context.Context, organizationID string, function *v1.Function) (*v1.Function, error) {
 	ret := _m.Called(ctx, organizationID, function)
@@ -195,3 +775,26 @@ func (_m *FunctionsClient) UpdateFunction(ctx context.Context, organizationID st
 
 	return r0, r1
 }
+
+// UpdateQuota provides a mock function with given fields: ctx, organizationID, quota
+func (_m *FunctionsClient) UpdateQuota(ctx context.Context, organizationID string, quota *v1.Quota) (*v1.Quota, error) {
+	ret := _m.Called(ctx, organizationID, quota)
+
+	var r0 *v1.Quota
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Quota) *v1.Quota); ok {
+		r0 = rf(ctx, organizationID, quota)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.Quota) error); ok {
+		r1 = rf(ctx, organizationID, quota)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
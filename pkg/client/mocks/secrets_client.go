@@ -71,6 +71,29 @@ func (_m *SecretsClient) GetSecret(ctx context.Context, organizationID string, s
 	return r0, r1
 }
 
+// ListInternalSecrets provides a mock function with given fields: ctx, organizationID
+func (_m *SecretsClient) ListInternalSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error) {
+	ret := _m.Called(ctx, organizationID)
+
+	var r0 []v1.Secret
+	if rf, ok := ret.Get(0).(func(context.Context, string) []v1.Secret); ok {
+		r0 = rf(ctx, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListSecrets provides a mock function with given fields: ctx, organizationID
 func (_m *SecretsClient) ListSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error) {
 	ret := _m.Called(ctx, organizationID)
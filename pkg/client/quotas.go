@@ -0,0 +1,191 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/client/quota"
+)
+
+// CreateQuota creates and adds a new quota
+func (c *DefaultFunctionsClient) CreateQuota(ctx context.Context, organizationID string, q *v1.Quota) (*v1.Quota, error) {
+	params := quota.AddQuotaParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		Body:         q,
+	}
+	response, err := c.client.Quota.AddQuota(&params, c.auth)
+	if err != nil {
+		return nil, createQuotaSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func createQuotaSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *quota.AddQuotaBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *quota.AddQuotaUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *quota.AddQuotaForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *quota.AddQuotaConflict:
+		return NewErrorAlreadyExists(v.Payload)
+	case *quota.AddQuotaDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// DeleteQuota deletes a quota
+func (c *DefaultFunctionsClient) DeleteQuota(ctx context.Context, organizationID string, quotaName string) (*v1.Quota, error) {
+	params := quota.DeleteQuotaParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		QuotaName:    quotaName,
+	}
+	response, err := c.client.Quota.DeleteQuota(&params, c.auth)
+	if err != nil {
+		return nil, deleteQuotaSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func deleteQuotaSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *quota.DeleteQuotaBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *quota.DeleteQuotaUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *quota.DeleteQuotaForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *quota.DeleteQuotaNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *quota.DeleteQuotaDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetQuota gets a quota by name, with its current usage
+func (c *DefaultFunctionsClient) GetQuota(ctx context.Context, organizationID string, quotaName string) (*v1.Quota, error) {
+	params := quota.GetQuotaParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		QuotaName:    quotaName,
+	}
+	response, err := c.client.Quota.GetQuota(&params, c.auth)
+	if err != nil {
+		return nil, getQuotaSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func getQuotaSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *quota.GetQuotaBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *quota.GetQuotaUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *quota.GetQuotaForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *quota.GetQuotaNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *quota.GetQuotaDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ListQuotas lists all quotas, with their current usage
+func (c *DefaultFunctionsClient) ListQuotas(ctx context.Context, organizationID string) ([]v1.Quota, error) {
+	params := quota.GetQuotasParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Quota.GetQuotas(&params, c.auth)
+	if err != nil {
+		return nil, listQuotasSwaggerError(err)
+	}
+	quotas := []v1.Quota{}
+	for _, q := range response.Payload {
+		quotas = append(quotas, *q)
+	}
+	return quotas, nil
+}
+
+func listQuotasSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *quota.GetQuotasBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *quota.GetQuotasUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *quota.GetQuotasForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *quota.GetQuotasDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// UpdateQuota updates the limits of a quota
+func (c *DefaultFunctionsClient) UpdateQuota(ctx context.Context, organizationID string, q *v1.Quota) (*v1.Quota, error) {
+	params := quota.UpdateQuotaParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		QuotaName:    *q.Name,
+		Body:         q,
+	}
+	response, err := c.client.Quota.UpdateQuota(&params, c.auth)
+	if err != nil {
+		return nil, updateQuotaSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func updateQuotaSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *quota.UpdateQuotaBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *quota.UpdateQuotaUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *quota.UpdateQuotaForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *quota.UpdateQuotaNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *quota.UpdateQuotaDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
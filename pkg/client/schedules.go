@@ -0,0 +1,189 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/event-manager/gen/client/schedules"
+)
+
+// CreateSchedule creates and adds a new schedule
+func (c *DefaultEventsClient) CreateSchedule(ctx context.Context, organizationID string, schedule *v1.Schedule) (*v1.Schedule, error) {
+	params := schedules.AddScheduleParams{
+		Context:      ctx,
+		Body:         schedule,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Schedules.AddSchedule(&params, c.auth)
+	if err != nil {
+		return nil, createScheduleSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func createScheduleSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *schedules.AddScheduleBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *schedules.AddScheduleUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *schedules.AddScheduleForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *schedules.AddScheduleConflict:
+		return NewErrorAlreadyExists(v.Payload)
+	case *schedules.AddScheduleDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// DeleteSchedule deletes a schedule
+func (c *DefaultEventsClient) DeleteSchedule(ctx context.Context, organizationID string, scheduleName string) (*v1.Schedule, error) {
+	params := schedules.DeleteScheduleParams{
+		Context:      ctx,
+		ScheduleName: scheduleName,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Schedules.DeleteSchedule(&params, c.auth)
+	if err != nil {
+		return nil, deleteScheduleSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func deleteScheduleSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *schedules.DeleteScheduleBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *schedules.DeleteScheduleUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *schedules.DeleteScheduleForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *schedules.DeleteScheduleNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *schedules.DeleteScheduleDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetSchedule gets a schedule by name
+func (c *DefaultEventsClient) GetSchedule(ctx context.Context, organizationID string, scheduleName string) (*v1.Schedule, error) {
+	params := schedules.GetScheduleParams{
+		Context:      ctx,
+		ScheduleName: scheduleName,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Schedules.GetSchedule(&params, c.auth)
+	if err != nil {
+		return nil, getScheduleSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func getScheduleSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *schedules.GetScheduleBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *schedules.GetScheduleUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *schedules.GetScheduleForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *schedules.GetScheduleNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *schedules.GetScheduleDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ListSchedules lists all schedules
+func (c *DefaultEventsClient) ListSchedules(ctx context.Context, organizationID string) ([]v1.Schedule, error) {
+	params := schedules.GetSchedulesParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Schedules.GetSchedules(&params, c.auth)
+	if err != nil {
+		return nil, listSchedulesSwaggerError(err)
+	}
+	schedules := []v1.Schedule{}
+	for _, f := range response.Payload {
+		schedules = append(schedules, *f)
+	}
+	return schedules, nil
+}
+
+func listSchedulesSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *schedules.GetSchedulesUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *schedules.GetSchedulesForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *schedules.GetSchedulesDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// UpdateSchedule updates a specific schedule
+func (c *DefaultEventsClient) UpdateSchedule(ctx context.Context, organizationID string, schedule *v1.Schedule) (*v1.Schedule, error) {
+	params := schedules.UpdateScheduleParams{
+		Context:      ctx,
+		Body:         schedule,
+		ScheduleName: *schedule.Name,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Schedules.UpdateSchedule(&params, c.auth)
+	if err != nil {
+		return nil, updateScheduleSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func updateScheduleSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *schedules.UpdateScheduleBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *schedules.UpdateScheduleUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *schedules.UpdateScheduleForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *schedules.UpdateScheduleNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *schedules.UpdateScheduleDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
@@ -24,6 +24,7 @@ type SecretsClient interface {
 	UpdateSecret(ctx context.Context, organizationID string, secret *v1.Secret) (*v1.Secret, error)
 	GetSecret(ctx context.Context, organizationID string, secretName string) (*v1.Secret, error)
 	ListSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error)
+	ListInternalSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error)
 }
 
 // NewSecretsClient is used to create a new secrets client
@@ -189,9 +190,19 @@ func getSecretSwaggerError(err error) error {
 
 // ListSecrets lists secrets
 func (c *DefaultSecretsClient) ListSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error) {
+	return c.listSecrets(ctx, organizationID, false)
+}
+
+// ListInternalSecrets lists the secrets dispatch keeps for itself, e.g. the secrets signing the callbacks of runs
+func (c *DefaultSecretsClient) ListInternalSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error) {
+	return c.listSecrets(ctx, organizationID, true)
+}
+
+func (c *DefaultSecretsClient) listSecrets(ctx context.Context, organizationID string, internal bool) ([]v1.Secret, error) {
 	params := secretclient.GetSecretsParams{
 		Context:      ctx,
 		XDispatchOrg: c.getOrgID(organizationID),
+		Internal:     &internal,
 	}
 	response, err := c.client.Secret.GetSecrets(&params, c.auth)
 	if err != nil {
@@ -0,0 +1,265 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/client/workflow"
+)
+
+// CreateWorkflow creates and adds a new workflow
+func (c *DefaultFunctionsClient) CreateWorkflow(ctx context.Context, organizationID string, w *v1.Workflow) (*v1.Workflow, error) {
+	params := workflow.AddWorkflowParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		Body:         w,
+	}
+	response, err := c.client.Workflow.AddWorkflow(&params, c.auth)
+	if err != nil {
+		return nil, createWorkflowSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func createWorkflowSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.AddWorkflowBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.AddWorkflowUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.AddWorkflowForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.AddWorkflowConflict:
+		return NewErrorAlreadyExists(v.Payload)
+	case *workflow.AddWorkflowDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// DeleteWorkflow deletes a workflow
+func (c *DefaultFunctionsClient) DeleteWorkflow(ctx context.Context, organizationID string, workflowName string) (*v1.Workflow, error) {
+	params := workflow.DeleteWorkflowParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		WorkflowName: workflowName,
+	}
+	response, err := c.client.Workflow.DeleteWorkflow(&params, c.auth)
+	if err != nil {
+		return nil, deleteWorkflowSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func deleteWorkflowSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.DeleteWorkflowBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.DeleteWorkflowUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.DeleteWorkflowForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.DeleteWorkflowNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *workflow.DeleteWorkflowDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetWorkflow gets a workflow by name
+func (c *DefaultFunctionsClient) GetWorkflow(ctx context.Context, organizationID string, workflowName string) (*v1.Workflow, error) {
+	params := workflow.GetWorkflowParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		WorkflowName: workflowName,
+	}
+	response, err := c.client.Workflow.GetWorkflow(&params, c.auth)
+	if err != nil {
+		return nil, getWorkflowSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func getWorkflowSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.GetWorkflowBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.GetWorkflowUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.GetWorkflowForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.GetWorkflowNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *workflow.GetWorkflowDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ListWorkflows lists all workflows
+func (c *DefaultFunctionsClient) ListWorkflows(ctx context.Context, organizationID string) ([]v1.Workflow, error) {
+	params := workflow.GetWorkflowsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+	}
+	response, err := c.client.Workflow.GetWorkflows(&params, c.auth)
+	if err != nil {
+		return nil, listWorkflowsSwaggerError(err)
+	}
+	workflows := []v1.Workflow{}
+	for _, w := range response.Payload {
+		workflows = append(workflows, *w)
+	}
+	return workflows, nil
+}
+
+func listWorkflowsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.GetWorkflowsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.GetWorkflowsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.GetWorkflowsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.GetWorkflowsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// RunWorkflow starts a new run of a workflow
+func (c *DefaultFunctionsClient) RunWorkflow(ctx context.Context, organizationID string, workflowName string, run *v1.WorkflowRun) (*v1.WorkflowRun, error) {
+	params := workflow.RunWorkflowParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		WorkflowName: workflowName,
+		Body:         run,
+	}
+	response, err := c.client.Workflow.RunWorkflow(&params, c.auth)
+	if err != nil {
+		return nil, runWorkflowSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func runWorkflowSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.RunWorkflowBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.RunWorkflowUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.RunWorkflowForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.RunWorkflowNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *workflow.RunWorkflowDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// GetWorkflowRun gets a workflow run, including the steps executed so far
+func (c *DefaultFunctionsClient) GetWorkflowRun(ctx context.Context, organizationID string, runName string) (*v1.WorkflowRun, error) {
+	params := workflow.GetWorkflowRunParams{
+		Context:         ctx,
+		XDispatchOrg:    c.getOrgID(organizationID),
+		WorkflowRunName: strfmt.UUID(runName),
+	}
+	response, err := c.client.Workflow.GetWorkflowRun(&params, c.auth)
+	if err != nil {
+		return nil, getWorkflowRunSwaggerError(err)
+	}
+	return response.Payload, nil
+}
+
+func getWorkflowRunSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.GetWorkflowRunBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.GetWorkflowRunUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.GetWorkflowRunForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.GetWorkflowRunNotFound:
+		return NewErrorNotFound(v.Payload)
+	case *workflow.GetWorkflowRunDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
+
+// ListWorkflowRuns lists workflow runs, optionally only those of a single workflow
+func (c *DefaultFunctionsClient) ListWorkflowRuns(ctx context.Context, organizationID string, workflowName *string) ([]v1.WorkflowRun, error) {
+	params := workflow.GetWorkflowRunsParams{
+		Context:      ctx,
+		XDispatchOrg: c.getOrgID(organizationID),
+		WorkflowName: workflowName,
+	}
+	response, err := c.client.Workflow.GetWorkflowRuns(&params, c.auth)
+	if err != nil {
+		return nil, listWorkflowRunsSwaggerError(err)
+	}
+	runs := []v1.WorkflowRun{}
+	for _, run := range response.Payload {
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+func listWorkflowRunsSwaggerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch v := err.(type) {
+	case *workflow.GetWorkflowRunsBadRequest:
+		return NewErrorBadRequest(v.Payload)
+	case *workflow.GetWorkflowRunsUnauthorized:
+		return NewErrorUnauthorized(v.Payload)
+	case *workflow.GetWorkflowRunsForbidden:
+		return NewErrorForbidden(v.Payload)
+	case *workflow.GetWorkflowRunsDefault:
+		return NewErrorServerUnknownError(v.Payload)
+	default:
+		// shouldn't happen, but we need to be prepared:
+		return fmt.Errorf("unexpected error received from server: %s", err)
+	}
+}
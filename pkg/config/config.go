@@ -67,11 +67,24 @@ type Kubeless struct {
 	ImagePullSecret string `json:"imagePullSecret"`
 }
 
+// Kubernetes defines the plain Kubernetes faas specific config
+type Kubernetes struct {
+	K8sConfig            string             `json:"k8sConfig"`
+	FuncNamespace        string             `json:"funcNamespace"`
+	ImagePullSecret      string             `json:"imagePullSecret"`
+	FuncDefaultLimits    *FunctionResources `json:"funcDefaultLimits"`
+	FuncDefaultRequests  *FunctionResources `json:"funcDefaultRequests"`
+	Replicas             int32              `json:"replicas"`
+	MaxReplicas          int32              `json:"maxReplicas"`
+	TargetCPUUtilization *int32             `json:"targetCPUUtilization"`
+}
+
 // Function defines the function manager specific config
 type Function struct {
 	Openwhisk        `json:"openwhisk"`
 	OpenFaas         `json:"openFaas"`
 	Kubeless         `json:"kubeless"`
+	Kubernetes       `json:"kubernetes"`
 	Riff             `json:"riff"`
 	Faas             string `json:"faas"`
 	ResyncPeriod     int    `json:"resyncPeriod"`
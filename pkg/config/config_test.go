@@ -25,6 +25,11 @@ func Test_loadConfig(t *testing.T) {
     "riff": {
       "kafkaBrokers": ["transport-kafka.riff-system:9092"],
       "funcNamespace": "default"
+    },
+    "kubernetes": {
+      "funcNamespace": "functions",
+      "replicas": 2,
+      "maxReplicas": 4
     }
   },
   "registry": {
@@ -37,4 +42,7 @@ func Test_loadConfig(t *testing.T) {
 	assert.Equal(t, "some-docker-user", config.Registry.RegistryURI)
 	assert.Equal(t, []string{"transport-kafka.riff-system:9092"}, config.Function.Riff.KafkaBrokers)
 	assert.Equal(t, "default", config.Function.Riff.FuncNamespace)
+	assert.Equal(t, "functions", config.Function.Kubernetes.FuncNamespace)
+	assert.Equal(t, int32(2), config.Function.Kubernetes.Replicas)
+	assert.Equal(t, int32(4), config.Function.Kubernetes.MaxReplicas)
 }
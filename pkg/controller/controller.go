@@ -39,6 +39,9 @@ type Options struct {
 
 	ResyncPeriod time.Duration
 	Workers      int
+	// Scheduler replaces the first in first out processing of watch events by Workers at a time, the entities of the
+	// periodic resyncs are pushed to it as well
+	Scheduler Scheduler
 }
 
 // WatchEvent captures entity together with the associated context
@@ -47,6 +50,18 @@ type WatchEvent struct {
 	Ctx    context.Context
 }
 
+// Scheduler decides the order in which the watch events are processed and how many are processed at a time
+type Scheduler interface {
+	// Push queues a watch event, the scheduler may drop it if the entity is already queued or being processed
+	Push(event WatchEvent)
+	// Next blocks until a queued watch event can be processed and returns it, or returns false once closed
+	Next() (WatchEvent, bool)
+	// Done releases the capacity taken by a watch event returned by Next once it is processed
+	Done(event WatchEvent)
+	// Close stops the scheduler, dropping the queued watch events
+	Close()
+}
+
 // Watcher channel type
 type Watcher chan<- WatchEvent
 
@@ -226,6 +241,27 @@ func (dc *DefaultController) sync() error {
 	return nil
 }
 
+// resync syncs the entities like sync, but pushes them to the scheduler if there is one. The scheduler then processes
+// them in its order, and they don't run concurrently with the watch events of the same entities it holds.
+func (dc *DefaultController) resync() error {
+	if dc.options.Scheduler == nil {
+		return dc.sync()
+	}
+	span, ctx := trace.Trace(context.Background(), "controller resync")
+	defer span.Finish()
+	for _, handler := range dc.entityHandlers {
+		entities, err := handler.Sync(ctx, dc.options.ResyncPeriod)
+		if err != nil {
+			return err
+		}
+		for _, e := range entities {
+			log.Debugf("sync: scheduling entity %s", e.GetName())
+			dc.options.Scheduler.Push(WatchEvent{e, opentracing.ContextWithSpan(context.Background(), span)})
+		}
+	}
+	return nil
+}
+
 // run runs the control loop
 func (dc *DefaultController) run(stopChan <-chan bool) {
 	resyncTicker := time.NewTicker(dc.options.ResyncPeriod)
@@ -233,30 +269,17 @@ func (dc *DefaultController) run(stopChan <-chan bool) {
 
 	defer close(dc.watcher)
 
-	// Start a worker pool.  The pool scales up to dc.options.Workers.
-	go func() {
-		sem := semaphore.NewWeighted(int64(dc.options.Workers))
-		for watchEvent := range dc.watcher {
-			if err := sem.Acquire(context.Background(), 1); err != nil {
-				log.Warnf("Failed to acquire semaphore: %v", err)
-				break
-			}
-			go func(event WatchEvent) {
-				e := event.Entity
-				defer sem.Release(1)
-				log.Infof("received event=%s entity=%s", e.GetStatus(), e.GetName())
-				if err := dc.processItem(event.Ctx, e); err != nil {
-					log.Error(err)
-				}
-			}(watchEvent)
-		}
-	}()
+	if dc.options.Scheduler != nil {
+		go dc.schedule(dc.options.Scheduler)
+	} else {
+		go dc.work()
+	}
 
 	go func() {
 		for range resyncTicker.C {
 			func() {
 				log.Debugf("%s periodic syncing with the underlying driver", dc.options.ServiceName)
-				if err := dc.sync(); err != nil {
+				if err := dc.resync(); err != nil {
 					log.Error(err)
 				}
 			}()
@@ -265,3 +288,46 @@ func (dc *DefaultController) run(stopChan <-chan bool) {
 
 	<-stopChan
 }
+
+// work processes the watch events first in first out with a worker pool, which scales up to dc.options.Workers.
+func (dc *DefaultController) work() {
+	sem := semaphore.NewWeighted(int64(dc.options.Workers))
+	for watchEvent := range dc.watcher {
+		if err := sem.Acquire(context.Background(), 1); err != nil {
+			log.Warnf("Failed to acquire semaphore: %v", err)
+			break
+		}
+		go func(event WatchEvent) {
+			e := event.Entity
+			defer sem.Release(1)
+			log.Infof("received event=%s entity=%s", e.GetStatus(), e.GetName())
+			if err := dc.processItem(event.Ctx, e); err != nil {
+				log.Error(err)
+			}
+		}(watchEvent)
+	}
+}
+
+// schedule processes the watch events in the order and at the pace decided by the scheduler
+func (dc *DefaultController) schedule(s Scheduler) {
+	go func() {
+		for watchEvent := range dc.watcher {
+			s.Push(watchEvent)
+		}
+		s.Close()
+	}()
+	for {
+		event, ok := s.Next()
+		if !ok {
+			return
+		}
+		go func(event WatchEvent) {
+			e := event.Entity
+			defer s.Done(event)
+			log.Infof("received event=%s entity=%s", e.GetStatus(), e.GetName())
+			if err := dc.processItem(event.Ctx, e); err != nil {
+				log.Error(err)
+			}
+		}(event)
+	}
+}
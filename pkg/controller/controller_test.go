@@ -8,6 +8,7 @@ package controller
 import (
 	"context"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -110,3 +111,93 @@ func TestController(t *testing.T) {
 		t.Logf("deleted %s", name)
 	}
 }
+
+// lifoScheduler processes the last pushed watch event first, one at a time once released
+type lifoScheduler struct {
+	sync.Mutex
+	cond    *sync.Cond
+	events  []WatchEvent
+	release bool
+	closed  bool
+	running bool
+}
+
+func newLIFOScheduler() *lifoScheduler {
+	s := &lifoScheduler{}
+	s.cond = sync.NewCond(&s.Mutex)
+	return s
+}
+
+func (s *lifoScheduler) Push(event WatchEvent) {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, event)
+	s.cond.Broadcast()
+}
+
+func (s *lifoScheduler) Next() (WatchEvent, bool) {
+	s.Lock()
+	defer s.Unlock()
+	for !s.closed && (!s.release || s.running || len(s.events) == 0) {
+		s.cond.Wait()
+	}
+	if s.closed {
+		return WatchEvent{}, false
+	}
+	event := s.events[len(s.events)-1]
+	s.events = s.events[:len(s.events)-1]
+	s.running = true
+	return event, true
+}
+
+func (s *lifoScheduler) Done(event WatchEvent) {
+	s.Lock()
+	defer s.Unlock()
+	s.running = false
+	s.cond.Broadcast()
+}
+
+func (s *lifoScheduler) Close() {
+	s.Lock()
+	defer s.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+func (s *lifoScheduler) Release() {
+	s.Lock()
+	defer s.Unlock()
+	s.release = true
+	s.cond.Broadcast()
+}
+
+func TestControllerScheduler(t *testing.T) {
+	ctx := context.Background()
+	store := helpers.MakeEntityStore(t)
+	addCounter := make(chan string, 100)
+	scheduler := newLIFOScheduler()
+
+	controller := NewController(Options{
+		ResyncPeriod: time.Hour,
+		Workers:      1,
+		Scheduler:    scheduler,
+	})
+	controller.AddEntityHandler(&testEntityHandler{t: t, store: store, addCounter: addCounter})
+	watcher := controller.Watcher()
+	controller.Start()
+	defer controller.Shutdown()
+
+	for _, name := range []string{"test-a", "test-b"} {
+		watcher.OnAction(ctx, &testEntity{entitystore.BaseEntity{Name: name, Status: entitystore.StatusCREATING}})
+	}
+	// OnAction doesn't wait for the watch events to be processed, they are queued until released
+	scheduler.Lock()
+	for len(scheduler.events) < 2 {
+		scheduler.cond.Wait()
+	}
+	scheduler.Unlock()
+
+	scheduler.Release()
+	assert.Equal(t, "test-b", <-addCounter)
+	assert.Equal(t, "test-a", <-addCounter)
+}
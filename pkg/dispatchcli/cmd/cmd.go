@@ -53,6 +53,7 @@ var validResources = i18n.T(`Valid resource types include:
 	* base-images
 	* eventdrivers
 	* eventdrivertypes
+	* events
 	* functions
 	* images
 	* secrets
@@ -177,6 +178,7 @@ func NewCLI(in io.Reader, out, errOut io.Writer) *cobra.Command {
 	cmds.AddCommand(NewCmdLogin(in, out, errOut))
 	cmds.AddCommand(NewCmdLogout(in, out, errOut))
 	cmds.AddCommand(NewCmdEmit(out, errOut))
+	cmds.AddCommand(NewCmdReplay(out, errOut))
 	cmds.AddCommand(NewCmdInstall(out, errOut))
 	cmds.AddCommand(NewCmdUninstall(out, errOut))
 	cmds.AddCommand(NewCmdVersion(out))
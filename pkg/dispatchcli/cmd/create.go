@@ -81,7 +81,11 @@ func importBytes(out io.Writer, b []byte, actionMap map[string]ModelAction, acti
 		DriverTypes      []*v1.EventDriverType `json:"driverTypes"`
 		Drivers          []*v1.EventDriver     `json:"drivers"`
 		Subscriptions    []*v1.Subscription    `json:"subscriptions"`
+		Schedules        []*v1.Schedule        `json:"schedules"`
 		Functions        []*v1.Function        `json:"functions"`
+		Workflows        []*v1.Workflow        `json:"workflows"`
+		ConfigMaps       []*v1.ConfigMap       `json:"configMaps"`
+		Quotas           []*v1.Quota           `json:"quotas"`
 		Secrets          []*v1.Secret          `json:"secrets"`
 		Policies         []*v1.Policy          `json:"policies"`
 		ServiceInstances []*v1.ServiceInstance `json:"serviceInstances"`
@@ -155,7 +159,10 @@ func importBytes(out io.Writer, b []byte, actionMap map[string]ModelAction, acti
 				if isDir && m.Handler == "" {
 					return fmt.Errorf("error creating function %s: handler is required, source path %s is a directory", *m.Name, sourcePath)
 				}
-				sourceTarGz, err := utils.TarGzBytes(sourcePath)
+				if utils.IsArchive(sourcePath) && m.Handler == "" {
+					return fmt.Errorf("error creating function %s: handler is required, source path %s is an archive", *m.Name, sourcePath)
+				}
+				sourceTarGz, err := utils.SourceTarGzBytes(sourcePath)
 				if err != nil {
 					return errors.Wrapf(err, "Error when reading content of %s", sourcePath)
 				}
@@ -167,6 +174,39 @@ func importBytes(out io.Writer, b []byte, actionMap map[string]ModelAction, acti
 			}
 			o.Functions = append(o.Functions, m)
 			fmt.Fprintf(out, "%s %s: %s\n", actionName, docKind, *m.Name)
+		case utils.WorkflowKind:
+			m := &v1.Workflow{}
+			if err := yaml.Unmarshal(doc, m); err != nil {
+				return errors.Wrapf(err, "Error decoding workflow document %s", string(doc))
+			}
+			err = actionMap[docKind](m)
+			if err != nil {
+				return err
+			}
+			o.Workflows = append(o.Workflows, m)
+			fmt.Fprintf(out, "%s %s: %s\n", actionName, docKind, *m.Name)
+		case utils.ConfigMapKind:
+			m := &v1.ConfigMap{}
+			if err := yaml.Unmarshal(doc, m); err != nil {
+				return errors.Wrapf(err, "Error decoding config map document %s", string(doc))
+			}
+			err = actionMap[docKind](m)
+			if err != nil {
+				return err
+			}
+			o.ConfigMaps = append(o.ConfigMaps, m)
+			fmt.Fprintf(out, "%s %s: %s\n", actionName, docKind, *m.Name)
+		case utils.QuotaKind:
+			m := &v1.Quota{}
+			if err := yaml.Unmarshal(doc, m); err != nil {
+				return errors.Wrapf(err, "Error decoding quota document %s", string(doc))
+			}
+			err = actionMap[docKind](m)
+			if err != nil {
+				return err
+			}
+			o.Quotas = append(o.Quotas, m)
+			fmt.Fprintf(out, "%s %s: %s\n", actionName, docKind, *m.Name)
 		case utils.DriverTypeKind:
 			m := &v1.EventDriverType{}
 			err = yaml.Unmarshal(doc, m)
@@ -203,6 +243,18 @@ func importBytes(out io.Writer, b []byte, actionMap map[string]ModelAction, acti
 			}
 			o.Subscriptions = append(o.Subscriptions, m)
 			fmt.Fprintf(out, "%s %s: %s\n", actionName, docKind, *m.Name)
+		case utils.ScheduleKind:
+			m := &v1.Schedule{}
+			err = yaml.Unmarshal(doc, m)
+			if err != nil {
+				return errors.Wrapf(err, "Error decoding schedule document %s", string(doc))
+			}
+			err = actionMap[docKind](m)
+			if err != nil {
+				return err
+			}
+			o.Schedules = append(o.Schedules, m)
+			fmt.Fprintf(out, "%s %s: %s\n", actionName, docKind, *m.Name)
 		case utils.SecretKind:
 			m := &v1.Secret{}
 			err = yaml.Unmarshal(doc, m)
@@ -290,6 +342,9 @@ func initCreateMap() {
 		utils.ImageKind:           CallCreateImage(imgClient),
 		utils.BaseImageKind:       CallCreateBaseImage(imgClient),
 		utils.FunctionKind:        CallCreateFunction(fnClient),
+		utils.WorkflowKind:        CallCreateWorkflow(fnClient),
+		utils.ConfigMapKind:       CallCreateConfigMap(fnClient),
+		utils.QuotaKind:           CallCreateQuota(fnClient),
 		utils.SecretKind:          CallCreateSecret(secClient),
 		utils.ServiceInstanceKind: CallCreateServiceInstance(svcClient),
 		utils.PolicyKind:          CallCreatePolicy(iamClient),
@@ -298,6 +353,7 @@ func initCreateMap() {
 		utils.DriverTypeKind:      CallCreateEventDriverType(eventClient),
 		utils.DriverKind:          CallCreateEventDriver(eventClient),
 		utils.SubscriptionKind:    CallCreateSubscription(eventClient),
+		utils.ScheduleKind:        CallCreateSchedule(eventClient),
 		utils.APIKind:             CallCreateAPI(apiClient),
 		utils.OrganizationKind:    callCreateOrganization(iamClient),
 	}
@@ -332,9 +388,13 @@ func NewCmdCreate(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdCreateBaseImage(out, errOut))
 	cmd.AddCommand(NewCmdCreateImage(out, errOut))
 	cmd.AddCommand(NewCmdCreateFunction(out, errOut))
+	cmd.AddCommand(NewCmdCreateWorkflow(out, errOut))
+	cmd.AddCommand(NewCmdCreateQuota(out, errOut))
+	cmd.AddCommand(NewCmdCreateConfigMap(out, errOut))
 	cmd.AddCommand(NewCmdCreateSecret(out, errOut))
 	cmd.AddCommand(NewCmdCreateAPI(out, errOut))
 	cmd.AddCommand(NewCmdCreateSubscription(out, errOut))
+	cmd.AddCommand(NewCmdCreateSchedule(out, errOut))
 	cmd.AddCommand(NewCmdCreateEventDriver(out, errOut))
 	cmd.AddCommand(NewCmdCreateEventDriverType(out, errOut))
 	cmd.AddCommand(NewCmdCreateApplication(out, errOut))
@@ -0,0 +1,110 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	createConfigMapLong = i18n.T(`Create dispatch config map. A config map holds non-secret configuration shared by functions.
+The values are available to the function in its context and as environment variables of its container.`)
+
+	createConfigMapExample = i18n.T(`
+# Create a config map with two values
+dispatch create configmap shared --data REGION=us-west --data LOG_LEVEL=debug
+
+# Use it from a function
+dispatch create function hello ./hello.py --image python3 --config-map shared
+`)
+	createConfigMapData []string
+)
+
+// NewCmdCreateConfigMap creates command responsible for config map creation.
+func NewCmdCreateConfigMap(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "configmap CONFIG_MAP_NAME [--data KEY=VALUE...]",
+		Short:   i18n.T("Create config map"),
+		Long:    createConfigMapLong,
+		Example: createConfigMapExample,
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"configmaps"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := createConfigMap(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "associate with an application")
+	cmd.Flags().StringArrayVar(&createConfigMapData, "data", []string{}, "Configuration value as KEY=VALUE, can be specified multiple times")
+	return cmd
+}
+
+// CallCreateConfigMap makes the API call to create a config map
+func CallCreateConfigMap(c client.FunctionsClient) ModelAction {
+	return func(i interface{}) error {
+		configMap := i.(*v1.ConfigMap)
+
+		created, err := c.CreateConfigMap(context.TODO(), "", configMap)
+		if err != nil {
+			return err
+		}
+		*configMap = *created
+		return nil
+	}
+}
+
+// parseKeyValues parses KEY=VALUE pairs into a map
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid configuration value %q, expected KEY=VALUE", pair)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, nil
+}
+
+func createConfigMap(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	data, err := parseKeyValues(createConfigMapData)
+	if err != nil {
+		return err
+	}
+	configMap := &v1.ConfigMap{
+		Name: swag.String(args[0]),
+		Data: data,
+	}
+	if cmdFlagApplication != "" {
+		configMap.Tags = append(configMap.Tags, &v1.Tag{
+			Key:   "Application",
+			Value: cmdFlagApplication,
+		})
+	}
+	err = CallCreateConfigMap(c)(configMap)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(configMap)
+	}
+	fmt.Fprintf(out, "Created config map: %s\n", *configMap.Name)
+	return nil
+}
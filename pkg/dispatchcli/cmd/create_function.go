@@ -11,8 +11,10 @@ import (
 	"io"
 	"io/ioutil"
 	"path"
+	"strings"
 
 	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -24,16 +26,38 @@ import (
 )
 
 var (
-	createFunctionLong = i18n.T(`Create dispatch function.`)
-	// TODO: add examples
-	createFunctionExample = i18n.T(``)
-	depsImage             = ""
-	handler               = ""
-	schemaInFile          = ""
-	schemaOutFile         = ""
-	fnSecrets             []string
-	fnServices            []string
-	timeout               int64
+	createFunctionLong = i18n.T(`Create dispatch function. The source is a single file, a directory or a .tar, .tar.gz, .tgz or .zip
+archive, the whole tree being available to the function. Paths matching the patterns in the .dispatchignore file at the
+root of a directory are left out.`)
+
+	createFunctionExample = i18n.T(`
+# Create a function from a single file
+dispatch create function hello ./hello.py --image python3
+
+# Create a function from a project directory, the handler being a module of the project
+dispatch create function report ./report --image python3 --handler report.main.handle
+
+# Create a function from a zip archive
+dispatch create function report ./report.zip --image python3 --handler report.main.handle
+
+# Create a function limiting the size of its input and logging its runs
+dispatch create function hello ./hello.py --image python3 --middleware size-limit:maxInput=1024 --middleware logging
+
+# Create a function publishing an event with its greeting when a run succeeds, and one with the error when it fails
+dispatch create function hello ./hello.py --image python3 --on-success hello.done:greeting=output.myField --on-failure hello.failed
+`)
+	depsImage     = ""
+	handler       = ""
+	schemaInFile  = ""
+	schemaOutFile = ""
+	fnSecrets     []string
+	fnServices    []string
+	fnConfig      []string
+	fnConfigMaps  []string
+	fnMiddlewares []string
+	fnOnSuccess   = ""
+	fnOnFailure   = ""
+	timeout       int64
 )
 
 // NewCmdCreateFunction creates command responsible for dispatch function creation.
@@ -57,6 +81,11 @@ func NewCmdCreateFunction(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&schemaOutFile, "schema-out", "", "path to file with output validation schema")
 	cmd.Flags().StringArrayVar(&fnSecrets, "secret", []string{}, "Function secrets, can be specified multiple times or a comma-delimited string")
 	cmd.Flags().StringArrayVar(&fnServices, "service", []string{}, "Service instances this function uses, can be specified multiple times or a comma-delimited string")
+	cmd.Flags().StringArrayVar(&fnConfig, "config", []string{}, "Function configuration as KEY=VALUE, can be specified multiple times")
+	cmd.Flags().StringArrayVar(&fnConfigMaps, "config-map", []string{}, "Config maps this function uses, can be specified multiple times")
+	cmd.Flags().StringArrayVar(&fnMiddlewares, "middleware", []string{}, "Middleware applied to the runs as NAME[:KEY=VALUE,...], can be specified multiple times, in order")
+	cmd.Flags().StringVar(&fnOnSuccess, "on-success", "", "Event published when a run succeeds as EVENT_TYPE[:FIELD=PATH,...], the output of the run by default")
+	cmd.Flags().StringVar(&fnOnFailure, "on-failure", "", "Event published when a run fails as EVENT_TYPE[:FIELD=PATH,...], the error of the run by default")
 	cmd.Flags().Int64Var(&timeout, "timeout", 0, "A timeout to limit function execution time.")
 	cmd.MarkFlagRequired("image")
 	return cmd
@@ -86,19 +115,43 @@ func createFunction(out, errOut io.Writer, cmd *cobra.Command, args []string, c
 	if isDir && handler == "" {
 		return fmt.Errorf("error creating function %s: handler is required, source path %s is a directory", args[0], sourcePath)
 	}
-	codeFileContent, err := utils.TarGzBytes(sourcePath)
+	if utils.IsArchive(sourcePath) && handler == "" {
+		return fmt.Errorf("error creating function %s: handler is required, source path %s is an archive", args[0], sourcePath)
+	}
+	config, err := parseKeyValues(fnConfig)
+	if err != nil {
+		return err
+	}
+	middlewares, err := parseMiddlewares(fnMiddlewares)
+	if err != nil {
+		return err
+	}
+	onSuccess, err := parseResultEvent(fnOnSuccess)
+	if err != nil {
+		return err
+	}
+	onFailure, err := parseResultEvent(fnOnFailure)
+	if err != nil {
+		return err
+	}
+	codeFileContent, err := utils.SourceTarGzBytes(sourcePath)
 	if err != nil {
 		return errors.Wrapf(err, "error reading %s", sourcePath)
 	}
 	function := &v1.Function{
-		Image:    &depsImage,
-		Name:     &args[0],
-		Source:   codeFileContent,
-		Handler:  handler,
-		Secrets:  fnSecrets,
-		Services: fnServices,
-		Timeout:  timeout,
-		Tags:     []*v1.Tag{},
+		Image:       &depsImage,
+		Name:        &args[0],
+		Source:      codeFileContent,
+		Handler:     handler,
+		Secrets:     fnSecrets,
+		Services:    fnServices,
+		Config:      config,
+		ConfigMaps:  fnConfigMaps,
+		Middlewares: middlewares,
+		OnSuccess:   onSuccess,
+		OnFailure:   onFailure,
+		Timeout:     timeout,
+		Tags:        []*v1.Tag{},
 	}
 	if cmdFlagApplication != "" {
 		function.Tags = append(function.Tags, &v1.Tag{
@@ -148,3 +201,46 @@ func createFunction(out, errOut io.Writer, cmd *cobra.Command, args []string, c
 	fmt.Fprintf(out, "Created function: %s\n", *function.Name)
 	return nil
 }
+
+// parseMiddlewares parses middlewares given as NAME[:KEY=VALUE,...]. Values containing commas, e.g. templates, must be
+// set in a resource file instead.
+func parseMiddlewares(values []string) ([]*v1.FunctionMiddleware, error) {
+	var middlewares []*v1.FunctionMiddleware
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid middleware %q, expected NAME[:KEY=VALUE,...]", value)
+		}
+		m := &v1.FunctionMiddleware{Name: swag.String(parts[0])}
+		if len(parts) == 2 {
+			config, err := parseKeyValues(strings.Split(parts[1], ","))
+			if err != nil {
+				return nil, err
+			}
+			m.Config = config
+		}
+		middlewares = append(middlewares, m)
+	}
+	return middlewares, nil
+}
+
+// parseResultEvent parses a result event given as EVENT_TYPE[:FIELD=PATH,...], the paths being dot-separated paths in
+// the run, e.g. output.myField
+func parseResultEvent(value string) (*v1.FunctionEvent, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.SplitN(value, ":", 2)
+	if parts[0] == "" {
+		return nil, fmt.Errorf("invalid event %q, expected EVENT_TYPE[:FIELD=PATH,...]", value)
+	}
+	e := &v1.FunctionEvent{EventType: swag.String(parts[0])}
+	if len(parts) == 2 {
+		data, err := parseKeyValues(strings.Split(parts[1], ","))
+		if err != nil {
+			return nil, err
+		}
+		e.Data = data
+	}
+	return e, nil
+}
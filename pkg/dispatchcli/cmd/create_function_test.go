@@ -10,7 +10,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-openapi/swag"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
 )
 
 func TestCmdCreateFunction(t *testing.T) {
@@ -23,3 +26,33 @@ func TestCmdCreateFunction(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, strings.Contains(buf.String(), "Create dispatch function"))
 }
+
+func TestParseMiddlewares(t *testing.T) {
+	middlewares, err := parseMiddlewares([]string{"size-limit:maxInput=1024,maxOutput=2048", "logging"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*v1.FunctionMiddleware{
+		{Name: swag.String("size-limit"), Config: map[string]string{"maxInput": "1024", "maxOutput": "2048"}},
+		{Name: swag.String("logging")},
+	}, middlewares)
+
+	_, err = parseMiddlewares([]string{":maxInput=1024"})
+	assert.Error(t, err)
+	_, err = parseMiddlewares([]string{"size-limit:maxInput"})
+	assert.Error(t, err)
+}
+
+func TestParseResultEvent(t *testing.T) {
+	e, err := parseResultEvent("")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = parseResultEvent("hello.done:greeting=output.myField,who=input.name")
+	assert.NoError(t, err)
+	assert.Equal(t, &v1.FunctionEvent{
+		EventType: swag.String("hello.done"),
+		Data:      map[string]string{"greeting": "output.myField", "who": "input.name"},
+	}, e)
+
+	_, err = parseResultEvent(":greeting=output")
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	createQuotaLong = i18n.T(`Create dispatch quota. A quota limits the function runs of the organization, or of a single function.
+Runs over a quota are rejected, the client is told when to retry.`)
+
+	createQuotaExample = i18n.T(`
+# Allow at most 100 runs per second in the organization
+dispatch create quota org-rate --runs-per-second 100
+
+# Run at most 2 runs of the function "report" at a time, queuing up to 10 more
+dispatch create quota report-concurrency --function report --max-concurrent-runs 2 --max-queued-runs 10
+`)
+	createQuotaFunction          string
+	createQuotaRunsPerSecond     float64
+	createQuotaMaxConcurrentRuns int64
+	createQuotaMaxQueuedRuns     int64
+)
+
+// NewCmdCreateQuota creates command responsible for quota creation.
+func NewCmdCreateQuota(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "quota QUOTA_NAME [--function FUNCTION_NAME] [--runs-per-second N] [--max-concurrent-runs N] [--max-queued-runs N]",
+		Short:   i18n.T("Create quota"),
+		Long:    createQuotaLong,
+		Example: createQuotaExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := createQuota(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "associate with an application")
+	cmd.Flags().StringVar(&createQuotaFunction, "function", "", "Function the quota applies to, all the functions of the organization if not specified")
+	cmd.Flags().Float64Var(&createQuotaRunsPerSecond, "runs-per-second", 0, "Maximum number of runs started per second")
+	cmd.Flags().Int64Var(&createQuotaMaxConcurrentRuns, "max-concurrent-runs", 0, "Maximum number of runs executing at the same time")
+	cmd.Flags().Int64Var(&createQuotaMaxQueuedRuns, "max-queued-runs", 0, "Maximum number of runs waiting for one of the concurrent runs to finish")
+	return cmd
+}
+
+// CallCreateQuota makes the API call to create a quota
+func CallCreateQuota(c client.FunctionsClient) ModelAction {
+	return func(i interface{}) error {
+		quota := i.(*v1.Quota)
+
+		created, err := c.CreateQuota(context.TODO(), "", quota)
+		if err != nil {
+			return err
+		}
+		*quota = *created
+		return nil
+	}
+}
+
+func createQuota(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	quota := &v1.Quota{
+		Name:              swag.String(args[0]),
+		Function:          createQuotaFunction,
+		RunsPerSecond:     createQuotaRunsPerSecond,
+		MaxConcurrentRuns: createQuotaMaxConcurrentRuns,
+		MaxQueuedRuns:     createQuotaMaxQueuedRuns,
+	}
+	if cmdFlagApplication != "" {
+		quota.Tags = append(quota.Tags, &v1.Tag{
+			Key:   "Application",
+			Value: cmdFlagApplication,
+		})
+	}
+	err := CallCreateQuota(c)(quota)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(quota)
+	}
+	fmt.Fprintf(out, "Created quota: %s\n", *quota.Name)
+	return nil
+}
@@ -0,0 +1,114 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	createScheduleLong = i18n.T(`Create dispatch schedule. A schedule runs a function, or emits an event, at the times of a cron expression.`)
+
+	createScheduleExample = i18n.T(`
+# Run the function "report" every day at 2am, Paris time
+dispatch create schedule nightly-report "0 2 * * *" --function report --timezone Europe/Paris
+
+# Emit the event "cleanup.requested" every 15 minutes, with an input payload
+dispatch create schedule cleanup "*/15 * * * *" --event-type cleanup.requested --input '{"older-than": "1h"}'
+
+# Skip a fire while the previous run of the function is still running
+dispatch create schedule sync "@hourly" --function sync --concurrency-policy Forbid
+`)
+	createScheduleFunction          string
+	createScheduleEventType         string
+	createScheduleTimezone          string
+	createScheduleInput             string
+	createScheduleSecrets           []string
+	createScheduleConcurrencyPolicy string
+)
+
+// NewCmdCreateSchedule creates command responsible for schedule creation.
+func NewCmdCreateSchedule(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule SCHEDULE_NAME CRON (--function FUNCTION_NAME | --event-type EVENT.TYPE) [--timezone TZ] [--input JSON] [--secret SECRET1,SECRET2...] [--concurrency-policy Allow|Forbid|Replace]",
+		Short:   i18n.T("Create schedule"),
+		Long:    createScheduleLong,
+		Example: createScheduleExample,
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			c := eventManagerClient()
+			err := createSchedule(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "associate with an application")
+	cmd.Flags().StringVar(&createScheduleFunction, "function", "", "Function to run")
+	cmd.Flags().StringVar(&createScheduleEventType, "event-type", "", "Event type to emit")
+	cmd.Flags().StringVar(&createScheduleTimezone, "timezone", "", "Timezone of the cron expression (e.g. America/Los_Angeles), UTC if not specified")
+	cmd.Flags().StringVar(&createScheduleInput, "input", "", "Function input or event data JSON")
+	cmd.Flags().StringArrayVar(&createScheduleSecrets, "secret", []string{}, "Function secrets, can be specified multiple times or a comma-delimited string")
+	cmd.Flags().StringVar(&createScheduleConcurrencyPolicy, "concurrency-policy", v1.ScheduleConcurrencyPolicyAllow, "What to do when a fire time comes and the previous function run is still running: Allow, Forbid (skip the fire) or Replace (cancel the previous run)")
+	return cmd
+}
+
+// CallCreateSchedule makes the API call to create a schedule
+func CallCreateSchedule(c client.EventsClient) ModelAction {
+	return func(i interface{}) error {
+		schedule := i.(*v1.Schedule)
+
+		created, err := c.CreateSchedule(context.TODO(), "", schedule)
+		if err != nil {
+			return err
+		}
+		*schedule = *created
+		return nil
+	}
+}
+
+func createSchedule(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.EventsClient) error {
+	schedule := &v1.Schedule{
+		Name:              swag.String(args[0]),
+		Cron:              swag.String(args[1]),
+		Timezone:          createScheduleTimezone,
+		Function:          createScheduleFunction,
+		EventType:         createScheduleEventType,
+		Secrets:           createScheduleSecrets,
+		ConcurrencyPolicy: createScheduleConcurrencyPolicy,
+	}
+	if createScheduleInput != "" {
+		if err := json.Unmarshal([]byte(createScheduleInput), &schedule.Input); err != nil {
+			fmt.Fprintf(errOut, "Error when parsing schedule input %s\n", createScheduleInput)
+			return err
+		}
+	}
+	if cmdFlagApplication != "" {
+		schedule.Tags = append(schedule.Tags, &v1.Tag{
+			Key:   "Application",
+			Value: cmdFlagApplication,
+		})
+	}
+	err := CallCreateSchedule(c)(schedule)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(schedule)
+	}
+	fmt.Fprintf(out, "Created schedule: %s\n", *schedule.Name)
+	return nil
+}
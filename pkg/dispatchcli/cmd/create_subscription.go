@@ -22,9 +22,22 @@ import (
 var (
 	createSubscriptionLong = i18n.T(`Create dispatch event subscription.`)
 
-	// TODO: add examples
-	createSubscriptionExample   = i18n.T(``)
+	createSubscriptionExample = i18n.T(`
+# Run a function for the events of a type
+dispatch create subscription hello --event-type vm.created
+
+# Run a function for the events of every vm type, * matches one word and # zero or more words
+dispatch create subscription hello --event-type "vm.*"
+
+# Run a function only for the events matching all the filters
+dispatch create subscription power-off --event-type vm.updated --filter 'source == "vcenter"' --filter 'data.vm.powerState == "off"'
+
+# Run a function again until it succeeds, events which can't be delivered go to the dead-letter topic of the subscription
+dispatch create subscription hello --event-type vm.created --ack succeeded
+`)
 	createSubscriptionSecrets   []string
+	createSubscriptionFilters   []string
+	createSubscriptionAck       string
 	createSubscriptionEventType string
 	createSubscriptionName      string
 )
@@ -32,7 +45,7 @@ var (
 // NewCmdCreateSubscription creates command responsible for subscription creation.
 func NewCmdCreateSubscription(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "subscription FUNCTION_NAME [--name SUBSCRIPTION_NAME] [--event-type EVENT.TYPE] [--secret SECRET1,SECRET2...]",
+		Use:     "subscription FUNCTION_NAME [--name SUBSCRIPTION_NAME] [--event-type EVENT.TYPE] [--secret SECRET1,SECRET2...] [--filter EXPRESSION...] [--ack accepted|succeeded]",
 		Short:   i18n.T("Create subscription"),
 		Long:    createSubscriptionLong,
 		Example: createSubscriptionExample,
@@ -47,7 +60,9 @@ func NewCmdCreateSubscription(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.Flags().StringArrayVar(&createSubscriptionSecrets, "secret", []string{}, "Function secrets, can be specified multiple times or a comma-delimited string")
 
 	cmd.Flags().StringVar(&createSubscriptionName, "name", "", "Subscription name. If not specified, will be randomly generated.")
-	cmd.Flags().StringVar(&createSubscriptionEventType, "event-type", "", "Event Type to filter on.")
+	cmd.Flags().StringVar(&createSubscriptionEventType, "event-type", "", "Event Type to filter on, or a pattern such as vm.* or vm.#")
+	cmd.Flags().StringArrayVar(&createSubscriptionFilters, "filter", []string{}, "Condition on the events, PATH OPERATOR VALUE where PATH is an attribute, extensions.NAME or data.FIELD and VALUE is JSON, can be specified multiple times")
+	cmd.Flags().StringVar(&createSubscriptionAck, "ack", "", "When events are acknowledged: once the run is accepted (default) or once it succeeded")
 
 	return cmd
 }
@@ -72,6 +87,8 @@ func createSubscription(out, errOut io.Writer, cmd *cobra.Command, args []string
 		EventType: &createSubscriptionEventType,
 		Function:  &args[0],
 		Secrets:   createSubscriptionSecrets,
+		Filters:   createSubscriptionFilters,
+		Ack:       createSubscriptionAck,
 	}
 	if cmdFlagApplication != "" {
 		subscription.Tags = append(subscription.Tags, &v1.Tag{
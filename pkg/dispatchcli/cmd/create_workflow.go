@@ -0,0 +1,112 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	createWorkflowLong = i18n.T(`Create dispatch workflow from a definition file (YAML or JSON) with the startAt and states of the workflow.`)
+
+	createWorkflowExample = i18n.T(`
+# Create a workflow running two functions in sequence
+cat <<EOF > order.yaml
+startAt: validate
+states:
+- name: validate
+  type: task
+  function: validate-order
+  next: charge
+- name: charge
+  type: task
+  function: charge-card
+  retry:
+    maxAttempts: 3
+  end: true
+EOF
+dispatch create workflow process-order order.yaml
+`)
+)
+
+// NewCmdCreateWorkflow creates command responsible for dispatch workflow creation.
+func NewCmdCreateWorkflow(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workflow NAME PATH",
+		Short:   i18n.T("Create workflow"),
+		Long:    createWorkflowLong,
+		Example: createWorkflowExample,
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := createWorkflow(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "associate with an application")
+	return cmd
+}
+
+// CallCreateWorkflow makes the API call to create a workflow
+func CallCreateWorkflow(c client.FunctionsClient) ModelAction {
+	return func(w interface{}) error {
+		workflow := w.(*v1.Workflow)
+
+		created, err := c.CreateWorkflow(context.TODO(), "", workflow)
+		if err != nil {
+			return err
+		}
+		*workflow = *created
+		return nil
+	}
+}
+
+func createWorkflow(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	fullPath := path.Join(workDir, args[1])
+	content, err := ioutil.ReadFile(fullPath)
+	if err != nil {
+		return errors.Wrapf(err, "error when reading content of %s", fullPath)
+	}
+	workflow := &v1.Workflow{}
+	if err := yaml.Unmarshal(content, workflow); err != nil {
+		return errors.Wrapf(err, "error when parsing workflow definition from %s", fullPath)
+	}
+	workflow.Name = &args[0]
+	if workflow.Tags == nil {
+		workflow.Tags = []*v1.Tag{}
+	}
+	if cmdFlagApplication != "" {
+		workflow.Tags = append(workflow.Tags, &v1.Tag{
+			Key:   "Application",
+			Value: cmdFlagApplication,
+		})
+	}
+
+	err = CallCreateWorkflow(c)(workflow)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(workflow)
+	}
+	fmt.Fprintf(out, "Created workflow: %s\n", *workflow.Name)
+	return nil
+}
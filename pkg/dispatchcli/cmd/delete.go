@@ -50,6 +50,9 @@ func NewCmdDelete(out io.Writer, errOut io.Writer) *cobra.Command {
 				utils.ImageKind:           CallDeleteImage(imgClient),
 				utils.BaseImageKind:       CallDeleteBaseImage(imgClient),
 				utils.FunctionKind:        CallDeleteFunction(fnClient),
+				utils.WorkflowKind:        CallDeleteWorkflow(fnClient),
+				utils.ConfigMapKind:       CallDeleteConfigMap(fnClient),
+				utils.QuotaKind:           CallDeleteQuota(fnClient),
 				utils.SecretKind:          CallDeleteSecret(secClient),
 				utils.ApplicationKind:     CallDeleteApplication,
 				utils.PolicyKind:          CallDeletePolicy(iamClient),
@@ -58,6 +61,7 @@ func NewCmdDelete(out io.Writer, errOut io.Writer) *cobra.Command {
 				utils.DriverTypeKind:      CallDeleteEventDriverType(eventClient),
 				utils.DriverKind:          CallDeleteEventDriver(eventClient),
 				utils.SubscriptionKind:    CallDeleteSubscription(eventClient),
+				utils.ScheduleKind:        CallDeleteSchedule(eventClient),
 				utils.APIKind:             CallDeleteAPI(apiClient),
 			}
 
@@ -69,9 +73,13 @@ func NewCmdDelete(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdDeleteBaseImage(out, errOut))
 	cmd.AddCommand(NewCmdDeleteImage(out, errOut))
 	cmd.AddCommand(NewCmdDeleteFunction(out, errOut))
+	cmd.AddCommand(NewCmdDeleteWorkflow(out, errOut))
+	cmd.AddCommand(NewCmdDeleteQuota(out, errOut))
+	cmd.AddCommand(NewCmdDeleteConfigMap(out, errOut))
 	cmd.AddCommand(NewCmdDeleteSecret(out, errOut))
 	cmd.AddCommand(NewCmdDeleteAPI(out, errOut))
 	cmd.AddCommand(NewCmdDeleteSubscription(out, errOut))
+	cmd.AddCommand(NewCmdDeleteSchedule(out, errOut))
 	cmd.AddCommand(NewCmdDeleteEventDriver(out, errOut))
 	cmd.AddCommand(NewCmdDeleteEventDriverType(out, errOut))
 	cmd.AddCommand(NewCmdDeleteApplication(out, errOut))
@@ -0,0 +1,89 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vmware/dispatch/pkg/client"
+	"golang.org/x/net/context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	deleteConfigMapLong = i18n.T(`Delete config maps.`)
+
+	// TODO: add examples
+	deleteConfigMapExample = i18n.T(``)
+)
+
+// NewCmdDeleteConfigMap creates command responsible for deleting configMaps.
+func NewCmdDeleteConfigMap(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "configmap CONFIG_MAP_NAME",
+		Short:   i18n.T("Delete config map"),
+		Long:    deleteConfigMapLong,
+		Example: deleteConfigMapExample,
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"configMaps"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := deleteConfigMap(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	return cmd
+}
+
+// CallDeleteConfigMap makes the API call to delete a config map
+func CallDeleteConfigMap(c client.FunctionsClient) ModelAction {
+	return func(i interface{}) error {
+		configMap := i.(*v1.ConfigMap)
+
+		deleted, err := c.DeleteConfigMap(context.TODO(), "", *configMap.Name)
+		if err != nil {
+			return err
+		}
+		*configMap = *deleted
+		return nil
+	}
+}
+
+func deleteConfigMap(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	configMapModel := v1.ConfigMap{
+		Name: &args[0],
+	}
+	err := CallDeleteConfigMap(c)(&configMapModel)
+	if err != nil {
+		return err
+	}
+	return formatDeleteConfigMapOutput(out, false, []*v1.ConfigMap{&configMapModel})
+}
+
+func formatDeleteConfigMapOutput(out io.Writer, list bool, configMaps []*v1.ConfigMap) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(configMaps)
+		}
+		return encoder.Encode(configMaps[0])
+	}
+	for _, s := range configMaps {
+		_, err := fmt.Fprintf(out, "Deleted config map: %s\n", *s.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
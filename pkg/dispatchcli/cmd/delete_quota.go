@@ -0,0 +1,89 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vmware/dispatch/pkg/client"
+	"golang.org/x/net/context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	deleteQuotaLong = i18n.T(`Delete quotas.`)
+
+	// TODO: add examples
+	deleteQuotaExample = i18n.T(``)
+)
+
+// NewCmdDeleteQuota creates command responsible for deleting quotas.
+func NewCmdDeleteQuota(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "quota QUOTA_NAME",
+		Short:   i18n.T("Delete quota"),
+		Long:    deleteQuotaLong,
+		Example: deleteQuotaExample,
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"quotas"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := deleteQuota(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	return cmd
+}
+
+// CallDeleteQuota makes the API call to delete a quota
+func CallDeleteQuota(c client.FunctionsClient) ModelAction {
+	return func(i interface{}) error {
+		quota := i.(*v1.Quota)
+
+		deleted, err := c.DeleteQuota(context.TODO(), "", *quota.Name)
+		if err != nil {
+			return err
+		}
+		*quota = *deleted
+		return nil
+	}
+}
+
+func deleteQuota(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	quotaModel := v1.Quota{
+		Name: &args[0],
+	}
+	err := CallDeleteQuota(c)(&quotaModel)
+	if err != nil {
+		return err
+	}
+	return formatDeleteQuotaOutput(out, false, []*v1.Quota{&quotaModel})
+}
+
+func formatDeleteQuotaOutput(out io.Writer, list bool, quotas []*v1.Quota) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(quotas)
+		}
+		return encoder.Encode(quotas[0])
+	}
+	for _, s := range quotas {
+		_, err := fmt.Fprintf(out, "Deleted quota: %s\n", *s.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vmware/dispatch/pkg/client"
+	"golang.org/x/net/context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	deleteScheduleLong = i18n.T(`Delete schedules.`)
+
+	// TODO: add examples
+	deleteScheduleExample = i18n.T(``)
+)
+
+// NewCmdDeleteSchedule creates command responsible for deleting schedules.
+func NewCmdDeleteSchedule(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule SCHEDULE_NAME",
+		Short:   i18n.T("Delete schedule"),
+		Long:    deleteScheduleLong,
+		Example: deleteScheduleExample,
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"schedules"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := eventManagerClient()
+			err := deleteSchedule(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	return cmd
+}
+
+// CallDeleteSchedule makes the API call to delete an event schedule
+func CallDeleteSchedule(c client.EventsClient) ModelAction {
+	return func(i interface{}) error {
+		schedule := i.(*v1.Schedule)
+
+		deleted, err := c.DeleteSchedule(context.TODO(), "", *schedule.Name)
+		if err != nil {
+			return err
+		}
+		*schedule = *deleted
+		return nil
+	}
+}
+
+func deleteSchedule(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.EventsClient) error {
+	scheduleModel := v1.Schedule{
+		Name: &args[0],
+	}
+	err := CallDeleteSchedule(c)(&scheduleModel)
+	if err != nil {
+		return err
+	}
+	return formatDeleteScheduleOutput(out, false, []*v1.Schedule{&scheduleModel})
+}
+
+func formatDeleteScheduleOutput(out io.Writer, list bool, schedules []*v1.Schedule) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(schedules)
+		}
+		return encoder.Encode(schedules[0])
+	}
+	for _, s := range schedules {
+		_, err := fmt.Fprintf(out, "Deleted schedule: %s\n", *s.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	deleteWorkflowLong = i18n.T(`Delete workflows, runs of the workflow are deleted as well.`)
+
+	deleteWorkflowExample = i18n.T(`
+# Delete the workflow "process-order"
+dispatch delete workflow process-order
+`)
+)
+
+// NewCmdDeleteWorkflow creates command responsible for deleting workflows.
+func NewCmdDeleteWorkflow(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workflow WORKFLOW_NAME",
+		Short:   i18n.T("Delete workflow"),
+		Long:    deleteWorkflowLong,
+		Example: deleteWorkflowExample,
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"workflows"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := deleteWorkflow(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	return cmd
+}
+
+// CallDeleteWorkflow makes the API call to delete a workflow
+func CallDeleteWorkflow(c client.FunctionsClient) ModelAction {
+	return func(i interface{}) error {
+		workflowModel := i.(*v1.Workflow)
+
+		deleted, err := c.DeleteWorkflow(context.Background(), "", *workflowModel.Name)
+		if err != nil {
+			return err
+		}
+		*workflowModel = *deleted
+		return nil
+	}
+}
+
+func deleteWorkflow(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	workflowModel := v1.Workflow{
+		Name: &args[0],
+	}
+	err := CallDeleteWorkflow(c)(&workflowModel)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(workflowModel)
+	}
+	_, err = fmt.Fprintf(out, "Deleted workflow: %s\n", *workflowModel.Name)
+	return err
+}
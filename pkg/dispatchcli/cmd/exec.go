@@ -9,7 +9,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 
@@ -21,26 +24,57 @@ import (
 var (
 	execLong = i18n.T(`Execute a dispatch function.`)
 
-	// TODO: Add examples
-	execExample = i18n.T(``)
+	execExample = i18n.T(`
+# Execute a function and wait for its output
+dispatch exec hello --input '{"name": "Jon"}' --wait
 
-	execWait      = false
-	execAllOutput = false
-	execInput     = "{}"
-	execSecrets   = []string{}
+# Execute a function and POST the run to a URL once it completes
+dispatch exec hello --input '{"name": "Jon"}' --callback-url https://example.com/done --callback-secret s3cr3t
+
+# Execute a past run again, with the same input
+dispatch exec --replay f98d0a7f-0c1d-4020-a488-cabc501b08e0 --wait
+
+# Execute the input of a past run of a function with another function
+dispatch exec --replay f98d0a7f-0c1d-4020-a488-cabc501b08e0 hello-fixed --wait
+
+# Execute a function once for each input of a file, a JSON array or one JSON value per line, 5 at a time
+dispatch exec hello --batch inputs.json --concurrency 5 --wait
+`)
+
+	execWait           = false
+	execAllOutput      = false
+	execInput          = "{}"
+	execSecrets        = []string{}
+	execIdempotencyKey = ""
+	execWorkflow       = false
+	execReplay         = ""
+	execCallbackURL    = ""
+	execCallbackSecret = ""
+	execCallbackEvent  = ""
+	execBatch          = ""
+	execConcurrency    = int64(0)
 )
 
 // NewCmdExec creates a command to execute a dispatch function.
 func NewCmdExec(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "exec [--wait] [--input JSON] [--secret SECRET_1,SECRET_2...] FUNCTION_NAME",
+		Use:     "exec [--wait] [--input JSON] [--secret SECRET_1,SECRET_2...] [--workflow] FUNCTION_NAME|WORKFLOW_NAME | --replay RUN_ID [FUNCTION_NAME] | --batch FILE [--concurrency N] FUNCTION_NAME",
 		Short:   i18n.T("Execute a dispatch function"),
 		Long:    execLong,
 		Example: execExample,
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
 			c := functionManagerClient()
-			err := runExec(out, errOut, cmd, args, c)
+			var err error
+			if execReplay != "" {
+				err = runExecReplay(out, c, args)
+			} else if len(args) != 1 {
+				err = fmt.Errorf("exactly one function or workflow name is required")
+			} else if execBatch != "" {
+				err = runExecBatch(out, c, args[0])
+			} else {
+				err = runExec(out, errOut, cmd, args, c)
+			}
 			CheckErr(err)
 		},
 		PreRunE: validateFnExecFunc(errOut),
@@ -50,6 +84,14 @@ func NewCmdExec(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&execInput, "input", "{}", "Function input JSON object")
 	cmd.Flags().StringArrayVar(&execSecrets, "secret", []string{}, "Function secrets, can be specified multiple times or a comma-delimited string")
 	cmd.Flags().BoolVar(&execAllOutput, "all", false, "Also print metadata along with json output, ONLY with --json")
+	cmd.Flags().StringVar(&execIdempotencyKey, "idempotency-key", "", "Runs with the same key are executed only once, the existing run is returned instead")
+	cmd.Flags().BoolVar(&execWorkflow, "workflow", false, "Execute a workflow instead of a function")
+	cmd.Flags().StringVar(&execReplay, "replay", "", "Execute again the run with this ID, with its input, event, secrets and services")
+	cmd.Flags().StringVar(&execCallbackURL, "callback-url", "", "URL the run is POSTed to once it completes")
+	cmd.Flags().StringVar(&execCallbackSecret, "callback-secret", "", "Secret signing the callback request, in the X-Dispatch-Signature header")
+	cmd.Flags().StringVar(&execCallbackEvent, "callback-event", "", "Type of an event published with the run once it completes")
+	cmd.Flags().StringVar(&execBatch, "batch", "", "Execute the function once for each input of this file, a JSON array or one JSON value per line")
+	cmd.Flags().Int64Var(&execConcurrency, "concurrency", 0, "Maximum number of runs of a batch executed at the same time, ONLY with --batch")
 	return cmd
 }
 
@@ -67,11 +109,22 @@ func runExec(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.
 		fmt.Fprintf(errOut, "Error when parsing function parameters %s\n", execInput)
 		return err
 	}
+	if execWorkflow {
+		return runExecWorkflow(out, c, functionName, input)
+	}
 	run := &v1.Run{
-		Blocking:     execWait,
-		Input:        input,
-		Secrets:      execSecrets,
-		FunctionName: functionName,
+		Blocking:       execWait,
+		Input:          input,
+		Secrets:        execSecrets,
+		FunctionName:   functionName,
+		IdempotencyKey: execIdempotencyKey,
+	}
+	if execCallbackURL != "" || execCallbackEvent != "" {
+		run.Callback = &v1.RunCallback{
+			URL:       execCallbackURL,
+			Secret:    execCallbackSecret,
+			EventType: execCallbackEvent,
+		}
 	}
 
 	functionResult, err := c.RunFunction(context.TODO(), "", run)
@@ -83,9 +136,98 @@ func runExec(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.
 	return formatExecOutput(out, functionResult)
 }
 
+// runExecReplay replays a run, against the function given as argument if any
+func runExecReplay(out io.Writer, c client.FunctionsClient, args []string) error {
+	opts := client.FunctionOpts{
+		RunName: &execReplay,
+	}
+	if len(args) == 1 {
+		opts.FunctionName = &args[0]
+	}
+	run, err := c.ReplayRun(context.TODO(), "", opts, execWait)
+	if err != nil {
+		return err
+	}
+	return formatExecOutput(out, run)
+}
+
+// runExecBatch runs a function over the inputs read from the batch file
+func runExecBatch(out io.Writer, c client.FunctionsClient, functionName string) error {
+	f, err := os.Open(execBatch)
+	if err != nil {
+		return errors.Wrapf(err, "Error reading file %s", execBatch)
+	}
+	defer f.Close()
+	inputs, err := readBatchInputs(f)
+	if err != nil {
+		return errors.Wrapf(err, "Error when parsing inputs of %s", execBatch)
+	}
+
+	b, err := c.CreateBatch(context.TODO(), "", &v1.Batch{
+		FunctionName: functionName,
+		Concurrency:  execConcurrency,
+		Inputs:       inputs,
+	})
+	if err != nil {
+		return err
+	}
+	// batches are always asynchronous, poll until all the runs are finished
+	for execWait && b.FinishedTime == 0 {
+		time.Sleep(followPeriod)
+		b, err = c.GetBatch(context.TODO(), "", b.Name.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(b)
+}
+
+// readBatchInputs reads the inputs of a batch, either the elements of a JSON array or one JSON value per line
+func readBatchInputs(r io.Reader) ([]interface{}, error) {
+	var values []interface{}
+	decoder := json.NewDecoder(r)
+	for {
+		var value interface{}
+		if err := decoder.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if len(values) == 1 {
+		if inputs, ok := values[0].([]interface{}); ok {
+			return inputs, nil
+		}
+	}
+	return values, nil
+}
+
 func formatExecOutput(out io.Writer, run *v1.Run) error {
 	// Always return json for execution
 	encoder := json.NewEncoder(out)
 	encoder.SetIndent("", "    ")
 	return encoder.Encode(run)
 }
+
+func runExecWorkflow(out io.Writer, c client.FunctionsClient, workflowName string, input interface{}) error {
+	run, err := c.RunWorkflow(context.TODO(), "", workflowName, &v1.WorkflowRun{Input: input})
+	if err != nil {
+		return err
+	}
+	// workflow runs are always asynchronous, poll until the run is finished
+	for execWait && run.FinishedTime == 0 {
+		time.Sleep(followPeriod)
+		run, err = c.GetWorkflowRun(context.TODO(), "", run.Name.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(run)
+}
@@ -11,6 +11,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/client/mocks"
 )
 
 func TestCmdExec(t *testing.T) {
@@ -23,3 +28,20 @@ func TestCmdExec(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, strings.Contains(buf.String(), "Execute a dispatch function"))
 }
+
+func TestExecReplay(t *testing.T) {
+	runName := "f98d0a7f-0c1d-4020-a488-cabc501b08e0"
+	functionName := "hello-fixed"
+	fnClient := &mocks.FunctionsClient{}
+	fnClient.On("ReplayRun", mock.Anything, mock.Anything, client.FunctionOpts{RunName: &runName, FunctionName: &functionName}, true).Return(
+		&v1.Run{FunctionName: functionName, ReplayOf: "f98d0a7f-0c1d-4020-a488-cabc501b08e0", Output: "hello"}, nil)
+
+	execReplay, execWait = runName, true
+	defer func() { execReplay, execWait = "", false }()
+
+	var buf bytes.Buffer
+	err := runExecReplay(&buf, fnClient, []string{functionName})
+	assert.NoError(t, err)
+	fnClient.AssertExpectations(t)
+	assert.Contains(t, buf.String(), `"replayOf": "f98d0a7f-0c1d-4020-a488-cabc501b08e0"`)
+}
@@ -43,9 +43,16 @@ func NewCmdGet(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdGetImage(out, errOut))
 	cmd.AddCommand(NewCmdGetFunction(out, errOut))
 	cmd.AddCommand(NewCmdGetRun(out, errOut))
+	cmd.AddCommand(NewCmdGetWorkflow(out, errOut))
+	cmd.AddCommand(NewCmdGetWorkflowRun(out, errOut))
+	cmd.AddCommand(NewCmdGetBatch(out, errOut))
+	cmd.AddCommand(NewCmdGetQuota(out, errOut))
+	cmd.AddCommand(NewCmdGetConfigMap(out, errOut))
 	cmd.AddCommand(NewCmdGetSecret(out, errOut))
 	cmd.AddCommand(NewCmdGetAPI(out, errOut))
 	cmd.AddCommand(NewCmdGetSubscription(out, errOut))
+	cmd.AddCommand(NewCmdGetEvent(out, errOut))
+	cmd.AddCommand(NewCmdGetSchedule(out, errOut))
 	cmd.AddCommand(NewCmdGetEventDriver(out, errOut))
 	cmd.AddCommand(NewCmdGetEventDriverType(out, errOut))
 	cmd.AddCommand(NewCmdGetApplication(out, errOut))
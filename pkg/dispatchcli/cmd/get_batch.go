@@ -0,0 +1,134 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getBatchLong = i18n.T(`Get batch(es). The result of each input of a batch is printed as one JSON object per line.`)
+
+	getBatchExample = i18n.T(`
+# Get all batches
+dispatch get batches
+
+# Get the batches of a specific function
+dispatch get batches --function hello
+
+# Get the progress of a specific batch
+dispatch get batch f98d0a7f-0c1d-4020-a488-cabc501b08e0
+
+# Get the inputs of a batch whose run failed
+dispatch get batch f98d0a7f-0c1d-4020-a488-cabc501b08e0 --items --failed
+`)
+
+	getBatchFunction = ""
+	getBatchItems    = false
+	getBatchFailed   = false
+)
+
+// NewCmdGetBatch creates command responsible for getting batches.
+func NewCmdGetBatch(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "batch [BATCH_ID [--items [--failed]]]",
+		Short:   i18n.T("Get batch(es)"),
+		Long:    getBatchLong,
+		Example: getBatchExample,
+		Args:    cobra.RangeArgs(0, 1),
+		Aliases: []string{"batches"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			var err error
+			if len(args) > 0 && getBatchItems {
+				err = getBatchItemsOutput(out, errOut, cmd, args, c)
+			} else if len(args) > 0 {
+				err = getBatch(out, errOut, cmd, args, c)
+			} else {
+				err = getBatches(out, errOut, cmd, c)
+			}
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVar(&getBatchFunction, "function", "", "filter by function")
+	cmd.Flags().BoolVar(&getBatchItems, "items", false, "Get the result of each input of the batch")
+	cmd.Flags().BoolVar(&getBatchFailed, "failed", false, "Only get the inputs whose run failed, ONLY with --items")
+	return cmd
+}
+
+func getBatch(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	resp, err := c.GetBatch(context.TODO(), dispatchConfig.Organization, args[0])
+	if err != nil {
+		return err
+	}
+	return formatBatchOutput(out, false, []v1.Batch{*resp})
+}
+
+func getBatches(out, errOut io.Writer, cmd *cobra.Command, c client.FunctionsClient) error {
+	var functionName *string
+	if getBatchFunction != "" {
+		functionName = &getBatchFunction
+	}
+	resp, err := c.ListBatches(context.TODO(), dispatchConfig.Organization, functionName)
+	if err != nil {
+		return err
+	}
+	return formatBatchOutput(out, true, resp)
+}
+
+// getBatchItemsOutput prints the items of a batch as JSON lines, to be processed or fed again to exec --batch
+func getBatchItemsOutput(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	items, err := c.GetBatchItems(context.TODO(), dispatchConfig.Organization, args[0], getBatchFailed)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(out)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatBatchOutput(out io.Writer, list bool, batches []v1.Batch) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(batches)
+		}
+		return encoder.Encode(batches[0])
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"ID", "Function", "Status", "Total", "Succeeded", "Failed", "Started", "Finished"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, b := range batches {
+		table.Append([]string{
+			b.Name.String(),
+			b.FunctionName,
+			string(b.Status),
+			strconv.FormatInt(b.Total, 10),
+			strconv.FormatInt(b.Succeeded, 10),
+			strconv.FormatInt(b.Failed, 10),
+			formatTimestamp(b.CreatedTime),
+			formatTimestamp(b.FinishedTime),
+		})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,68 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client/mocks"
+)
+
+func TestGetBatch(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	b := &v1.Batch{
+		Name:         "f98d0a7f-0c1d-4020-a488-cabc501b08e0",
+		FunctionName: "hello",
+		Status:       v1.StatusCREATING,
+		Total:        10,
+		Succeeded:    7,
+		Failed:       1,
+	}
+	fc.On("GetBatch", mock.Anything, mock.Anything, b.Name.String()).Return(b, nil)
+
+	cmd := NewCmdGetBatch(buf, buf)
+	err := getBatch(buf, buf, cmd, []string{b.Name.String()}, fc)
+	assert.NoError(t, err)
+	assert.Regexp(t, `f98d0a7f-0c1d-4020-a488-cabc501b08e0 \| hello\s+\| CREATING \|\s+10 \|\s+7 \|\s+1`, buf.String())
+	fc.AssertExpectations(t)
+}
+
+func TestGetBatchItems(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	message := "function failed"
+	items := []v1.BatchItem{
+		{Index: 3, Status: v1.StatusERROR, Error: &v1.InvocationError{Message: &message}},
+	}
+	fc.On("GetBatchItems", mock.Anything, mock.Anything, "f98d0a7f-0c1d-4020-a488-cabc501b08e0", true).Return(items, nil)
+
+	cmd := NewCmdGetBatch(buf, buf)
+	cmd.Flags().Set("failed", "true")
+	err := getBatchItemsOutput(buf, buf, cmd, []string{"f98d0a7f-0c1d-4020-a488-cabc501b08e0"}, fc)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"index":3`)
+	assert.Contains(t, buf.String(), `"message":"function failed"`)
+	fc.AssertExpectations(t)
+}
+
+func TestReadBatchInputs(t *testing.T) {
+	inputs, err := readBatchInputs(bytes.NewBufferString(`[{"name": "Jon"}, {"name": "Jane"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, inputs, 2)
+
+	inputs, err = readBatchInputs(bytes.NewBufferString("{\"name\": \"Jon\"}\n{\"name\": \"Jane\"}\n\"text\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Jon"}, map[string]interface{}{"name": "Jane"}, "text"}, inputs)
+
+	_, err = readBatchInputs(bytes.NewBufferString("{\"name\": \"Jon\"}\n{invalid"))
+	assert.Error(t, err)
+}
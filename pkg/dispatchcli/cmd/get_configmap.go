@@ -0,0 +1,100 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getConfigMapsLong = i18n.T(`Get config maps.`)
+
+	getConfigMapsExample = i18n.T(`
+# Get all config maps
+dispatch get configmaps
+
+# Get a specific config map
+dispatch get configmap shared
+`)
+)
+
+// NewCmdGetConfigMap creates command responsible for getting config maps.
+func NewCmdGetConfigMap(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "configmap [CONFIG_MAP]",
+		Short:   i18n.T("Get config maps"),
+		Long:    getConfigMapsLong,
+		Example: getConfigMapsExample,
+		Args:    cobra.MaximumNArgs(1),
+		Aliases: []string{"configmaps"},
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			c := functionManagerClient()
+			if len(args) > 0 {
+				err = getConfigMap(out, errOut, cmd, args, c)
+			} else {
+				err = getConfigMaps(out, errOut, cmd, c)
+			}
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	return cmd
+}
+
+func getConfigMap(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	resp, err := c.GetConfigMap(context.TODO(), "", args[0])
+	if err != nil {
+		return err
+	}
+	return formatConfigMapOutput(out, false, []v1.ConfigMap{*resp})
+}
+
+func getConfigMaps(out, errOut io.Writer, cmd *cobra.Command, c client.FunctionsClient) error {
+	resp, err := c.ListConfigMaps(context.TODO(), "")
+	if err != nil {
+		return err
+	}
+	return formatConfigMapOutput(out, true, resp)
+}
+
+func formatConfigMapOutput(out io.Writer, list bool, configMaps []v1.ConfigMap) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(configMaps)
+		}
+		return encoder.Encode(configMaps[0])
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Name", "Data"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	table.SetAutoWrapText(false)
+	for _, cm := range configMaps {
+		var data []string
+		for k, v := range cm.Data {
+			data = append(data, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(data)
+		table.Append([]string{*cm.Name, strings.Join(data, ", ")})
+	}
+	table.Render()
+	return nil
+}
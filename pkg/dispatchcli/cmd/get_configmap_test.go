@@ -0,0 +1,40 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+)
+
+func TestFormatConfigMapOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	configMaps := []v1.ConfigMap{
+		{
+			Name: swag.String("shared"),
+			Data: map[string]string{"REGION": "us-west", "LOG_LEVEL": "debug"},
+		},
+	}
+
+	assert.NoError(t, formatConfigMapOutput(buf, true, configMaps))
+	assert.Regexp(t, `shared \| LOG_LEVEL=debug, REGION=us-west`, buf.String())
+}
+
+func TestParseKeyValues(t *testing.T) {
+	values, err := parseKeyValues([]string{"REGION=us-west", "URL=http://example.com/?a=b", "EMPTY="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"REGION": "us-west", "URL": "http://example.com/?a=b", "EMPTY": ""}, values)
+
+	_, err = parseKeyValues([]string{"REGION"})
+	assert.Error(t, err)
+	_, err = parseKeyValues([]string{"=us-west"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getEventsLong = i18n.T(`Get the events recorded in the event log, with the outcomes of their deliveries.`)
+
+	getEventsExample = i18n.T(`
+# Get the events emitted or received in the last hour
+dispatch get events --since 1h
+
+# Get the events of a type from a source
+dispatch get events --type vm.created --source vcenter
+
+# Get the events of every vm type, * matches one word and # zero or more words
+dispatch get events --type "vm.*"
+`)
+
+	eventType   = ""
+	eventSource = ""
+	eventSince  time.Duration
+	eventUntil  time.Duration
+)
+
+// NewCmdGetEvent creates command responsible for getting recorded events.
+func NewCmdGetEvent(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "event [--type EVENT_TYPE] [--source SOURCE] [--since DURATION] [--until DURATION]",
+		Short:   i18n.T("Get recorded events"),
+		Long:    getEventsLong,
+		Example: getEventsExample,
+		Args:    cobra.NoArgs,
+		Aliases: []string{"events"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := eventManagerClient()
+			err := getEvents(out, errOut, cmd, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVar(&eventType, "type", "", "filter by event type, or a pattern such as vm.*")
+	cmd.Flags().StringVar(&eventSource, "source", "", "filter by event source")
+	cmd.Flags().DurationVar(&eventSince, "since", 0, "only events recorded within the duration (e.g. 1h)")
+	cmd.Flags().DurationVar(&eventUntil, "until", 0, "only events recorded before the duration (e.g. 10m)")
+	return cmd
+}
+
+// eventOpts returns the options selecting events recorded between since and until ago, zero durations do not filter
+func eventOpts(since, until time.Duration) client.EventOpts {
+	opts := client.EventOpts{}
+	now := time.Now()
+	if since > 0 {
+		opts.Since = now.Add(-since)
+	}
+	if until > 0 {
+		opts.Until = now.Add(-until)
+	}
+	return opts
+}
+
+func getEvents(out, errOut io.Writer, cmd *cobra.Command, c client.EventsClient) error {
+	opts := eventOpts(eventSince, eventUntil)
+	opts.EventType = eventType
+	opts.Source = eventSource
+	resp, err := c.ListEvents(context.TODO(), "", opts)
+	if err != nil {
+		return err
+	}
+	return formatEventOutput(out, resp)
+}
+
+func formatEventOutput(out io.Writer, records []v1.EventRecord) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(records)
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"ID", "Type", "Source", "Origin", "Deliveries", "Failed", "Recorded date"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, r := range records {
+		if r.Event == nil {
+			continue
+		}
+		failed := 0
+		for _, d := range r.Deliveries {
+			if d.Error != "" {
+				failed++
+			}
+		}
+		table.Append([]string{
+			r.Event.EventID, r.Event.EventType, r.Event.Source, r.Origin,
+			fmt.Sprintf("%d", len(r.Deliveries)), fmt.Sprintf("%d", failed),
+			time.Unix(r.RecordedTime, 0).Local().Format(time.UnixDate),
+		})
+	}
+	table.Render()
+	return nil
+}
@@ -7,6 +7,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"time"
 
@@ -22,8 +23,14 @@ import (
 var (
 	getFunctionLong = i18n.T(`Get function(s).`)
 
-	// TODO: add examples
-	getFunctionExample = i18n.T(``)
+	getFunctionExample = i18n.T(`
+# Get all functions
+dispatch get functions
+
+# Get the output of the latest image build of a function
+dispatch get function hello --build-logs
+`)
+	getFunctionBuildLogs = false
 )
 
 // NewCmdGetFunction creates command responsible for getting functions.
@@ -47,12 +54,17 @@ func NewCmdGetFunction(out io.Writer, errOut io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	cmd.Flags().BoolVar(&getFunctionBuildLogs, "build-logs", false, "show the output of the latest image build of the function")
 	return cmd
 }
 
 func getFunction(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
 	functionName := args[0]
 
+	if getFunctionBuildLogs {
+		return getFunctionBuild(out, functionName, c)
+	}
+
 	resp, err := c.GetFunction(context.TODO(), dispatchConfig.Organization, functionName)
 	if err != nil {
 		return err
@@ -61,6 +73,35 @@ func getFunction(out, errOut io.Writer, cmd *cobra.Command, args []string, c cli
 	return formatFunctionOutput(out, false, []v1.Function{*resp})
 }
 
+func getFunctionBuild(out io.Writer, functionName string, c client.FunctionsClient) error {
+	builds, err := c.ListFunctionBuilds(context.TODO(), dispatchConfig.Organization, functionName)
+	if err != nil {
+		return err
+	}
+	if len(builds) == 0 {
+		return fmt.Errorf("function %s has no builds", functionName)
+	}
+	return formatFunctionBuildOutput(out, builds[0])
+}
+
+func formatFunctionBuildOutput(out io.Writer, build v1.FunctionBuild) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(build)
+	}
+	status := string(build.Status)
+	if build.Cached {
+		status += ", cached"
+	}
+	fmt.Fprintf(out, "Build %s of function %s (%s) at %s\n", build.Name, build.FunctionName, status, time.Unix(build.CreatedTime, 0).Local().Format(time.UnixDate))
+	for _, reason := range build.Reason {
+		fmt.Fprintf(out, "Reason: %s\n", reason)
+	}
+	_, err := io.WriteString(out, build.Logs)
+	return err
+}
+
 func getFunctions(out, errOut io.Writer, cmd *cobra.Command, c client.FunctionsClient) error {
 	resp, err := c.ListFunctions(context.TODO(), dispatchConfig.Organization)
 	if err != nil {
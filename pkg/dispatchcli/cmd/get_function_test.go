@@ -0,0 +1,42 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client/mocks"
+)
+
+func TestGetFunctionBuildLogs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	fc.On("ListFunctionBuilds", mock.Anything, mock.Anything, "hello").Return([]v1.FunctionBuild{
+		{
+			Name:         "build-2",
+			FunctionName: "hello",
+			Status:       v1.StatusERROR,
+			Reason:       []string{"failed to build image"},
+			Logs:         "Step 1/2 : FROM python3\nCould not find a version that satisfies the requirement\n",
+		},
+		{
+			Name:         "build-1",
+			FunctionName: "hello",
+			Status:       v1.StatusREADY,
+		},
+	}, nil)
+
+	err := getFunctionBuild(buf, "hello", fc)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^Build build-2 of function hello \(ERROR\)`, buf.String())
+	assert.Contains(t, buf.String(), "Reason: failed to build image\nStep 1/2 : FROM python3\nCould not find a version")
+	assert.NotContains(t, buf.String(), "build-1")
+	fc.AssertExpectations(t)
+}
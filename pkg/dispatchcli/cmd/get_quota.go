@@ -0,0 +1,117 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getQuotasLong = i18n.T(`Get quotas, with their limits and the runs currently counted against them.`)
+
+	getQuotasExample = i18n.T(`
+# Get all quotas
+dispatch get quotas
+
+# Get a specific quota
+dispatch get quota report-concurrency
+`)
+)
+
+// NewCmdGetQuota creates command responsible for getting quotas.
+func NewCmdGetQuota(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "quota [QUOTA]",
+		Short:   i18n.T("Get quotas"),
+		Long:    getQuotasLong,
+		Example: getQuotasExample,
+		Args:    cobra.MaximumNArgs(1),
+		Aliases: []string{"quotas"},
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			c := functionManagerClient()
+			if len(args) > 0 {
+				err = getQuota(out, errOut, cmd, args, c)
+			} else {
+				err = getQuotas(out, errOut, cmd, c)
+			}
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	return cmd
+}
+
+func getQuota(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	resp, err := c.GetQuota(context.TODO(), "", args[0])
+	if err != nil {
+		return err
+	}
+	return formatQuotaOutput(out, false, []v1.Quota{*resp})
+}
+
+func getQuotas(out, errOut io.Writer, cmd *cobra.Command, c client.FunctionsClient) error {
+	resp, err := c.ListQuotas(context.TODO(), "")
+	if err != nil {
+		return err
+	}
+	return formatQuotaOutput(out, true, resp)
+}
+
+// formatLimit formats a quota limit, 0 meaning no limit
+func formatLimit(limit string) string {
+	if limit == "0" {
+		return "-"
+	}
+	return limit
+}
+
+func formatQuotaOutput(out io.Writer, list bool, quotas []v1.Quota) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(quotas)
+		}
+		return encoder.Encode(quotas[0])
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Name", "Function", "Runs/s", "Concurrent", "Queued"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, q := range quotas {
+		function := q.Function
+		if function == "" {
+			function = "*"
+		}
+		concurrent := strconv.FormatInt(q.ConcurrentRuns, 10)
+		queued := strconv.FormatInt(q.QueuedRuns, 10)
+		if q.MaxConcurrentRuns > 0 {
+			concurrent = fmt.Sprintf("%d/%d", q.ConcurrentRuns, q.MaxConcurrentRuns)
+			queued = fmt.Sprintf("%d/%d", q.QueuedRuns, q.MaxQueuedRuns)
+		}
+		table.Append([]string{
+			*q.Name,
+			function,
+			formatLimit(strconv.FormatFloat(q.RunsPerSecond, 'g', -1, 64)),
+			concurrent,
+			queued,
+		})
+	}
+	table.Render()
+	return nil
+}
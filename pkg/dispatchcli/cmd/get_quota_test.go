@@ -0,0 +1,37 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+)
+
+func TestFormatQuotaOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	quotas := []v1.Quota{
+		{
+			Name:          swag.String("org-rate"),
+			RunsPerSecond: 2.5,
+		},
+		{
+			Name:              swag.String("report-concurrency"),
+			Function:          "report",
+			MaxConcurrentRuns: 2,
+			MaxQueuedRuns:     10,
+			ConcurrentRuns:    2,
+			QueuedRuns:        3,
+		},
+	}
+
+	assert.NoError(t, formatQuotaOutput(buf, true, quotas))
+	assert.Regexp(t, `org-rate\s+\| \*\s+\|\s+2.5 \|\s+0 \|\s+0`, buf.String())
+	assert.Regexp(t, `report-concurrency \| report\s+\|\s+- \| 2/2\s+\| 3/10`, buf.String())
+}
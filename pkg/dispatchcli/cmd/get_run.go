@@ -7,9 +7,11 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -41,10 +43,22 @@ dispatch get runs example-function f98d0a7f-0c1d-4020-a488-cabc501b08e0
 
 # Follow runs for a specific function
 dispatch get runs example-function --follow
+
+# Get the runs which failed in the last hour
+dispatch get runs --status ERROR --since 1h
+
+# Get statistics of the runs of each function in the last hour
+dispatch get runs --since 1h --stats
 `)
 
-	followRuns = false
-	last       = false
+	followRuns        = false
+	last              = false
+	runStatus         = ""
+	runErrorType      = ""
+	runSince          time.Duration
+	runMinDuration    time.Duration
+	runMaxDuration    time.Duration
+	runStatsRequested = false
 )
 
 // NewCmdGetRun creates command responsible for getting runs.
@@ -80,6 +94,12 @@ func NewCmdGetRun(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
 	cmd.Flags().BoolVarP(&followRuns, "follow", "f", false, "follow function runs, default: false")
 	cmd.Flags().BoolVar(&last, "last", false, "get last executed run, default: false")
+	cmd.Flags().StringVar(&runStatus, "status", "", "filter by run status (e.g. READY, ERROR)")
+	cmd.Flags().StringVar(&runErrorType, "error-type", "", "filter by error type (InputError, FunctionError or SystemError)")
+	cmd.Flags().DurationVar(&runSince, "since", 0, "only runs modified within the duration (e.g. 1h)")
+	cmd.Flags().DurationVar(&runMinDuration, "min-duration", 0, "only finished runs which took at least the duration")
+	cmd.Flags().DurationVar(&runMaxDuration, "max-duration", 0, "only finished runs which took at most the duration")
+	cmd.Flags().BoolVar(&runStatsRequested, "stats", false, "show the number of runs, error rate and duration percentiles of each function")
 	return cmd
 }
 
@@ -104,6 +124,16 @@ func getFunctionRun(out, errOut io.Writer, cmd *cobra.Command, opts client.Funct
 
 func getRuns(out, errOut io.Writer, cmd *cobra.Command, opts client.FunctionOpts, c client.FunctionsClient) error {
 	since := time.Now()
+	if runSince > 0 {
+		opts.Since = since.Add(-runSince)
+	}
+	if runStatsRequested {
+		return getRunStats(out, opts, c)
+	}
+	opts.Status = strings.ToUpper(runStatus)
+	opts.ErrorType = runErrorType
+	opts.MinDuration = runMinDuration
+	opts.MaxDuration = runMaxDuration
 	resp, err := c.ListRuns(context.TODO(), "", opts)
 
 	if err != nil {
@@ -189,7 +219,7 @@ func formatRunOutput(out io.Writer, list bool, header bool, runs []v1.Run) error
 	}
 	table := tablewriter.NewWriter(out)
 	if header {
-		table.SetHeader([]string{"ID", "Function", "Status", "Started", "Finished"})
+		table.SetHeader([]string{"ID", "Function", "Status", "Started", "Finished", "Trace"})
 	}
 	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
 	table.SetCenterSeparator("")
@@ -200,6 +230,39 @@ func formatRunOutput(out io.Writer, list bool, header bool, runs []v1.Run) error
 			string(run.Status),
 			time.Unix(run.ExecutedTime, 0).Local().Format(time.UnixDate),
 			time.Unix(run.FinishedTime, 0).Local().Format(time.UnixDate),
+			run.TraceID,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func getRunStats(out io.Writer, opts client.FunctionOpts, c client.FunctionsClient) error {
+	stats, err := c.GetRunStats(context.TODO(), "", opts)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(stats)
+	}
+	milliseconds := func(ms int64) string {
+		return (time.Duration(ms) * time.Millisecond).String()
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Function", "Runs", "Errors", "Error Rate", "P50", "P95", "P99"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, s := range stats {
+		table.Append([]string{
+			s.FunctionName,
+			fmt.Sprintf("%d", s.Count),
+			fmt.Sprintf("%d", s.ErrorCount),
+			fmt.Sprintf("%.1f%%", s.ErrorRate*100),
+			milliseconds(s.P50Duration),
+			milliseconds(s.P95Duration),
+			milliseconds(s.P99Duration),
 		})
 	}
 	table.Render()
@@ -0,0 +1,52 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/client/mocks"
+)
+
+func TestGetRunsFiltered(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	run := v1.Run{Name: "f98d0a7f-0c1d-4020-a488-cabc501b08e0", FunctionName: "hello", Status: v1.StatusERROR, TraceID: "5b8aa5a2d2c872e8"}
+	fc.On("ListRuns", mock.Anything, mock.Anything, mock.MatchedBy(func(opts client.FunctionOpts) bool {
+		return opts.Status == "ERROR" && time.Since(opts.Since) >= time.Hour && time.Since(opts.Since) < 2*time.Hour
+	})).Return([]v1.Run{run}, nil)
+
+	cmd := NewCmdGetRun(buf, buf)
+	cmd.Flags().Set("status", "error")
+	cmd.Flags().Set("since", "1h")
+	err := getRuns(buf, buf, cmd, client.FunctionOpts{}, fc)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), run.Name.String())
+	assert.Contains(t, buf.String(), run.TraceID)
+	fc.AssertExpectations(t)
+}
+
+func TestGetRunStats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	stats := []v1.RunStats{
+		{FunctionName: "hello", Count: 100, ErrorCount: 25, ErrorRate: 0.25, P50Duration: 50, P95Duration: 1500, P99Duration: 2000},
+	}
+	fc.On("GetRunStats", mock.Anything, mock.Anything, mock.Anything).Return(stats, nil)
+
+	cmd := NewCmdGetRun(buf, buf)
+	cmd.Flags().Set("stats", "true")
+	err := getRuns(buf, buf, cmd, client.FunctionOpts{}, fc)
+	assert.NoError(t, err)
+	assert.Regexp(t, `hello\s+\|\s+100 \|\s+25 \| 25.0%\s+\| 50ms \| 1.5s \| 2s`, buf.String())
+	fc.AssertExpectations(t)
+}
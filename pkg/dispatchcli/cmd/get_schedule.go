@@ -0,0 +1,108 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getSchedulesLong = i18n.T(`Get schedules, with the time they last fired and the time they fire next.`)
+
+	getSchedulesExample = i18n.T(`
+# Get all schedules
+dispatch get schedules
+
+# Get a specific schedule
+dispatch get schedule nightly-report
+`)
+)
+
+// NewCmdGetSchedule creates command responsible for getting schedules.
+func NewCmdGetSchedule(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule [SCHEDULE]",
+		Short:   i18n.T("Get schedules"),
+		Long:    getSchedulesLong,
+		Example: getSchedulesExample,
+		Args:    cobra.MaximumNArgs(1),
+		Aliases: []string{"schedules"},
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			c := eventManagerClient()
+			if len(args) > 0 {
+				err = getSchedule(out, errOut, cmd, args, c)
+			} else {
+				err = getSchedules(out, errOut, cmd, c)
+			}
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&cmdFlagApplication, "application", "a", "", "filter by application")
+	return cmd
+}
+
+func getSchedule(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.EventsClient) error {
+	resp, err := c.GetSchedule(context.TODO(), "", args[0])
+	if err != nil {
+		return err
+	}
+	return formatScheduleOutput(out, false, []v1.Schedule{*resp})
+}
+
+func getSchedules(out, errOut io.Writer, cmd *cobra.Command, c client.EventsClient) error {
+	resp, err := c.ListSchedules(context.TODO(), "")
+	if err != nil {
+		return err
+	}
+	return formatScheduleOutput(out, true, resp)
+}
+
+func formatScheduleOutput(out io.Writer, list bool, schedules []v1.Schedule) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(schedules)
+		}
+		return encoder.Encode(schedules[0])
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Name", "Cron", "Timezone", "Target", "Status", "Last fire", "Next fire", "Last run"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, s := range schedules {
+		timezone := s.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		target := "function " + s.Function
+		if s.EventType != "" {
+			target = "event " + s.EventType
+		}
+		table.Append([]string{
+			*s.Name,
+			*s.Cron,
+			timezone,
+			target,
+			string(s.Status),
+			formatTimestamp(s.LastFireTime),
+			formatTimestamp(s.NextFireTime),
+			s.LastRun,
+		})
+	}
+	table.Render()
+	return nil
+}
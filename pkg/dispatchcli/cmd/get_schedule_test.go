@@ -0,0 +1,45 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+)
+
+func TestFormatScheduleOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	next := time.Date(2018, time.March, 15, 2, 0, 0, 0, time.UTC)
+	schedules := []v1.Schedule{
+		{
+			Name:         swag.String("nightly-report"),
+			Cron:         swag.String("0 2 * * *"),
+			Function:     "report",
+			Status:       v1.StatusREADY,
+			LastFireTime: next.Add(-24 * time.Hour).Unix(),
+			NextFireTime: next.Unix(),
+			LastRun:      "9f2f2b2e-0000-4000-8000-000000000001",
+		},
+		{
+			Name:      swag.String("cleanup"),
+			Cron:      swag.String("*/15 * * * *"),
+			Timezone:  "Europe/Paris",
+			EventType: "cleanup.requested",
+			Status:    v1.StatusCREATING,
+		},
+	}
+
+	assert.NoError(t, formatScheduleOutput(buf, true, schedules))
+	assert.Regexp(t, `nightly-report \| 0 2 \* \* \*\s+\| UTC\s+\| function report\s+\| READY`, buf.String())
+	assert.Contains(t, buf.String(), formatTimestamp(next.Unix()))
+	assert.Contains(t, buf.String(), "9f2f2b2e-0000-4000-8000-000000000001")
+	assert.Regexp(t, `cleanup\s+\| \*/15 \* \* \* \* \| Europe/Paris \| event cleanup.requested \| CREATING`, buf.String())
+}
@@ -0,0 +1,102 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getWorkflowLong = i18n.T(`Get workflow(s).`)
+
+	getWorkflowExample = i18n.T(`
+# Get all workflows
+dispatch get workflows
+
+# Get a specific workflow
+dispatch get workflow process-order
+`)
+)
+
+// NewCmdGetWorkflow creates command responsible for getting workflows.
+func NewCmdGetWorkflow(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workflow [WORKFLOW_NAME]",
+		Short:   i18n.T("Get workflow(s)"),
+		Long:    getWorkflowLong,
+		Example: getWorkflowExample,
+		Args:    cobra.RangeArgs(0, 1),
+		Aliases: []string{"workflows"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			var err error
+			if len(args) > 0 {
+				err = getWorkflow(out, errOut, cmd, args, c)
+			} else {
+				err = getWorkflows(out, errOut, cmd, c)
+			}
+			CheckErr(err)
+		},
+	}
+	return cmd
+}
+
+func getWorkflow(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	resp, err := c.GetWorkflow(context.TODO(), dispatchConfig.Organization, args[0])
+	if err != nil {
+		return err
+	}
+	return formatWorkflowOutput(out, false, []v1.Workflow{*resp})
+}
+
+func getWorkflows(out, errOut io.Writer, cmd *cobra.Command, c client.FunctionsClient) error {
+	resp, err := c.ListWorkflows(context.TODO(), dispatchConfig.Organization)
+	if err != nil {
+		return err
+	}
+	return formatWorkflowOutput(out, true, resp)
+}
+
+func formatWorkflowOutput(out io.Writer, list bool, workflows []v1.Workflow) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(workflows)
+		}
+		return encoder.Encode(workflows[0])
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Name", "Start At", "States", "Status", "Created Date"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, w := range workflows {
+		startAt := ""
+		if w.StartAt != nil {
+			startAt = *w.StartAt
+		}
+		table.Append([]string{
+			*w.Name,
+			startAt,
+			strconv.Itoa(len(w.States)),
+			string(w.Status),
+			time.Unix(w.CreatedTime, 0).Local().Format(time.UnixDate),
+		})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,147 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	getWorkflowRunLong = i18n.T(`Get workflow run(s). When getting a single run, the steps executed so far are shown in order.`)
+
+	getWorkflowRunExample = i18n.T(`
+# Get all workflow runs
+dispatch get workflowruns
+
+# Get the runs of a specific workflow
+dispatch get workflowruns --workflow process-order
+
+# Get a specific workflow run, step by step
+dispatch get workflowrun f98d0a7f-0c1d-4020-a488-cabc501b08e0
+`)
+
+	getWorkflowRunWorkflow = ""
+)
+
+// NewCmdGetWorkflowRun creates command responsible for getting workflow runs.
+func NewCmdGetWorkflowRun(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workflowrun [RUN_ID]",
+		Short:   i18n.T("Get workflow run(s)"),
+		Long:    getWorkflowRunLong,
+		Example: getWorkflowRunExample,
+		Args:    cobra.RangeArgs(0, 1),
+		Aliases: []string{"workflowruns"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			var err error
+			if len(args) > 0 {
+				err = getWorkflowRun(out, errOut, cmd, args, c)
+			} else {
+				err = getWorkflowRuns(out, errOut, cmd, c)
+			}
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVar(&getWorkflowRunWorkflow, "workflow", "", "filter by workflow")
+	return cmd
+}
+
+func getWorkflowRun(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	resp, err := c.GetWorkflowRun(context.TODO(), dispatchConfig.Organization, args[0])
+	if err != nil {
+		return err
+	}
+	return formatWorkflowRunOutput(out, false, []v1.WorkflowRun{*resp})
+}
+
+func getWorkflowRuns(out, errOut io.Writer, cmd *cobra.Command, c client.FunctionsClient) error {
+	var workflowName *string
+	if getWorkflowRunWorkflow != "" {
+		workflowName = &getWorkflowRunWorkflow
+	}
+	resp, err := c.ListWorkflowRuns(context.TODO(), dispatchConfig.Organization, workflowName)
+	if err != nil {
+		return err
+	}
+	return formatWorkflowRunOutput(out, true, resp)
+}
+
+func formatWorkflowRunOutput(out io.Writer, list bool, runs []v1.WorkflowRun) error {
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		if list {
+			return encoder.Encode(runs)
+		}
+		return encoder.Encode(runs[0])
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"ID", "Workflow", "Status", "Started", "Finished"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetCenterSeparator("")
+	for _, run := range runs {
+		table.Append([]string{
+			run.Name.String(),
+			run.WorkflowName,
+			string(run.Status),
+			formatTimestamp(run.ExecutedTime),
+			formatTimestamp(run.FinishedTime),
+		})
+	}
+	table.Render()
+	if list {
+		return nil
+	}
+
+	run := runs[0]
+	if run.Error != "" {
+		fmt.Fprintf(out, "\nError: %s\n", run.Error)
+	}
+	fmt.Fprintf(out, "\nSteps:\n")
+	steps := tablewriter.NewWriter(out)
+	steps.SetHeader([]string{"#", "State", "Type", "Branch", "Attempt", "Status", "Function Run", "Error"})
+	steps.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	steps.SetCenterSeparator("")
+	for i, step := range run.Steps {
+		attempt := ""
+		if step.Attempt > 0 {
+			attempt = strconv.FormatInt(step.Attempt, 10)
+		}
+		steps.Append([]string{
+			strconv.Itoa(i + 1),
+			step.State,
+			step.Type,
+			step.Branch,
+			attempt,
+			string(step.Status),
+			step.FunctionRun,
+			step.Error,
+		})
+	}
+	steps.Render()
+	return nil
+}
+
+// formatTimestamp formats a unix timestamp, zero meaning not set (yet)
+func formatTimestamp(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(t, 0).Local().Format(time.UnixDate)
+}
@@ -0,0 +1,41 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client/mocks"
+)
+
+func TestGetWorkflowRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	run := &v1.WorkflowRun{
+		Name:         "f98d0a7f-0c1d-4020-a488-cabc501b08e0",
+		WorkflowName: "process-order",
+		Status:       v1.StatusERROR,
+		Error:        "card declined",
+		Steps: []*v1.WorkflowStep{
+			{State: "validate", Type: "task", Attempt: 1, Status: v1.StatusREADY, FunctionRun: "a3b1e1c2-0000-4000-8000-000000000001"},
+			{State: "charge", Type: "task", Attempt: 1, Status: v1.StatusERROR, Error: "card declined"},
+		},
+	}
+	fc.On("GetWorkflowRun", mock.Anything, mock.Anything, run.Name.String()).Return(run, nil)
+
+	cmd := NewCmdGetWorkflowRun(buf, buf)
+	err := getWorkflowRun(buf, buf, cmd, []string{run.Name.String()}, fc)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "process-order")
+	assert.Contains(t, buf.String(), "Error: card declined")
+	assert.Regexp(t, `1 \| validate \| task \|\s+\|\s+1 \| READY\s+\| a3b1e1c2`, buf.String())
+	assert.Regexp(t, `2 \| charge\s+\| task \|\s+\|\s+1 \| ERROR\s+\|\s+\| card declined`, buf.String())
+	fc.AssertExpectations(t)
+}
@@ -199,7 +199,7 @@ type dispatchInstallConfig struct {
 	OAuth2Proxy     *oauth2ProxyConfig    `json:"oauth2Proxy,omitempty" validate:"required"`
 	TLS             *tlsConfig            `json:"tls,omitempty" validate:"required"`
 	SkipAuth        bool                  `json:"skipAuth,omitempty" validate:"omitempty"`
-	Faas            string                `json:"faas,omitempty" validate:"required,eq=openfaas|eq=riff|eq=kubeless"`
+	Faas            string                `json:"faas,omitempty" validate:"required,eq=openfaas|eq=riff|eq=kubeless|eq=kubernetes"`
 	EventTransport  string                `json:"eventTransport,omitempty" validate:"required,eq=kafka|eq=rabbitmq"`
 	Service         *serviceCatalogConfig `json:"service,omitemtpy" validate:"required"`
 }
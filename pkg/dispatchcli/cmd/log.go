@@ -32,6 +32,12 @@ dispatch log event-manager -f -t 10
 
 # Display last 100 lines of "identity-manager" container in identity manager
 dispatch log identity-manager identity-manager -t 100
+
+# Display the logs of a function run
+dispatch log run example-function f98d0a7f-0c1d-4020-a488-cabc501b08e0
+
+# Display the logs of all runs of a function and keep following the runs as they complete
+dispatch log function example-function -f
 `)
 
 	components = map[string]bool{
@@ -68,6 +74,8 @@ func NewCmdLog(out, errOut io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Following logs")
 	cmd.Flags().IntVarP(&tail, "tail", "t", -1, "Lines of recent log file to display. -1 means display all")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "dispatch", "Namespace of Dispatch install.")
+	cmd.AddCommand(NewCmdLogRun(out, errOut))
+	cmd.AddCommand(NewCmdLogFunction(out, errOut))
 	return cmd
 }
 
@@ -0,0 +1,117 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	logRunLong = i18n.T(`Get stdout/stderr of a function run. The logs of the function are available once it returns, they
+can't be followed while it runs.`)
+
+	logRunExample = i18n.T(`
+# Display the logs of a run
+dispatch log run example-function f98d0a7f-0c1d-4020-a488-cabc501b08e0
+`)
+
+	logFunctionLong = i18n.T(`Get stdout/stderr of the recent runs of a function, each line is prefixed with the run name.
+The logs of a run are available once its function returns.`)
+
+	logFunctionExample = i18n.T(`
+# Display the buffered logs of all runs of a function
+dispatch log function example-function
+
+# Display the logs of all runs of a function and keep following the logs of the runs as they complete
+dispatch log function example-function -f
+`)
+)
+
+// NewCmdLogRun creates a command object for function run logs
+func NewCmdLogRun(out, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     i18n.T(`run FUNCTION_NAME RUN_ID`),
+		Short:   i18n.T(`Get logs for a function run`),
+		Long:    logRunLong,
+		Example: logRunExample,
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := logRun(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	return cmd
+}
+
+// NewCmdLogFunction creates a command object for the aggregated logs of a function
+func NewCmdLogFunction(out, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     i18n.T(`function FUNCTION_NAME [-f]`),
+		Short:   i18n.T(`Get logs for all runs of a function`),
+		Long:    logFunctionLong,
+		Example: logFunctionExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			c := functionManagerClient()
+			err := logFunction(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow the logs of runs as they complete")
+	return cmd
+}
+
+// interruptibleContext returns a context cancelled on SIGINT/SIGTERM, so that following logs ends cleanly
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, cancel
+}
+
+func logRun(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	opts := client.FunctionOpts{
+		FunctionName: &args[0],
+		RunName:      &args[1],
+	}
+	return c.GetRunLogs(context.Background(), "", opts, func(line v1.LogLine) {
+		fmt.Fprintln(out, line.Text)
+	})
+}
+
+func logFunction(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.FunctionsClient) error {
+	ctx, cancel := interruptibleContext()
+	defer cancel()
+
+	err := c.GetFunctionLogs(ctx, "", args[0], follow, func(line v1.LogLine) {
+		fmt.Fprintf(out, "[%s] %s\n", line.RunName, line.Text)
+	})
+	if ctx.Err() != nil {
+		// interrupted while following
+		return nil
+	}
+	return err
+}
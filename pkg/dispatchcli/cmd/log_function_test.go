@@ -0,0 +1,51 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/client/mocks"
+)
+
+func TestLogRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	fc.On("GetRunLogs", mock.Anything, "", mock.AnythingOfType("client.FunctionOpts"), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		opts := args.Get(2).(client.FunctionOpts)
+		assert.Equal(t, "hello", *opts.FunctionName)
+		handler := args.Get(3).(func(v1.LogLine))
+		handler(v1.LogLine{RunName: *opts.RunName, Stream: "stdout", Text: "first"})
+		handler(v1.LogLine{RunName: *opts.RunName, Stream: "stderr", Text: "second"})
+	})
+
+	cmd := NewCmdLogRun(buf, buf)
+	err := logRun(buf, buf, cmd, []string{"hello", "f98d0a7f-0c1d-4020-a488-cabc501b08e0"}, fc)
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", buf.String())
+	fc.AssertExpectations(t)
+}
+
+func TestLogFunction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fc := &mocks.FunctionsClient{}
+	fc.On("GetFunctionLogs", mock.Anything, "", "hello", false, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		handler := args.Get(4).(func(v1.LogLine))
+		handler(v1.LogLine{FunctionName: "hello", RunName: "run-1", Stream: "stdout", Text: "first"})
+		handler(v1.LogLine{FunctionName: "hello", RunName: "run-2", Stream: "stdout", Text: "second"})
+	})
+
+	cmd := NewCmdLogFunction(buf, buf)
+	err := logFunction(buf, buf, cmd, []string{"hello"}, fc)
+	assert.NoError(t, err)
+	assert.Equal(t, "[run-1] first\n[run-2] second\n", buf.String())
+	fc.AssertExpectations(t)
+}
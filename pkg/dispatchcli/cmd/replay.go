@@ -0,0 +1,37 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	replayLong = i18n.T(`Replay recorded resources.`)
+
+	replayExample = i18n.T(`
+# Deliver again the events received in the last hour to a subscription
+dispatch replay events --subscription vm-created --since 1h`)
+)
+
+// NewCmdReplay creates a command object for the generic "replay" action.
+func NewCmdReplay(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "replay TYPE [flags]",
+		Short:   i18n.T("Replay recorded resources"),
+		Long:    replayLong,
+		Example: replayExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			runHelp(cmd, args)
+		},
+	}
+	cmd.AddCommand(NewCmdReplayEvents(out, errOut))
+	return cmd
+}
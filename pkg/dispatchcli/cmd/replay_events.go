@@ -0,0 +1,96 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
+)
+
+var (
+	replayEventsLong = i18n.T(`Deliver recorded events again to a subscription, running its function even if the events were
+already delivered to it. Events are selected by ID, or by the time they were recorded.`)
+
+	replayEventsExample = i18n.T(`
+# Replay the events of the subscription type recorded in the last hour
+dispatch replay events --subscription vm-created --since 1h
+
+# Replay the events recorded between 2 hours and 1 hour ago
+dispatch replay events --subscription vm-created --since 2h --until 1h
+
+# Replay specific events
+dispatch replay events --subscription vm-created f98d0a7f-0c1d-4020-a488-cabc501b08e0
+`)
+
+	replaySubscription = ""
+	replaySince        time.Duration
+	replayUntil        time.Duration
+)
+
+// NewCmdReplayEvents creates command responsible for replaying events.
+func NewCmdReplayEvents(out io.Writer, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "events --subscription SUBSCRIPTION [EVENT_ID...] | --since DURATION [--until DURATION]",
+		Short:   i18n.T("Replay recorded events to a subscription"),
+		Long:    replayEventsLong,
+		Example: replayEventsExample,
+		Aliases: []string{"event"},
+		Run: func(cmd *cobra.Command, args []string) {
+			c := eventManagerClient()
+			err := replayEvents(out, errOut, cmd, args, c)
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVar(&replaySubscription, "subscription", "", "subscription to deliver the events to")
+	cmd.Flags().DurationVar(&replaySince, "since", 0, "replay the events recorded within the duration (e.g. 1h)")
+	cmd.Flags().DurationVar(&replayUntil, "until", 0, "replay the events recorded before the duration (e.g. 10m)")
+	return cmd
+}
+
+func replayEvents(out, errOut io.Writer, cmd *cobra.Command, args []string, c client.EventsClient) error {
+	if replaySubscription == "" {
+		return errors.New("--subscription is required")
+	}
+	if len(args) == 0 && replaySince == 0 {
+		return errors.New("either event IDs or --since are required")
+	}
+	if len(args) > 0 && (replaySince > 0 || replayUntil > 0) {
+		return errors.New("event IDs and --since/--until are mutually exclusive, specify only one of them")
+	}
+
+	replay := &v1.EventReplay{
+		Subscription: &replaySubscription,
+		EventIds:     args,
+	}
+	opts := eventOpts(replaySince, replayUntil)
+	if !opts.Since.IsZero() {
+		replay.Since = opts.Since.Unix()
+	}
+	if !opts.Until.IsZero() {
+		replay.Until = opts.Until.Unix()
+	}
+	resp, err := c.ReplayEvents(context.TODO(), "", replay)
+	if err != nil {
+		return err
+	}
+	if dispatchConfig.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(resp)
+	}
+	fmt.Fprintf(out, "%d events accepted for replay to subscription %s\n", resp.Accepted, replaySubscription)
+	return nil
+}
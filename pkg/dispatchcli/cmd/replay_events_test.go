@@ -0,0 +1,72 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+)
+
+// replayEventsClient records the replay requests, other methods of the events client are not implemented
+type replayEventsClient struct {
+	client.EventsClient
+	replays []*v1.EventReplay
+}
+
+func (c *replayEventsClient) ReplayEvents(ctx context.Context, organizationID string, replay *v1.EventReplay) (*v1.EventReplay, error) {
+	c.replays = append(c.replays, replay)
+	resp := *replay
+	resp.Accepted = 2
+	return &resp, nil
+}
+
+func TestReplayEvents(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := &replayEventsClient{}
+
+	cmd := NewCmdReplayEvents(buf, buf)
+	cmd.Flags().Set("subscription", "vm-created")
+	cmd.Flags().Set("since", "2h")
+	cmd.Flags().Set("until", "1h")
+	defer func() {
+		replaySubscription, replaySince, replayUntil = "", 0, 0
+	}()
+	require.NoError(t, replayEvents(buf, buf, cmd, nil, c))
+	require.Len(t, c.replays, 1)
+	replay := c.replays[0]
+	assert.Equal(t, "vm-created", *replay.Subscription)
+	assert.InDelta(t, time.Now().Add(-2*time.Hour).Unix(), replay.Since, 5)
+	assert.InDelta(t, time.Now().Add(-time.Hour).Unix(), replay.Until, 5)
+	assert.Contains(t, buf.String(), "2 events accepted for replay to subscription vm-created")
+
+	// event IDs can't be combined with a time range
+	assert.Error(t, replayEvents(buf, buf, cmd, []string{"ev-1"}, c))
+	assert.Len(t, c.replays, 1)
+}
+
+func TestReplayEventsRequired(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := &replayEventsClient{}
+
+	cmd := NewCmdReplayEvents(buf, buf)
+	assert.Error(t, replayEvents(buf, buf, cmd, []string{"ev-1"}, c))
+	cmd.Flags().Set("subscription", "vm-created")
+	defer func() {
+		replaySubscription = ""
+	}()
+	assert.Error(t, replayEvents(buf, buf, cmd, nil, c))
+	assert.NoError(t, replayEvents(buf, buf, cmd, []string{"ev-1"}, c))
+	require.Len(t, c.replays, 1)
+	assert.Equal(t, []string{"ev-1"}, c.replays[0].EventIds)
+}
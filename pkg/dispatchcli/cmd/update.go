@@ -49,6 +49,7 @@ func NewCmdUpdate(out io.Writer, errOut io.Writer) *cobra.Command {
 				pkgUtils.APIKind:            CallUpdateAPI(apiClient),
 				pkgUtils.ApplicationKind:    CallUpdateApplication,
 				pkgUtils.BaseImageKind:      CallUpdateBaseImage(imgClient),
+				pkgUtils.ConfigMapKind:      CallUpdateConfigMap(fnClient),
 				pkgUtils.DriverKind:         CallUpdateDriver(eventClient),
 				pkgUtils.DriverTypeKind:     CallUpdateDriverType(eventClient),
 				pkgUtils.FunctionKind:       CallUpdateFunction(fnClient),
@@ -0,0 +1,27 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+)
+
+// CallUpdateConfigMap makes the API call to update a config map
+func CallUpdateConfigMap(c client.FunctionsClient) ModelAction {
+	return func(input interface{}) error {
+		configMap := input.(*v1.ConfigMap)
+
+		_, err := c.UpdateConfigMap(context.TODO(), "", configMap)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
@@ -42,6 +42,14 @@ func secretsClient(config *serverConfig) client.SecretsClient {
 
 }
 
+func eventsClient(config *serverConfig) client.EventsClient {
+	if config.EventManager != "" {
+		return client.NewEventsClient(config.EventManager, getAuth(), "")
+	}
+	return client.NewEventsClient(getLocalEndpoint(config), getAuth(), "")
+
+}
+
 func getAuth() runtime.ClientAuthInfoWriter {
 	return client.AuthWithToken("cookie")
 }
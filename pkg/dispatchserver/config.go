@@ -9,6 +9,11 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
+	"github.com/vmware/dispatch/pkg/event-manager/subscriptions"
+	"github.com/vmware/dispatch/pkg/events/transport"
+	"github.com/vmware/dispatch/pkg/function-manager"
 )
 
 // emptyRegistryAuth == echo -n '{"username":"","password":"","email":""}' | base64
@@ -31,6 +36,7 @@ type serverConfig struct {
 	FunctionManager string `mapstructure:"function-manager" json:"function-manager"`
 	ServiceManager  string `mapstructure:"service-manager" json:"service-manager"`
 	SecretsStore    string `mapstructure:"secrets-store" json:"secrets-store"`
+	EventManager    string `mapstructure:"event-manager" json:"event-manager"`
 
 	Host              string `mapstructure:"host" json:"host"`
 	Port              int    `mapstructure:"port" json:"port"`
@@ -40,6 +46,29 @@ type serverConfig struct {
 	TLSCertificate    string `mapstructure:"tls-certificate" json:"tls-certificate"`
 	TLSCertificateKey string `mapstrucutre:"tls-certificate-key" json:"tls-certificate-key"`
 
+	BlobStore        string `mapstructure:"blob-store" json:"blob-store"`
+	BlobStoreDir     string `mapstructure:"blob-store-dir" json:"blob-store-dir"`
+	S3Endpoint       string `mapstructure:"s3-endpoint" json:"s3-endpoint"`
+	S3Bucket         string `mapstructure:"s3-bucket" json:"s3-bucket"`
+	S3Region         string `mapstructure:"s3-region" json:"s3-region"`
+	S3AccessKey      string `mapstructure:"s3-access-key" json:"s3-access-key"`
+	S3SecretKey      string `mapstructure:"s3-secret-key" json:"s3-secret-key"`
+	PayloadThreshold int    `mapstructure:"payload-threshold" json:"payload-threshold"`
+
+	LogBufferRuns  int `mapstructure:"log-buffer-runs" json:"log-buffer-runs"`
+	LogBufferLines int `mapstructure:"log-buffer-lines" json:"log-buffer-lines"`
+
+	EventQueueSize   int    `mapstructure:"event-queue-size" json:"event-queue-size"`
+	EventQueuePolicy string `mapstructure:"event-queue-policy" json:"event-queue-policy"`
+
+	EventRetention    time.Duration `mapstructure:"event-retention" json:"event-retention"`
+	RedeliveryTimeout time.Duration `mapstructure:"redelivery-timeout" json:"redelivery-timeout"`
+
+	InvocationTokenKey string `mapstructure:"invocation-token-key" json:"invocation-token-key"`
+	InvocationEndpoint string `mapstructure:"invocation-endpoint" json:"invocation-endpoint"`
+
+	HookHosts []string `mapstructure:"hook-host" json:"hook-host"`
+
 	Tracer string `mapstructure:"tracer" json:"tracer"`
 	Debug  bool   `mapstructure:"debug" json:"debug"`
 
@@ -66,6 +95,7 @@ func configGlobalFlags(flags *pflag.FlagSet) {
 	flags.String("function-manager", "", "URL to Function Manager")
 	flags.String("service-manager", "", "URL to Service Manager")
 	flags.String("secrets-store", "", "URL to Secrets Store")
+	flags.String("event-manager", "", "URL to Event Manager")
 
 	flags.String("host", "127.0.0.1", "Host/IP to listen on")
 	flags.Int("port", 8080, "HTTP port to listen on")
@@ -75,6 +105,28 @@ func configGlobalFlags(flags *pflag.FlagSet) {
 	flags.String("tls-certificate-key", "", "Path to the certificate private key")
 	flags.Bool("enable-tls", false, "Enable TLS (HTTPS) listener.")
 
+	flags.String("blob-store", "none", "Blob store for large run inputs, outputs and logs (none, file or s3)")
+	flags.String("blob-store-dir", "./blobs", "Directory of the file blob store")
+	flags.String("s3-endpoint", "", "Endpoint of the S3-compatible blob store")
+	flags.String("s3-bucket", "dispatch", "Bucket of the S3-compatible blob store")
+	flags.String("s3-region", "us-east-1", "Region of the S3-compatible blob store")
+	flags.String("s3-access-key", "", "Access key of the S3-compatible blob store")
+	flags.String("s3-secret-key", "", "Secret key of the S3-compatible blob store")
+	flags.Int("payload-threshold", functionmanager.DefaultPayloadThreshold, "Size in bytes above which run inputs, outputs and logs are kept in the blob store")
+
+	flags.Int("log-buffer-runs", functionmanager.DefaultLogBufferRuns, "Number of function runs to keep logs for in memory")
+	flags.Int("log-buffer-lines", functionmanager.DefaultLogBufferLines, "Number of log lines to keep in memory per function run")
+
+	flags.Int("event-queue-size", transport.DefaultQueueSize, "Number of events queued in memory for every subscriber")
+	flags.String("event-queue-policy", string(transport.QueueBlock), "What publishing does when the queue of a subscriber is full (block or drop)")
+	flags.Duration("event-retention", eventlog.DefaultRetention, "Time emitted and received events are kept in the event log")
+	flags.Duration("redelivery-timeout", subscriptions.DefaultRedeliveryTimeout, "Time the delivery of an event to a subscription is retried before the event is published to the dead-letter topic of the subscription")
+
+	flags.String("invocation-token-key", "", "Path to the PEM-encoded RSA private key signing the invocation tokens of function runs, empty disables them")
+	flags.String("invocation-endpoint", "", "Dispatch API endpoint given to function runs with their invocation token")
+
+	flags.StringSlice("hook-host", nil, "Host the http-hook middleware of functions may call, can be repeated")
+
 	flags.String("tracer", "", "OpenTracing-compatible Tracer URL")
 	flags.Bool("debug", false, "Enable debugging logs")
 }
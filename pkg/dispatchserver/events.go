@@ -17,8 +17,10 @@ import (
 	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
 	"github.com/vmware/dispatch/pkg/entity-store"
 	"github.com/vmware/dispatch/pkg/event-manager"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions"
 	"github.com/vmware/dispatch/pkg/events/transport"
 )
@@ -60,18 +62,31 @@ func initEvents(config *serverConfig, store entitystore.EntityStore, fnClient cl
 	}
 	api := operations.NewEventManagerAPI(swaggerSpec)
 
-	eventTransport := transport.NewInMemory()
+	policy := transport.QueuePolicy(config.EventQueuePolicy)
+	if policy != transport.QueueBlock && policy != transport.QueueDrop {
+		log.Fatalf("Invalid event queue policy %s, must be %s or %s", policy, transport.QueueBlock, transport.QueueDrop)
+	}
+	eventTransport := transport.NewInMemory(
+		transport.OptInMemoryQueueSize(config.EventQueueSize),
+		transport.OptInMemoryQueuePolicy(policy),
+	)
+
+	eventLog := eventlog.NewLog(store, eventTransport, config.EventRetention)
+	eventLog.Start()
 
-	subManager, err := subscriptions.NewManager(eventTransport, fnClient)
+	subManager, err := subscriptions.NewManager(eventTransport, fnClient, eventLog, config.RedeliveryTimeout)
 	if err != nil {
 		log.Fatalf("Error creating Event Subscription Manager: %v", err)
 	}
+	scheduler := schedules.NewScheduler(store, eventTransport, fnClient, eventLog)
 	// event controller
 	eventController := eventmanager.NewEventController(
 		subManager,
+		scheduler,
 		// TODO: add backend for event drivers in docker
 		nil,
 		store,
+		eventLog,
 		eventmanager.EventControllerConfig{
 			ResyncPeriod: config.ResyncPeriod,
 		},
@@ -80,16 +95,20 @@ func initEvents(config *serverConfig, store entitystore.EntityStore, fnClient cl
 	eventController.Start()
 	// handler
 	handlers := &eventmanager.Handlers{
-		Store:         store,
-		Transport:     eventTransport,
-		Watcher:       eventController.Watcher(),
-		SecretsClient: secretsClient,
+		Store:               store,
+		Transport:           eventTransport,
+		Watcher:             eventController.Watcher(),
+		SecretsClient:       secretsClient,
+		EventLog:            eventLog,
+		SubscriptionManager: subManager,
 	}
 
 	handlers.ConfigureHandlers(api)
 
 	return api.Serve(nil), func() {
 		eventController.Shutdown()
+		scheduler.Shutdown()
+		eventLog.Shutdown()
 		eventTransport.Close()
 	}
 }
@@ -15,17 +15,21 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/vmware/dispatch/pkg/blobstore"
 	"github.com/vmware/dispatch/pkg/client"
 	"github.com/vmware/dispatch/pkg/dispatchcli/i18n"
 	"github.com/vmware/dispatch/pkg/entity-store"
 	"github.com/vmware/dispatch/pkg/function-manager"
 	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi"
 	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
 	"github.com/vmware/dispatch/pkg/functions"
 	"github.com/vmware/dispatch/pkg/functions/docker"
 	"github.com/vmware/dispatch/pkg/functions/injectors"
+	"github.com/vmware/dispatch/pkg/functions/middleware"
 	"github.com/vmware/dispatch/pkg/functions/runner"
 	"github.com/vmware/dispatch/pkg/functions/validator"
+	"github.com/vmware/dispatch/pkg/invocation"
 	"github.com/vmware/dispatch/pkg/utils"
 )
 
@@ -53,8 +57,9 @@ func runFunctions(config *serverConfig) {
 	secrets := secretsClient(config)
 	services := servicesClient(config)
 	images := imagesClient(config)
+	events := eventsClient(config)
 
-	fnHandler, shutdown := initFunctions(config, store, docker, images, secrets, services)
+	fnHandler, shutdown := initFunctions(config, store, docker, images, secrets, services, events)
 	defer shutdown()
 
 	handler := addMiddleware(fnHandler)
@@ -68,7 +73,7 @@ func runFunctions(config *serverConfig) {
 
 func initFunctions(
 	config *serverConfig, store entitystore.EntityStore, dockerclient dockerclient.CommonAPIClient, imagesClient client.ImagesClient,
-	secretsClient client.SecretsClient, servicesClient client.ServicesClient) (http.Handler, func()) {
+	secretsClient client.SecretsClient, servicesClient client.ServicesClient, eventsClient client.EventsClient) (http.Handler, func()) {
 	swaggerSpec, err := loads.Analyzed(restapi.FlatSwaggerJSON, "2.0")
 	if err != nil {
 		log.Fatalln(err)
@@ -76,17 +81,35 @@ func initFunctions(
 
 	api := operations.NewFunctionManagerAPI(swaggerSpec)
 
+	blobs, err := blobstore.New(config.BlobStore, config.BlobStoreDir, blobstore.S3Config{
+		Endpoint:  config.S3Endpoint,
+		Bucket:    config.S3Bucket,
+		Region:    config.S3Region,
+		AccessKey: config.S3AccessKey,
+		SecretKey: config.S3SecretKey,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if blobs != nil {
+		store = functionmanager.NewPayloadStore(store, blobs, config.PayloadThreshold)
+	}
+
 	faas := docker.New(dockerclient)
 
 	c := &functionmanager.ControllerConfig{
-		ResyncPeriod: config.ResyncPeriod,
+		ResyncPeriod:    config.ResyncPeriod,
+		ReservedWorkers: functionmanager.DefaultReservedWorkers,
 	}
 
+	middlewares := middleware.New(config.HookHosts)
 	r := runner.New(&runner.Config{
 		Faas:            faas,
 		Validator:       validator.New(),
 		SecretInjector:  injectors.NewSecretInjector(secretsClient),
 		ServiceInjector: injectors.NewServiceInjector(secretsClient, servicesClient),
+		ConfigInjector:  injectors.NewConfigInjector(store),
+		Middlewares:     middlewares,
 	})
 
 	imageBuilder := functions.NewDockerImageBuilder(config.ImageRegistry, config.RegistryAuth, dockerclient)
@@ -95,10 +118,30 @@ func initFunctions(
 		imageBuilder.PullImages = false
 	}
 
-	controller := functionmanager.NewController(c, store, faas, r, imagesClient, imageBuilder)
+	logs := functionmanager.NewLogBuffer(config.LogBufferRuns, config.LogBufferLines)
+
+	limiter := quotas.NewLimiter(store)
+
+	callbacks := functionmanager.NewCallbacks(store, eventsClient, secretsClient)
+
+	var invocations *functionmanager.Invocations
+	if config.InvocationTokenKey != "" {
+		key, err := invocation.LoadPrivateKey(config.InvocationTokenKey)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		invocations = functionmanager.NewInvocations(key, config.InvocationEndpoint)
+	}
+
+	cancellations := functionmanager.NewCancellations()
+
+	controller := functionmanager.NewController(c, store, faas, r, imagesClient, imageBuilder, logs, limiter, callbacks, invocations, cancellations)
 	controller.Start()
 
-	handlers := functionmanager.NewHandlers(controller.Watcher(), store)
+	handlers := functionmanager.NewHandlers(controller.Watcher(), store, logs, limiter, middlewares, functionmanager.DefaultIdempotencyWindow)
+	handlers.Invocations = invocations
+	handlers.Callbacks = callbacks
+	handlers.Cancellations = cancellations
 	handlers.ConfigureHandlers(api)
 
 	return api.Serve(nil), func() {
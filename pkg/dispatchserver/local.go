@@ -50,13 +50,14 @@ func runLocal(config *serverConfig) {
 	secrets := secretsClient(config)
 	services := servicesClient(config)
 	images := imagesClient(config)
+	events := eventsClient(config)
 
 	secretsHandler := initSecrets(config, store)
 
 	imagesHandler, imagesShutdown := initImages(config, store)
 	defer imagesShutdown()
 
-	functionsHandler, functionsShutdown := initFunctions(config, store, docker, images, secrets, services)
+	functionsHandler, functionsShutdown := initFunctions(config, store, docker, images, secrets, services, events)
 	defer functionsShutdown()
 
 	gw, err := local.NewGateway(functions)
@@ -373,7 +373,7 @@ func makeListQuery(organizationID string, filter Filter, entityType reflect.Type
 	}
 
 	if filter != nil {
-		for _, fs := range filter.FilterStats() {
+		for i, fs := range filter.FilterStats() {
 			column := ""
 			object := ""
 			switch fs.Scope {
@@ -400,6 +400,8 @@ func makeListQuery(organizationID string, filter Filter, entityType reflect.Type
 				// the value is inside the JSONB field 'value'
 				column = fmt.Sprintf("value->>'%s'", object)
 			}
+			// the parameters are numbered, as a field may be filtered on twice, e.g. for a time range
+			object = fmt.Sprintf("%s_%d", object, i)
 			argsMap[object] = fs.Object
 
 			switch fs.Verb {
@@ -405,6 +405,7 @@ func testListWithFilterOnTags(t *testing.T, es EntityStore) {
 
 func testListWithFilter(t *testing.T, es EntityStore) {
 
+	testTimeStart := time.Now().Add(-time.Second)
 	testTimeBeforeEntity := &testEntity{
 		BaseEntity: BaseEntity{
 			OrganizationID: "testOrg",
@@ -472,6 +473,13 @@ func testListWithFilter(t *testing.T, es EntityStore) {
 	assert.Len(t, result, 1)
 	assert.Equal(t, "testTimeBefore", result[0].Name)
 
+	// a time range filters twice on the same field
+	filterTimeAfter := FilterStat{Scope: FilterScopeField, Subject: "CreatedTime", Verb: FilterVerbAfter, Object: testTimeStart}
+	err = es.List(context.Background(), "testOrg", Options{Filter: FilterEverything().Add(filterTimeAfter).Add(filterTimeBefore)}, &result)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "testTimeBefore", result[0].Name)
+
 	err = es.List(context.Background(), "testOrg", Options{Filter: FilterEverything().Add(filterEqualValue)}, &result)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
@@ -538,6 +546,21 @@ func testMixedTypes(t *testing.T, es EntityStore) {
 	assert.NoError(t, err, "Error clean up")
 }
 
+func TestMakeListQueryTimeRange(t *testing.T) {
+	since, until := time.Unix(1000, 0), time.Unix(2000, 0)
+	filter := FilterEverything().Add(
+		FilterStat{Scope: FilterScopeField, Subject: "ModifiedTime", Verb: FilterVerbAfter, Object: since},
+		FilterStat{Scope: FilterScopeField, Subject: "ModifiedTime", Verb: FilterVerbBefore, Object: until},
+	)
+	sql, args, err := makeListQuery("testOrg", filter, reflect.TypeOf(testEntity{}))
+	require.NoError(t, err)
+	assert.Contains(t, sql, "modified_time > ")
+	assert.Contains(t, sql, "modified_time < ")
+	// both ends of the range are bound, rather than the last one twice
+	assert.Contains(t, args, since)
+	assert.Contains(t, args, until)
+}
+
 func Test_getType(t *testing.T) {
 	var something interface{} = &BaseEntity{}
 
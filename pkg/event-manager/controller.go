@@ -11,6 +11,8 @@ import (
 	"github.com/vmware/dispatch/pkg/controller"
 	"github.com/vmware/dispatch/pkg/entity-store"
 	"github.com/vmware/dispatch/pkg/event-manager/drivers"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions"
 )
 
@@ -27,7 +29,7 @@ type EventControllerConfig struct {
 }
 
 // NewEventController creates a new controller to manage the reconciliation of event manager entities
-func NewEventController(manager subscriptions.Manager, backend drivers.Backend, store entitystore.EntityStore, config EventControllerConfig) controller.Controller {
+func NewEventController(manager subscriptions.Manager, scheduler schedules.Scheduler, backend drivers.Backend, store entitystore.EntityStore, eventLog *eventlog.Log, config EventControllerConfig) controller.Controller {
 	if config.WorkerNumber == 0 {
 		config.WorkerNumber = defaultWorkerNumber
 	}
@@ -42,8 +44,9 @@ func NewEventController(manager subscriptions.Manager, backend drivers.Backend,
 		ServiceName:  "events",
 	})
 
-	c.AddEntityHandler(drivers.NewEntityHandler(store, backend))
+	c.AddEntityHandler(drivers.NewEntityHandler(store, backend, eventLog))
 	c.AddEntityHandler(subscriptions.NewEntityHandler(store, manager))
+	c.AddEntityHandler(schedules.NewEntityHandler(store, scheduler))
 
 	return c
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/vmware/dispatch/pkg/entity-store"
 	mocks2 "github.com/vmware/dispatch/pkg/event-manager/drivers/mocks"
+	mocks3 "github.com/vmware/dispatch/pkg/event-manager/schedules/mocks"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/mocks"
 	helpers "github.com/vmware/dispatch/pkg/testing/api"
@@ -18,20 +19,22 @@ import (
 
 func TestControllerRun(t *testing.T) {
 	manager := &mocks.Manager{}
+	scheduler := &mocks3.Scheduler{}
 	k8sBackend := &mocks2.Backend{}
 	es := helpers.MakeEntityStore(t)
 
-	controller := NewEventController(manager, k8sBackend, es, EventControllerConfig{})
+	controller := NewEventController(manager, scheduler, k8sBackend, es, nil, EventControllerConfig{})
 	controller.Start()
 	controller.Shutdown()
 }
 
 func TestControllerRunWithSubs(t *testing.T) {
 	manager := &mocks.Manager{}
+	scheduler := &mocks3.Scheduler{}
 	k8sBackend := &mocks2.Backend{}
 	es := helpers.MakeEntityStore(t)
 
-	controller := NewEventController(manager, k8sBackend, es, EventControllerConfig{})
+	controller := NewEventController(manager, scheduler, k8sBackend, es, nil, EventControllerConfig{})
 	defer controller.Shutdown()
 	controller.Start()
 
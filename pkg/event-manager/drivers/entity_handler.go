@@ -17,20 +17,24 @@ import (
 	"github.com/vmware/dispatch/pkg/controller"
 	"github.com/vmware/dispatch/pkg/entity-store"
 	"github.com/vmware/dispatch/pkg/event-manager/drivers/entities"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
 	"github.com/vmware/dispatch/pkg/trace"
 )
 
 // EntityHandler handles driver entity operations
 type EntityHandler struct {
-	store   entitystore.EntityStore
-	backend Backend
+	store    entitystore.EntityStore
+	backend  Backend
+	eventLog *eventlog.Log
 }
 
-// NewEntityHandler creates new instance of EntityHandler
-func NewEntityHandler(store entitystore.EntityStore, backend Backend) *EntityHandler {
+// NewEntityHandler creates new instance of EntityHandler, the event log records the events ingested by the drivers
+// if not nil
+func NewEntityHandler(store entitystore.EntityStore, backend Backend, eventLog *eventlog.Log) *EntityHandler {
 	return &EntityHandler{
-		store:   store,
-		backend: backend,
+		store:    store,
+		backend:  backend,
+		eventLog: eventLog,
 	}
 }
 
@@ -63,6 +67,7 @@ func (h *EntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err er
 	}
 
 	driver.Status = entitystore.StatusREADY
+	h.listen(ctx, driver)
 
 	log.Infof("%s-driver %s has been deployed on k8s", driver.Type, driver.Name)
 
@@ -84,6 +89,7 @@ func (h *EntityHandler) Update(ctx context.Context, obj entitystore.Entity) (err
 
 	driver.Status = entitystore.StatusREADY
 	driver.SetReason([]string{})
+	h.listen(ctx, driver)
 
 	log.Infof("%s-driver %s has been updated", driver.Type, driver.Name)
 
@@ -173,6 +179,14 @@ func (h *EntityHandler) Error(ctx context.Context, obj entitystore.Entity) error
 	return err
 }
 
+// listen records the events published by the sidecars of the drivers of the organization, which has no access to the
+// event log. Ready drivers are updated on every sync, so the organizations of the drivers are listened to on restart.
+func (h *EntityHandler) listen(ctx context.Context, driver *entities.Driver) {
+	if err := h.eventLog.Listen(ctx, driver.OrganizationID); err != nil {
+		log.Errorf("error recording the events of driver %s: %+v", driver.Name, err)
+	}
+}
+
 func translateErrorToEntityState(driver *entities.Driver, e error) {
 	if e == nil {
 		return
@@ -0,0 +1,66 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package entities
+
+import (
+	"time"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/helpers"
+	"github.com/vmware/dispatch/pkg/events"
+)
+
+// NO TESTS
+
+// EventRecord struct represents an event recorded by the event manager. Its name is derived from the event ID, its
+// created time is the time it was recorded. The type and source of the event are copied so the store can filter on
+// them. Records are never updated, the outcomes of the deliveries of the event are stored apart.
+type EventRecord struct {
+	entitystore.BaseEntity
+	Topic     string            `json:"topic"`
+	Origin    string            `json:"origin"`
+	EventType string            `json:"eventType"`
+	Source    string            `json:"source"`
+	Event     events.CloudEvent `json:"event"`
+
+	// Deliveries are set when the record is read from the log
+	Deliveries []*Delivery `json:"-"`
+}
+
+// Delivery is the outcome of the delivery of an event to a subscription, recorded under the name of the event record
+// followed by a unique suffix
+type Delivery struct {
+	entitystore.BaseEntity
+	Record       string    `json:"record"`
+	Subscription string    `json:"subscription"`
+	Function     string    `json:"function"`
+	Run          string    `json:"run,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	Replay       bool      `json:"replay,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// ToModel converts event record to swagger model
+func (r *EventRecord) ToModel() *v1.EventRecord {
+	m := v1.EventRecord{
+		Event:        helpers.CloudEventToAPI(&r.Event),
+		Origin:       r.Origin,
+		RecordedTime: r.CreatedTime.Unix(),
+		Topic:        r.Topic,
+	}
+	for _, d := range r.Deliveries {
+		m.Deliveries = append(m.Deliveries, &v1.EventDelivery{
+			Subscription: d.Subscription,
+			Function:     d.Function,
+			Run:          d.Run,
+			Error:        d.Error,
+			Replay:       d.Replay,
+			Time:         d.Time.Unix(),
+		})
+	}
+	return &m
+}
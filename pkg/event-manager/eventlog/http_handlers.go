@@ -0,0 +1,178 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog/entities"
+	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
+	eventsapi "github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations/events"
+	subentities "github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// Replayer delivers recorded events to a subscription again
+type Replayer interface {
+	Matching(sub *subentities.Subscription, evs []*events.CloudEvent) ([]*events.CloudEvent, error)
+	Replay(ctx context.Context, sub *subentities.Subscription, evs []*events.CloudEvent) int
+}
+
+// Handlers is a base struct for event log API handlers.
+type Handlers struct {
+	store    entitystore.EntityStore
+	log      *Log
+	replayer Replayer
+}
+
+// NewHandlers Creates new instance of event log handlers
+func NewHandlers(store entitystore.EntityStore, log *Log, replayer Replayer) *Handlers {
+	return &Handlers{
+		store:    store,
+		log:      log,
+		replayer: replayer,
+	}
+}
+
+// ConfigureHandlers configures API handlers for the event log endpoints
+func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
+	a, ok := api.(*operations.EventManagerAPI)
+	if !ok {
+		panic("Cannot configure api")
+	}
+
+	a.EventsGetEventsHandler = eventsapi.GetEventsHandlerFunc(h.getEvents)
+	a.EventsReplayEventsHandler = eventsapi.ReplayEventsHandlerFunc(h.replayEvents)
+}
+
+// getEvents handles retrieval of recorded events
+func (h *Handlers) getEvents(params eventsapi.GetEventsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getEvents")
+	defer span.Finish()
+
+	query := Query{
+		EventType: swag.StringValue(params.Type),
+		Source:    swag.StringValue(params.Source),
+	}
+	if params.Since != nil {
+		query.Since = time.Unix(*params.Since, 0)
+	}
+	if params.Until != nil {
+		query.Until = time.Unix(*params.Until, 0)
+	}
+	records, err := h.log.Query(ctx, params.XDispatchOrg, query)
+	if err != nil {
+		log.Errorf("error when querying the event log: %+v", err)
+		return eventsapi.NewGetEventsDefault(http.StatusInternalServerError).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: swag.String("internal server error when getting events"),
+		})
+	}
+	models := []*v1.EventRecord{}
+	for _, r := range records {
+		models = append(models, r.ToModel())
+	}
+	return eventsapi.NewGetEventsOK().WithPayload(models)
+}
+
+// replayEvents handles the replay of recorded events to a subscription
+func (h *Handlers) replayEvents(params eventsapi.ReplayEventsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "replayEvents")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return eventsapi.NewReplayEventsBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+	if len(params.Body.EventIds) == 0 && params.Body.Since == 0 {
+		return eventsapi.NewReplayEventsBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String("either event IDs or a start time are required"),
+		})
+	}
+
+	name := *params.Body.Subscription
+	sub := &subentities.Subscription{}
+	opts := entitystore.Options{Filter: entitystore.FilterEverything()}
+	if err := h.store.Get(ctx, params.XDispatchOrg, name, opts, sub); err != nil {
+		log.Debugf("store error when getting subscription: %+v", err)
+		return eventsapi.NewReplayEventsNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("subscription", name),
+		})
+	}
+
+	var records []*entities.EventRecord
+	var err error
+	if len(params.Body.EventIds) > 0 {
+		records, err = h.log.Get(ctx, params.XDispatchOrg, params.Body.EventIds)
+		if err != nil {
+			return eventsapi.NewReplayEventsNotFound().WithPayload(&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: swag.String(err.Error()),
+			})
+		}
+	} else {
+		query := Query{EventType: sub.EventType, Since: time.Unix(params.Body.Since, 0)}
+		if params.Body.Until != 0 {
+			query.Until = time.Unix(params.Body.Until, 0)
+		}
+		records, err = h.log.Query(ctx, params.XDispatchOrg, query)
+		if err != nil {
+			log.Errorf("error when querying the event log: %+v", err)
+			return eventsapi.NewReplayEventsDefault(http.StatusInternalServerError).WithPayload(&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when replaying events"),
+			})
+		}
+	}
+
+	evs := make([]*events.CloudEvent, 0, len(records))
+	for _, r := range records {
+		ev := r.Event
+		// the subscription would not have received events of another type
+		if !events.MatchEventType(sub.EventType, ev.EventType) {
+			return eventsapi.NewReplayEventsBadRequest().WithPayload(&v1.Error{
+				Code: http.StatusBadRequest,
+				Message: swag.String(fmt.Sprintf("event %s of type %s doesn't match the event type %s of subscription %s",
+					ev.EventID, ev.EventType, sub.EventType, name)),
+			})
+		}
+		evs = append(evs, &ev)
+	}
+	matching, err := h.replayer.Matching(sub, evs)
+	if err != nil {
+		log.Errorf("error when matching the events to replay to subscription %s: %+v", name, err)
+		return eventsapi.NewReplayEventsDefault(http.StatusInternalServerError).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: swag.String("internal server error when replaying events"),
+		})
+	}
+
+	// the functions may take long to run, the events are replayed once the request is answered
+	go func() {
+		replaySpan, replayCtx := trace.FollowsFrom(context.Background(), "replayEvents", span.Context())
+		defer replaySpan.Finish()
+		replayed := h.replayer.Replay(replayCtx, sub, matching)
+		log.Infof("%d of %d events replayed to subscription %s", replayed, len(matching), name)
+	}()
+	params.Body.Accepted = int64(len(matching))
+	return eventsapi.NewReplayEventsAccepted().WithPayload(params.Body)
+}
@@ -0,0 +1,116 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package eventlog
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog/entities"
+	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
+	eventsapi "github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations/events"
+	subentities "github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
+	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/mocks"
+	"github.com/vmware/dispatch/pkg/events"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func TestEventsGetEventsHandler(t *testing.T) {
+	api := operations.NewEventManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	l := NewLog(es, nil, 0)
+	h := NewHandlers(es, l, nil)
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	ctx := context.Background()
+	ev := testEvent("ev-1", "vm.created", "vcenter")
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, ev))
+	require.NoError(t, l.Deliver(ctx, testOrgID, "ev-1", entities.Delivery{Subscription: "sub-1", Function: "hello", Run: "run-1"}))
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.deleted", v1.EventRecordOriginAPI, testEvent("ev-2", "vm.deleted", "vcenter")))
+
+	params := eventsapi.GetEventsParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/event?type=vm.created", nil),
+		Type:         swag.String("vm.created"),
+		XDispatchOrg: testOrgID,
+	}
+	var respBody []v1.EventRecord
+	helpers.HandlerRequest(t, api.EventsGetEventsHandler.Handle(params, "testCookie"), &respBody, 200)
+	require.Len(t, respBody, 1)
+	assert.Equal(t, "ev-1", respBody[0].Event.EventID)
+	assert.Equal(t, v1.EventRecordOriginAPI, respBody[0].Origin)
+	require.Len(t, respBody[0].Deliveries, 1)
+	assert.Equal(t, "run-1", respBody[0].Deliveries[0].Run)
+}
+
+func TestEventsReplayEventsHandler(t *testing.T) {
+	api := operations.NewEventManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	l := NewLog(es, nil, 0)
+	replayer := &mocks.Manager{}
+	h := NewHandlers(es, l, replayer)
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	ctx := context.Background()
+	_, err := es.Add(ctx, &subentities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1"},
+		EventType:  "vm.created",
+		Function:   "hello",
+	})
+	require.NoError(t, err)
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, testEvent("ev-1", "vm.created", "vcenter")))
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.deleted", v1.EventRecordOriginAPI, testEvent("ev-2", "vm.deleted", "vcenter")))
+
+	replay := func(body *v1.EventReplay, status int) *v1.EventReplay {
+		params := eventsapi.ReplayEventsParams{
+			HTTPRequest:  httptest.NewRequest("POST", "/v1/event/replay", nil),
+			Body:         body,
+			XDispatchOrg: testOrgID,
+		}
+		var respBody v1.EventReplay
+		helpers.HandlerRequest(t, api.EventsReplayEventsHandler.Handle(params, "testCookie"), &respBody, status)
+		return &respBody
+	}
+
+	// the events are replayed in the background
+	replayed := make(chan []*events.CloudEvent, 1)
+	replayer.On("Matching", mock.Anything, mock.Anything).Return(func(sub *subentities.Subscription, evs []*events.CloudEvent) []*events.CloudEvent {
+		return evs
+	}, nil)
+	replayer.On("Replay", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, sub *subentities.Subscription, evs []*events.CloudEvent) int {
+		replayed <- evs
+		return len(evs)
+	})
+
+	// a time range replays the events of the subscription type
+	resp := replay(&v1.EventReplay{Subscription: swag.String("sub-1"), Since: 1}, 202)
+	assert.EqualValues(t, 1, resp.Accepted)
+	evs := <-replayed
+	require.Len(t, evs, 1)
+	assert.Equal(t, "ev-1", evs[0].EventID)
+
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, testEvent("ev-3", "vm.created", "vcenter")))
+	resp = replay(&v1.EventReplay{Subscription: swag.String("sub-1"), EventIds: []string{"ev-3", "ev-1"}}, 202)
+	assert.EqualValues(t, 2, resp.Accepted)
+	evs = <-replayed
+	require.Len(t, evs, 2)
+	assert.Equal(t, "ev-3", evs[0].EventID)
+	assert.Equal(t, "ev-1", evs[1].EventID)
+
+	// the subscription would not have received events of another type
+	replay(&v1.EventReplay{Subscription: swag.String("sub-1"), EventIds: []string{"ev-2", "ev-1"}}, 400)
+	replay(&v1.EventReplay{Subscription: swag.String("sub-1")}, 400)
+	replay(&v1.EventReplay{Subscription: swag.String("sub-2"), Since: 1}, 404)
+	replay(&v1.EventReplay{Subscription: swag.String("sub-1"), EventIds: []string{"ev-4"}}, 404)
+	replayer.AssertExpectations(t)
+}
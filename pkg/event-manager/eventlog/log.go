@@ -0,0 +1,324 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package eventlog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog/entities"
+	"github.com/vmware/dispatch/pkg/events"
+)
+
+const (
+	// DefaultRetention is the default time events are kept in the log
+	DefaultRetention = 24 * time.Hour
+	// pruneInterval is the time between two removals of the events older than the retention
+	pruneInterval = 5 * time.Minute
+	// listenerName names the subscriptions of the log, so the events published while the event manager is down are
+	// recorded once it is up. Whether the replicas of the event manager share the events to record depends on the
+	// transport: with Kafka every replica receives every event, the records being named after the event IDs keeps
+	// a single record of each event.
+	listenerName = "dispatch-event-log"
+)
+
+// Log records the events emitted through the API, by schedules and by event drivers, and the outcomes of their
+// deliveries to subscriptions, in the entity store. Events older than the retention are removed. A nil Log records
+// nothing.
+type Log struct {
+	store     entitystore.EntityStore
+	transport events.Transport
+	retention time.Duration
+
+	// listeners are the subscriptions to all the events of the organizations listened to
+	listeners     map[string]events.Subscription
+	listenersLock sync.Mutex
+	done          chan struct{}
+}
+
+// Query selects recorded events, zero fields select every event
+type Query struct {
+	// EventType is an event type or a pattern, e.g. vm.*
+	EventType string
+	Source    string
+	Since     time.Time
+	Until     time.Time
+}
+
+// NewLog is the constructor for Log, listening to transport for the events published by others. A retention of 0
+// keeps events for DefaultRetention.
+func NewLog(store entitystore.EntityStore, transport events.Transport, retention time.Duration) *Log {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Log{
+		store:     store,
+		transport: transport,
+		retention: retention,
+		listeners: make(map[string]events.Subscription),
+		done:      make(chan struct{}),
+	}
+}
+
+// RecordName returns the name of the record of an event, event IDs aren't valid entity names
+func RecordName(eventID string) string {
+	sum := sha256.Sum256([]byte(eventID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Record records an event published to a topic, before it is published. An event already recorded is left as is,
+// e.g. an event emitted through the API is received from the transport too.
+func (l *Log) Record(ctx context.Context, organizationID, topic, origin string, event *events.CloudEvent) error {
+	if l == nil {
+		return nil
+	}
+	record := &entities.EventRecord{
+		BaseEntity: entitystore.BaseEntity{
+			OrganizationID: organizationID,
+			Name:           RecordName(event.EventID),
+			Status:         entitystore.StatusREADY,
+		},
+		Topic:     topic,
+		Origin:    origin,
+		EventType: event.EventType,
+		Source:    event.Source,
+		Event:     *event,
+	}
+	if _, err := l.store.Add(ctx, record); err != nil && !entitystore.IsUniqueViolation(err) {
+		return errors.Wrapf(err, "error recording event %s", event.EventID)
+	}
+	return nil
+}
+
+// Listen records the events published in an organization by others than the event manager, i.e. event drivers, as
+// they are published. Listening to an organization again does nothing.
+func (l *Log) Listen(ctx context.Context, organizationID string) error {
+	if l == nil {
+		return nil
+	}
+	l.listenersLock.Lock()
+	defer l.listenersLock.Unlock()
+	if _, ok := l.listeners[organizationID]; ok {
+		return nil
+	}
+	sub, err := l.transport.Subscribe(ctx, events.WildcardWords, organizationID, listenerName, func(ctx context.Context, event *events.CloudEvent) error {
+		return l.Record(ctx, organizationID, event.DefaultTopic(), v1.EventRecordOriginTransport, event)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error listening to the events of organization %s", organizationID)
+	}
+	l.listeners[organizationID] = sub
+	return nil
+}
+
+// Deliver records the outcome of the delivery of an event to a subscription, apart from the record of the event
+func (l *Log) Deliver(ctx context.Context, organizationID, eventID string, delivery entities.Delivery) error {
+	if l == nil {
+		return nil
+	}
+	record := RecordName(eventID)
+	delivery.BaseEntity = entitystore.BaseEntity{
+		OrganizationID: organizationID,
+		Name:           record + "-" + uuid.NewV4().String(),
+		Status:         entitystore.StatusREADY,
+	}
+	delivery.Record = record
+	_, err := l.store.Add(ctx, &delivery)
+	return errors.Wrapf(err, "error recording the delivery of event %s", eventID)
+}
+
+// Query returns the recorded events of an organization selected by the query, oldest first
+func (l *Log) Query(ctx context.Context, organizationID string, query Query) ([]*entities.EventRecord, error) {
+	if l == nil {
+		return nil, nil
+	}
+	filter := entitystore.FilterEverything()
+	if query.EventType != "" && !events.IsEventTypePattern(query.EventType) {
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeExtra,
+			Subject: "EventType",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  query.EventType,
+		})
+	}
+	if query.Source != "" {
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeExtra,
+			Subject: "Source",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  query.Source,
+		})
+	}
+	if !query.Since.IsZero() {
+		filter.Add(sinceFilter(query.Since))
+	}
+	if !query.Until.IsZero() {
+		// records are never updated, their modified time is their created time too
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "ModifiedTime",
+			Verb:    entitystore.FilterVerbBefore,
+			Object:  query.Until,
+		})
+	}
+	var records []*entities.EventRecord
+	if err := l.store.List(ctx, organizationID, entitystore.Options{Filter: filter}, &records); err != nil {
+		return nil, errors.Wrap(err, "error listing recorded events")
+	}
+	var selected []*entities.EventRecord
+	for _, r := range records {
+		// the store can't match patterns
+		if query.EventType == "" || events.MatchEventType(query.EventType, r.EventType) {
+			selected = append(selected, r)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].CreatedTime.Before(selected[j].CreatedTime)
+	})
+
+	// events are delivered after they are recorded
+	filter = entitystore.FilterEverything()
+	if !query.Since.IsZero() {
+		filter.Add(sinceFilter(query.Since))
+	}
+	if err := l.addDeliveries(ctx, organizationID, filter, selected); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// Get returns the recorded events of an organization with the given IDs, in order. It fails if one is not recorded.
+func (l *Log) Get(ctx context.Context, organizationID string, eventIDs []string) ([]*entities.EventRecord, error) {
+	if l == nil {
+		return nil, errors.New("events are not recorded")
+	}
+	var records []*entities.EventRecord
+	for _, id := range eventIDs {
+		record := &entities.EventRecord{}
+		opts := entitystore.Options{Filter: entitystore.FilterEverything()}
+		found, err := l.store.Find(ctx, organizationID, RecordName(id), opts, record)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting the record of event %s", id)
+		}
+		if !found {
+			return nil, errors.Errorf("event %s is not recorded", id)
+		}
+		filter := entitystore.FilterEverything().Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeExtra,
+			Subject: "Record",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  record.Name,
+		})
+		if err := l.addDeliveries(ctx, organizationID, filter, []*entities.EventRecord{record}); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Start removes the events older than the retention in the background, until Shutdown
+func (l *Log) Start() {
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for {
+			if err := l.Prune(context.Background()); err != nil {
+				log.Errorf("error pruning the event log: %+v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-l.done:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops removing old events and listening to organizations
+func (l *Log) Shutdown() {
+	close(l.done)
+	l.listenersLock.Lock()
+	defer l.listenersLock.Unlock()
+	for org, sub := range l.listeners {
+		sub.Unsubscribe()
+		delete(l.listeners, org)
+	}
+}
+
+// Prune removes the events and deliveries of all organizations older than the retention
+func (l *Log) Prune(ctx context.Context) error {
+	expiry := time.Now().Add(-l.retention)
+	filter := entitystore.FilterEverything().Add(entitystore.FilterStat{
+		Scope:   entitystore.FilterScopeField,
+		Subject: "CreatedTime",
+		Verb:    entitystore.FilterVerbBefore,
+		Object:  expiry,
+	})
+	var records []*entities.EventRecord
+	if err := l.store.ListGlobal(ctx, entitystore.Options{Filter: filter}, &records); err != nil {
+		return errors.Wrap(err, "error listing recorded events")
+	}
+	for _, r := range records {
+		if err := l.store.Delete(ctx, r.OrganizationID, r.Name, r); err != nil {
+			return errors.Wrapf(err, "error deleting the record of event %s", r.Event.EventID)
+		}
+	}
+	var deliveries []*entities.Delivery
+	if err := l.store.ListGlobal(ctx, entitystore.Options{Filter: filter}, &deliveries); err != nil {
+		return errors.Wrap(err, "error listing recorded deliveries")
+	}
+	for _, d := range deliveries {
+		if err := l.store.Delete(ctx, d.OrganizationID, d.Name, d); err != nil {
+			return errors.Wrapf(err, "error deleting the delivery %s", d.Name)
+		}
+	}
+	return nil
+}
+
+// addDeliveries sets the deliveries selected by filter of the records, in order
+func (l *Log) addDeliveries(ctx context.Context, organizationID string, filter entitystore.Filter, records []*entities.EventRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var deliveries []*entities.Delivery
+	if err := l.store.List(ctx, organizationID, entitystore.Options{Filter: filter}, &deliveries); err != nil {
+		return errors.Wrap(err, "error listing recorded deliveries")
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Time.Before(deliveries[j].Time)
+	})
+	byRecord := make(map[string]*entities.EventRecord)
+	for _, r := range records {
+		byRecord[r.Name] = r
+	}
+	for _, d := range deliveries {
+		if r, ok := byRecord[d.Record]; ok {
+			r.Deliveries = append(r.Deliveries, d)
+		}
+	}
+	return nil
+}
+
+// sinceFilter selects the entities created at or after since
+func sinceFilter(since time.Time) entitystore.FilterStat {
+	return entitystore.FilterStat{
+		Scope:   entitystore.FilterScopeField,
+		Subject: "CreatedTime",
+		Verb:    entitystore.FilterVerbAfter,
+		Object:  since.Add(-time.Nanosecond),
+	}
+}
@@ -0,0 +1,147 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package eventlog
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog/entities"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/events/transport"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+const testOrgID = "testOrg"
+
+func testEvent(id, eventType, source string) *events.CloudEvent {
+	return &events.CloudEvent{
+		EventID:            id,
+		EventType:          eventType,
+		Source:             source,
+		CloudEventsVersion: events.CloudEventsVersion,
+		EventTime:          time.Now(),
+	}
+}
+
+func TestLogRecordAndDeliver(t *testing.T) {
+	queue := transport.NewInMemory()
+	defer queue.Close()
+	l := NewLog(helpers.MakeEntityStore(t), queue, 0)
+	defer l.Shutdown()
+	ctx := context.Background()
+
+	emitted := testEvent("ev-1", "vm.created", "vcenter")
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, emitted))
+	require.NoError(t, l.Deliver(ctx, testOrgID, "ev-1", entities.Delivery{Subscription: "sub-1", Function: "hello", Run: "run-1", Time: time.Now()}))
+	require.NoError(t, l.Deliver(ctx, testOrgID, "ev-1", entities.Delivery{Subscription: "sub-2", Function: "bye", Error: "failed", Time: time.Now().Add(time.Millisecond)}))
+
+	// an event ingested by a driver is recorded when published, an event recorded already is left as is
+	require.NoError(t, l.Listen(ctx, testOrgID))
+	require.NoError(t, l.Listen(ctx, testOrgID))
+	require.NoError(t, queue.Publish(ctx, testEvent("ev-2", "vm.deleted", "vcenter"), "vm.deleted", testOrgID))
+	require.NoError(t, queue.Publish(ctx, emitted, "vm.created", testOrgID))
+	for i := 0; i < 100; i++ {
+		if _, err := l.Get(ctx, testOrgID, []string{"ev-2"}); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	records, err := l.Get(ctx, testOrgID, []string{"ev-1", "ev-2"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, v1.EventRecordOriginAPI, records[0].Origin)
+	require.Len(t, records[0].Deliveries, 2)
+	assert.Equal(t, "run-1", records[0].Deliveries[0].Run)
+	assert.Equal(t, "failed", records[0].Deliveries[1].Error)
+	assert.Equal(t, v1.EventRecordOriginTransport, records[1].Origin)
+	assert.Equal(t, "vm.deleted", records[1].Topic)
+	assert.Empty(t, records[1].Deliveries)
+
+	_, err = l.Get(ctx, testOrgID, []string{"ev-3"})
+	assert.Error(t, err)
+	_, err = l.Get(ctx, "otherOrg", []string{"ev-1"})
+	assert.Error(t, err)
+}
+
+func TestLogQuery(t *testing.T) {
+	l := NewLog(helpers.MakeEntityStore(t), nil, 0)
+	ctx := context.Background()
+
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, testEvent("ev-1", "vm.created", "vcenter")))
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.deleted", v1.EventRecordOriginAPI, testEvent("ev-2", "vm.deleted", "vcenter")))
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, testEvent("ev-3", "vm.created", "openstack")))
+
+	ids := func(records []*entities.EventRecord) []string {
+		var ids []string
+		for _, r := range records {
+			ids = append(ids, r.Event.EventID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+	records, err := l.Query(ctx, testOrgID, Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ev-1", "ev-2", "ev-3"}, ids(records))
+
+	records, err = l.Query(ctx, testOrgID, Query{EventType: "vm.created"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ev-1", "ev-3"}, ids(records))
+
+	records, err = l.Query(ctx, testOrgID, Query{EventType: "vm.*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ev-1", "ev-2", "ev-3"}, ids(records))
+
+	records, err = l.Query(ctx, testOrgID, Query{EventType: "vm.created", Source: "openstack"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ev-3"}, ids(records))
+
+	records, err = l.Query(ctx, testOrgID, Query{Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	records, err = l.Query(ctx, testOrgID, Query{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+}
+
+func TestLogPrune(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	ctx := context.Background()
+
+	l := NewLog(store, nil, time.Hour)
+	require.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, testEvent("ev-1", "vm.created", "vcenter")))
+	require.NoError(t, l.Prune(ctx))
+	records, err := l.Query(ctx, testOrgID, Query{})
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	expired := NewLog(store, nil, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	require.NoError(t, expired.Prune(ctx))
+	records, err = l.Query(ctx, testOrgID, Query{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	ctx := context.Background()
+	ev := testEvent("ev-1", "vm.created", "vcenter")
+	assert.NoError(t, l.Record(ctx, testOrgID, "vm.created", v1.EventRecordOriginAPI, ev))
+	assert.NoError(t, l.Deliver(ctx, testOrgID, "ev-1", entities.Delivery{}))
+	assert.NoError(t, l.Listen(ctx, testOrgID))
+	records, err := l.Query(ctx, testOrgID, Query{})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -0,0 +1,78 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"foo * * * *",
+		"@reboot",
+	} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2018, time.March, 14, 10, 27, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2018, time.March, 14, 10, 28, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2018, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2018, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * *", time.Date(2018, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2018, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2018, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2018, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 6 1,15 jun,dec *", time.Date(2018, time.June, 1, 6, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week
+		{"0 0 1 * fri", time.Date(2018, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.next, c.Next(from), tt.expr)
+	}
+}
+
+func TestCronNextLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	c, err := ParseCron("30 2 * * *")
+	require.NoError(t, err)
+
+	next := c.Next(time.Date(2018, time.March, 9, 12, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2018, time.March, 10, 2, 30, 0, 0, loc), next)
+	assert.Equal(t, loc, next.Location())
+
+	// 2:30 does not exist on March 11th (daylight saving time change), it is skipped
+	next = c.Next(next)
+	assert.Equal(t, time.Date(2018, time.March, 12, 2, 30, 0, 0, loc), next)
+}
@@ -0,0 +1,92 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package entities
+
+import (
+	"time"
+
+	"github.com/go-openapi/swag"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// NO TESTS
+
+// Schedule struct represents a function run or an event emission fired at the times of a cron expression
+type Schedule struct {
+	entitystore.BaseEntity
+	Cron              string      `json:"cron"`
+	Timezone          string      `json:"timezone,omitempty"`
+	Function          string      `json:"function,omitempty"`
+	EventType         string      `json:"eventType,omitempty"`
+	Input             interface{} `json:"input,omitempty"`
+	Secrets           []string    `json:"secrets,omitempty"`
+	ConcurrencyPolicy string      `json:"concurrencyPolicy"`
+	// LastFireTime is the cron time of the last fire, it is persisted before firing so a fire time is never
+	// fired twice
+	LastFireTime time.Time `json:"lastFireTime,omitempty"`
+	NextFireTime time.Time `json:"nextFireTime,omitempty"`
+	LastRun      string    `json:"lastRun,omitempty"`
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// ToModel converts schedule to swagger model
+func (s *Schedule) ToModel() *v1.Schedule {
+	var tags []*v1.Tag
+	for k, v := range s.Tags {
+		tags = append(tags, &v1.Tag{Key: k, Value: v})
+	}
+	m := v1.Schedule{
+		Name:              swag.String(s.Name),
+		Kind:              utils.ScheduleKind,
+		Cron:              swag.String(s.Cron),
+		Timezone:          s.Timezone,
+		Function:          s.Function,
+		EventType:         s.EventType,
+		Input:             s.Input,
+		Secrets:           s.Secrets,
+		ConcurrencyPolicy: s.ConcurrencyPolicy,
+		LastFireTime:      unixOrZero(s.LastFireTime),
+		NextFireTime:      unixOrZero(s.NextFireTime),
+		LastRun:           s.LastRun,
+		Status:            v1.Status(s.Status),
+		Reason:            s.Reason,
+		CreatedTime:       s.CreatedTime.Unix(),
+		ModifiedTime:      s.ModifiedTime.Unix(),
+		Tags:              tags,
+	}
+	return &m
+}
+
+// FromModel builds schedule based on swagger model
+func (s *Schedule) FromModel(m *v1.Schedule, orgID string) {
+	tags := make(map[string]string)
+	for _, t := range m.Tags {
+		tags[t.Key] = t.Value
+	}
+	s.BaseEntity.OrganizationID = orgID
+	s.BaseEntity.Name = *m.Name
+	s.BaseEntity.Status = entitystore.Status(m.Status)
+	s.BaseEntity.Tags = tags
+	s.Cron = *m.Cron
+	s.Timezone = m.Timezone
+	s.Function = m.Function
+	s.EventType = m.EventType
+	s.Input = m.Input
+	s.Secrets = m.Secrets
+	s.ConcurrencyPolicy = m.ConcurrencyPolicy
+	if s.ConcurrencyPolicy == "" {
+		s.ConcurrencyPolicy = v1.ScheduleConcurrencyPolicyAllow
+	}
+}
@@ -0,0 +1,136 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	ewrapper "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules/entities"
+	"github.com/vmware/dispatch/pkg/trace"
+)
+
+// EntityHandler handles Schedule entity operations
+type EntityHandler struct {
+	store     entitystore.EntityStore
+	scheduler Scheduler
+}
+
+// NewEntityHandler returns new instance of EntityHandler
+func NewEntityHandler(store entitystore.EntityStore, scheduler Scheduler) *EntityHandler {
+	return &EntityHandler{
+		store:     store,
+		scheduler: scheduler,
+	}
+}
+
+// Type returns entity handler type
+func (h *EntityHandler) Type() reflect.Type {
+	return reflect.TypeOf(&entities.Schedule{})
+}
+
+// Add handles adding new schedule entity
+func (h *EntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	schedule := obj.(*entities.Schedule)
+	defer func() { h.store.UpdateWithError(ctx, schedule, err) }()
+
+	if err := h.activate(ctx, schedule); err != nil {
+		return err
+	}
+
+	log.Infof("schedule %s (%s) has been activated", schedule.Name, schedule.Cron)
+
+	return nil
+}
+
+// Update handles schedule entity update, it also reactivates ready schedules on resync (e.g. after a restart)
+func (h *EntityHandler) Update(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	schedule := obj.(*entities.Schedule)
+	defer func() { h.store.UpdateWithError(ctx, schedule, err) }()
+
+	return h.activate(ctx, schedule)
+}
+
+func (h *EntityHandler) activate(ctx context.Context, schedule *entities.Schedule) error {
+	next, err := NextFireTime(schedule, time.Now())
+	if err != nil {
+		return ewrapper.Wrap(err, "error activating schedule")
+	}
+	schedule.NextFireTime = next
+
+	if err := h.scheduler.Add(context.Background(), schedule); err != nil {
+		return ewrapper.Wrap(err, "error activating schedule")
+	}
+
+	schedule.Status = entitystore.StatusREADY
+	return nil
+}
+
+// Delete handles schedule entity deletion
+func (h *EntityHandler) Delete(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	schedule := obj.(*entities.Schedule)
+
+	if err := h.scheduler.Delete(context.Background(), schedule); err != nil {
+		return ewrapper.Wrap(err, "error deactivating schedule")
+	}
+
+	// hard deletion
+	if err := h.store.Delete(ctx, schedule.OrganizationID, schedule.Name, schedule); err != nil {
+		return ewrapper.Wrap(err, "store error when deleting schedule")
+	}
+	log.Infof("schedule %s deactivated and deleted from the entity store", schedule.Name)
+	return nil
+}
+
+// Sync is responsible for syncing the state of active schedules and their entities
+func (h *EntityHandler) Sync(ctx context.Context, resyncPeriod time.Duration) ([]entitystore.Entity, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	// list entity filter
+	now := time.Now().Add(-resyncPeriod)
+	filter := entitystore.FilterEverything().Add(
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "ModifiedTime",
+			Verb:    entitystore.FilterVerbBefore,
+			Object:  now,
+		},
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbIn,
+			Object: []entitystore.Status{
+				entitystore.StatusCREATING, entitystore.StatusUPDATING, entitystore.StatusDELETING,
+				entitystore.StatusREADY,
+			},
+		})
+	return controller.DefaultSync(ctx, h.store, h.Type(), resyncPeriod, filter)
+}
+
+// Error handles error state
+func (h *EntityHandler) Error(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	log.Errorf("handleError func not implemented yet")
+	return nil
+}
@@ -0,0 +1,65 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules/entities"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules/mocks"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func TestScheduleAdd(t *testing.T) {
+	scheduler := &mocks.Scheduler{}
+	es := helpers.MakeEntityStore(t)
+	handler := NewEntityHandler(es, scheduler)
+	schedule := &entities.Schedule{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "nightly",
+			Status:         entitystore.StatusCREATING,
+			OrganizationID: testOrgID,
+		},
+		Cron:     "@daily",
+		Function: "hello",
+	}
+	es.Add(context.Background(), schedule)
+	scheduler.On("Add", mock.Anything, mock.Anything).Return(nil)
+	assert.NoError(t, handler.Add(context.Background(), schedule))
+	scheduler.AssertExpectations(t)
+
+	stored := getSchedule(t, es, "nightly")
+	assert.Equal(t, entitystore.StatusREADY, stored.Status)
+	assert.True(t, stored.NextFireTime.After(stored.CreatedTime))
+}
+
+func TestScheduleDelete(t *testing.T) {
+	scheduler := &mocks.Scheduler{}
+	es := helpers.MakeEntityStore(t)
+	handler := NewEntityHandler(es, scheduler)
+	schedule := &entities.Schedule{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "nightly",
+			Status:         entitystore.StatusDELETING,
+			OrganizationID: testOrgID,
+		},
+		Cron:     "@daily",
+		Function: "hello",
+	}
+	es.Add(context.Background(), schedule)
+	scheduler.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	assert.NoError(t, handler.Delete(context.Background(), schedule))
+	scheduler.AssertExpectations(t)
+
+	var schedules []*entities.Schedule
+	es.List(context.Background(), testOrgID, entitystore.Options{}, &schedules)
+	assert.Len(t, schedules, 0)
+}
@@ -0,0 +1,277 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
+	schedulesapi "github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations/schedules"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules/entities"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// Handlers is a base struct for event manager API handlers.
+type Handlers struct {
+	store   entitystore.EntityStore
+	watcher controller.Watcher
+}
+
+// NewHandlers Creates new instance of schedule handlers
+func NewHandlers(store entitystore.EntityStore, watcher controller.Watcher) *Handlers {
+	return &Handlers{
+		watcher: watcher,
+		store:   store,
+	}
+}
+
+// ConfigureHandlers configures API handlers for Schedule endpoints
+func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
+	a, ok := api.(*operations.EventManagerAPI)
+	if !ok {
+		panic("Cannot configure api")
+	}
+
+	a.SchedulesAddScheduleHandler = schedulesapi.AddScheduleHandlerFunc(h.addSchedule)
+	a.SchedulesGetScheduleHandler = schedulesapi.GetScheduleHandlerFunc(h.getSchedule)
+	a.SchedulesGetSchedulesHandler = schedulesapi.GetSchedulesHandlerFunc(h.getSchedules)
+	a.SchedulesUpdateScheduleHandler = schedulesapi.UpdateScheduleHandlerFunc(h.updateSchedule)
+	a.SchedulesDeleteScheduleHandler = schedulesapi.DeleteScheduleHandlerFunc(h.deleteSchedule)
+}
+
+// addSchedule handles creation of new Event Schedules
+func (h *Handlers) addSchedule(params schedulesapi.AddScheduleParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "addSchedule")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return schedulesapi.NewAddScheduleBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+
+	s := &entities.Schedule{}
+	s.FromModel(params.Body, params.XDispatchOrg)
+	if err := Validate(s); err != nil {
+		return schedulesapi.NewAddScheduleBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("invalid schedule: %s", err)),
+		})
+	}
+	s.Status = entitystore.StatusCREATING
+	_, err := h.store.Add(ctx, s)
+	if err != nil {
+		if entitystore.IsUniqueViolation(err) {
+			return schedulesapi.NewAddScheduleConflict().WithPayload(&v1.Error{
+				Code:    http.StatusConflict,
+				Message: utils.ErrorMsgAlreadyExists("schedule", s.Name),
+			})
+		}
+		log.Errorf("error when storing the schedule: %+v", err)
+		return schedulesapi.NewAddScheduleDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("schedule", s.Name),
+		})
+	}
+	log.Debugf("Sending new schedule %s to worker", s.Name)
+	h.watcher.OnAction(ctx, s)
+	return schedulesapi.NewAddScheduleCreated().WithPayload(s.ToModel())
+}
+
+// getSchedule handles retrieval of single Schedule
+func (h *Handlers) getSchedule(params schedulesapi.GetScheduleParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getSchedule")
+	defer span.Finish()
+
+	s := entities.Schedule{}
+	var err error
+
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %+v", err)
+		return schedulesapi.NewGetScheduleBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+	err = h.store.Get(ctx, params.XDispatchOrg, params.ScheduleName, opts, &s)
+	if err != nil {
+		log.Warnf("Received GET for non-existent schedule %s", params.ScheduleName)
+		log.Debugf("store error when getting schedule: %+v", err)
+		return schedulesapi.NewGetScheduleNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("schedule", params.ScheduleName),
+			})
+	}
+	return schedulesapi.NewGetScheduleOK().WithPayload(s.ToModel())
+}
+
+// getSchedules handles retrieval of Schedule list
+func (h *Handlers) getSchedules(params schedulesapi.GetSchedulesParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getSchedules")
+	defer span.Finish()
+
+	var schedules []*entities.Schedule
+	var err error
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %+v", err)
+		return schedulesapi.NewGetSchedulesBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+
+	err = h.store.List(ctx, params.XDispatchOrg, opts, &schedules)
+	if err != nil {
+		log.Errorf("store error when listing schedules: %+v", err)
+		return schedulesapi.NewGetSchedulesDefault(http.StatusInternalServerError).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when getting schedules"),
+			})
+	}
+	var scheduleModels []*v1.Schedule
+	for _, sub := range schedules {
+		scheduleModels = append(scheduleModels, sub.ToModel())
+	}
+	return schedulesapi.NewGetSchedulesOK().WithPayload(scheduleModels)
+}
+
+func (h *Handlers) updateSchedule(params schedulesapi.UpdateScheduleParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "updateSchedule")
+	defer span.Finish()
+
+	s := &entities.Schedule{}
+	var err error
+
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %+v", err)
+		return schedulesapi.NewUpdateScheduleBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+	err = h.store.Get(ctx, params.XDispatchOrg, params.ScheduleName, opts, s)
+	if err != nil {
+		log.Warnf("Received UPDATE for non-existent schedule %s", params.ScheduleName)
+		log.Debugf("store error when getting schedule: %+v", err)
+		return schedulesapi.NewUpdateScheduleNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("schedule", params.ScheduleName),
+			})
+	}
+	if s.Status == entitystore.StatusUPDATING {
+		log.Warnf("Attempting to update schedule %s which already is in UPDATING state", s.Name)
+		return schedulesapi.NewUpdateScheduleBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(fmt.Sprintf("Unable to update schedule %s: schedule is already being updated", s.Name)),
+			})
+	}
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return schedulesapi.NewUpdateScheduleBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+	s.FromModel(params.Body, s.OrganizationID)
+	if err := Validate(s); err != nil {
+		return schedulesapi.NewUpdateScheduleBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("invalid schedule: %s", err)),
+		})
+	}
+	s.Status = entitystore.StatusUPDATING
+	if _, err = h.store.Update(ctx, s.Revision, s); err != nil {
+		log.Errorf("store error when updating a schedule %s: %+v", s.Name, err)
+		return schedulesapi.NewUpdateScheduleDefault(500).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: utils.ErrorMsgInternalError("schedule", s.Name),
+			})
+	}
+	log.Debugf("Sending updated schedule %s update to worker", s.Name)
+	h.watcher.OnAction(ctx, s)
+	return schedulesapi.NewUpdateScheduleOK().WithPayload(s.ToModel())
+}
+
+// deleteSchedule handles deletion of a Schedule
+func (h *Handlers) deleteSchedule(params schedulesapi.DeleteScheduleParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "deleteSchedule")
+	defer span.Finish()
+
+	s := &entities.Schedule{}
+	var err error
+
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %+v", err)
+		return schedulesapi.NewDeleteScheduleBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+	err = h.store.Get(ctx, params.XDispatchOrg, params.ScheduleName, opts, s)
+	if err != nil {
+		log.Warnf("Received DELETE for non-existent schedule %s", params.ScheduleName)
+		log.Debugf("store error when getting schedule: %+v", err)
+		return schedulesapi.NewDeleteScheduleNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("schedule", params.ScheduleName),
+			})
+	}
+	if s.Status == entitystore.StatusDELETING {
+		log.Warnf("Attempting to delete schedule %s which already is in DELETING state", s.Name)
+		return schedulesapi.NewDeleteScheduleBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("Unable to delete schedule %s: schedule is already being deleted", s.Name)),
+		})
+	}
+	s.Status = entitystore.StatusDELETING
+	if _, err = h.store.Update(ctx, s.Revision, s); err != nil {
+		log.Errorf("store error when deleting a schedule %s: %+v", s.Name, err)
+		return schedulesapi.NewDeleteScheduleDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("schedule", s.Name),
+		})
+	}
+	log.Debugf("Sending deleted schedule %s update to worker", s.Name)
+	h.watcher.OnAction(ctx, s)
+	return schedulesapi.NewDeleteScheduleOK().WithPayload(s.ToModel())
+}
@@ -0,0 +1,79 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations/schedules"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func addScheduleRequest(api *operations.EventManagerAPI, body *v1.Schedule) middleware.Responder {
+	r := httptest.NewRequest("POST", "/v1/event/schedules", nil)
+	params := schedules.AddScheduleParams{
+		HTTPRequest:  r,
+		Body:         body,
+		XDispatchOrg: testOrgID,
+	}
+	return api.SchedulesAddScheduleHandler.Handle(params, "testCookie")
+}
+
+func TestSchedulesAddScheduleHandler(t *testing.T) {
+	api := operations.NewEventManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := Handlers{es, nil}
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	responder := addScheduleRequest(api, &v1.Schedule{
+		Name:     swag.String("nightly"),
+		Cron:     swag.String("0 2 * * *"),
+		Timezone: "Europe/Paris",
+		Function: "hello",
+	})
+	var respBody v1.Schedule
+	helpers.HandlerRequest(t, responder, &respBody, 201)
+	assert.Equal(t, "0 2 * * *", *respBody.Cron)
+	assert.Equal(t, "hello", respBody.Function)
+	assert.Equal(t, v1.ScheduleConcurrencyPolicyAllow, respBody.ConcurrencyPolicy)
+	assert.Equal(t, v1.StatusCREATING, respBody.Status)
+
+	r := httptest.NewRequest("GET", "/v1/event/schedules/nightly", nil)
+	get := schedules.GetScheduleParams{
+		HTTPRequest:  r,
+		ScheduleName: "nightly",
+		XDispatchOrg: testOrgID,
+	}
+	var getBody v1.Schedule
+	helpers.HandlerRequest(t, api.SchedulesGetScheduleHandler.Handle(get, "testCookie"), &getBody, 200)
+	assert.Equal(t, "Europe/Paris", getBody.Timezone)
+}
+
+func TestSchedulesAddScheduleHandlerInvalid(t *testing.T) {
+	api := operations.NewEventManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := Handlers{es, nil}
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	for _, body := range []*v1.Schedule{
+		{Name: swag.String("bad-cron"), Cron: swag.String("every day"), Function: "hello"},
+		{Name: swag.String("bad-timezone"), Cron: swag.String("@daily"), Timezone: "Mars/Olympus", Function: "hello"},
+		{Name: swag.String("no-target"), Cron: swag.String("@daily")},
+		{Name: swag.String("two-targets"), Cron: swag.String("@daily"), Function: "hello", EventType: "tick"},
+	} {
+		var errorBody v1.Error
+		helpers.HandlerRequest(t, addScheduleRequest(api, body), &errorBody, 400)
+		assert.EqualValues(t, http.StatusBadRequest, errorBody.Code, *body.Name)
+	}
+}
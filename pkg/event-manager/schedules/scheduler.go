@@ -0,0 +1,362 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules/entities"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/trace"
+)
+
+const (
+	// retryPeriod is how long to wait before trying again after an error
+	retryPeriod = 10 * time.Second
+	// maxWait bounds the time a schedule sleeps before it re-reads its entity
+	maxWait = time.Hour
+	// maxRecordAttempts bounds the attempts to record the last run of a schedule modified concurrently
+	maxRecordAttempts = 3
+)
+
+// Scheduler defines the schedule scheduler interface
+type Scheduler interface {
+	Add(context.Context, *entities.Schedule) error
+	Delete(context.Context, *entities.Schedule) error
+	Shutdown()
+}
+
+type activeSchedule struct {
+	wake chan struct{}
+	stop chan struct{}
+}
+
+type defaultScheduler struct {
+	store    entitystore.EntityStore
+	queue    events.Transport
+	fnClient client.FunctionsClient
+	eventLog *eventlog.Log
+	now      func() time.Time
+
+	sync.Mutex
+	active map[string]*activeSchedule
+}
+
+// NewScheduler creates a new scheduler, running functions with fnClient and emitting events to queue, recording them
+// in the event log if not nil
+func NewScheduler(store entitystore.EntityStore, queue events.Transport, fnClient client.FunctionsClient, eventLog *eventlog.Log) Scheduler {
+	return &defaultScheduler{
+		store:    store,
+		queue:    queue,
+		fnClient: fnClient,
+		eventLog: eventLog,
+		now:      time.Now,
+		active:   make(map[string]*activeSchedule),
+	}
+}
+
+// Add starts firing a schedule. The schedule entity is re-read from the store before every fire, so adding an
+// already active schedule only wakes it up to pick up the changes.
+func (s *defaultScheduler) Add(ctx context.Context, schedule *entities.Schedule) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	if err := Validate(schedule); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if a, ok := s.active[schedule.ID]; ok {
+		select {
+		case a.wake <- struct{}{}:
+		default:
+		}
+		return nil
+	}
+	a := &activeSchedule{
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+	s.active[schedule.ID] = a
+	go s.run(schedule.OrganizationID, schedule.Name, a)
+	log.Debugf("schedule %s activated", schedule.Name)
+	return nil
+}
+
+// Delete stops firing a schedule
+func (s *defaultScheduler) Delete(ctx context.Context, schedule *entities.Schedule) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	s.Lock()
+	defer s.Unlock()
+	if a, ok := s.active[schedule.ID]; ok {
+		close(a.stop)
+		delete(s.active, schedule.ID)
+	}
+	return nil
+}
+
+// Shutdown stops firing all schedules
+func (s *defaultScheduler) Shutdown() {
+	log.Infof("Scheduler shutdown")
+	s.Lock()
+	defer s.Unlock()
+	for id, a := range s.active {
+		close(a.stop)
+		delete(s.active, id)
+	}
+}
+
+func (s *defaultScheduler) run(organizationID, name string, a *activeSchedule) {
+	for {
+		wait, err := s.fireDue(context.Background(), organizationID, name)
+		if err != nil {
+			log.Errorf("error firing schedule %s: %+v", name, err)
+			if wait == 0 {
+				wait = retryPeriod
+			}
+		}
+		if wait > maxWait {
+			wait = maxWait
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-a.stop:
+			timer.Stop()
+			return
+		case <-a.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// fireDue fires the schedule if one of its fire times is due, and returns how long to wait for the next one.
+//
+// The fire time is recorded in the store before firing, with the revision of the entity read, so concurrent
+// schedulers (or a scheduler restarted with the store) never fire the same time twice. Fire times missed while
+// no scheduler was running are collapsed into a single fire.
+func (s *defaultScheduler) fireDue(ctx context.Context, organizationID, name string) (time.Duration, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	schedule := new(entities.Schedule)
+	if err := s.store.Get(ctx, organizationID, name, entitystore.Options{}, schedule); err != nil {
+		return 0, errors.Wrapf(err, "error getting schedule %s from store", name)
+	}
+	cron, loc, err := parseSchedule(schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	due := dueFireTime(schedule, cron, loc, now)
+	if due.IsZero() {
+		next := cron.Next(lastFireTime(schedule).In(loc))
+		if next.IsZero() {
+			return maxWait, nil
+		}
+		return next.Sub(now), nil
+	}
+
+	schedule.LastFireTime = due
+	schedule.NextFireTime = cron.Next(now.In(loc))
+	if _, err := s.store.Update(ctx, schedule.Revision, schedule); err != nil {
+		// most likely the schedule was modified (or fired) concurrently, try again with a fresh entity
+		return time.Second, errors.Wrapf(err, "error recording fire time of schedule %s", name)
+	}
+
+	s.fire(ctx, schedule, due)
+
+	if schedule.NextFireTime.IsZero() {
+		return maxWait, nil
+	}
+	return schedule.NextFireTime.Sub(now), nil
+}
+
+func (s *defaultScheduler) fire(ctx context.Context, schedule *entities.Schedule, at time.Time) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	span.SetTag("schedule", schedule.Name)
+
+	// a key unique to the fire time, so a fire delivered twice is handled only once
+	key := fmt.Sprintf("schedule-%s-%d", schedule.ID, at.Unix())
+	if schedule.EventType != "" {
+		s.emitEvent(ctx, schedule, key, at)
+		return
+	}
+	s.runFunction(ctx, schedule, key)
+}
+
+func (s *defaultScheduler) emitEvent(ctx context.Context, schedule *entities.Schedule, key string, at time.Time) {
+	ev := events.NewCloudEventWithDefaults(schedule.EventType)
+	ev.Source = "dispatch/schedules/" + schedule.Name
+	ev.EventID = key
+	ev.EventTime = at
+	if schedule.Input != nil {
+		data, err := json.Marshal(schedule.Input)
+		if err != nil {
+			log.Errorf("error marshalling input of schedule %s: %+v", schedule.Name, err)
+			return
+		}
+		ev.Data = data
+		ev.ContentType = "application/json"
+	}
+	if err := s.eventLog.Record(ctx, schedule.OrganizationID, ev.DefaultTopic(), v1.EventRecordOriginSchedule, &ev); err != nil {
+		log.Errorf("error recording event %s of schedule %s: %+v", ev.EventID, schedule.Name, err)
+	}
+	if err := s.queue.Publish(ctx, &ev, ev.DefaultTopic(), schedule.OrganizationID); err != nil {
+		log.Errorf("error emitting event %s of schedule %s: %+v", schedule.EventType, schedule.Name, err)
+		return
+	}
+	log.Debugf("schedule %s emitted event %s", schedule.Name, schedule.EventType)
+}
+
+func (s *defaultScheduler) runFunction(ctx context.Context, schedule *entities.Schedule, key string) {
+	policy := schedule.ConcurrencyPolicy
+	if schedule.LastRun != "" && (policy == v1.ScheduleConcurrencyPolicyForbid || policy == v1.ScheduleConcurrencyPolicyReplace) && s.isRunning(ctx, schedule) {
+		if policy == v1.ScheduleConcurrencyPolicyForbid {
+			log.Infof("skipping fire of schedule %s: run %s is still running", schedule.Name, schedule.LastRun)
+			return
+		}
+		s.cancelLastRun(ctx, schedule)
+	}
+
+	run := &v1.Run{
+		Blocking:       false,
+		FunctionName:   schedule.Function,
+		Input:          schedule.Input,
+		Secrets:        schedule.Secrets,
+		IdempotencyKey: key,
+		Priority:       v1.RunPriorityLow,
+	}
+	result, err := s.fnClient.RunFunction(ctx, schedule.OrganizationID, run)
+	if err != nil {
+		log.Errorf("error running function %s of schedule %s: %+v", schedule.Function, schedule.Name, err)
+		return
+	}
+	log.Debugf("schedule %s started run %s of function %s", schedule.Name, result.Name, schedule.Function)
+	s.recordLastRun(ctx, schedule, result.Name.String())
+}
+
+func (s *defaultScheduler) isRunning(ctx context.Context, schedule *entities.Schedule) bool {
+	opts := client.FunctionOpts{
+		FunctionName: &schedule.Function,
+		RunName:      &schedule.LastRun,
+	}
+	run, err := s.fnClient.GetFunctionRun(ctx, schedule.OrganizationID, opts)
+	if err != nil {
+		log.Debugf("error getting run %s of schedule %s: %+v", schedule.LastRun, schedule.Name, err)
+		return false
+	}
+	return run.Status != v1.StatusREADY && run.Status != v1.StatusERROR
+}
+
+// cancelLastRun cancels the previous run of a schedule replaced by the run of a new fire, the new run is started even
+// when the previous one can't be cancelled
+func (s *defaultScheduler) cancelLastRun(ctx context.Context, schedule *entities.Schedule) {
+	opts := client.FunctionOpts{
+		FunctionName: &schedule.Function,
+		RunName:      &schedule.LastRun,
+	}
+	if _, err := s.fnClient.CancelRun(ctx, schedule.OrganizationID, opts); err != nil {
+		log.Errorf("error cancelling run %s of schedule %s: %+v", schedule.LastRun, schedule.Name, err)
+		return
+	}
+	log.Infof("run %s of schedule %s was still running, replaced it", schedule.LastRun, schedule.Name)
+}
+
+func (s *defaultScheduler) recordLastRun(ctx context.Context, schedule *entities.Schedule, runName string) {
+	for i := 0; i < maxRecordAttempts; i++ {
+		schedule.LastRun = runName
+		_, err := s.store.Update(ctx, schedule.Revision, schedule)
+		if err == nil {
+			return
+		}
+		log.Debugf("error recording last run of schedule %s: %+v", schedule.Name, err)
+		if err := s.store.Get(ctx, schedule.OrganizationID, schedule.Name, entitystore.Options{}, schedule); err != nil {
+			break
+		}
+	}
+	log.Errorf("unable to record run %s as last run of schedule %s", runName, schedule.Name)
+}
+
+// Validate validates the cron expression, timezone, target and concurrency policy of a schedule
+func Validate(schedule *entities.Schedule) error {
+	if _, _, err := parseSchedule(schedule); err != nil {
+		return err
+	}
+	if (schedule.Function == "") == (schedule.EventType == "") {
+		return errors.New("exactly one of function and event type is required")
+	}
+	switch schedule.ConcurrencyPolicy {
+	case "", v1.ScheduleConcurrencyPolicyAllow, v1.ScheduleConcurrencyPolicyForbid, v1.ScheduleConcurrencyPolicyReplace:
+	default:
+		return errors.Errorf("invalid concurrency policy '%s'", schedule.ConcurrencyPolicy)
+	}
+	return nil
+}
+
+// NextFireTime returns the next time the schedule fires, now if one of its fire times is due
+func NextFireTime(schedule *entities.Schedule, now time.Time) (time.Time, error) {
+	cron, loc, err := parseSchedule(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if due := dueFireTime(schedule, cron, loc, now); !due.IsZero() {
+		return due, nil
+	}
+	return cron.Next(lastFireTime(schedule).In(loc)), nil
+}
+
+func parseSchedule(schedule *entities.Schedule) (*Cron, *time.Location, error) {
+	cron, err := ParseCron(schedule.Cron)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid timezone '%s'", schedule.Timezone)
+	}
+	return cron, loc, nil
+}
+
+// lastFireTime returns the time fire times are computed from
+func lastFireTime(schedule *entities.Schedule) time.Time {
+	if schedule.LastFireTime.IsZero() {
+		return schedule.CreatedTime
+	}
+	return schedule.LastFireTime
+}
+
+// dueFireTime returns the latest fire time after the last fire which is not after now, the zero time if none
+func dueFireTime(schedule *entities.Schedule, cron *Cron, loc *time.Location, now time.Time) time.Time {
+	due := cron.Next(lastFireTime(schedule).In(loc))
+	if due.IsZero() || due.After(now) {
+		return time.Time{}
+	}
+	for {
+		next := cron.Next(due)
+		if next.IsZero() || next.After(now) {
+			return due
+		}
+		due = next
+	}
+}
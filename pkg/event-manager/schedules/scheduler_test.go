@@ -0,0 +1,212 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/client"
+	clientmocks "github.com/vmware/dispatch/pkg/client/mocks"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
+	"github.com/vmware/dispatch/pkg/event-manager/schedules/entities"
+	"github.com/vmware/dispatch/pkg/events"
+	eventsmocks "github.com/vmware/dispatch/pkg/events/mocks"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+const testOrgID = "testOrg"
+
+func addSchedule(t *testing.T, es entitystore.EntityStore, schedule *entities.Schedule) *entities.Schedule {
+	schedule.OrganizationID = testOrgID
+	schedule.Status = entitystore.StatusREADY
+	if schedule.ConcurrencyPolicy == "" {
+		schedule.ConcurrencyPolicy = v1.ScheduleConcurrencyPolicyAllow
+	}
+	_, err := es.Add(context.Background(), schedule)
+	require.NoError(t, err)
+	return schedule
+}
+
+func getSchedule(t *testing.T, es entitystore.EntityStore, name string) *entities.Schedule {
+	schedule := new(entities.Schedule)
+	require.NoError(t, es.Get(context.Background(), testOrgID, name, entitystore.Options{}, schedule))
+	return schedule
+}
+
+func newTestScheduler(es entitystore.EntityStore, queue events.Transport, fnClient client.FunctionsClient, now time.Time) *defaultScheduler {
+	s := NewScheduler(es, queue, fnClient, nil).(*defaultScheduler)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestFireDueNotDue(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	fnClient := &clientmocks.FunctionsClient{}
+	schedule := addSchedule(t, es, &entities.Schedule{
+		BaseEntity: entitystore.BaseEntity{Name: "every-hour"},
+		Cron:       "0 * * * *",
+		Function:   "hello",
+	})
+	schedule.LastFireTime = schedule.CreatedTime.Truncate(time.Hour)
+	_, err := es.Update(context.Background(), schedule.Revision, schedule)
+	require.NoError(t, err)
+
+	now := schedule.LastFireTime.Add(20 * time.Minute)
+	s := newTestScheduler(es, nil, fnClient, now)
+	wait, err := s.fireDue(context.Background(), testOrgID, "every-hour")
+	assert.NoError(t, err)
+	assert.Equal(t, 40*time.Minute, wait)
+	fnClient.AssertNotCalled(t, "RunFunction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFireDueRunsFunction(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	fnClient := &clientmocks.FunctionsClient{}
+	schedule := addSchedule(t, es, &entities.Schedule{
+		BaseEntity: entitystore.BaseEntity{Name: "every-minute"},
+		Cron:       "* * * * *",
+		Function:   "hello",
+		Input:      map[string]interface{}{"name": "Jon"},
+	})
+	// missed fire times are collapsed into one
+	now := schedule.CreatedTime.Add(10*time.Minute + 30*time.Second)
+	due := now.Truncate(time.Minute)
+
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.MatchedBy(func(run *v1.Run) bool {
+		return run.FunctionName == "hello" && !run.Blocking && run.IdempotencyKey != ""
+	})).Return(&v1.Run{Name: "9f2f2b2e-0000-4000-8000-000000000001"}, nil).Once()
+
+	s := newTestScheduler(es, nil, fnClient, now)
+	wait, err := s.fireDue(context.Background(), testOrgID, "every-minute")
+	assert.NoError(t, err)
+	assert.Equal(t, due.Add(time.Minute).Sub(now), wait)
+	fnClient.AssertExpectations(t)
+
+	stored := getSchedule(t, es, "every-minute")
+	assert.True(t, due.Equal(stored.LastFireTime))
+	assert.True(t, due.Add(time.Minute).Equal(stored.NextFireTime))
+	assert.Equal(t, "9f2f2b2e-0000-4000-8000-000000000001", stored.LastRun)
+
+	// the fire time was recorded, firing again (e.g. after a restart) does not fire twice
+	wait, err = s.fireDue(context.Background(), testOrgID, "every-minute")
+	assert.NoError(t, err)
+	assert.Equal(t, due.Add(time.Minute).Sub(now), wait)
+	fnClient.AssertNumberOfCalls(t, "RunFunction", 1)
+}
+
+func TestFireDueForbid(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	fnClient := &clientmocks.FunctionsClient{}
+	schedule := addSchedule(t, es, &entities.Schedule{
+		BaseEntity:        entitystore.BaseEntity{Name: "every-minute"},
+		Cron:              "* * * * *",
+		Function:          "hello",
+		ConcurrencyPolicy: v1.ScheduleConcurrencyPolicyForbid,
+		LastRun:           "9f2f2b2e-0000-4000-8000-000000000001",
+	})
+	fnClient.On("GetFunctionRun", mock.Anything, testOrgID, mock.Anything).Return(&v1.Run{Status: v1.StatusINITIALIZED}, nil)
+
+	s := newTestScheduler(es, nil, fnClient, schedule.CreatedTime.Add(90*time.Second))
+	_, err := s.fireDue(context.Background(), testOrgID, "every-minute")
+	assert.NoError(t, err)
+	fnClient.AssertNotCalled(t, "RunFunction", mock.Anything, mock.Anything, mock.Anything)
+
+	// the skipped fire time is still recorded
+	assert.False(t, getSchedule(t, es, "every-minute").LastFireTime.IsZero())
+}
+
+func TestFireDueReplace(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	fnClient := &clientmocks.FunctionsClient{}
+	schedule := addSchedule(t, es, &entities.Schedule{
+		BaseEntity:        entitystore.BaseEntity{Name: "every-minute"},
+		Cron:              "* * * * *",
+		Function:          "hello",
+		ConcurrencyPolicy: v1.ScheduleConcurrencyPolicyReplace,
+		LastRun:           "9f2f2b2e-0000-4000-8000-000000000001",
+	})
+	fnClient.On("GetFunctionRun", mock.Anything, testOrgID, mock.Anything).Return(&v1.Run{Status: v1.StatusCREATING}, nil)
+	var cancelled bool
+	fnClient.On("CancelRun", mock.Anything, testOrgID, mock.MatchedBy(func(opts client.FunctionOpts) bool {
+		return *opts.RunName == "9f2f2b2e-0000-4000-8000-000000000001" && *opts.FunctionName == "hello"
+	})).Return(&v1.Run{Status: v1.StatusERROR, Cancelled: true}, nil).Run(func(mock.Arguments) {
+		cancelled = true
+	}).Once()
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.Anything).Return(&v1.Run{Name: "9f2f2b2e-0000-4000-8000-000000000002"}, nil).Run(func(mock.Arguments) {
+		// the previous run is cancelled before the new one starts
+		assert.True(t, cancelled)
+	}).Once()
+
+	s := newTestScheduler(es, nil, fnClient, schedule.CreatedTime.Add(90*time.Second))
+	_, err := s.fireDue(context.Background(), testOrgID, "every-minute")
+	assert.NoError(t, err)
+	fnClient.AssertExpectations(t)
+	assert.Equal(t, "9f2f2b2e-0000-4000-8000-000000000002", getSchedule(t, es, "every-minute").LastRun)
+}
+
+func TestFireDueEmitsEvent(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	queue := &eventsmocks.Transport{}
+	schedule := addSchedule(t, es, &entities.Schedule{
+		BaseEntity: entitystore.BaseEntity{Name: "nightly"},
+		Cron:       "0 0 * * *",
+		Timezone:   "Europe/Paris",
+		EventType:  "nightly.tick",
+		Input:      map[string]interface{}{"report": "daily"},
+	})
+	loc, _ := time.LoadLocation("Europe/Paris")
+	due, _ := ParseCron("0 0 * * *")
+	fireTime := due.Next(schedule.CreatedTime.In(loc))
+
+	var emitted *events.CloudEvent
+	queue.On("Publish", mock.Anything, mock.Anything, "nightly.tick", testOrgID).Return(nil).Run(func(args mock.Arguments) {
+		emitted = args.Get(1).(*events.CloudEvent)
+	})
+
+	s := newTestScheduler(es, queue, nil, fireTime.Add(time.Minute))
+	s.eventLog = eventlog.NewLog(es, queue, 0)
+	_, err := s.fireDue(context.Background(), testOrgID, "nightly")
+	assert.NoError(t, err)
+	queue.AssertExpectations(t)
+
+	require.NotNil(t, emitted)
+	assert.Equal(t, "nightly.tick", emitted.EventType)
+	assert.True(t, fireTime.Equal(emitted.EventTime))
+	assert.Equal(t, "application/json", emitted.ContentType)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(emitted.Data, &data))
+	assert.Equal(t, "daily", data["report"])
+
+	records, err := s.eventLog.Get(context.Background(), testOrgID, []string{emitted.EventID})
+	require.NoError(t, err)
+	assert.Equal(t, v1.EventRecordOriginSchedule, records[0].Origin)
+}
+
+func TestValidate(t *testing.T) {
+	valid := &entities.Schedule{Cron: "@daily", Function: "hello"}
+	assert.NoError(t, Validate(valid))
+	valid.ConcurrencyPolicy = v1.ScheduleConcurrencyPolicyReplace
+	assert.NoError(t, Validate(valid))
+
+	for _, schedule := range []*entities.Schedule{
+		{Cron: "bogus", Function: "hello"},
+		{Cron: "@daily", Timezone: "Mars/Olympus", Function: "hello"},
+		{Cron: "@daily"},
+		{Cron: "@daily", Function: "hello", EventType: "tick"},
+		{Cron: "@daily", Function: "hello", ConcurrencyPolicy: "Skip"},
+	} {
+		assert.Error(t, Validate(schedule), "%+v", schedule)
+	}
+}
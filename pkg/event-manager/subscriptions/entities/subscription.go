@@ -14,12 +14,23 @@ import (
 
 // NO TESTS
 
+// DeadLetterPrefix prefixes the name of a subscription in the event type of its dead-letter topic
+const DeadLetterPrefix = "dispatch.dead-letter."
+
 // Subscription struct represents a single subscription of subscriber to publisher
 type Subscription struct {
 	entitystore.BaseEntity
 	EventType string   `json:"eventType"`
 	Function  string   `json:"function"`
 	Secrets   []string `json:"secrets,omitempty"`
+	Filters   []string `json:"filters,omitempty"`
+	// Ack is when events are acknowledged, v1.SubscriptionAckAccepted if empty
+	Ack string `json:"ack,omitempty"`
+}
+
+// DeadLetterTopic returns the topic of the events which could not be delivered to the subscription
+func (s *Subscription) DeadLetterTopic() string {
+	return DeadLetterPrefix + s.Name
 }
 
 // ToModel converts subscription to swagger model
@@ -35,9 +46,13 @@ func (s *Subscription) ToModel() *v1.Subscription {
 		Function:     &s.Function,
 		Status:       v1.Status(s.Status),
 		Secrets:      s.Secrets,
+		Filters:      s.Filters,
+		Ack:          s.Ack,
 		CreatedTime:  s.CreatedTime.Unix(),
 		ModifiedTime: s.ModifiedTime.Unix(),
 		Tags:         tags,
+
+		DeadLetterEventType: s.DeadLetterTopic(),
 	}
 	return &m
 }
@@ -55,4 +70,6 @@ func (s *Subscription) FromModel(m *v1.Subscription, orgID string) {
 	s.EventType = *m.EventType
 	s.Function = *m.Function
 	s.Secrets = m.Secrets
+	s.Filters = m.Filters
+	s.Ack = m.Ack
 }
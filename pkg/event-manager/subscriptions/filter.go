@@ -0,0 +1,126 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// filterPattern is PATH OPERATOR VALUE, the value being a JSON literal
+var filterPattern = regexp.MustCompile(`^\s*([\w\-]+(?:\.[\w\-]+)*)\s*(==|!=|<=|>=|<|>)\s*(.+?)\s*$`)
+
+// filterAttributes are the CloudEvent attributes a filter may select, besides extensions and data
+var filterAttributes = map[string]bool{
+	"eventType":          true,
+	"eventTypeVersion":   true,
+	"cloudEventsVersion": true,
+	"source":             true,
+	"eventID":            true,
+	"schemaURL":          true,
+	"contentType":        true,
+}
+
+// Filter is a condition on an attribute, an extension or a field of the data of events, e.g. data.vm.powerState == "off"
+type Filter struct {
+	Path     string
+	Operator string
+	Value    interface{}
+}
+
+// ParseFilter parses a filter expression
+func ParseFilter(expression string) (*Filter, error) {
+	match := filterPattern.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, errors.Errorf("invalid filter %q, expected PATH OPERATOR VALUE", expression)
+	}
+	f := &Filter{Path: match[1], Operator: match[2]}
+	root := strings.SplitN(f.Path, ".", 2)
+	switch {
+	case root[0] == "data":
+	case root[0] == "extensions" && len(root) == 2:
+	case filterAttributes[f.Path]:
+	default:
+		return nil, errors.Errorf("invalid filter %q, %s is not an event attribute, extensions.NAME or data", expression, f.Path)
+	}
+	if err := json.Unmarshal([]byte(match[3]), &f.Value); err != nil {
+		return nil, errors.Errorf("invalid filter %q, %s is not a JSON value (strings are quoted)", expression, match[3])
+	}
+	return f, nil
+}
+
+// ParseFilters parses the filters of a subscription
+func ParseFilters(sub *entities.Subscription) ([]*Filter, error) {
+	var filters []*Filter
+	for _, expression := range sub.Filters {
+		f, err := ParseFilter(expression)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// match evaluates the filter on an event given as returned by filterInput. A missing value doesn't match.
+func (f *Filter) match(input map[string]interface{}) (bool, error) {
+	value, ok := utils.LookupPath(input, f.Path)
+	if !ok {
+		return false, nil
+	}
+	return utils.Compare(value, f.Operator, f.Value)
+}
+
+// matchFilters returns whether an event matches all filters
+func matchFilters(filters []*Filter, event *events.CloudEvent) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+	input := filterInput(filters, event)
+	for _, f := range filters {
+		matched, err := f.match(input)
+		if err != nil {
+			return false, errors.Wrapf(err, "error evaluating filter on %s", f.Path)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// filterInput returns the attributes of an event as a JSON object, data is decoded only if a filter selects it
+func filterInput(filters []*Filter, event *events.CloudEvent) map[string]interface{} {
+	input := map[string]interface{}{
+		"eventType":          event.EventType,
+		"eventTypeVersion":   event.EventTypeVersion,
+		"cloudEventsVersion": event.CloudEventsVersion,
+		"source":             event.Source,
+		"eventID":            event.EventID,
+		"schemaURL":          event.SchemaURL,
+		"contentType":        event.ContentType,
+		"extensions":         map[string]interface{}(event.Extensions),
+	}
+	for _, f := range filters {
+		if f.Path != "data" && !strings.HasPrefix(f.Path, "data.") {
+			continue
+		}
+		var data interface{}
+		// data which isn't JSON matches no filter on data
+		if len(event.Data) > 0 && json.Unmarshal(event.Data, &data) == nil {
+			input["data"] = data
+		}
+		break
+	}
+	return input
+}
@@ -0,0 +1,78 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/events"
+)
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter(`data.vm.powerState == "off"`)
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{Path: "data.vm.powerState", Operator: "==", Value: "off"}, f)
+
+	f, err = ParseFilter(`extensions.priority>=2`)
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{Path: "extensions.priority", Operator: ">=", Value: float64(2)}, f)
+
+	for _, expression := range []string{
+		`data.vm.powerState = "off"`,
+		`data.vm.powerState == off`,
+		`origin == "vcenter"`,
+		`extensions == "x"`,
+		`== "off"`,
+	} {
+		_, err := ParseFilter(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestMatchFilters(t *testing.T) {
+	event := &events.CloudEvent{
+		EventType:        "vm.updated",
+		EventTypeVersion: "6.5",
+		Source:           "vcenter",
+		Extensions:       events.CloudEventExtensions{"priority": 3},
+		Data:             json.RawMessage(`{"vm": {"name": "web-1", "powerState": "off", "cpus": 4}}`),
+	}
+	cases := []struct {
+		filters []string
+		matched bool
+	}{
+		{nil, true},
+		{[]string{`source == "vcenter"`, `eventTypeVersion == "6.5"`}, true},
+		{[]string{`source == "vcenter"`, `data.vm.powerState == "on"`}, false},
+		{[]string{`data.vm.powerState == "off"`, `data.vm.cpus > 2`}, true},
+		{[]string{`extensions.priority >= 3`}, true},
+		{[]string{`extensions.owner == "me"`}, false},
+		{[]string{`data.vm.memory < 1024`}, false},
+		// a type mismatch doesn't match
+		{[]string{`data.vm.name > 2`}, false},
+	}
+	for _, c := range cases {
+		var filters []*Filter
+		for _, expression := range c.filters {
+			f, err := ParseFilter(expression)
+			require.NoError(t, err)
+			filters = append(filters, f)
+		}
+		matched, _ := matchFilters(filters, event)
+		assert.Equal(t, c.matched, matched, "%v", c.filters)
+	}
+
+	// data which isn't JSON matches no filter on data
+	f, err := ParseFilter(`data == "text"`)
+	require.NoError(t, err)
+	matched, err := matchFilters([]*Filter{f}, &events.CloudEvent{Data: json.RawMessage(`text`)})
+	assert.NoError(t, err)
+	assert.False(t, matched)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
@@ -20,6 +21,7 @@ import (
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
 	subscriptionsapi "github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations/subscriptions"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
+	"github.com/vmware/dispatch/pkg/events/validator"
 	"github.com/vmware/dispatch/pkg/trace"
 	"github.com/vmware/dispatch/pkg/utils"
 )
@@ -32,13 +34,16 @@ const (
 type Handlers struct {
 	store   entitystore.EntityStore
 	watcher controller.Watcher
+	stats   FilterStats
 }
 
-// NewHandlers Creates new instance of subscription handlers
-func NewHandlers(store entitystore.EntityStore, watcher controller.Watcher) *Handlers {
+// NewHandlers Creates new instance of subscription handlers, the filter counters of subscriptions are reported if
+// stats is not nil
+func NewHandlers(store entitystore.EntityStore, watcher controller.Watcher, stats FilterStats) *Handlers {
 	return &Handlers{
 		watcher: watcher,
 		store:   store,
+		stats:   stats,
 	}
 }
 
@@ -70,6 +75,12 @@ func (h *Handlers) addSubscription(params subscriptionsapi.AddSubscriptionParams
 
 	s := &entities.Subscription{}
 	s.FromModel(params.Body, params.XDispatchOrg)
+	if err := validate(s); err != nil {
+		return subscriptionsapi.NewAddSubscriptionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
 	s.Status = entitystore.StatusCREATING
 	_, err := h.store.Add(ctx, s)
 	if err != nil {
@@ -120,7 +131,7 @@ func (h *Handlers) getSubscription(params subscriptionsapi.GetSubscriptionParams
 				Message: utils.ErrorMsgNotFound("subscription", params.SubscriptionName),
 			})
 	}
-	return subscriptionsapi.NewGetSubscriptionOK().WithPayload(s.ToModel())
+	return subscriptionsapi.NewGetSubscriptionOK().WithPayload(h.toModel(&s))
 }
 
 // getSubscriptions handles retrieval of Subscription list
@@ -154,7 +165,7 @@ func (h *Handlers) getSubscriptions(params subscriptionsapi.GetSubscriptionsPara
 	}
 	var subscriptionModels []*v1.Subscription
 	for _, sub := range subscriptions {
-		subscriptionModels = append(subscriptionModels, sub.ToModel())
+		subscriptionModels = append(subscriptionModels, h.toModel(sub))
 	}
 	return subscriptionsapi.NewGetSubscriptionsOK().WithPayload(subscriptionModels)
 }
@@ -198,6 +209,13 @@ func (h *Handlers) updateSubscription(params subscriptionsapi.UpdateSubscription
 	}
 
 	s.FromModel(params.Body, s.OrganizationID)
+	if err := validate(s); err != nil {
+		return subscriptionsapi.NewUpdateSubscriptionBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
 	s.Status = entitystore.StatusUPDATING
 	if _, err = h.store.Update(ctx, s.Revision, s); err != nil {
 		log.Errorf("store error when updating a subscription %s: %+v", s.Name, err)
@@ -212,6 +230,15 @@ func (h *Handlers) updateSubscription(params subscriptionsapi.UpdateSubscription
 	return subscriptionsapi.NewUpdateSubscriptionOK().WithPayload(s.ToModel())
 }
 
+// validate checks the event type, which may be a pattern, and the filters of a subscription
+func validate(s *entities.Subscription) error {
+	if !validator.ValidEventTypePattern(s.EventType) {
+		return errors.Errorf("invalid event type '%s'", s.EventType)
+	}
+	_, err := ParseFilters(s)
+	return err
+}
+
 // deleteSubscription handles deletion of a Subscription
 func (h *Handlers) deleteSubscription(params subscriptionsapi.DeleteSubscriptionParams, principal interface{}) middleware.Responder {
 	span, ctx := trace.Trace(params.HTTPRequest.Context(), "deleteSubscription")
@@ -261,3 +288,12 @@ func (h *Handlers) deleteSubscription(params subscriptionsapi.DeleteSubscription
 	h.watcher.OnAction(ctx, s)
 	return subscriptionsapi.NewDeleteSubscriptionOK().WithPayload(s.ToModel())
 }
+
+// toModel converts a subscription to its model, with its filter counters
+func (h *Handlers) toModel(s *entities.Subscription) *v1.Subscription {
+	m := s.ToModel()
+	if h.stats != nil {
+		m.MatchedEvents, m.UnmatchedEvents = h.stats.FilterStats(s)
+	}
+	return m
+}
@@ -10,12 +10,15 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/swag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations"
 	"github.com/vmware/dispatch/pkg/event-manager/gen/restapi/operations/subscriptions"
+	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/mocks"
 	helpers "github.com/vmware/dispatch/pkg/testing/api"
 )
 
@@ -56,7 +59,7 @@ func addSubscriptionEntityWithError(t *testing.T, api *operations.EventManagerAP
 func TestSubscriptionsAddSubscriptionHandlerError(t *testing.T) {
 	api := operations.NewEventManagerAPI(nil)
 	es := helpers.MakeEntityStore(t)
-	h := Handlers{es, nil}
+	h := Handlers{es, nil, nil}
 	helpers.MakeAPI(t, h.ConfigureHandlers, api)
 
 	respBody := addSubscriptionEntityWithError(t, api, "test.topic", "testfunction")
@@ -64,10 +67,41 @@ func TestSubscriptionsAddSubscriptionHandlerError(t *testing.T) {
 	assert.Equal(t, int64(http.StatusBadRequest), respBody.Code)
 }
 
+func TestSubscriptionsInvalidEventType(t *testing.T) {
+	api := operations.NewEventManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := Handlers{es, nil, nil}
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	reqBody := &v1.Subscription{
+		Name:      swag.String("mysubscription"),
+		EventType: swag.String("vm.cre#"),
+		Function:  swag.String("testfunction"),
+	}
+	params := subscriptions.AddSubscriptionParams{
+		HTTPRequest:  httptest.NewRequest("POST", "/v1/event/subscriptions", nil),
+		Body:         reqBody,
+		XDispatchOrg: testOrgID,
+	}
+	var errorBody v1.Error
+	helpers.HandlerRequest(t, api.SubscriptionsAddSubscriptionHandler.Handle(params, "testCookie"), &errorBody, 400)
+
+	addSubscriptionEntity(t, api, "mysubscription", "vm.#", "testfunction")
+	reqBody.EventType = swag.String("vm*")
+	updateParams := subscriptions.UpdateSubscriptionParams{
+		HTTPRequest:      httptest.NewRequest("PUT", "/v1/event/subscriptions/mysubscription", nil),
+		Body:             reqBody,
+		SubscriptionName: "mysubscription",
+		XDispatchOrg:     testOrgID,
+	}
+	helpers.HandlerRequest(t, api.SubscriptionsUpdateSubscriptionHandler.Handle(updateParams, "testCookie"), &errorBody, 400)
+	assert.Equal(t, "invalid event type 'vm*'", *errorBody.Message)
+}
+
 func TestSubscriptionsAddSubscriptionHandler(t *testing.T) {
 	api := operations.NewEventManagerAPI(nil)
 	es := helpers.MakeEntityStore(t)
-	h := Handlers{es, nil}
+	h := Handlers{es, nil, nil}
 	helpers.MakeAPI(t, h.ConfigureHandlers, api)
 
 	respBody := addSubscriptionEntity(t, api, "mysubscription", "test.topic", "testfunction")
@@ -78,7 +112,7 @@ func TestSubscriptionsAddSubscriptionHandler(t *testing.T) {
 func TestSubscriptionsGetSubscriptionHandler(t *testing.T) {
 	api := operations.NewEventManagerAPI(nil)
 	es := helpers.MakeEntityStore(t)
-	h := Handlers{es, nil}
+	h := Handlers{es, nil, nil}
 	helpers.MakeAPI(t, h.ConfigureHandlers, api)
 
 	addBody := addSubscriptionEntity(t, api, "mysubscription", "test.topic", "testfunction")
@@ -112,10 +146,50 @@ func TestSubscriptionsGetSubscriptionHandler(t *testing.T) {
 	assert.EqualValues(t, http.StatusNotFound, errorBody.Code)
 }
 
+func TestSubscriptionsFilters(t *testing.T) {
+	api := operations.NewEventManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	stats := &mocks.Manager{}
+	stats.On("FilterStats", mock.Anything).Return(int64(3), int64(2))
+	h := NewHandlers(es, nil, stats)
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	add := func(name string, filters []string) middleware.Responder {
+		return api.SubscriptionsAddSubscriptionHandler.Handle(subscriptions.AddSubscriptionParams{
+			HTTPRequest: httptest.NewRequest("POST", "/v1/event/subscriptions", nil),
+			Body: &v1.Subscription{
+				Name:      swag.String(name),
+				EventType: swag.String("vm.updated"),
+				Function:  swag.String("testfunction"),
+				Filters:   filters,
+			},
+			XDispatchOrg: testOrgID,
+		}, "testCookie")
+	}
+	var errorBody v1.Error
+	helpers.HandlerRequest(t, add("invalid", []string{`data.vm.powerState = "off"`}), &errorBody, 400)
+	assert.Contains(t, *errorBody.Message, "invalid filter")
+
+	var addBody v1.Subscription
+	helpers.HandlerRequest(t, add("poweroff", []string{`data.vm.powerState == "off"`}), &addBody, 201)
+	assert.Equal(t, []string{`data.vm.powerState == "off"`}, addBody.Filters)
+
+	get := subscriptions.GetSubscriptionParams{
+		HTTPRequest:      httptest.NewRequest("GET", "/v1/event/subscriptions/poweroff", nil),
+		SubscriptionName: "poweroff",
+		XDispatchOrg:     testOrgID,
+	}
+	var getBody v1.Subscription
+	helpers.HandlerRequest(t, api.SubscriptionsGetSubscriptionHandler.Handle(get, "testCookie"), &getBody, 200)
+	assert.Equal(t, []string{`data.vm.powerState == "off"`}, getBody.Filters)
+	assert.EqualValues(t, 3, getBody.MatchedEvents)
+	assert.EqualValues(t, 2, getBody.UnmatchedEvents)
+}
+
 func TestSubscriptionsDeleteSubscriptionHandler(t *testing.T) {
 	api := operations.NewEventManagerAPI(nil)
 	es := helpers.MakeEntityStore(t)
-	h := Handlers{es, nil}
+	h := Handlers{es, nil, nil}
 	helpers.MakeAPI(t, h.ConfigureHandlers, api)
 
 	addBody := addSubscriptionEntity(t, api, "mysubscription", "test.topic", "testfunction")
@@ -9,41 +9,80 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/entity-store"
 
 	"github.com/vmware/dispatch/pkg/client"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
+	logentities "github.com/vmware/dispatch/pkg/event-manager/eventlog/entities"
 	"github.com/vmware/dispatch/pkg/event-manager/helpers"
 	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
 	"github.com/vmware/dispatch/pkg/events"
 	"github.com/vmware/dispatch/pkg/trace"
 )
 
+// DefaultRedeliveryTimeout is how long the delivery of an event to a subscription is retried by default
+const DefaultRedeliveryTimeout = time.Minute
+
+// deadLetterExtension prefixes the extensions added to the events published to dead-letter topics
+const deadLetterExtension = "dispatch-dead-letter-"
+
 // Manager defines the subscription manager interface
 type Manager interface {
 	Run(context.Context, []*entities.Subscription) error
 	Create(context.Context, *entities.Subscription) error
 	Update(context.Context, *entities.Subscription) error
 	Delete(context.Context, *entities.Subscription) error
+	Matching(*entities.Subscription, []*events.CloudEvent) ([]*events.CloudEvent, error)
+	Replay(context.Context, *entities.Subscription, []*events.CloudEvent) int
+	FilterStats
+}
+
+// FilterStats counts the events received by subscriptions which matched their filters, and those which didn't
+type FilterStats interface {
+	FilterStats(*entities.Subscription) (matched int64, unmatched int64)
+}
+
+// filterStats are the counters of a subscription, updated atomically
+type filterStats struct {
+	matched   int64
+	unmatched int64
 }
 
 type defaultManager struct {
 	queue    events.Transport
 	fnClient client.FunctionsClient
+	eventLog *eventlog.Log
+	// redeliveryTimeout is how long the delivery of an event is retried before it is dead-lettered, 0 for one attempt
+	redeliveryTimeout time.Duration
 
 	sync.RWMutex
 	activeSubs map[string]events.Subscription
+	// counters of the subscriptions, since the manager started
+	stats map[string]*filterStats
 }
 
-// NewManager creates a new subscription manager
-func NewManager(mq events.Transport, fnClient client.FunctionsClient) (Manager, error) {
+// NewManager creates a new subscription manager, recording the deliveries of events in the event log if not nil. An
+// event which could not be delivered is delivered again by the transport for the redelivery timeout,
+// DefaultRedeliveryTimeout if 0.
+func NewManager(mq events.Transport, fnClient client.FunctionsClient, eventLog *eventlog.Log, redeliveryTimeout time.Duration) (Manager, error) {
+	if redeliveryTimeout <= 0 {
+		redeliveryTimeout = DefaultRedeliveryTimeout
+	}
 	ec := defaultManager{
-		queue:      mq,
-		fnClient:   fnClient,
-		activeSubs: make(map[string]events.Subscription),
+		queue:             mq,
+		fnClient:          fnClient,
+		eventLog:          eventLog,
+		redeliveryTimeout: redeliveryTimeout,
+		activeSubs:        make(map[string]events.Subscription),
+		stats:             make(map[string]*filterStats),
 	}
 
 	return &ec, nil
@@ -120,9 +159,21 @@ func (m *defaultManager) Update(ctx context.Context, sub *entities.Subscription)
 }
 
 func (m *defaultManager) createSubscription(ctx context.Context, sub *entities.Subscription) (events.Subscription, error) {
+	filters, err := ParseFilters(sub)
+	if err != nil {
+		err = errors.Wrapf(err, "unable to create a subscription for event %s and function %s", sub.EventType, sub.Function)
+		log.Error(err)
+		return nil, err
+	}
+	stats, ok := m.stats[sub.ID]
+	if !ok {
+		stats = &filterStats{}
+		m.stats[sub.ID] = stats
+	}
 	topic := sub.EventType
-	// subscribe
-	eventSub, err := m.queue.Subscribe(ctx, topic, sub.OrganizationID, m.handler(ctx, sub))
+	// subscribe, named after the ID of the subscription so a subscription created again with the same name doesn't
+	// receive the events published to the one deleted
+	eventSub, err := m.queue.Subscribe(ctx, topic, sub.OrganizationID, sub.ID, m.handler(ctx, sub, filters, stats))
 	if err != nil {
 		err = errors.Wrapf(err, "unable to create a subscription for event %s and function %s", sub.EventType, sub.Function)
 
@@ -147,6 +198,7 @@ func (m *defaultManager) Delete(ctx context.Context, sub *entities.Subscription)
 		eventSub.Unsubscribe()
 		delete(m.activeSubs, sub.ID)
 	}
+	delete(m.stats, sub.ID)
 	log.Debugf("Deleting subscription topic=%s id=%s revision=%d", sub.EventType, sub.Name, sub.Revision)
 	return nil
 }
@@ -161,26 +213,158 @@ func (m *defaultManager) Shutdown() {
 	}
 }
 
-// handler creates a function to handle the incoming event. it takes name of the function to be invoked as an argument.
-func (m *defaultManager) handler(ctx context.Context, sub *entities.Subscription) func(context.Context, *events.CloudEvent) {
+// FilterStats returns the numbers of events received by a subscription which matched its filters and which didn't
+func (m *defaultManager) FilterStats(sub *entities.Subscription) (int64, int64) {
+	m.RLock()
+	stats, ok := m.stats[sub.ID]
+	m.RUnlock()
+	if !ok {
+		return 0, 0
+	}
+	return atomic.LoadInt64(&stats.matched), atomic.LoadInt64(&stats.unmatched)
+}
+
+// handler creates a function to handle the incoming event, running the function of the subscription if the event
+// matches its filters. The event is acknowledged once delivered, or published to the dead-letter topic.
+func (m *defaultManager) handler(ctx context.Context, sub *entities.Subscription, filters []*Filter, stats *filterStats) events.Handler {
 	span, _ := trace.Trace(ctx, "")
 	defer span.Finish()
 
 	span.SetTag("eventType", sub.EventType)
 	span.SetTag("functionName", sub.Function)
 
-	return func(ctx context.Context, event *events.CloudEvent) {
-		span, ctx := trace.Trace(ctx, "EventHandler")
+	return func(ctx context.Context, event *events.CloudEvent) error {
+		span, ctx := eventSpan(ctx, event)
 		defer span.Finish()
 		span.SetTag("eventType", sub.EventType)
 		span.SetTag("functionName", sub.Function)
 
-		m.runFunction(ctx, sub.OrganizationID, sub.Function, event, sub.Secrets)
+		matched, err := matchFilters(filters, event)
+		if err != nil {
+			log.Debugf("event %s does not match the filters of subscription %s: %s", event.EventID, sub.Name, err)
+		}
+		if !matched {
+			atomic.AddInt64(&stats.unmatched, 1)
+			span.SetTag("filtered", true)
+			return nil
+		}
+		atomic.AddInt64(&stats.matched, 1)
+
+		return m.deliverOrDeadLetter(ctx, sub, event)
+	}
+}
+
+// deliverOrDeadLetter delivers an event to a subscription, the transport delivering it again if that fails. An event
+// which still could not be delivered after the redelivery timeout is published to the dead-letter topic of the
+// subscription, an error is returned only if that fails too.
+func (m *defaultManager) deliverOrDeadLetter(ctx context.Context, sub *entities.Subscription, event *events.CloudEvent) error {
+	delivery := events.DeliveryFromContext(ctx)
+	// a redelivered event must not run the function again, unless its run failed. Keys are scoped to the subscription,
+	// as another subscription of the same function must run it for the same event.
+	key := sub.ID + "/" + event.EventID
+	if sub.Ack == v1.SubscriptionAckSucceeded {
+		key = fmt.Sprintf("%s/%d", key, delivery.Count)
+	}
+	err := m.deliver(ctx, sub, event, key, false)
+	if err == nil || time.Since(delivery.First) < m.redeliveryTimeout {
+		return err
+	}
+	return m.deadLetter(ctx, sub, event, delivery.Count, err)
+}
+
+// deadLetter publishes an event which could not be delivered to the dead-letter topic of the subscription, with the
+// subscription, the number of attempts and the last error in extensions
+func (m *defaultManager) deadLetter(ctx context.Context, sub *entities.Subscription, event *events.CloudEvent, attempts int, cause error) error {
+	// a subscription receiving its own dead letters, e.g. to #, would publish them again forever
+	if event.Extensions[deadLetterExtension+"subscription"] == sub.Name {
+		log.Errorf("dropping event %s of the dead-letter topic of subscription %s, which could not be delivered to it: %s", event.EventID, sub.Name, cause)
+		return nil
+	}
+	dead := *event
+	dead.Extensions = events.CloudEventExtensions{}
+	for k, v := range event.Extensions {
+		dead.Extensions[k] = v
+	}
+	dead.Extensions[deadLetterExtension+"subscription"] = sub.Name
+	dead.Extensions[deadLetterExtension+"attempts"] = attempts
+	dead.Extensions[deadLetterExtension+"error"] = cause.Error()
+	if err := m.queue.Publish(ctx, &dead, sub.DeadLetterTopic(), sub.OrganizationID); err != nil {
+		return errors.Wrapf(err, "error publishing event %s to the dead-letter topic of subscription %s", event.EventID, sub.Name)
+	}
+	log.Warnf("event %s could not be delivered to subscription %s after %d attempts, published to %s: %s", event.EventID, sub.Name, attempts, sub.DeadLetterTopic(), cause)
+	return nil
+}
+
+// Matching returns the events matching the filters of the subscription, the subscription would not have received
+// the others
+func (m *defaultManager) Matching(sub *entities.Subscription, evs []*events.CloudEvent) ([]*events.CloudEvent, error) {
+	filters, err := ParseFilters(sub)
+	if err != nil {
+		return nil, err
+	}
+	var matching []*events.CloudEvent
+	for _, event := range evs {
+		if matched, _ := matchFilters(filters, event); matched {
+			matching = append(matching, event)
+		}
+	}
+	return matching, nil
+}
+
+// Replay runs the function of the subscription for recorded events, even if they were already delivered to it. The
+// events are selected with Matching.
+// It returns the number of events replayed, failures are recorded in the event log.
+func (m *defaultManager) Replay(ctx context.Context, sub *entities.Subscription, evs []*events.CloudEvent) int {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	// replaying the same events twice runs the function twice
+	replayID := uuid.NewV4().String()
+	replayed := 0
+	for _, event := range evs {
+		if err := m.deliver(ctx, sub, event, event.EventID+"/"+replayID, true); err == nil {
+			replayed++
+		}
+	}
+	return replayed
+}
+
+// deliver runs the function of the subscription for an event and records the outcome in the event log. The run must
+// be accepted, or succeed if the subscription acknowledges events once their run succeeded.
+func (m *defaultManager) deliver(ctx context.Context, sub *entities.Subscription, event *events.CloudEvent, idempotencyKey string, replay bool) error {
+	delivery := logentities.Delivery{
+		Subscription: sub.Name,
+		Function:     sub.Function,
+		Replay:       replay,
+		Time:         time.Now(),
+	}
+	succeeded := sub.Ack == v1.SubscriptionAckSucceeded
+	run, err := m.runFunction(ctx, sub.OrganizationID, sub.Function, event, sub.Secrets, idempotencyKey, succeeded)
+	if err == nil && succeeded && run.Status != v1.StatusREADY {
+		err = errors.Errorf("run %s of function %s ended with status %s", run.Name, sub.Function, run.Status)
+	}
+	if run != nil {
+		delivery.Run = run.Name.String()
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	if err := m.eventLog.Deliver(ctx, sub.OrganizationID, event.EventID, delivery); err != nil {
+		log.Errorf("error recording the delivery of event %s to subscription %s: %+v", event.EventID, sub.Name, err)
+	}
+	return err
+}
+
+// eventSpan begins the span handling an event, following from the span of its emission when the event carries it
+func eventSpan(ctx context.Context, event *events.CloudEvent) (opentracing.Span, context.Context) {
+	if spanCtx, err := event.ExtractSpan(); err == nil && spanCtx != nil {
+		return trace.FollowsFrom(ctx, "EventHandler", spanCtx)
 	}
+	return trace.Trace(ctx, "EventHandler")
 }
 
-// executes a function by connecting to function manager
-func (m *defaultManager) runFunction(ctx context.Context, organizationID string, fnName string, event *events.CloudEvent, secrets []string) {
+// executes a function by connecting to function manager, waiting for the end of the run if blocking
+func (m *defaultManager) runFunction(ctx context.Context, organizationID string, fnName string, event *events.CloudEvent, secrets []string, idempotencyKey string, blocking bool) (*v1.Run, error) {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
@@ -188,9 +372,11 @@ func (m *defaultManager) runFunction(ctx context.Context, organizationID string,
 	span.SetTag("functionName", fnName)
 
 	run := v1.Run{
-		Blocking:     false,
-		FunctionName: fnName,
-		Input:        event.Data,
+		Blocking:       blocking,
+		FunctionName:   fnName,
+		Input:          event.Data,
+		IdempotencyKey: idempotencyKey,
+		Priority:       v1.RunPriorityLow,
 	}
 	eventCopy := *event
 	eventCopy.Data = nil
@@ -201,11 +387,11 @@ func (m *defaultManager) runFunction(ctx context.Context, organizationID string,
 		errorMsg := fmt.Sprintf("Unable to run function %s, error from function manager: %+v", fnName, err)
 		span.LogKV("error", errorMsg)
 		log.Error(errorMsg)
-		return
+		return nil, errors.Wrapf(err, "error running function %s", fnName)
 	}
 	span.LogKV("functionName", result.FunctionName,
 		"functionResult", result.Output)
 	log.Debugf("Function %s returned %+v", result.FunctionName, result.Output)
 
-	return
+	return result, nil
 }
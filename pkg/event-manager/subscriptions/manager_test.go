@@ -9,14 +9,23 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/client"
 	clientmocks "github.com/vmware/dispatch/pkg/client/mocks"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/eventlog"
+	"github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
 	"github.com/vmware/dispatch/pkg/events"
 	eventsmocks "github.com/vmware/dispatch/pkg/events/mocks"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
 )
 
 func mockSubscriptionManager(queue events.Transport, fnClient client.FunctionsClient) *defaultManager {
@@ -24,6 +33,7 @@ func mockSubscriptionManager(queue events.Transport, fnClient client.FunctionsCl
 		queue:      queue,
 		fnClient:   fnClient,
 		activeSubs: make(map[string]events.Subscription),
+		stats:      make(map[string]*filterStats),
 	}
 }
 
@@ -33,9 +43,243 @@ func TestRunFunction(t *testing.T) {
 	manager := mockSubscriptionManager(queue, fnClient)
 	ev := &events.CloudEvent{}
 	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.AnythingOfType("*v1.Run")).Return(&v1.Run{}, nil).Once()
-	manager.runFunction(context.Background(), testOrgID, "testFunction", ev, []string{"secret1", "secret2"})
+	_, err := manager.runFunction(context.Background(), testOrgID, "testFunction", ev, []string{"secret1", "secret2"}, ev.EventID, false)
+	assert.NoError(t, err)
 
 	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.AnythingOfType("*v1.Run")).Return(&v1.Run{}, errors.New("testerror")).Once()
-	manager.runFunction(context.Background(), testOrgID, "testFunction", ev, nil)
+	_, err = manager.runFunction(context.Background(), testOrgID, "testFunction", ev, nil, ev.EventID, false)
+	assert.Error(t, err)
 	fnClient.AssertNumberOfCalls(t, "RunFunction", 2)
 }
+
+func TestMatching(t *testing.T) {
+	manager := mockSubscriptionManager(&eventsmocks.Transport{}, &clientmocks.FunctionsClient{})
+	sub := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1"},
+		EventType:  "vm.created",
+		Function:   "hello",
+		Filters:    []string{`source == "vcenter"`},
+	}
+	evs := []*events.CloudEvent{{EventID: "ev-1", Source: "vcenter"}, {EventID: "ev-2", Source: "other"}}
+
+	matching, err := manager.Matching(sub, evs)
+	require.NoError(t, err)
+	require.Len(t, matching, 1)
+	assert.Equal(t, "ev-1", matching[0].EventID)
+
+	sub.Filters = []string{`source = "vcenter"`}
+	_, err = manager.Matching(sub, evs)
+	assert.Error(t, err)
+}
+
+func TestReplay(t *testing.T) {
+	fnClient := &clientmocks.FunctionsClient{}
+	queue := &eventsmocks.Transport{}
+	manager := mockSubscriptionManager(queue, fnClient)
+	manager.eventLog = eventlog.NewLog(helpers.MakeEntityStore(t), queue, 0)
+	sub := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1"},
+		EventType:  "vm.created",
+		Function:   "hello",
+	}
+	evs := []*events.CloudEvent{{EventID: "ev-1"}, {EventID: "ev-2"}}
+
+	var keys []string
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.MatchedBy(func(run *v1.Run) bool {
+		if run.Event.EventID != "ev-1" {
+			return false
+		}
+		keys = append(keys, run.IdempotencyKey)
+		return true
+	})).Return(&v1.Run{Name: "run-1"}, nil)
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.Anything).Return(nil, errors.New("testerror"))
+
+	assert.Equal(t, 1, manager.Replay(context.Background(), sub, evs))
+	assert.Equal(t, 1, manager.Replay(context.Background(), sub, evs[:1]))
+	// every replay runs the function again
+	require.Len(t, keys, 2)
+	assert.NotEqual(t, keys[0], keys[1])
+	assert.NotEqual(t, "ev-1", keys[0])
+
+	for _, ev := range evs {
+		require.NoError(t, manager.eventLog.Record(context.Background(), testOrgID, "vm.created", v1.EventRecordOriginAPI, ev))
+	}
+	records, err := manager.eventLog.Get(context.Background(), testOrgID, []string{"ev-1", "ev-2"})
+	require.NoError(t, err)
+	require.Len(t, records[0].Deliveries, 2)
+	assert.Equal(t, "run-1", records[0].Deliveries[0].Run)
+	assert.True(t, records[0].Deliveries[0].Replay)
+	require.Len(t, records[1].Deliveries, 1)
+	assert.Equal(t, "error running function hello: testerror", records[1].Deliveries[0].Error)
+}
+
+func TestHandlerFilters(t *testing.T) {
+	fnClient := &clientmocks.FunctionsClient{}
+	queue := &eventsmocks.Transport{}
+	manager := mockSubscriptionManager(queue, fnClient)
+	sub := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1", ID: "sub-1-id"},
+		EventType:  "vm.updated",
+		Function:   "hello",
+		Filters:    []string{`data.vm.powerState == "off"`},
+	}
+
+	var handler events.Handler
+	queue.On("Subscribe", mock.Anything, "vm.updated", testOrgID, "sub-1-id", mock.Anything).Run(func(args mock.Arguments) {
+		handler = args.Get(4).(events.Handler)
+	}).Return(nil, nil)
+	require.NoError(t, manager.Create(context.Background(), sub))
+	require.NotNil(t, handler)
+
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.AnythingOfType("*v1.Run")).Return(&v1.Run{Name: "run-1"}, nil).Once()
+	assert.NoError(t, handler(context.Background(), &events.CloudEvent{EventID: "ev-1", Data: []byte(`{"vm": {"powerState": "off"}}`)}))
+	assert.NoError(t, handler(context.Background(), &events.CloudEvent{EventID: "ev-2", Data: []byte(`{"vm": {"powerState": "on"}}`)}))
+	assert.NoError(t, handler(context.Background(), &events.CloudEvent{EventID: "ev-3"}))
+	fnClient.AssertNumberOfCalls(t, "RunFunction", 1)
+
+	matched, unmatched := manager.FilterStats(sub)
+	assert.EqualValues(t, 1, matched)
+	assert.EqualValues(t, 2, unmatched)
+
+	// invalid filters can't be subscribed
+	invalid := *sub
+	invalid.ID = "sub-2-id"
+	invalid.Filters = []string{"powerState"}
+	assert.Error(t, manager.Create(context.Background(), &invalid))
+}
+
+func TestHandlerRetries(t *testing.T) {
+	fnClient := &clientmocks.FunctionsClient{}
+	queue := &eventsmocks.Transport{}
+	manager := mockSubscriptionManager(queue, fnClient)
+	manager.redeliveryTimeout = 5 * time.Second
+	sub := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1", ID: "sub-1-id"},
+		EventType:  "vm.created",
+		Function:   "hello",
+	}
+	handler := manager.handler(context.Background(), sub, nil, &filterStats{})
+
+	delivery := func(count int) context.Context {
+		return events.WithDelivery(context.Background(), events.Delivery{Count: count, First: time.Now()})
+	}
+
+	// the event is rejected until its run is accepted, the transport delivering it again with the same idempotency key
+	var keys []string
+	record := mock.MatchedBy(func(run *v1.Run) bool {
+		keys = append(keys, run.IdempotencyKey)
+		return !run.Blocking
+	})
+	fnClient.On("RunFunction", mock.Anything, testOrgID, record).Return(nil, errors.New("testerror")).Once()
+	fnClient.On("RunFunction", mock.Anything, testOrgID, record).Return(&v1.Run{Name: "run-1"}, nil).Once()
+	assert.Error(t, handler(delivery(1), &events.CloudEvent{EventID: "ev-1"}))
+	assert.NoError(t, handler(delivery(2), &events.CloudEvent{EventID: "ev-1"}))
+	fnClient.AssertNumberOfCalls(t, "RunFunction", 2)
+	assert.Equal(t, []string{"sub-1-id/ev-1", "sub-1-id/ev-1"}, keys)
+
+	// the function of a subscription acknowledging events once their run succeeded runs again after a failure
+	keys = nil
+	sub.Ack = v1.SubscriptionAckSucceeded
+	record = mock.MatchedBy(func(run *v1.Run) bool {
+		keys = append(keys, run.IdempotencyKey)
+		return run.Blocking
+	})
+	fnClient.On("RunFunction", mock.Anything, testOrgID, record).Return(&v1.Run{Name: "run-2", Status: v1.StatusERROR}, nil).Once()
+	fnClient.On("RunFunction", mock.Anything, testOrgID, record).Return(&v1.Run{Name: "run-3", Status: v1.StatusREADY}, nil).Once()
+	assert.Error(t, handler(delivery(1), &events.CloudEvent{EventID: "ev-2"}))
+	assert.NoError(t, handler(delivery(2), &events.CloudEvent{EventID: "ev-2"}))
+	fnClient.AssertNumberOfCalls(t, "RunFunction", 4)
+	assert.Equal(t, []string{"sub-1-id/ev-2/1", "sub-1-id/ev-2/2"}, keys)
+
+	// the event is dead-lettered once its deliveries failed for the redelivery timeout
+	queue.On("Publish", mock.Anything, mock.Anything, "dispatch.dead-letter.sub-1", testOrgID).Return(nil).Once()
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.Anything).Return(nil, errors.New("testerror")).Once()
+	late := events.WithDelivery(context.Background(), events.Delivery{Count: 7, First: time.Now().Add(-time.Minute)})
+	assert.NoError(t, handler(late, &events.CloudEvent{EventID: "ev-3"}))
+	queue.AssertExpectations(t)
+}
+
+func TestHandlerSameFunction(t *testing.T) {
+	fnClient := &clientmocks.FunctionsClient{}
+	manager := mockSubscriptionManager(&eventsmocks.Transport{}, fnClient)
+	first := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1", ID: "sub-1-id"},
+		EventType:  "vm.created",
+		Function:   "hello",
+	}
+	second := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-2", ID: "sub-2-id"},
+		EventType:  "vm.*",
+		Function:   "hello",
+	}
+
+	// each subscription runs the function for the event, their runs aren't deduplicated against each other
+	var keys []string
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.MatchedBy(func(run *v1.Run) bool {
+		keys = append(keys, run.IdempotencyKey)
+		return run.FunctionName == "hello"
+	})).Return(&v1.Run{Name: "run-1"}, nil)
+	ev := &events.CloudEvent{EventID: "ev-1", EventType: "vm.created"}
+	delivery := events.WithDelivery(context.Background(), events.Delivery{Count: 1, First: time.Now()})
+	assert.NoError(t, manager.handler(context.Background(), first, nil, &filterStats{})(delivery, ev))
+	assert.NoError(t, manager.handler(context.Background(), second, nil, &filterStats{})(delivery, ev))
+	fnClient.AssertNumberOfCalls(t, "RunFunction", 2)
+	require.Len(t, keys, 2)
+	assert.NotEqual(t, keys[0], keys[1])
+}
+
+func TestHandlerDeadLetter(t *testing.T) {
+	fnClient := &clientmocks.FunctionsClient{}
+	queue := &eventsmocks.Transport{}
+	manager := mockSubscriptionManager(queue, fnClient)
+	sub := &entities.Subscription{
+		BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID, Name: "sub-1", ID: "sub-1-id"},
+		EventType:  "vm.created",
+		Function:   "hello",
+	}
+	handler := manager.handler(context.Background(), sub, nil, &filterStats{})
+	fnClient.On("RunFunction", mock.Anything, testOrgID, mock.Anything).Return(nil, errors.New("testerror"))
+
+	deadLetter := mock.MatchedBy(func(event *events.CloudEvent) bool {
+		return event.EventID == "ev-1" && event.EventType == "vm.created" &&
+			event.Extensions["dispatch-dead-letter-subscription"] == "sub-1" &&
+			event.Extensions["dispatch-dead-letter-attempts"] == 1 &&
+			event.Extensions["dispatch-dead-letter-error"] == "error running function hello: testerror"
+	})
+	queue.On("Publish", mock.Anything, deadLetter, "dispatch.dead-letter.sub-1", testOrgID).Return(nil).Once()
+	ev := &events.CloudEvent{EventID: "ev-1", EventType: "vm.created"}
+	assert.NoError(t, handler(context.Background(), ev))
+	assert.Nil(t, ev.Extensions)
+
+	// the event is rejected if it can't be dead-lettered
+	queue.On("Publish", mock.Anything, deadLetter, "dispatch.dead-letter.sub-1", testOrgID).Return(errors.New("testerror")).Once()
+	assert.Error(t, handler(context.Background(), ev))
+
+	// the subscription doesn't dead-letter its own dead letters
+	ev.Extensions = events.CloudEventExtensions{"dispatch-dead-letter-subscription": "sub-1"}
+	assert.NoError(t, handler(context.Background(), ev))
+	queue.AssertNumberOfCalls(t, "Publish", 2)
+}
+
+func TestEventSpan(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	emission := tracer.StartSpan("emitEvent")
+	ev := &events.CloudEvent{EventID: "ev-1"}
+	require.NoError(t, ev.InjectSpan(emission))
+	emission.Finish()
+
+	span, ctx := eventSpan(context.Background(), ev)
+	span.Finish()
+	assert.Equal(t, span, opentracing.SpanFromContext(ctx))
+	handled := span.(*mocktracer.MockSpan)
+	assert.Equal(t, emission.(*mocktracer.MockSpan).SpanContext.TraceID, handled.SpanContext.TraceID)
+	assert.Equal(t, emission.(*mocktracer.MockSpan).SpanContext.SpanID, handled.ParentID)
+
+	// an event without span begins a span of the context
+	span, _ = eventSpan(context.Background(), &events.CloudEvent{EventID: "ev-2"})
+	span.Finish()
+	assert.NotEqual(t, handled.SpanContext.TraceID, span.(*mocktracer.MockSpan).SpanContext.TraceID)
+}
@@ -4,6 +4,7 @@ package mocks
 
 import context "context"
 import entities "github.com/vmware/dispatch/pkg/event-manager/subscriptions/entities"
+import events "github.com/vmware/dispatch/pkg/events"
 import mock "github.com/stretchr/testify/mock"
 
 // Manager is an autogenerated mock type for the Manager type
@@ -39,6 +40,64 @@ func (_m *Manager) Delete(_a0 context.Context, _a1 *entities.Subscription) error
 	return r0
 }
 
+// FilterStats provides a mock function with given fields: _a0
+func (_m *Manager) FilterStats(_a0 *entities.Subscription) (int64, int64) {
+	ret := _m.Called(_a0)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*entities.Subscription) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(*entities.Subscription) int64); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	return r0, r1
+}
+
+// Matching provides a mock function with given fields: _a0, _a1
+func (_m *Manager) Matching(_a0 *entities.Subscription, _a1 []*events.CloudEvent) ([]*events.CloudEvent, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*events.CloudEvent
+	if rf, ok := ret.Get(0).(func(*entities.Subscription, []*events.CloudEvent) []*events.CloudEvent); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*events.CloudEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*entities.Subscription, []*events.CloudEvent) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replay provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Manager) Replay(_a0 context.Context, _a1 *entities.Subscription, _a2 []*events.CloudEvent) int {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Subscription, []*events.CloudEvent) int); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // Run provides a mock function with given fields: _a0, _a1
 func (_m *Manager) Run(_a0 context.Context, _a1 []*entities.Subscription) error {
 	ret := _m.Called(_a0, _a1)
@@ -61,9 +61,22 @@ type CloudEvent struct {
 // CloudEventExtensions holds attributes for CloudEvent that are not part of the standard.
 type CloudEventExtensions map[string]interface{}
 
-// InjectSpan injects OpenTracing Span into a CloudEvent.
+// InjectSpan injects OpenTracing Span into a CloudEvent. Extensions are left untouched if the tracer injects nothing.
 func (e *CloudEvent) InjectSpan(span opentracing.Span) error {
-	return span.Tracer().Inject(span.Context(), opentracing.TextMap, e.Extensions)
+	carrier := CloudEventExtensions{}
+	if err := span.Tracer().Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+		return err
+	}
+	if len(carrier) == 0 {
+		return nil
+	}
+	if e.Extensions == nil {
+		e.Extensions = CloudEventExtensions{}
+	}
+	for k, v := range carrier {
+		e.Extensions[k] = v
+	}
+	return nil
 }
 
 // ExtractSpan extracts OpenTracing Span from a CloudEvent.
@@ -0,0 +1,37 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// NO TESTS
+
+// Delivery is how many times, and since when, a transport delivered an event to a handler
+type Delivery struct {
+	// Count is 1 for the first delivery of the event
+	Count int
+	// First is the time of the first delivery of the event
+	First time.Time
+}
+
+type deliveryKey struct{}
+
+// WithDelivery returns a context carrying the delivery of the event handled with it
+func WithDelivery(ctx context.Context, delivery Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, delivery)
+}
+
+// DeliveryFromContext returns the delivery of the event handled with a context, a first delivery now if the context
+// doesn't carry it
+func DeliveryFromContext(ctx context.Context) Delivery {
+	if delivery, ok := ctx.Value(deliveryKey{}).(Delivery); ok {
+		return delivery
+	}
+	return Delivery{Count: 1, First: time.Now()}
+}
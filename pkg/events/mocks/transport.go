@@ -30,13 +30,13 @@ func (_m *Transport) Publish(ctx context.Context, event *events.CloudEvent, topi
 	return r0
 }
 
-// Subscribe provides a mock function with given fields: ctx, topic, organization, handler
-func (_m *Transport) Subscribe(ctx context.Context, topic string, organization string, handler events.Handler) (events.Subscription, error) {
-	ret := _m.Called(ctx, topic, organization, handler)
+// Subscribe provides a mock function with given fields: ctx, topic, organization, name, handler
+func (_m *Transport) Subscribe(ctx context.Context, topic string, organization string, name string, handler events.Handler) (events.Subscription, error) {
+	ret := _m.Called(ctx, topic, organization, name, handler)
 
 	var r0 events.Subscription
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, events.Handler) events.Subscription); ok {
-		r0 = rf(ctx, topic, organization, handler)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, events.Handler) events.Subscription); ok {
+		r0 = rf(ctx, topic, organization, name, handler)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(events.Subscription)
@@ -44,8 +44,8 @@ func (_m *Transport) Subscribe(ctx context.Context, topic string, organization s
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, events.Handler) error); ok {
-		r1 = rf(ctx, topic, organization, handler)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, events.Handler) error); ok {
+		r1 = rf(ctx, topic, organization, name, handler)
 	} else {
 		r1 = ret.Error(1)
 	}
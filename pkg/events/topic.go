@@ -0,0 +1,54 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package events
+
+import "strings"
+
+// Event types are words separated by dots, e.g. vm.disk.attached. Subscriptions may use a pattern instead, where the
+// word * matches exactly one word and the word # matches zero or more words, as the topics of AMQP: vm.* matches
+// vm.created but not vm.disk.attached, vm.# matches vm, vm.created and vm.disk.attached.
+const (
+	// WildcardWord matches exactly one word of an event type
+	WildcardWord = "*"
+	// WildcardWords matches zero or more words of an event type
+	WildcardWords = "#"
+)
+
+// IsEventTypePattern returns whether an event type contains wildcards
+func IsEventTypePattern(eventType string) bool {
+	for _, word := range strings.Split(eventType, ".") {
+		if word == WildcardWord || word == WildcardWords {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchEventType returns whether an event type matches a pattern, a pattern without wildcards matches only itself
+func MatchEventType(pattern, eventType string) bool {
+	if pattern == eventType {
+		return true
+	}
+	return matchWords(strings.Split(pattern, "."), strings.Split(eventType, "."))
+}
+
+func matchWords(pattern, words []string) bool {
+	for i, p := range pattern {
+		if p == WildcardWords {
+			rest := pattern[i+1:]
+			for j := i; j <= len(words); j++ {
+				if matchWords(rest, words[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(words) || p != WildcardWord && p != words[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(words)
+}
@@ -0,0 +1,51 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEventTypePattern(t *testing.T) {
+	assert.False(t, IsEventTypePattern("vm.created"))
+	assert.False(t, IsEventTypePattern("vm.created*"))
+	assert.True(t, IsEventTypePattern("vm.*"))
+	assert.True(t, IsEventTypePattern("#"))
+	assert.True(t, IsEventTypePattern("vm.#.attached"))
+}
+
+func TestMatchEventType(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		eventType string
+		match     bool
+	}{
+		{"vm.created", "vm.created", true},
+		{"vm.created", "vm.deleted", false},
+		{"vm.*", "vm.created", true},
+		{"vm.*", "vm", false},
+		{"vm.*", "vm.disk.attached", false},
+		{"vm.*", "host.created", false},
+		{"*.created", "vm.created", true},
+		{"vm.*.attached", "vm.disk.attached", true},
+		{"vm.#", "vm", true},
+		{"vm.#", "vm.created", true},
+		{"vm.#", "vm.disk.attached", true},
+		{"vm.#", "vmware.created", false},
+		{"#", "vm.disk.attached", true},
+		{"#.attached", "vm.disk.attached", true},
+		{"#.attached", "vm.disk.detached", false},
+		{"vm.#.attached", "vm.attached", true},
+		{"vm.#.attached", "vm.disk.nic.attached", true},
+		{"#.*", "vm", true},
+		{"*.#.*", "vm", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, MatchEventType(c.pattern, c.eventType), "%s matching %s", c.pattern, c.eventType)
+	}
+}
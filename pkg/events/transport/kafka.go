@@ -8,6 +8,8 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/opentracing/opentracing-go"
@@ -18,11 +20,19 @@ import (
 	"github.com/vmware/dispatch/pkg/trace"
 )
 
+const (
+	defaultKafkaTopicRefresh = 10 * time.Second
+)
+
 // Kafka Implements transport interface using Kafka broker.
 type Kafka struct {
 	producer     sarama.SyncProducer
+	client       sarama.Client
 	consumer     sarama.Consumer
 	producerOnly bool
+
+	// topicRefresh is how often subscriptions to a pattern look for new topics
+	topicRefresh time.Duration
 }
 
 // OptKafkaSendOnly creates producer only. Subscribe operation will panic
@@ -33,6 +43,14 @@ func OptKafkaSendOnly() func(k *Kafka) error {
 	}
 }
 
+// OptKafkaTopicRefresh sets how often subscriptions to a pattern look for new topics matching it
+func OptKafkaTopicRefresh(interval time.Duration) func(k *Kafka) error {
+	return func(k *Kafka) error {
+		k.topicRefresh = interval
+		return nil
+	}
+}
+
 // NewKafka creates an instance of transport based on Kafka broker.
 func NewKafka(brokerAddrs []string, options ...func(k *Kafka) error) (*Kafka, error) {
 	config := sarama.NewConfig()
@@ -44,7 +62,8 @@ func NewKafka(brokerAddrs []string, options ...func(k *Kafka) error) (*Kafka, er
 	}
 
 	k := Kafka{
-		producer: syncProducer,
+		producer:     syncProducer,
+		topicRefresh: defaultKafkaTopicRefresh,
 	}
 	for _, option := range options {
 		// TODO: handle errors from options
@@ -55,7 +74,12 @@ func NewKafka(brokerAddrs []string, options ...func(k *Kafka) error) (*Kafka, er
 	}
 	config = sarama.NewConfig()
 	config.Version = sarama.V0_11_0_0
-	k.consumer, err = sarama.NewConsumer(brokerAddrs, config)
+	// the client is kept to refresh the topics matching patterns
+	k.client, err = sarama.NewClient(brokerAddrs, config)
+	if err != nil {
+		return &k, err
+	}
+	k.consumer, err = sarama.NewConsumerFromClient(k.client)
 	return &k, err
 }
 
@@ -91,8 +115,11 @@ func (k *Kafka) Publish(ctx context.Context, event *events.CloudEvent, topic str
 	return nil
 }
 
-// Subscribe subscribes to an event
-func (k *Kafka) Subscribe(ctx context.Context, topic string, organization string, handler events.Handler) (events.Subscription, error) {
+// Subscribe subscribes to an event. The topic may be a pattern, e.g. vm.* or vm.#, in which case every topic of the
+// organization matching it is consumed, topics created later included. A named subscription commits the offsets of
+// the events acknowledged under its name, and resumes from them. It doesn't join a consumer group: only partition 0
+// of a topic is consumed, and the handlers subscribed with the same name each receive every event.
+func (k *Kafka) Subscribe(ctx context.Context, topic string, organization string, name string, handler events.Handler) (events.Subscription, error) {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
@@ -104,23 +131,160 @@ func (k *Kafka) Subscribe(ctx context.Context, topic string, organization string
 		return nil, errors.New("topic cannot be empty")
 	}
 
+	doneChan := make(chan struct{})
+
+	var offsets sarama.OffsetManager
+	if name != "" && k.client != nil {
+		var err error
+		offsets, err = sarama.NewOffsetManagerFromClient(organization+"."+name, k.client)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating the offset manager of subscription %s in organization %s", name, organization)
+		}
+		go func() {
+			<-doneChan
+			offsets.Close()
+		}()
+	}
+
+	if events.IsEventTypePattern(topic) {
+		since := time.Now()
+		topics, err := k.matchingTopics(topic, organization)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing the topics matching %s in organization %s", topic, organization)
+		}
+		consumed := make(map[string]bool)
+		for _, t := range topics {
+			if err := k.consumeTopic(t, sarama.OffsetNewest, offsets, handler, doneChan); err != nil {
+				close(doneChan)
+				return nil, errors.Wrapf(err, "error creating a partition consumer for topic %s", t)
+			}
+			consumed[t] = true
+		}
+		go k.refreshTopics(topic, organization, since, consumed, offsets, handler, doneChan)
+		return &subscription{done: doneChan, topic: topic, organization: organization}, nil
+	}
+
 	topicWithOrg := organization + "." + topic
+	if err := k.consumeTopic(topicWithOrg, sarama.OffsetNewest, offsets, handler, doneChan); err != nil {
+		close(doneChan)
+		return nil, errors.Wrapf(err, "error creating a partition consumer for topic %s in organization %s", topic, organization)
+	}
+	return &subscription{done: doneChan, topic: topic, organization: organization}, nil
+}
 
-	doneChan := make(chan struct{})
+// matchingTopics returns the Kafka topics of an organization matching a pattern
+func (k *Kafka) matchingTopics(pattern string, organization string) ([]string, error) {
+	if k.client != nil {
+		if err := k.client.RefreshMetadata(); err != nil {
+			return nil, err
+		}
+	}
+	topics, err := k.consumer.Topics()
+	if err != nil {
+		return nil, err
+	}
+	var matching []string
+	prefix := organization + "."
+	for _, t := range topics {
+		if strings.HasPrefix(t, prefix) && events.MatchEventType(pattern, strings.TrimPrefix(t, prefix)) {
+			matching = append(matching, t)
+		}
+	}
+	return matching, nil
+}
 
+// refreshTopics consumes the topics matching a pattern created after the subscription, from their first message
+// published since the subscription
+func (k *Kafka) refreshTopics(pattern string, organization string, since time.Time, consumed map[string]bool, offsets sarama.OffsetManager, handler events.Handler, doneChan chan struct{}) {
+	interval := k.topicRefresh
+	if interval <= 0 {
+		interval = defaultKafkaTopicRefresh
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-doneChan:
+			return
+		}
+		topics, err := k.matchingTopics(pattern, organization)
+		if err != nil {
+			log.Warnf("error listing the topics matching %s in organization %s: %+v", pattern, organization, err)
+			continue
+		}
+		for _, t := range topics {
+			if consumed[t] {
+				continue
+			}
+			if err := k.consumeTopic(t, k.offsetSince(t, since), offsets, handler, doneChan); err != nil {
+				log.Warnf("error creating a partition consumer for topic %s: %+v", t, err)
+				continue
+			}
+			log.Debugf("subscription to %s in organization %s consumes new topic %s", pattern, organization, t)
+			consumed[t] = true
+		}
+	}
+}
+
+// offsetSince returns the offset of the first message of a topic published at or after a time, the newest offset if
+// there is none
+func (k *Kafka) offsetSince(topicWithOrg string, since time.Time) int64 {
+	if k.client == nil {
+		return sarama.OffsetNewest
+	}
+	// Kafka looks up offsets by timestamps in milliseconds, -1 (the newest offset) when no message is that recent
+	offset, err := k.client.GetOffset(topicWithOrg, 0, since.UnixNano()/int64(time.Millisecond))
+	if err != nil {
+		log.Warnf("error getting the offset of topic %s at %s: %+v", topicWithOrg, since, err)
+		return sarama.OffsetNewest
+	}
+	return offset
+}
+
+// consumeTopic invokes the handler for every message of a Kafka topic until doneChan is closed, from the offset
+// committed with offsets if any, from the initial offset otherwise. Offsets may be nil.
+func (k *Kafka) consumeTopic(topicWithOrg string, initial int64, offsets sarama.OffsetManager, handler events.Handler, doneChan chan struct{}) error {
+	offset := initial
+	var committed sarama.PartitionOffsetManager
+	if offsets != nil {
+		var err error
+		committed, err = offsets.ManagePartition(topicWithOrg, 0)
+		if err != nil {
+			return err
+		}
+		// the offset manager returns the newest offset (-1) when nothing was committed
+		if next, _ := committed.NextOffset(); next >= 0 {
+			offset = next
+		}
+	}
 	// create partition consumer. Since we are creating only one consumer, we should automatically consume messages
 	// from all partitions regardless of the partition number we select
-	partitionConsumer, err := k.consumer.ConsumePartition(topicWithOrg, 0, sarama.OffsetNewest)
+	partitionConsumer, err := k.consumer.ConsumePartition(topicWithOrg, 0, offset)
+	if err == sarama.ErrOffsetOutOfRange && offset != initial {
+		// the committed offset was removed by the retention of the topic
+		partitionConsumer, err = k.consumer.ConsumePartition(topicWithOrg, 0, initial)
+	}
 	if err != nil {
-		return nil, errors.Wrapf(err, "error creating a partition consumer for topic %s in organization %s", topic, organization)
+		if committed != nil {
+			committed.Close()
+		}
+		return err
 	}
 
+	closeConsumers := func() {
+		partitionConsumer.Close()
+		if committed != nil {
+			// commits the last offset marked
+			committed.Close()
+		}
+	}
 	go func() {
 		for {
 			select {
 			case msg, open := <-partitionConsumer.Messages():
 				if !open {
-					partitionConsumer.Close()
+					closeConsumers()
 					return
 				}
 				log.Debugf("Received a kafka message %+v", *msg)
@@ -135,24 +299,27 @@ func (k *Kafka) Subscribe(ctx context.Context, topic string, organization string
 				func() {
 					defer spSub.Finish()
 					// Update the context with the span for the subsequent reference.
-					ctx = opentracing.ContextWithSpan(context.Background(), spSub)
+					ctx := opentracing.ContextWithSpan(context.Background(), spSub)
 					event, err := toEvent(msg)
 					if err != nil {
 						log.Errorf("Error when converting Kafka message to event: %+v", err)
 						return
 					}
 					log.Debugf("Got an event %+v", event)
-					handler(ctx, event)
+					// the offset of an event is committed once acknowledged, so it is received again if the
+					// consumer stops before
+					if handleUntilAcked(ctx, handler, event, doneChan, nil) && committed != nil {
+						committed.MarkOffset(msg.Offset+1, "")
+					}
 				}()
 
 			case <-doneChan:
-				partitionConsumer.Close()
+				closeConsumers()
 				return
 			}
 		}
 	}()
-
-	return &subscription{done: doneChan, topic: topic, organization: organization}, nil
+	return nil
 }
 
 // Close closes the transport
@@ -166,6 +333,12 @@ func (k *Kafka) Close() {
 	if err := k.consumer.Close(); err != nil {
 		log.Warnf("error when closing Kafka consumer: %+v", err)
 	}
+	if k.client == nil {
+		return
+	}
+	if err := k.client.Close(); err != nil {
+		log.Warnf("error when closing Kafka client: %+v", err)
+	}
 }
 
 // injectSpan injects OpenTracing Span into sarama.ProducerMessage.Headers structure.
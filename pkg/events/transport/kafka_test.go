@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/Shopify/sarama/mocks"
@@ -62,12 +63,53 @@ func TestKafkaSubscribe(t *testing.T) {
 
 	done := make(chan struct{})
 
-	_, err := kafka.Subscribe(context.Background(), testTopic, testOrg, func(ctx context.Context, e *events.CloudEvent) {
+	_, err := kafka.Subscribe(context.Background(), testTopic, testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
 		assert.Equal(t, event.EventID, e.EventID)
 		done <- struct{}{}
+		return nil
 	})
 	assert.NoError(t, err)
 
 	<-done
 
 }
+
+func TestKafkaSubscribePattern(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{
+		testOrg + ".vm.created":       {0},
+		testOrg + ".vm.disk.attached": {0},
+		testOrg + ".host.created":     {0},
+		"other.vm.created":            {0},
+	})
+	pc := consumer.ExpectConsumePartition(testOrg+".vm.created", 0, sarama.OffsetNewest)
+	created := events.NewCloudEventWithDefaults("vm.created")
+	createdBytes, _ := json.Marshal(created)
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: createdBytes})
+
+	kafka := &Kafka{
+		consumer:     consumer,
+		topicRefresh: 10 * time.Millisecond,
+	}
+
+	received := make(chan string)
+	sub, err := kafka.Subscribe(context.Background(), "vm.*", testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
+		received <- e.EventID
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, created.EventID, <-received)
+
+	// a topic created later is consumed from its first message since the subscription, the newest without a client
+	pc = consumer.ExpectConsumePartition(testOrg+".vm.deleted", 0, sarama.OffsetNewest)
+	deleted := events.NewCloudEventWithDefaults("vm.deleted")
+	deletedBytes, _ := json.Marshal(deleted)
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: deletedBytes})
+	consumer.SetTopicMetadata(map[string][]int32{
+		testOrg + ".vm.created": {0},
+		testOrg + ".vm.deleted": {0},
+	})
+	assert.Equal(t, deleted.EventID, <-received)
+
+	assert.NoError(t, sub.Unsubscribe())
+}
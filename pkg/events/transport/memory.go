@@ -9,82 +9,184 @@ import (
 	"context"
 	"sync"
 
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
 	"github.com/vmware/dispatch/pkg/events"
 )
 
 const (
-	defaultQueueSize = 20
+	// DefaultQueueSize is the default number of events queued for every subscriber
+	DefaultQueueSize = 20
+)
+
+// QueuePolicy is what Publish does when the queue of a subscriber is full
+type QueuePolicy string
+
+const (
+	// QueueBlock blocks Publish until the subscriber has room for the event, or the context of Publish is done
+	QueueBlock QueuePolicy = "block"
+	// QueueDrop drops the event for the subscriber, other subscribers still receive it
+	QueueDrop QueuePolicy = "drop"
 )
 
-type queue map[string]chan events.CloudEvent
+// memorySubscriber is a subscription of the in-memory transport, with its own queue of events
+type memorySubscriber struct {
+	queue chan events.CloudEvent
+	done  chan struct{}
+}
 
-// InMemory provides event transport implemented completely in memory.
+// InMemory provides event transport implemented completely in memory. Every event is delivered to every subscriber of
+// its topic or of a pattern matching it, each subscriber having its own queue.
 type InMemory struct {
-	exchanges map[string]queue
-	mu        sync.Mutex
+	// subscribers of every topic or pattern of every organization
+	subscribers map[string]map[string]map[*memorySubscriber]bool
+	mu          sync.Mutex
+	closed      chan struct{}
 
 	queueSize int
+	policy    QueuePolicy
 }
 
-// NewInMemory returns an initialized instance of InMemory event transport.
-func NewInMemory() *InMemory {
-	return &InMemory{
-		exchanges: make(map[string]queue),
-		queueSize: defaultQueueSize,
+// OptInMemoryQueueSize sets the number of events queued for every subscriber
+func OptInMemoryQueueSize(size int) func(m *InMemory) {
+	return func(m *InMemory) {
+		if size >= 0 {
+			m.queueSize = size
+		}
 	}
 }
 
-// Publish implements Transport interface publish method
+// OptInMemoryQueuePolicy sets what Publish does when the queue of a subscriber is full
+func OptInMemoryQueuePolicy(policy QueuePolicy) func(m *InMemory) {
+	return func(m *InMemory) {
+		m.policy = policy
+	}
+}
+
+// NewInMemory returns an initialized instance of InMemory event transport. Accepts variadic list of function options.
+func NewInMemory(options ...func(m *InMemory)) *InMemory {
+	m := &InMemory{
+		subscribers: make(map[string]map[string]map[*memorySubscriber]bool),
+		closed:      make(chan struct{}),
+		queueSize:   DefaultQueueSize,
+		policy:      QueueBlock,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Publish implements Transport interface publish method. The event is queued for every subscriber of the topic or of a
+// pattern matching it, the mutex isn't held while waiting for a full queue.
 func (m *InMemory) Publish(ctx context.Context, event *events.CloudEvent, topic string, organization string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	exchange, ok := m.exchanges[organization]
-	if !ok || exchange == nil {
-		return nil
-	}
-	queue, ok := exchange[topic]
-	if !ok || queue == nil {
-		return nil
+	var subscribers []*memorySubscriber
+	for pattern, subs := range m.subscribers[organization] {
+		if !events.MatchEventType(pattern, topic) {
+			continue
+		}
+		for sub := range subs {
+			subscribers = append(subscribers, sub)
+		}
 	}
-	queue <- *event
+	m.mu.Unlock()
 
+	for _, sub := range subscribers {
+		if m.policy == QueueDrop {
+			select {
+			case sub.queue <- *event:
+			case <-sub.done:
+			default:
+				log.Warnf("in-memory transport: queue of a subscriber of topic %s in organization %s is full, dropping event %s", topic, organization, event.EventID)
+			}
+			continue
+		}
+		select {
+		case sub.queue <- *event:
+		case <-sub.done:
+			// unsubscribed meanwhile
+		case <-m.closed:
+			return errors.New("in-memory transport is closed")
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "error publishing event %s to topic %s in organization %s", event.EventID, topic, organization)
+		}
+	}
 	return nil
 }
 
-// Subscribe implements Transport interface subscribe method
-func (m *InMemory) Subscribe(ctx context.Context, topic string, organization string, handler events.Handler) (events.Subscription, error) {
+// Subscribe implements Transport interface subscribe method. The topic may be a pattern, e.g. vm.* or vm.#. Events
+// are kept in memory only, so names are ignored.
+func (m *InMemory) Subscribe(ctx context.Context, topic string, organization string, name string, handler events.Handler) (events.Subscription, error) {
+	sub := &memorySubscriber{
+		queue: make(chan events.CloudEvent, m.queueSize),
+		done:  make(chan struct{}),
+	}
+
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	exchange, ok := m.exchanges[organization]
-	if !ok || exchange == nil {
-		exchange = make(queue)
-		m.exchanges[organization] = exchange
+	select {
+	case <-m.closed:
+		m.mu.Unlock()
+		return nil, errors.New("in-memory transport is closed")
+	default:
+	}
+	exchange, ok := m.subscribers[organization]
+	if !ok {
+		exchange = make(map[string]map[*memorySubscriber]bool)
+		m.subscribers[organization] = exchange
 	}
-	queue, ok := exchange[topic]
-	if !ok || queue == nil {
-		queue = make(chan events.CloudEvent, m.queueSize)
-		exchange[topic] = queue
+	if exchange[topic] == nil {
+		exchange[topic] = make(map[*memorySubscriber]bool)
 	}
+	exchange[topic][sub] = true
+	m.mu.Unlock()
 
-	doneChan := make(chan struct{})
 	go func() {
+		defer m.unsubscribe(sub, topic, organization)
 		for {
+			// stop before handling queued events once unsubscribed
 			select {
-			case event := <-queue:
-				handler(ctx, &event)
-			case <-doneChan:
-				m.mu.Lock()
-				close(queue)
-				delete(exchange, topic)
-				m.mu.Unlock()
+			case <-sub.done:
+				return
+			case <-m.closed:
+				return
+			default:
+			}
+			select {
+			case event := <-sub.queue:
+				handleUntilAcked(ctx, handler, &event, sub.done, m.closed)
+			case <-sub.done:
+				return
+			case <-m.closed:
 				return
 			}
 		}
 	}()
-	return &subscription{done: doneChan, topic: topic, organization: organization}, nil
+	return &subscription{done: sub.done, topic: topic, organization: organization}, nil
 }
 
-// Close implements Transport interface close method.
-func (m *InMemory) Close() {
+// unsubscribe removes a subscriber. Its queue is left open, so a Publish in progress never sends on a closed channel.
+func (m *InMemory) unsubscribe(sub *memorySubscriber, topic string, organization string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exchange := m.subscribers[organization]
+	delete(exchange[topic], sub)
+	if len(exchange[topic]) == 0 {
+		delete(exchange, topic)
+	}
+	if len(exchange) == 0 {
+		delete(m.subscribers, organization)
+	}
+}
 
+// Close implements Transport interface close method. It stops all subscriptions.
+func (m *InMemory) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	select {
+	case <-m.closed:
+	default:
+		close(m.closed)
+	}
 }
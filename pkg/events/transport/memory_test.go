@@ -8,7 +8,9 @@ package transport
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/vmware/dispatch/pkg/events"
@@ -28,9 +30,10 @@ func TestInMemorySubscribe(t *testing.T) {
 	memory := NewInMemory()
 
 	done := make(chan struct{})
-	_, err := memory.Subscribe(context.Background(), testTopic, testOrg, func(ctx context.Context, e *events.CloudEvent) {
+	_, err := memory.Subscribe(context.Background(), testTopic, testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
 		assert.Equal(t, event.EventID, e.EventID)
 		done <- struct{}{}
+		return nil
 	})
 	assert.NoError(t, err)
 
@@ -40,3 +43,121 @@ func TestInMemorySubscribe(t *testing.T) {
 	<-done
 
 }
+
+func TestInMemoryFanOut(t *testing.T) {
+	event := events.NewCloudEventWithDefaults(testTopic)
+	memory := NewInMemory()
+	defer memory.Close()
+
+	received1 := make(chan string, 2)
+	sub1, err := memory.Subscribe(context.Background(), testTopic, testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
+		received1 <- e.EventID
+		return nil
+	})
+	assert.NoError(t, err)
+	received2 := make(chan string, 2)
+	_, err = memory.Subscribe(context.Background(), testTopic, testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
+		received2 <- e.EventID
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, memory.Publish(context.Background(), &event, testTopic, testOrg))
+	assert.Equal(t, event.EventID, <-received1)
+	assert.Equal(t, event.EventID, <-received2)
+
+	// the other subscriber still receives events once one unsubscribed
+	assert.NoError(t, sub1.Unsubscribe())
+	assert.NoError(t, memory.Publish(context.Background(), &event, testTopic, testOrg))
+	assert.Equal(t, event.EventID, <-received2)
+}
+
+func TestInMemoryQueuePolicy(t *testing.T) {
+	event := events.NewCloudEventWithDefaults(testTopic)
+	blocked := make(chan struct{})
+	handler := func(ctx context.Context, e *events.CloudEvent) error {
+		<-blocked
+		return nil
+	}
+	defer close(blocked)
+
+	memory := NewInMemory(OptInMemoryQueueSize(1), OptInMemoryQueuePolicy(QueueDrop))
+	defer memory.Close()
+	_, err := memory.Subscribe(context.Background(), testTopic, testOrg, "", handler)
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, memory.Publish(context.Background(), &event, testTopic, testOrg))
+	}
+
+	memory = NewInMemory(OptInMemoryQueueSize(1))
+	defer memory.Close()
+	_, err = memory.Subscribe(context.Background(), testTopic, testOrg, "", handler)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for err == nil {
+		err = memory.Publish(ctx, &event, testTopic, testOrg)
+	}
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+}
+
+func TestInMemoryPatternSubscribe(t *testing.T) {
+	memory := NewInMemory()
+	defer memory.Close()
+
+	received := make(chan string, 4)
+	_, err := memory.Subscribe(context.Background(), "vm.*", testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
+		received <- e.EventType
+		return nil
+	})
+	assert.NoError(t, err)
+
+	for _, topic := range []string{"vm.disk.attached", "host.created", "vm.created"} {
+		event := events.NewCloudEventWithDefaults(topic)
+		assert.NoError(t, memory.Publish(context.Background(), &event, topic, testOrg))
+	}
+	// only vm.created matches, events are queued in order
+	assert.Equal(t, "vm.created", <-received)
+	select {
+	case eventType := <-received:
+		t.Errorf("unexpected event of type %s", eventType)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInMemoryRedelivery(t *testing.T) {
+	defer func(delay time.Duration) { minRedeliveryDelay = delay }(minRedeliveryDelay)
+	minRedeliveryDelay = time.Millisecond
+
+	memory := NewInMemory()
+	defer memory.Close()
+
+	attempts := make(chan string, 4)
+	counts := make(chan int, 4)
+	rejections := 2
+	_, err := memory.Subscribe(context.Background(), testTopic, testOrg, "", func(ctx context.Context, e *events.CloudEvent) error {
+		attempts <- e.EventID
+		counts <- events.DeliveryFromContext(ctx).Count
+		if rejections > 0 {
+			rejections--
+			return errors.New("not now")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	event1 := events.NewCloudEventWithDefaults(testTopic)
+	event2 := events.NewCloudEventWithDefaults(testTopic)
+	assert.NoError(t, memory.Publish(context.Background(), &event1, testTopic, testOrg))
+	assert.NoError(t, memory.Publish(context.Background(), &event2, testTopic, testOrg))
+
+	// the rejected event is delivered again until acknowledged, before the next event
+	assert.Equal(t, event1.EventID, <-attempts)
+	assert.Equal(t, event1.EventID, <-attempts)
+	assert.Equal(t, event1.EventID, <-attempts)
+	assert.Equal(t, event2.EventID, <-attempts)
+	// handlers get the number of deliveries of the event
+	for _, count := range []int{1, 2, 3, 1} {
+		assert.Equal(t, count, <-counts)
+	}
+}
@@ -36,7 +36,7 @@ func (t *Noop) Publish(ctx context.Context, event *events.CloudEvent, topic stri
 }
 
 // Subscribe subscribes to event.
-func (t *Noop) Subscribe(ctx context.Context, topic string, organization string, handler events.Handler) (events.Subscription, error) {
+func (t *Noop) Subscribe(ctx context.Context, topic string, organization string, name string, handler events.Handler) (events.Subscription, error) {
 	if t.out != nil {
 		fmt.Fprintf(t.out, "Subscription to topic %s using handler %T\n", topic, handler)
 	}
@@ -9,6 +9,7 @@ package transport
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentracing-contrib/go-amqp/amqptracer"
 	"github.com/opentracing/opentracing-go"
@@ -23,6 +24,9 @@ import (
 const (
 	// rabbitMQDefaultExchange is the default exchange name when using the rabbitmq transport
 	rabbitMQDefaultExchange = "dispatch"
+	// rabbitMQQueueExpiry is how long the queue of a durable subscription is kept without consumers, e.g. after the
+	// subscription was deleted
+	rabbitMQQueueExpiry = 24 * time.Hour
 )
 
 // RabbitMQ implements transport over AMQP protocol and RabbitMQ messaging service
@@ -157,8 +161,10 @@ func (mq *RabbitMQ) Publish(ctx context.Context, event *events.CloudEvent, topic
 }
 
 // Subscribe creates an active subscription on specified topic, and invokes handler function
-// for every event received on given topic.
-func (mq *RabbitMQ) Subscribe(ctx context.Context, topic string, organization string, handler events.Handler) (events.Subscription, error) {
+// for every event received on given topic. The topic may be a pattern, e.g. vm.* or vm.#, which the topic exchange
+// matches against the routing keys of events. A named subscription consumes a durable queue shared by the consumers
+// with the same name, other subscriptions an exclusive queue deleted when unsubscribed.
+func (mq *RabbitMQ) Subscribe(ctx context.Context, topic string, organization string, name string, handler events.Handler) (events.Subscription, error) {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
@@ -171,19 +177,19 @@ func (mq *RabbitMQ) Subscribe(ctx context.Context, topic string, organization st
 	}
 
 	topicWithOrg := organization + "." + topic
-	ch, q, err := mq.initQueue(topicWithOrg)
+	ch, q, err := mq.initQueue(topicWithOrg, name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error initializing from queue %s", q.Name)
+		return nil, errors.Wrapf(err, "error initializing the queue of topic %s", topicWithOrg)
 	}
 
 	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		false,  // auto ack
-		true,   // exclusive
-		false,  // no local
-		false,  // no wait
-		nil,    // args
+		q.Name,     // queue
+		"",         // consumer
+		false,      // auto ack
+		name == "", // exclusive
+		false,      // no local
+		false,      // no wait
+		nil,        // args
 	)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error when creating consume channel for queue %s", q.Name)
@@ -207,8 +213,12 @@ func (mq *RabbitMQ) Subscribe(ctx context.Context, topic string, organization st
 				ctx = opentracing.ContextWithSpan(context.Background(), spSub)
 				log.Debugf("Got an event: %s, %s, %s", msg.Exchange, msg.MessageId, msg.ContentType)
 				event := mq.msgToEvent(msg)
-				handler(ctx, event)
-				msg.Ack(false)
+				if handleUntilAcked(ctx, handler, event, doneChan, nil) {
+					msg.Ack(false)
+				} else {
+					// unsubscribed before the event was acknowledged
+					msg.Nack(false, true)
+				}
 				spSub.Finish()
 			case <-doneChan:
 				ch.Close()
@@ -227,6 +237,8 @@ func (mq *RabbitMQ) eventToMsg(event *events.CloudEvent) amqp.Publishing {
 		Timestamp:     event.EventTime,
 		Type:          event.EventType,
 		Body:          event.Data,
+		// events survive a restart of the broker in durable queues
+		DeliveryMode: amqp.Persistent,
 		Headers: amqp.Table{
 			"dispatch-schema-url":         event.SchemaURL,
 			"dispatch-event-type-version": event.EventTypeVersion,
@@ -248,19 +260,26 @@ func (mq *RabbitMQ) msgToEvent(message amqp.Delivery) *events.CloudEvent {
 	}
 }
 
-// initQueue initializes and binds to a queue
-func (mq *RabbitMQ) initQueue(topic string) (*amqp.Channel, *amqp.Queue, error) {
+// initQueue initializes and binds to a queue, durable if the subscription is named. The queue of a named
+// subscription is named after the topic too, so the queue of a subscription to another topic isn't bound to both.
+func (mq *RabbitMQ) initQueue(topic string, name string) (*amqp.Channel, *amqp.Queue, error) {
 	ch, err := mq.recvConn.Channel()
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to acquire a RabbitMQ channel")
 	}
+	queueName := ""
+	var args amqp.Table
+	if name != "" {
+		queueName = mq.exchangeName + "." + name + "." + topic
+		args = amqp.Table{"x-expires": int64(rabbitMQQueueExpiry / time.Millisecond)}
+	}
 	q, err := ch.QueueDeclare(
-		"",    // name
-		false, // durable
-		true,  // delete when unused
-		true,  // exclusive
-		false, // no-wait
-		nil,   // arguments
+		queueName,  // name
+		name != "", // durable
+		name == "", // delete when unused
+		name == "", // exclusive
+		false,      // no-wait
+		args,       // arguments
 	)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "error when declaring a queue")
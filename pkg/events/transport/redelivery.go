@@ -0,0 +1,51 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package transport
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/events"
+)
+
+var (
+	// minRedeliveryDelay is the delay before delivering a rejected event again, doubled after every rejection
+	minRedeliveryDelay = time.Second
+	// maxRedeliveryDelay caps the delay between two deliveries of a rejected event
+	maxRedeliveryDelay = time.Minute
+)
+
+// handleUntilAcked invokes the handler until it acknowledges the event, waiting longer after every rejection. Events
+// are redelivered by the subscriber rather than the broker, so they keep their order. The handler gets the number of
+// deliveries in its context. It returns false if done or closed is closed first, closed may be nil.
+func handleUntilAcked(ctx context.Context, handler events.Handler, event *events.CloudEvent, done, closed <-chan struct{}) bool {
+	delay := minRedeliveryDelay
+	delivery := events.Delivery{Count: 1, First: time.Now()}
+	for {
+		err := handler(events.WithDelivery(ctx, delivery), event)
+		if err == nil {
+			return true
+		}
+		log.Warnf("event %s was rejected, delivering it again in %s: %+v", event.EventID, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-done:
+			timer.Stop()
+			return false
+		case <-closed:
+			timer.Stop()
+			return false
+		}
+		if delay *= 2; delay > maxRedeliveryDelay {
+			delay = maxRedeliveryDelay
+		}
+		delivery.Count++
+	}
+}
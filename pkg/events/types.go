@@ -18,13 +18,20 @@ type Transport interface {
 	// Publish publishes the event using the underlying transport
 	Publish(ctx context.Context, event *CloudEvent, topic string, organization string) error
 
-	// Subscribe takes a handler to run on every event received on topic. Returns a cancelable Subscription
-	Subscribe(ctx context.Context, topic string, organization string, handler Handler) (Subscription, error)
+	// Subscribe takes a handler to run on every event received on topic. Returns a cancelable Subscription.
+	// Events are delivered at least once: an event the handler doesn't acknowledge is delivered again, with backoff,
+	// until it is acknowledged or the subscription is canceled. The context of the handler carries the Delivery of
+	// the event. A non-empty name makes the subscription durable: the events published while nothing is subscribed
+	// with the name are received once subscribed again. Whether handlers subscribed with the same name share the
+	// events depends on the transport: they do with RabbitMQ, while the Kafka transport delivers every event to each
+	// of them.
+	Subscribe(ctx context.Context, topic string, organization string, name string, handler Handler) (Subscription, error)
 	Close()
 }
 
-// Handler is a callback function used to handle received event
-type Handler func(context.Context, *CloudEvent)
+// Handler is a callback function used to handle received event. Returning nil acknowledges the event, returning an
+// error rejects it and the transport delivers it again.
+type Handler func(context.Context, *CloudEvent) error
 
 // Subscription represents an active subscription within Transport. Subscription can be stopped
 // by calling Unsubscribe()
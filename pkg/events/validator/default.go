@@ -18,6 +18,11 @@ func eventType(fl govalidator.FieldLevel) bool {
 	return eventTypeRegex.MatchString(fl.Field().String())
 }
 
+// ValidEventTypePattern returns whether an event type, or a pattern with wildcards, may be subscribed to
+func ValidEventTypePattern(pattern string) bool {
+	return pattern != "" && eventTypePatternRegex.MatchString(pattern)
+}
+
 var validator = NewDefaultValidator()
 
 // Validate validates cloud event using default validator
@@ -37,3 +37,20 @@ func TestDefaultValidate(t *testing.T) {
 	incorrect.EventID = ""
 	assert.Error(t, v.Validate(&incorrect))
 }
+
+func TestValidEventTypePattern(t *testing.T) {
+	assert.True(t, validator.ValidEventTypePattern("vm.created"))
+	assert.True(t, validator.ValidEventTypePattern("vm.*"))
+	assert.True(t, validator.ValidEventTypePattern("vm.#"))
+	assert.True(t, validator.ValidEventTypePattern("#"))
+	assert.True(t, validator.ValidEventTypePattern("vm.*.attached"))
+	assert.False(t, validator.ValidEventTypePattern(""))
+	assert.False(t, validator.ValidEventTypePattern("vm*"))
+	assert.False(t, validator.ValidEventTypePattern("vm.cre#"))
+
+	// events are of a type, not a pattern
+	v := validator.NewDefaultValidator()
+	wildcard := testEvent1
+	wildcard.EventType = "test.*"
+	assert.Error(t, v.Validate(&wildcard))
+}
@@ -9,8 +9,11 @@ import "regexp"
 
 var (
 	eventTypeRegexString = "^[\\w\\d\\.\\-]+$"
+	// eventTypePatternRegexString also accepts the wildcard words * and # of subscriptions, e.g. vm.* or vm.#
+	eventTypePatternRegexString = "^([\\w\\d\\-]*|\\*|#)(\\.([\\w\\d\\-]*|\\*|#))*$"
 )
 
 var (
-	eventTypeRegex = regexp.MustCompile(eventTypeRegexString)
+	eventTypeRegex        = regexp.MustCompile(eventTypeRegexString)
+	eventTypePatternRegex = regexp.MustCompile(eventTypePatternRegexString)
 )
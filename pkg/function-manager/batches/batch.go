@@ -0,0 +1,121 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package batches
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/satori/go.uuid"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+)
+
+// NO TESTS
+
+// Batch struct represents the runs of a function over a list of inputs, the items of the batch being stored apart
+type Batch struct {
+	entitystore.BaseEntity
+	FunctionName string `json:"functionName"`
+	Concurrency  int64  `json:"concurrency"`
+	Total        int64  `json:"total"`
+	// Submitted is the number of items whose run was submitted, items being submitted in the order of their index
+	Submitted    int64     `json:"submitted"`
+	Succeeded    int64     `json:"succeeded"`
+	Failed       int64     `json:"failed"`
+	FinishedTime time.Time `json:"finishedTime,omitempty"`
+}
+
+// Item struct represents the run of the input of a batch with the same index. An item is INITIALIZED until its run is
+// submitted, CREATING until its run completes, and then READY or ERROR like its run
+type Item struct {
+	entitystore.BaseEntity
+	Batch string              `json:"batch"`
+	Index int64               `json:"index"`
+	Input interface{}         `json:"input,omitempty"`
+	Run   string              `json:"run,omitempty"`
+	Error *v1.InvocationError `json:"error,omitempty"`
+}
+
+// itemName returns the name of the item of the batch with the index
+func itemName(batchName string, index int64) string {
+	return fmt.Sprintf("%s-%d", batchName, index)
+}
+
+// runName returns the name of the run of the item, the same whenever the item is submitted so a batch resumed after
+// an interruption doesn't run an item twice
+func (i *Item) runName() string {
+	return uuid.NewV5(uuid.FromStringOrNil(i.Batch), strconv.FormatInt(i.Index, 10)).String()
+}
+
+// ToModel converts batch to swagger model, without its inputs
+func (b *Batch) ToModel() *v1.Batch {
+	tags := []*v1.Tag{}
+	for k, v := range b.Tags {
+		tags = append(tags, &v1.Tag{Key: k, Value: v})
+	}
+	var finished int64
+	if !b.FinishedTime.IsZero() {
+		finished = b.FinishedTime.Unix()
+	}
+	return &v1.Batch{
+		Name:         strfmt.UUID(b.Name),
+		FunctionName: b.FunctionName,
+		Concurrency:  b.Concurrency,
+		CreatedTime:  b.CreatedTime.Unix(),
+		FinishedTime: finished,
+		Total:        b.Total,
+		Succeeded:    b.Succeeded,
+		Failed:       b.Failed,
+		Status:       v1.Status(b.Status),
+		Reason:       b.Reason,
+		Tags:         tags,
+	}
+}
+
+// FromModel builds batch based on swagger model, its items are built by Items
+func (b *Batch) FromModel(m *v1.Batch, orgID string) {
+	tags := make(map[string]string)
+	for _, t := range m.Tags {
+		tags[t.Key] = t.Value
+	}
+	b.BaseEntity.OrganizationID = orgID
+	b.BaseEntity.Tags = tags
+	b.FunctionName = m.FunctionName
+	b.Concurrency = m.Concurrency
+	b.Total = int64(len(m.Inputs))
+}
+
+// Items returns the items of the batch for the inputs
+func (b *Batch) Items(inputs []interface{}) []*Item {
+	items := make([]*Item, len(inputs))
+	for i, input := range inputs {
+		items[i] = &Item{
+			BaseEntity: entitystore.BaseEntity{
+				Name:           itemName(b.Name, int64(i)),
+				OrganizationID: b.OrganizationID,
+				Status:         entitystore.StatusINITIALIZED,
+			},
+			Batch: b.Name,
+			Index: int64(i),
+			Input: input,
+		}
+	}
+	return items
+}
+
+// ToModel converts item to swagger model, without the output of its run
+func (i *Item) ToModel() *v1.BatchItem {
+	return &v1.BatchItem{
+		Index:  i.Index,
+		Run:    strfmt.UUID(i.Run),
+		Status: v1.Status(i.Status),
+		Error:  i.Error,
+	}
+}
@@ -0,0 +1,331 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package batches
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	ewrapper "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+)
+
+// ItemSubmitter submits the runs of the items of batches
+type ItemSubmitter interface {
+	// SubmitItem creates the run of the item with the given name and queues it like any other run, returning the
+	// existing run instead if it was submitted before. A *quotas.ExceededError is returned if the run isn't admitted
+	SubmitItem(ctx context.Context, b *Batch, item *Item, runName string) (*functions.FnRun, error)
+}
+
+// Only return entities in INITIALIZED or CREATING status, a batch being executed is resumed where it stopped
+func syncFilter(resyncPeriod time.Duration) entitystore.Filter {
+	now := time.Now().Add(-resyncPeriod)
+	return entitystore.FilterEverything().Add(
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "ModifiedTime",
+			Verb:    entitystore.FilterVerbBefore,
+			Object:  now,
+		},
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbIn,
+			Object:  []entitystore.Status{entitystore.StatusINITIALIZED, entitystore.StatusCREATING},
+		})
+}
+
+// EntityHandler handles Batch entity operations, executing batches
+type EntityHandler struct {
+	store     entitystore.EntityStore
+	submitter ItemSubmitter
+	watcher   controller.Watcher
+
+	// active holds the batches being advanced, mapped to true when they must be advanced again afterwards
+	active     map[string]bool
+	activeLock sync.Mutex
+}
+
+// NewEntityHandler returns new instance of EntityHandler submitting the runs of items with the given submitter
+func NewEntityHandler(store entitystore.EntityStore, submitter ItemSubmitter, watcher controller.Watcher) *EntityHandler {
+	return &EntityHandler{
+		store:     store,
+		submitter: submitter,
+		watcher:   watcher,
+		active:    make(map[string]bool),
+	}
+}
+
+// Type returns entity handler type
+func (h *EntityHandler) Type() reflect.Type {
+	return reflect.TypeOf(&Batch{})
+}
+
+// Add advances a batch being executed. The batch is advanced whenever the run of one of its items completes, and when
+// it is resynced
+func (h *EntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	b := obj.(*Batch)
+	key := b.OrganizationID + "/" + b.Name
+	if !h.activate(key) {
+		return nil
+	}
+	for {
+		err = h.advance(ctx, b.OrganizationID, b.Name)
+		if !h.deactivate(key, err) {
+			return err
+		}
+	}
+}
+
+// activate marks the batch as being advanced, or returns false if it already is, the batch being advanced again then
+func (h *EntityHandler) activate(key string) bool {
+	h.activeLock.Lock()
+	defer h.activeLock.Unlock()
+	if _, ok := h.active[key]; ok {
+		h.active[key] = true
+		return false
+	}
+	h.active[key] = false
+	return true
+}
+
+// deactivate returns true if the batch must be advanced again, otherwise it is no longer marked as being advanced
+func (h *EntityHandler) deactivate(key string, err error) bool {
+	h.activeLock.Lock()
+	defer h.activeLock.Unlock()
+	if h.active[key] && err == nil {
+		h.active[key] = false
+		return true
+	}
+	delete(h.active, key)
+	return false
+}
+
+// advance records the items of the batch whose run completed, submits the runs of the next items up to the
+// concurrency of the batch and finishes the batch once the runs of all its items completed
+func (h *EntityHandler) advance(ctx context.Context, organizationID, name string) error {
+	b := new(Batch)
+	if err := h.store.Get(ctx, organizationID, name, entitystore.Options{}, b); err != nil {
+		return ewrapper.Wrapf(err, "error getting batch %s", name)
+	}
+	if b.Status != entitystore.StatusINITIALIZED && b.Status != entitystore.StatusCREATING {
+		return nil
+	}
+	b.Status = entitystore.StatusCREATING
+
+	running, err := h.listItems(ctx, b, entitystore.StatusCREATING)
+	if err != nil {
+		return err
+	}
+	var pending int64
+	for _, item := range running {
+		run := new(functions.FnRun)
+		if err := h.store.Get(ctx, b.OrganizationID, item.Run, entitystore.Options{}, run); err != nil {
+			return ewrapper.Wrapf(err, "error getting run %s of batch %s", item.Run, b.Name)
+		}
+		if run.Status != entitystore.StatusREADY && run.Status != entitystore.StatusERROR {
+			pending++
+			continue
+		}
+		if err := h.updateItem(ctx, b, item, run, nil); err != nil {
+			return err
+		}
+	}
+
+	for b.Submitted < b.Total {
+		item := new(Item)
+		if err := h.store.Get(ctx, b.OrganizationID, itemName(b.Name, b.Submitted), entitystore.Options{}, item); err != nil {
+			return ewrapper.Wrapf(err, "error getting item %d of batch %s", b.Submitted, b.Name)
+		}
+		if item.Status != entitystore.StatusINITIALIZED {
+			// submitted before the batch was interrupted, the item was recorded with the running items
+			b.Submitted++
+			continue
+		}
+		if pending >= b.Concurrency {
+			break
+		}
+		run, err := h.submitter.SubmitItem(ctx, b, item, item.runName())
+		if exceeded, ok := err.(*quotas.ExceededError); ok {
+			// the item is submitted once a run of the batch completes, or when the batch is resynced
+			log.Debugf("item %d of batch %s held back: %s", item.Index, b.Name, exceeded)
+			break
+		}
+		if err := h.updateItem(ctx, b, item, run, err); err != nil {
+			return err
+		}
+		b.Submitted++
+		if item.Status == entitystore.StatusCREATING {
+			pending++
+		}
+	}
+
+	if pending == 0 && b.Submitted == b.Total {
+		if err := h.finish(ctx, b); err != nil {
+			return err
+		}
+	}
+	if _, err := h.store.Update(ctx, b.Revision, b); err != nil {
+		return ewrapper.Wrapf(err, "error updating batch %s", b.Name)
+	}
+	return nil
+}
+
+// finish sets the final status of the batch, a batch with failed items being READY as they are part of its result
+func (h *EntityHandler) finish(ctx context.Context, b *Batch) error {
+	// the items are counted again as a batch interrupted while updating an item may have counted it twice
+	succeeded, err := h.listItems(ctx, b, entitystore.StatusREADY)
+	if err != nil {
+		return err
+	}
+	failed, err := h.listItems(ctx, b, entitystore.StatusERROR)
+	if err != nil {
+		return err
+	}
+	b.Succeeded, b.Failed = int64(len(succeeded)), int64(len(failed))
+	b.Status = entitystore.StatusREADY
+	b.FinishedTime = time.Now()
+	log.Infof("batch %s of function %s finished: %d runs READY, %d in ERROR", b.Name, b.FunctionName, b.Succeeded, b.Failed)
+	return nil
+}
+
+// updateItem records the run of the item, or the error submitting it, and counts the item once its run completed
+func (h *EntityHandler) updateItem(ctx context.Context, b *Batch, item *Item, run *functions.FnRun, err error) error {
+	setItem(item, run, err)
+	switch item.Status {
+	case entitystore.StatusREADY:
+		b.Succeeded++
+	case entitystore.StatusERROR:
+		b.Failed++
+	}
+	if _, err := h.store.Update(ctx, item.Revision, item); err != nil {
+		return ewrapper.Wrapf(err, "error updating item %d of batch %s", item.Index, b.Name)
+	}
+	return nil
+}
+
+// listItems returns the items of the batch with the status
+func (h *EntityHandler) listItems(ctx context.Context, b *Batch, status entitystore.Status) ([]*Item, error) {
+	var items []*Item
+	if err := h.store.List(ctx, b.OrganizationID, itemsOptions(b.Name, status), &items); err != nil {
+		return nil, ewrapper.Wrapf(err, "error listing items of batch %s", b.Name)
+	}
+	return items, nil
+}
+
+// itemsOptions selects the items of the batch, only those with the status unless it is empty
+func itemsOptions(batchName string, status entitystore.Status) entitystore.Options {
+	filter := entitystore.FilterEverything().Add(entitystore.FilterStat{
+		Scope:   entitystore.FilterScopeExtra,
+		Subject: "Batch",
+		Verb:    entitystore.FilterVerbEqual,
+		Object:  batchName,
+	})
+	if status != "" {
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  status,
+		})
+	}
+	return entitystore.Options{Filter: filter}
+}
+
+// setItem records the run of the item, the item being CREATING until the run completes
+func setItem(item *Item, run *functions.FnRun, err error) {
+	item.Status = entitystore.StatusCREATING
+	if run != nil {
+		item.Run = run.Name
+		if run.Status == entitystore.StatusREADY || run.Status == entitystore.StatusERROR {
+			item.Status = run.Status
+			item.Error = run.Error
+		}
+	}
+	if err != nil {
+		item.Status = entitystore.StatusERROR
+	}
+	if item.Status != entitystore.StatusERROR || item.Error != nil {
+		return
+	}
+	// the run failed before the function was invoked
+	var message string
+	if err != nil {
+		message = err.Error()
+	} else {
+		message = strings.Join(run.Reason, "; ")
+	}
+	item.Error = &v1.InvocationError{Message: &message, Type: v1.ErrorTypeSystemError}
+}
+
+// RunCompleted advances the batch of a completed run, if any, so the run of its next item is submitted
+func (h *EntityHandler) RunCompleted(ctx context.Context, run *functions.FnRun) {
+	if h == nil {
+		return
+	}
+	name, ok := run.Tags[RunTag]
+	if !ok {
+		return
+	}
+	b := new(Batch)
+	if err := h.store.Get(ctx, run.OrganizationID, name, entitystore.Options{}, b); err != nil {
+		log.Errorf("error getting batch %s of function run %s: %+v", name, run.Name, err)
+		return
+	}
+	if b.Status == entitystore.StatusINITIALIZED || b.Status == entitystore.StatusCREATING {
+		h.watcher.OnAction(ctx, b)
+	}
+}
+
+// Update updates a batch
+func (h *EntityHandler) Update(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	b := obj.(*Batch)
+	defer func() { h.store.UpdateWithError(ctx, b, err) }()
+	return ewrapper.Errorf("updating batches not supported, batch: '%s'", b.Name)
+}
+
+// Delete deletes a batch
+func (h *EntityHandler) Delete(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	b := obj.(*Batch)
+	defer func() { h.store.UpdateWithError(ctx, b, err) }()
+	return ewrapper.Errorf("deleting batches not supported, batch: '%s'", b.Name)
+}
+
+// Sync returns the list of batch entities which must be resolved
+func (h *EntityHandler) Sync(ctx context.Context, resyncPeriod time.Duration) ([]entitystore.Entity, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	return controller.DefaultSync(ctx, h.store, h.Type(), resyncPeriod, syncFilter(resyncPeriod))
+}
+
+// Error handles error state
+func (h *EntityHandler) Error(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	log.Errorf("handleError func not implemented yet")
+	return nil
+}
@@ -0,0 +1,210 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package batches
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+type fakeSubmitter struct {
+	store     entitystore.EntityStore
+	submitted int
+	exceeded  bool
+}
+
+// SubmitItem adds the run of the item to the store, it fails the input "missing" before the run is created
+func (s *fakeSubmitter) SubmitItem(ctx context.Context, b *Batch, item *Item, runName string) (*functions.FnRun, error) {
+	if s.exceeded {
+		return nil, &quotas.ExceededError{Quota: "runs", Reason: "too many runs"}
+	}
+	if item.Input == "missing" {
+		return nil, errors.New("function hello is not READY")
+	}
+	s.submitted++
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           runName,
+			OrganizationID: b.OrganizationID,
+			Status:         entitystore.StatusINITIALIZED,
+			Tags:           map[string]string{RunTag: b.Name},
+		},
+		Input: item.Input,
+	}
+	if _, err := s.store.Add(ctx, run); err != nil {
+		if !entitystore.IsUniqueViolation(err) {
+			return nil, err
+		}
+		existing := new(functions.FnRun)
+		err := s.store.Get(ctx, b.OrganizationID, runName, entitystore.Options{}, existing)
+		return existing, err
+	}
+	return run, nil
+}
+
+// completeRuns completes the runs of the batch which are not yet, failing those of the input "fail"
+func completeRuns(t *testing.T, es entitystore.EntityStore, b *Batch) int {
+	var runs []*functions.FnRun
+	opts := entitystore.Options{Filter: entitystore.FilterEverything().Add(entitystore.FilterStat{
+		Scope:   entitystore.FilterScopeTag,
+		Subject: RunTag,
+		Verb:    entitystore.FilterVerbEqual,
+		Object:  b.Name,
+	})}
+	require.NoError(t, es.List(context.Background(), testOrgID, opts, &runs))
+	completed := 0
+	for _, run := range runs {
+		if run.Status != entitystore.StatusINITIALIZED {
+			continue
+		}
+		completed++
+		run.Status = entitystore.StatusREADY
+		if run.Input == "fail" {
+			message := "function failed"
+			run.Status = entitystore.StatusERROR
+			run.Error = &v1.InvocationError{Message: &message, Type: v1.ErrorTypeFunctionError}
+		}
+		_, err := es.Update(context.Background(), run.Revision, run)
+		require.NoError(t, err)
+	}
+	return completed
+}
+
+func addBatch(t *testing.T, es entitystore.EntityStore, concurrency int64, inputs ...interface{}) *Batch {
+	b := &Batch{}
+	b.FromModel(&v1.Batch{FunctionName: "hello", Concurrency: concurrency, Inputs: inputs}, testOrgID)
+	b.Name = "f98d0a7f-0c1d-4020-a488-cabc501b08e0"
+	b.Status = entitystore.StatusINITIALIZED
+	for _, item := range b.Items(inputs) {
+		_, err := es.Add(context.Background(), item)
+		require.NoError(t, err)
+	}
+	_, err := es.Add(context.Background(), b)
+	require.NoError(t, err)
+	return b
+}
+
+func getBatch(t *testing.T, es entitystore.EntityStore, b *Batch) *Batch {
+	stored := new(Batch)
+	require.NoError(t, es.Get(context.Background(), testOrgID, b.Name, entitystore.Options{}, stored))
+	return stored
+}
+
+func getItem(t *testing.T, es entitystore.EntityStore, b *Batch, index int64) *Item {
+	item := new(Item)
+	require.NoError(t, es.Get(context.Background(), testOrgID, itemName(b.Name, index), entitystore.Options{}, item))
+	return item
+}
+
+func TestBatchAdd(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	var inputs []interface{}
+	for i := 0; i < 20; i++ {
+		inputs = append(inputs, i)
+	}
+	inputs = append(inputs, "fail", "missing")
+	b := addBatch(t, es, 3, inputs...)
+
+	submitter := &fakeSubmitter{store: es}
+	h := NewEntityHandler(es, submitter, nil)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, h.Add(context.Background(), b))
+		if getBatch(t, es, b).Status == entitystore.StatusREADY {
+			break
+		}
+		assert.True(t, completeRuns(t, es, b) <= 3, "at most 3 runs at the same time")
+	}
+	assert.Equal(t, 21, submitter.submitted)
+
+	stored := getBatch(t, es, b)
+	assert.Equal(t, entitystore.StatusREADY, stored.Status)
+	assert.False(t, stored.FinishedTime.IsZero())
+	assert.Equal(t, int64(22), stored.Submitted)
+	assert.Equal(t, int64(20), stored.Succeeded)
+	assert.Equal(t, int64(2), stored.Failed)
+
+	item := getItem(t, es, b, 0)
+	assert.Equal(t, entitystore.StatusREADY, item.Status)
+	assert.NotEmpty(t, item.Run)
+	item = getItem(t, es, b, 20)
+	assert.Equal(t, v1.ErrorTypeFunctionError, item.Error.Type)
+	item = getItem(t, es, b, 21)
+	assert.Empty(t, item.Run)
+	assert.Equal(t, entitystore.StatusERROR, item.Status)
+	assert.Equal(t, "function hello is not READY", *item.Error.Message)
+	assert.Equal(t, v1.ErrorTypeSystemError, item.Error.Type)
+}
+
+func TestBatchResume(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	b := addBatch(t, es, 2, "Jon", "Jane", "Joe")
+	submitter := &fakeSubmitter{store: es}
+	h := NewEntityHandler(es, submitter, nil)
+
+	// items held back by the quotas are submitted when the batch is advanced again
+	submitter.exceeded = true
+	require.NoError(t, h.Add(context.Background(), b))
+	assert.Equal(t, entitystore.StatusINITIALIZED, getItem(t, es, b, 0).Status)
+	assert.Equal(t, int64(0), getBatch(t, es, b).Submitted)
+	submitter.exceeded = false
+
+	require.NoError(t, h.Add(context.Background(), b))
+	stored := getBatch(t, es, b)
+	assert.Equal(t, entitystore.StatusCREATING, stored.Status)
+	assert.Equal(t, int64(2), stored.Submitted)
+	assert.Equal(t, entitystore.StatusCREATING, getItem(t, es, b, 1).Status)
+
+	// the batch was interrupted before storing its progress: the submitted items are not submitted again
+	stored.Submitted = 0
+	_, err := es.Update(context.Background(), stored.Revision, stored)
+	require.NoError(t, err)
+	require.NoError(t, h.Add(context.Background(), b))
+	assert.Equal(t, 2, submitter.submitted)
+	assert.Equal(t, int64(2), getBatch(t, es, b).Submitted)
+
+	// an item whose run was added before the batch was interrupted gets the existing run
+	item := getItem(t, es, b, 2)
+	_, err = submitter.SubmitItem(context.Background(), b, item, item.runName())
+	require.NoError(t, err)
+	completeRuns(t, es, b)
+	require.NoError(t, h.Add(context.Background(), b))
+	require.NoError(t, h.Add(context.Background(), b))
+	completeRuns(t, es, b)
+	require.NoError(t, h.Add(context.Background(), b))
+
+	stored = getBatch(t, es, b)
+	assert.Equal(t, entitystore.StatusREADY, stored.Status)
+	assert.Equal(t, int64(3), stored.Succeeded)
+	var runs []*functions.FnRun
+	require.NoError(t, es.List(context.Background(), testOrgID, entitystore.Options{}, &runs))
+	assert.Len(t, runs, 3)
+}
+
+func TestBatchRunCompleted(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	b := addBatch(t, es, 2, "Jon")
+	watcher := make(chan controller.WatchEvent, 1)
+	h := NewEntityHandler(es, &fakeSubmitter{store: es}, watcher)
+
+	h.RunCompleted(context.Background(), &functions.FnRun{BaseEntity: entitystore.BaseEntity{OrganizationID: testOrgID}})
+	h.RunCompleted(context.Background(), &functions.FnRun{BaseEntity: entitystore.BaseEntity{
+		OrganizationID: testOrgID,
+		Tags:           map[string]string{RunTag: b.Name},
+	}})
+	require.Len(t, watcher, 1)
+	assert.Equal(t, b.Name, (<-watcher).Entity.GetName())
+}
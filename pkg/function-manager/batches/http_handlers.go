@@ -0,0 +1,281 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package batches
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	ewrapper "github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	batchapi "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/batch"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+const (
+	// DefaultConcurrency is the maximum number of runs of a batch executed at the same time, when not set
+	DefaultConcurrency = 10
+
+	// RunTag is the tag set on function runs executed on behalf of a batch
+	RunTag = "batch"
+
+	// JSONLinesMediaType is the media type of batch inputs uploaded as one JSON value per line
+	JSONLinesMediaType = "application/x-ndjson"
+)
+
+// Handlers is a base struct for batch API handlers.
+type Handlers struct {
+	store   entitystore.EntityStore
+	watcher controller.Watcher
+}
+
+// NewHandlers Creates new instance of batch handlers
+func NewHandlers(store entitystore.EntityStore, watcher controller.Watcher) *Handlers {
+	return &Handlers{
+		watcher: watcher,
+		store:   store,
+	}
+}
+
+// ConfigureHandlers configures API handlers for Batch endpoints
+func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
+	a, ok := api.(*operations.FunctionManagerAPI)
+	if !ok {
+		panic("Cannot configure api")
+	}
+
+	a.RegisterConsumer(JSONLinesMediaType, JSONLinesConsumer())
+
+	a.BatchAddBatchHandler = batchapi.AddBatchHandlerFunc(h.addBatch)
+	a.BatchGetBatchHandler = batchapi.GetBatchHandlerFunc(h.getBatch)
+	a.BatchGetBatchesHandler = batchapi.GetBatchesHandlerFunc(h.getBatches)
+	a.BatchGetBatchItemsHandler = batchapi.GetBatchItemsHandlerFunc(h.getBatchItems)
+}
+
+// JSONLinesConsumer decodes the inputs of a batch from one JSON value per line
+func JSONLinesConsumer() runtime.Consumer {
+	return runtime.ConsumerFunc(func(reader io.Reader, data interface{}) error {
+		b, ok := data.(*v1.Batch)
+		if !ok {
+			return ewrapper.Errorf("%s can only be decoded into a batch, not %T", JSONLinesMediaType, data)
+		}
+		decoder := json.NewDecoder(reader)
+		for {
+			var input interface{}
+			if err := decoder.Decode(&input); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return ewrapper.Wrapf(err, "error decoding input %d", len(b.Inputs)+1)
+			}
+			b.Inputs = append(b.Inputs, input)
+		}
+	})
+}
+
+// addBatch handles starting the runs of a function over a list of inputs
+func (h *Handlers) addBatch(params batchapi.AddBatchParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "addBatch")
+	defer span.Finish()
+
+	b := &Batch{}
+	b.FromModel(params.Body, params.XDispatchOrg)
+	if params.FunctionName != nil {
+		b.FunctionName = *params.FunctionName
+	}
+	if params.Concurrency != nil {
+		b.Concurrency = *params.Concurrency
+	}
+	if b.Concurrency == 0 {
+		b.Concurrency = DefaultConcurrency
+	}
+	if b.FunctionName == "" {
+		return batchapi.NewAddBatchBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String("Bad Request: No function specified"),
+		})
+	}
+	if b.Total == 0 {
+		return batchapi.NewAddBatchBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String("Bad Request: No inputs"),
+		})
+	}
+
+	f := new(functions.Function)
+	if err := h.store.Get(ctx, params.XDispatchOrg, b.FunctionName, entitystore.Options{}, f); err != nil {
+		log.Debugf("store error when getting function: %+v", err)
+		return batchapi.NewAddBatchNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("function", b.FunctionName),
+		})
+	}
+	if f.Status != entitystore.StatusREADY {
+		return batchapi.NewAddBatchNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: swag.String(fmt.Sprintf("function %s is not READY", b.FunctionName)),
+		})
+	}
+
+	b.Name = uuid.NewV4().String()
+	b.Status = entitystore.StatusINITIALIZED
+
+	// the items are stored before the batch, which is only executed once they all are
+	for _, item := range b.Items(params.Body.Inputs) {
+		if _, err := h.store.Add(ctx, item); err != nil {
+			log.Errorf("store error when adding item %d of new batch %s: %+v", item.Index, b.Name, err)
+			return batchapi.NewAddBatchDefault(500).WithPayload(&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: utils.ErrorMsgInternalError("batch", b.Name),
+			})
+		}
+	}
+	if _, err := h.store.Add(ctx, b); err != nil {
+		log.Errorf("store error when adding new batch %s: %+v", b.Name, err)
+		return batchapi.NewAddBatchDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("batch", b.Name),
+		})
+	}
+	log.Infof("batch %s of %d runs of function %s created", b.Name, b.Total, b.FunctionName)
+	h.watcher.OnAction(ctx, b)
+	return batchapi.NewAddBatchAccepted().WithPayload(b.ToModel())
+}
+
+// getBatch handles retrieval of a single batch
+func (h *Handlers) getBatch(params batchapi.GetBatchParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getBatch")
+	defer span.Finish()
+
+	b := Batch{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.BatchName.String(), entitystore.Options{}, &b); err != nil {
+		log.Debugf("store error when getting batch: %+v", err)
+		return batchapi.NewGetBatchNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("batch", params.BatchName.String()),
+		})
+	}
+	return batchapi.NewGetBatchOK().WithPayload(b.ToModel())
+}
+
+// getBatches handles retrieval of batch list
+func (h *Handlers) getBatches(params batchapi.GetBatchesParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getBatches")
+	defer span.Finish()
+
+	var err error
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	if params.FunctionName != nil {
+		opts.Filter.Add(
+			entitystore.FilterStat{
+				Scope:   entitystore.FilterScopeExtra,
+				Subject: "FunctionName",
+				Verb:    entitystore.FilterVerbEqual,
+				Object:  *params.FunctionName,
+			})
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %s", err)
+		return batchapi.NewGetBatchesBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+
+	var batches []*Batch
+	if err = h.store.List(ctx, params.XDispatchOrg, opts, &batches); err != nil {
+		log.Errorf("store error when listing batches: %+v", err)
+		return batchapi.NewGetBatchesDefault(http.StatusInternalServerError).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when getting batches"),
+			})
+	}
+	batchModels := []*v1.Batch{}
+	for _, b := range batches {
+		batchModels = append(batchModels, b.ToModel())
+	}
+	return batchapi.NewGetBatchesOK().WithPayload(batchModels)
+}
+
+// getBatchItems handles retrieval of the result of each input of a batch, with the output of its run
+func (h *Handlers) getBatchItems(params batchapi.GetBatchItemsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getBatchItems")
+	defer span.Finish()
+
+	b := Batch{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.BatchName.String(), entitystore.Options{}, &b); err != nil {
+		log.Debugf("store error when getting batch: %+v", err)
+		return batchapi.NewGetBatchItemsNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("batch", params.BatchName.String()),
+		})
+	}
+
+	var status entitystore.Status
+	if swag.BoolValue(params.Failed) {
+		status = entitystore.StatusERROR
+	}
+	var items []*Item
+	if err := h.store.List(ctx, params.XDispatchOrg, itemsOptions(b.Name, status), &items); err != nil {
+		log.Errorf("store error when listing items of batch %s: %+v", b.Name, err)
+		return batchapi.NewGetBatchItemsDefault(http.StatusInternalServerError).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("batch", b.Name),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Index < items[j].Index })
+
+	// the outputs are those of the runs of the batch, listed at once instead of getting them one by one
+	var runs []*functions.FnRun
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything().Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeTag,
+			Subject: RunTag,
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  b.Name,
+		}),
+	}
+	if err := h.store.List(ctx, params.XDispatchOrg, opts, &runs); err != nil {
+		log.Errorf("store error when listing runs of batch %s: %+v", b.Name, err)
+		return batchapi.NewGetBatchItemsDefault(http.StatusInternalServerError).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("batch", b.Name),
+		})
+	}
+	runsByName := make(map[string]*functions.FnRun, len(runs))
+	for _, run := range runs {
+		runsByName[run.Name] = run
+	}
+
+	models := []*v1.BatchItem{}
+	for _, item := range items {
+		m := item.ToModel()
+		if run, ok := runsByName[item.Run]; ok {
+			m.Output = run.Output
+			m.OutputContentType = run.OutputContentType
+		}
+		models = append(models, m)
+	}
+	return batchapi.NewGetBatchItemsOK().WithPayload(models)
+}
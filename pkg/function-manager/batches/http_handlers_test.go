@@ -0,0 +1,143 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package batches
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/batch"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+const testOrgID = "testOrg"
+
+func TestJSONLinesConsumer(t *testing.T) {
+	var b v1.Batch
+	err := JSONLinesConsumer().Consume(strings.NewReader("{\"name\": \"Jon\"}\n\"text\"\n\n42\n"), &b)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Jon"}, "text", float64(42)}, b.Inputs)
+
+	err = JSONLinesConsumer().Consume(strings.NewReader("{\"name\": \"Jon\"}\n{invalid\n"), &b)
+	assert.Error(t, err)
+}
+
+func TestBatchesHandlers(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	watcher := make(chan controller.WatchEvent, 1)
+	h := NewHandlers(es, watcher)
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	_, err := es.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{Name: "hello", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+	})
+	require.NoError(t, err)
+
+	add := batch.AddBatchParams{
+		HTTPRequest:  httptest.NewRequest("POST", "/v1/batches", nil),
+		Body:         &v1.Batch{FunctionName: "missing", Inputs: []interface{}{"Jon", "Jane"}},
+		XDispatchOrg: testOrgID,
+	}
+	var errorBody v1.Error
+	helpers.HandlerRequest(t, api.BatchAddBatchHandler.Handle(add, "testCookie"), &errorBody, 404)
+
+	add.Body = &v1.Batch{FunctionName: "hello"}
+	helpers.HandlerRequest(t, api.BatchAddBatchHandler.Handle(add, "testCookie"), &errorBody, 400)
+
+	// the function name and concurrency of a JSON-lines upload are query parameters
+	add.Body = &v1.Batch{Inputs: []interface{}{"Jon", "Jane"}}
+	add.FunctionName = swag.String("hello")
+	add.Concurrency = swag.Int64(2)
+	var addBody v1.Batch
+	helpers.HandlerRequest(t, api.BatchAddBatchHandler.Handle(add, "testCookie"), &addBody, 202)
+	assert.Equal(t, "hello", addBody.FunctionName)
+	assert.Equal(t, int64(2), addBody.Concurrency)
+	assert.Equal(t, int64(2), addBody.Total)
+	assert.Empty(t, addBody.Inputs)
+
+	b := (<-watcher).Entity.(*Batch)
+	assert.Equal(t, addBody.Name.String(), b.Name)
+	b.Succeeded, b.Failed = 1, 1
+	_, err = es.Update(context.Background(), b.Revision, b)
+	require.NoError(t, err)
+	var stored []*Item
+	require.NoError(t, es.List(context.Background(), testOrgID, itemsOptions(b.Name, ""), &stored))
+	require.Len(t, stored, 2)
+	message := "function failed"
+	for _, item := range stored {
+		if item.Index == 0 {
+			assert.Equal(t, "Jon", item.Input)
+			item.Run, item.Status = "f98d0a7f-0c1d-4020-a488-cabc501b08e0", entitystore.StatusREADY
+		} else {
+			item.Run, item.Status = "a3b1e1c2-0000-4000-8000-000000000001", entitystore.StatusERROR
+			item.Error = &v1.InvocationError{Message: &message}
+		}
+		_, err = es.Update(context.Background(), item.Revision, item)
+		require.NoError(t, err)
+	}
+	_, err = es.Add(context.Background(), &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "f98d0a7f-0c1d-4020-a488-cabc501b08e0",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+			Tags:           map[string]string{RunTag: b.Name},
+		},
+		FunctionName: "hello",
+		Output:       "hello Jon",
+	})
+	require.NoError(t, err)
+
+	get := batch.GetBatchParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/batches/"+b.Name, nil),
+		BatchName:    addBody.Name,
+		XDispatchOrg: testOrgID,
+	}
+	var getBody v1.Batch
+	helpers.HandlerRequest(t, api.BatchGetBatchHandler.Handle(get, "testCookie"), &getBody, 200)
+	assert.Equal(t, int64(1), getBody.Succeeded)
+	assert.Equal(t, int64(1), getBody.Failed)
+
+	list := batch.GetBatchesParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/batches", nil),
+		FunctionName: swag.String("other"),
+		XDispatchOrg: testOrgID,
+	}
+	var listBody []v1.Batch
+	helpers.HandlerRequest(t, api.BatchGetBatchesHandler.Handle(list, "testCookie"), &listBody, 200)
+	assert.Len(t, listBody, 0)
+	list.FunctionName = swag.String("hello")
+	helpers.HandlerRequest(t, api.BatchGetBatchesHandler.Handle(list, "testCookie"), &listBody, 200)
+	assert.Len(t, listBody, 1)
+
+	items := batch.GetBatchItemsParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/batches/"+b.Name+"/items", nil),
+		BatchName:    addBody.Name,
+		XDispatchOrg: testOrgID,
+	}
+	var itemsBody []v1.BatchItem
+	helpers.HandlerRequest(t, api.BatchGetBatchItemsHandler.Handle(items, "testCookie"), &itemsBody, 200)
+	require.Len(t, itemsBody, 2)
+	assert.Equal(t, "hello Jon", itemsBody[0].Output)
+	assert.Equal(t, v1.StatusERROR, itemsBody[1].Status)
+	assert.Equal(t, "function failed", *itemsBody[1].Error.Message)
+
+	items.Failed = swag.Bool(true)
+	helpers.HandlerRequest(t, api.BatchGetBatchItemsHandler.Handle(items, "testCookie"), &itemsBody, 200)
+	require.Len(t, itemsBody, 1)
+	assert.Equal(t, int64(1), itemsBody[0].Index)
+}
@@ -0,0 +1,144 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+const (
+	// maxBuildLogSize bounds the build output kept with a function build, the end of the output is kept
+	maxBuildLogSize = 256 * 1024
+	// maxBuildsPerFunction is the number of builds kept for each function, older ones are removed
+	maxBuildsPerFunction = 10
+	// buildLogFlushInterval is how often the build output is saved while the build is running
+	buildLogFlushInterval = 2 * time.Second
+)
+
+// buildLog collects the output of a build, saving it to the store as it is produced
+type buildLog struct {
+	sync.Mutex
+	ctx       context.Context
+	store     entitystore.EntityStore
+	build     *functions.FunctionBuild
+	buf       []byte
+	lastFlush time.Time
+}
+
+func (l *buildLog) Write(p []byte) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.buf = append(l.buf, p...)
+	if len(l.buf) > maxBuildLogSize {
+		l.buf = l.buf[len(l.buf)-maxBuildLogSize:]
+	}
+	if time.Since(l.lastFlush) > buildLogFlushInterval {
+		l.flush()
+	}
+	return len(p), nil
+}
+
+func (l *buildLog) flush() {
+	l.build.Logs = string(l.buf)
+	l.store.UpdateWithError(l.ctx, l.build, nil)
+	l.lastFlush = time.Now()
+}
+
+// getBuilds lists the builds of an organization matching the value of an extra field, the latest first
+func getBuilds(ctx context.Context, store entitystore.EntityStore, organizationID, field, value string) ([]*functions.FunctionBuild, error) {
+	var builds []*functions.FunctionBuild
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything().Add(
+			entitystore.FilterStat{
+				Scope:   entitystore.FilterScopeExtra,
+				Subject: field,
+				Verb:    entitystore.FilterVerbEqual,
+				Object:  value,
+			}),
+	}
+	if err := store.List(ctx, organizationID, opts, &builds); err != nil {
+		return nil, errors.Wrap(err, "store error when listing function builds")
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].CreatedTime.After(builds[j].CreatedTime)
+	})
+	return builds, nil
+}
+
+// buildImage builds the image of the function, or reuses an image built from the same content, recording the build
+func (h *funcEntityHandler) buildImage(ctx context.Context, e *functions.Function, hash string) (imageURL string, err error) {
+	build := &functions.FunctionBuild{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           uuid.NewV4().String(),
+			OrganizationID: e.OrganizationID,
+			Status:         entitystore.StatusCREATING,
+		},
+		FunctionName: e.Name,
+		ContentHash:  hash,
+	}
+
+	cached, err := getBuilds(ctx, h.Store, e.OrganizationID, "ContentHash", hash)
+	if err != nil {
+		return "", err
+	}
+	for _, b := range cached {
+		if b.Status == entitystore.StatusREADY {
+			build.Cached = true
+			build.FunctionImageURL = b.FunctionImageURL
+			build.Logs = fmt.Sprintf("Using image %s of build %s\n", b.FunctionImageURL, b.Name)
+			build.Status = entitystore.StatusREADY
+			if _, err := h.Store.Add(ctx, build); err != nil {
+				return "", errors.Wrap(err, "store error when adding function build")
+			}
+			h.pruneBuilds(ctx, e)
+			return build.FunctionImageURL, nil
+		}
+	}
+
+	if _, err := h.Store.Add(ctx, build); err != nil {
+		return "", errors.Wrap(err, "store error when adding function build")
+	}
+	output := &buildLog{ctx: ctx, store: h.Store, build: build, lastFlush: time.Now()}
+	defer func() {
+		output.Lock()
+		defer output.Unlock()
+		build.Logs = string(output.buf)
+		if err == nil {
+			build.Status = entitystore.StatusREADY
+		}
+		h.Store.UpdateWithError(ctx, build, err)
+		h.pruneBuilds(ctx, e)
+	}()
+
+	build.FunctionImageURL, err = h.ImageBuilder.BuildImage(ctx, e, output)
+	return build.FunctionImageURL, err
+}
+
+// pruneBuilds removes the oldest builds of the function
+func (h *funcEntityHandler) pruneBuilds(ctx context.Context, e *functions.Function) {
+	builds, err := getBuilds(ctx, h.Store, e.OrganizationID, "FunctionName", e.Name)
+	if err != nil {
+		log.Errorf("error when pruning builds of function %s: %+v", e.Name, err)
+		return
+	}
+	for i := maxBuildsPerFunction; i < len(builds); i++ {
+		if err := h.Store.Delete(ctx, e.OrganizationID, builds[i].Name, builds[i]); err != nil {
+			log.Errorf("store error when deleting build %s of function %s: %+v", builds[i].Name, e.Name, err)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/helpers"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/functions"
+	secretstore "github.com/vmware/dispatch/pkg/secret-store"
+)
+
+const (
+	// DefaultCallbackRetries is the number of times a failed callback is retried
+	DefaultCallbackRetries = 5
+	// DefaultCallbackBackoff is the delay before the first retry of a failed callback, doubled after each retry
+	DefaultCallbackBackoff = time.Second
+	// DefaultCallbackSweepPeriod is how often the callback secrets left behind by runs are looked for
+	DefaultCallbackSweepPeriod = time.Hour
+
+	// CallbackSignatureHeader is the header of the HMAC-SHA256 signature of a callback request signed with a secret
+	CallbackSignatureHeader = "X-Dispatch-Signature"
+
+	callbackTimeout = 10 * time.Second
+	// callbackLease is how long an attempt claimed by a process is left to it, before the run is resynced
+	callbackLease = 2 * callbackTimeout
+
+	// callbackSecretKey is the entry of the secret holding the key of a callback
+	callbackSecretKey = "secret"
+)
+
+// callbackSecretPrefix starts the names of the secrets holding the keys of callbacks, reserved so that they are left out
+// of the secrets listed to users
+var callbackSecretPrefix = secretstore.ReservedSecretPrefix + "callback-"
+
+// EventEmitter emits events through the event manager
+type EventEmitter interface {
+	EmitEvent(ctx context.Context, organizationID string, emission *v1.Emission) (*v1.Emission, error)
+}
+
+// CallbackSecrets keeps the secrets signing the callback requests of runs
+type CallbackSecrets interface {
+	CreateSecret(ctx context.Context, organizationID string, secret *v1.Secret) (*v1.Secret, error)
+	GetSecret(ctx context.Context, organizationID string, secretName string) (*v1.Secret, error)
+	DeleteSecret(ctx context.Context, organizationID string, secretName string) error
+	ListInternalSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error)
+}
+
+// Callbacks invokes the callbacks of completed runs. The state of a callback is stored with its run, so a pending
+// callback is delivered by the controller resyncing the run after a restart
+type Callbacks struct {
+	Store       entitystore.EntityStore
+	Events      EventEmitter
+	Secrets     CallbackSecrets
+	Client      *http.Client
+	Retries     int
+	Backoff     time.Duration
+	SweepPeriod time.Duration
+
+	// active holds the runs whose callback is being delivered or retried by this process
+	active     map[string]bool
+	activeLock sync.Mutex
+
+	// orphans holds the callback secrets found orphaned by the last sweep, deleted if the next sweep finds them again
+	orphans   map[string]bool
+	lastSweep time.Time
+	sweepLock sync.Mutex
+}
+
+// NewCallbacks is the constructor for Callbacks
+func NewCallbacks(store entitystore.EntityStore, events EventEmitter, secrets CallbackSecrets) *Callbacks {
+	return &Callbacks{
+		Store:       store,
+		Events:      events,
+		Secrets:     secrets,
+		Client:      &http.Client{Timeout: callbackTimeout},
+		Retries:     DefaultCallbackRetries,
+		Backoff:     DefaultCallbackBackoff,
+		SweepPeriod: DefaultCallbackSweepPeriod,
+		active:      make(map[string]bool),
+	}
+}
+
+// StoreSecret keeps the secret of the callback of a new run in the secret store, the run only referencing it by name
+func (c *Callbacks) StoreSecret(ctx context.Context, run *functions.FnRun, secret string) error {
+	if c == nil || run.Callback == nil || secret == "" {
+		return nil
+	}
+	if c.Secrets == nil {
+		return errors.New("no secret store to keep the callback secret")
+	}
+	name := callbackSecretPrefix + run.Name
+	_, err := c.Secrets.CreateSecret(ctx, run.OrganizationID, &v1.Secret{
+		Name:    &name,
+		Secrets: v1.SecretValue{callbackSecretKey: secret},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error storing the callback secret of function run %s", run.Name)
+	}
+	run.Callback.SecretName = name
+	return nil
+}
+
+// DeleteSecret removes the secret of the callback of a run from the secret store
+func (c *Callbacks) DeleteSecret(ctx context.Context, run *functions.FnRun) {
+	if c == nil || c.Secrets == nil || run.Callback == nil || run.Callback.SecretName == "" {
+		return
+	}
+	if err := c.Secrets.DeleteSecret(ctx, run.OrganizationID, run.Callback.SecretName); err != nil {
+		log.Warnf("error deleting the callback secret of function run %s: %s", run.Name, err)
+	}
+}
+
+// SweepSecrets deletes the callback secrets left behind by runs, e.g. as deleting them failed or as the function
+// manager stopped between storing the secret and adding the run, at most once per SweepPeriod. A secret is deleted
+// when two sweeps in a row find it orphaned, so that the secret of a run being added isn't taken for an orphan.
+func (c *Callbacks) SweepSecrets(ctx context.Context) {
+	if c == nil || c.Secrets == nil {
+		return
+	}
+	c.sweepLock.Lock()
+	defer c.sweepLock.Unlock()
+	if time.Since(c.lastSweep) < c.SweepPeriod {
+		return
+	}
+	c.lastSweep = time.Now()
+
+	// the organizations with callback secrets are the ones with functions, the secrets of the runs of deleted
+	// functions being deleted with them
+	var fns []*functions.Function
+	if err := c.Store.ListGlobal(ctx, entitystore.Options{}, &fns); err != nil {
+		log.Errorf("error listing the functions to sweep their callback secrets: %+v", err)
+		return
+	}
+	organizations := make(map[string]bool)
+	for _, f := range fns {
+		organizations[f.OrganizationID] = true
+	}
+	orphans := make(map[string]bool)
+	for organizationID := range organizations {
+		secrets, err := c.Secrets.ListInternalSecrets(ctx, organizationID)
+		if err != nil {
+			log.Warnf("error listing the callback secrets of organization %s: %s", organizationID, err)
+			continue
+		}
+		for _, secret := range secrets {
+			name := swag.StringValue(secret.Name)
+			if !strings.HasPrefix(name, callbackSecretPrefix) {
+				continue
+			}
+			if !c.orphaned(ctx, organizationID, strings.TrimPrefix(name, callbackSecretPrefix)) {
+				continue
+			}
+			key := organizationID + "/" + name
+			if !c.orphans[key] {
+				orphans[key] = true
+				continue
+			}
+			log.Infof("deleting the orphaned callback secret %s", name)
+			if err := c.Secrets.DeleteSecret(ctx, organizationID, name); err != nil {
+				log.Warnf("error deleting the orphaned callback secret %s: %s", name, err)
+				orphans[key] = true
+			}
+		}
+	}
+	c.orphans = orphans
+}
+
+// orphaned returns true if the run of a callback secret doesn't exist or its callback isn't pending anymore
+func (c *Callbacks) orphaned(ctx context.Context, organizationID, runName string) bool {
+	run := new(functions.FnRun)
+	found, err := c.Store.Find(ctx, organizationID, runName, entitystore.Options{}, run)
+	if err != nil {
+		log.Warnf("error getting function run %s to sweep its callback secret: %s", runName, err)
+		return false
+	}
+	return !found || run.Callback == nil || run.CallbackStatus != functions.CallbackPending
+}
+
+// Notify invokes the pending callback of a completed run in the background once its next attempt is due
+func (c *Callbacks) Notify(run *functions.FnRun) {
+	if c == nil || run.Callback == nil || run.CallbackStatus != functions.CallbackPending {
+		return
+	}
+	key := run.OrganizationID + "/" + run.Name
+	c.activeLock.Lock()
+	defer c.activeLock.Unlock()
+	if c.active[key] {
+		return
+	}
+	c.active[key] = true
+	c.schedule(run.OrganizationID, run.Name, run.Callback.NextAttempt)
+}
+
+func (c *Callbacks) schedule(organizationID, runName string, at time.Time) {
+	time.AfterFunc(time.Until(at), func() {
+		if next, pending := c.attempt(context.Background(), organizationID, runName); pending {
+			c.schedule(organizationID, runName, next)
+			return
+		}
+		c.activeLock.Lock()
+		delete(c.active, organizationID+"/"+runName)
+		c.activeLock.Unlock()
+	})
+}
+
+// attempt invokes the pending callback of the run once and stores the result with the run. A failed attempt is
+// retried with an exponential backoff until the retries are exhausted, attempt returns when the next one is due if the
+// callback is still pending.
+//
+// The attempt is claimed before the callback is invoked, by storing the end of its lease as the next attempt, so that
+// a process racing to deliver the callback, e.g. after resyncing the run, finds the run updated and leaves it. The
+// callback is only invoked twice if storing its result fails, receivers can tell the invocations apart by run name.
+func (c *Callbacks) attempt(ctx context.Context, organizationID, runName string) (time.Time, bool) {
+	run := new(functions.FnRun)
+	if err := c.Store.Get(ctx, organizationID, runName, entitystore.Options{}, run); err != nil {
+		log.Errorf("error getting function run %s for its callback: %+v", runName, err)
+		return time.Time{}, false
+	}
+	callback := run.Callback
+	if callback == nil || run.CallbackStatus != functions.CallbackPending {
+		return time.Time{}, false
+	}
+	// the run may have been resynced before the retry stored with it is due
+	if time.Now().Before(callback.NextAttempt) {
+		return callback.NextAttempt, true
+	}
+	callback.NextAttempt = time.Now().Add(callbackLease)
+	if _, err := c.Store.Update(ctx, run.Revision, run); err != nil {
+		log.Debugf("callback of function run %s not claimed, checking it again: %s", run.Name, err)
+		return c.recheck(ctx, organizationID, runName)
+	}
+
+	err := c.deliver(ctx, run)
+	switch {
+	case err == nil:
+		log.Debugf("callback of function run %s invoked", run.Name)
+		run.CallbackStatus = functions.CallbackDelivered
+		callback.Error = ""
+	case callback.Attempts >= c.Retries:
+		log.Errorf("giving up on the callback of function run %s: %+v", run.Name, err)
+		run.CallbackStatus = functions.CallbackFailed
+		callback.Attempts++
+		callback.Error = err.Error()
+	default:
+		callback.Attempts++
+		log.Warnf("callback of function run %s failed (attempt %d of %d): %s", run.Name, callback.Attempts, c.Retries+1, err)
+		callback.NextAttempt = time.Now().Add(c.Backoff << uint(callback.Attempts-1))
+		callback.Error = err.Error()
+	}
+	if run.CallbackStatus != functions.CallbackPending {
+		c.DeleteSecret(ctx, run)
+	}
+	if _, err := c.Store.Update(ctx, run.Revision, run); err != nil {
+		log.Errorf("error storing the callback of function run %s: %+v", run.Name, err)
+		return c.recheck(ctx, organizationID, runName)
+	}
+	return callback.NextAttempt, run.CallbackStatus == functions.CallbackPending
+}
+
+// recheck returns when the next attempt of the callback of the run is due, if it is still pending
+func (c *Callbacks) recheck(ctx context.Context, organizationID, runName string) (time.Time, bool) {
+	run := new(functions.FnRun)
+	if err := c.Store.Get(ctx, organizationID, runName, entitystore.Options{}, run); err != nil {
+		log.Errorf("error getting function run %s for its callback: %+v", runName, err)
+		return time.Time{}, false
+	}
+	if run.Callback == nil || run.CallbackStatus != functions.CallbackPending {
+		return time.Time{}, false
+	}
+	return run.Callback.NextAttempt, true
+}
+
+// deliver invokes the callback of the run with the run, either by POSTing it or by publishing it as an event
+func (c *Callbacks) deliver(ctx context.Context, run *functions.FnRun) error {
+	body, err := json.Marshal(runEntityToModel(run))
+	if err != nil {
+		return errors.Wrap(err, "error encoding the function run")
+	}
+	if run.Callback.EventType != "" {
+		return c.emit(ctx, run.OrganizationID, run.Name, run.Callback.EventType, body)
+	}
+	secret, err := c.secret(ctx, run.OrganizationID, run.Callback.SecretName)
+	if err != nil {
+		return err
+	}
+	return c.post(run.Callback.URL, secret, body)
+}
+
+// secret returns the secret signing the callback requests from the secret store
+func (c *Callbacks) secret(ctx context.Context, organizationID, secretName string) (string, error) {
+	if secretName == "" {
+		return "", nil
+	}
+	if c.Secrets == nil {
+		return "", errors.New("no secret store to get the callback secret")
+	}
+	secret, err := c.Secrets.GetSecret(ctx, organizationID, secretName)
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting the callback secret %s", secretName)
+	}
+	return secret.Secrets[callbackSecretKey], nil
+}
+
+// retry calls fn with an exponential backoff until it succeeds or the retries are exhausted
+func (c *Callbacks) retry(what string, fn func() error) error {
+	backoff := c.Backoff
+	var err error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = fn(); err == nil {
+			log.Debugf("%s invoked", what)
+			return nil
+		}
+		log.Warnf("%s failed (attempt %d of %d): %s", what, attempt+1, c.Retries+1, err)
+	}
+	log.Errorf("giving up on the %s: %+v", what, err)
+	return err
+}
+
+func (c *Callbacks) post(u, secret string, body []byte) error {
+	req, err := http.NewRequest("POST", u, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating callback request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(CallbackSignatureHeader, signCallback(secret, body))
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("callback returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *Callbacks) emit(ctx context.Context, organizationID, runName, eventType string, body []byte) error {
+	if c.Events == nil {
+		return errors.New("no event manager to publish the event")
+	}
+	ev := events.NewCloudEventWithDefaults(eventType)
+	ev.Source = "dispatch/runs/" + runName
+	// the same ID for every attempt, so subscribers can tell an event published twice
+	ev.EventID = runName
+	ev.ContentType = "application/json"
+	ev.Data = body
+	_, err := c.Events.EmitEvent(ctx, organizationID, &v1.Emission{CloudEvent: *helpers.CloudEventToAPI(&ev)})
+	return err
+}
+
+// signCallback returns the value of the signature header of a callback request with body signed with secret
+func signCallback(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateCallback(m *v1.RunCallback) error {
+	if m == nil {
+		return nil
+	}
+	if (m.URL == "") == (m.EventType == "") {
+		return errors.New("callback requires either a url or an eventType")
+	}
+	if m.URL != "" {
+		if u, err := url.Parse(m.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("invalid callback url %q", m.URL)
+		}
+	}
+	if m.Secret != "" && m.URL == "" {
+		return errors.New("callback secret requires a url")
+	}
+	return nil
+}
+
+// callbackModelToEntity leaves out the secret, which is kept in the secret store by Callbacks.StoreSecret
+func callbackModelToEntity(m *v1.RunCallback) *functions.RunCallback {
+	if m == nil {
+		return nil
+	}
+	return &functions.RunCallback{
+		URL:       m.URL,
+		EventType: m.EventType,
+	}
+}
+
+// callbackEntityToModel leaves out the secret, which is never returned
+func callbackEntityToModel(c *functions.RunCallback) *v1.RunCallback {
+	if c == nil {
+		return nil
+	}
+	return &v1.RunCallback{
+		URL:       c.URL,
+		EventType: c.EventType,
+	}
+}
+
+// pendingCallbacksFilter selects the completed runs whose callback is still pending
+func pendingCallbacksFilter() entitystore.Filter {
+	return entitystore.FilterEverything().Add(
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeExtra,
+			Subject: "CallbackStatus",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  functions.CallbackPending,
+		},
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbIn,
+			Object:  []entitystore.Status{entitystore.StatusREADY, entitystore.StatusERROR},
+		})
+}
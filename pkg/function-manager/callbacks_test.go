@@ -0,0 +1,285 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+	secretstore "github.com/vmware/dispatch/pkg/secret-store"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+type fakeEmitter struct {
+	failures  int
+	emissions []*v1.Emission
+}
+
+func (e *fakeEmitter) EmitEvent(ctx context.Context, organizationID string, emission *v1.Emission) (*v1.Emission, error) {
+	if e.failures > 0 {
+		e.failures--
+		return nil, errors.New("event manager unavailable")
+	}
+	e.emissions = append(e.emissions, emission)
+	return emission, nil
+}
+
+type fakeSecrets map[string]string
+
+func (s fakeSecrets) CreateSecret(ctx context.Context, organizationID string, secret *v1.Secret) (*v1.Secret, error) {
+	s[*secret.Name] = secret.Secrets[callbackSecretKey]
+	return secret, nil
+}
+
+func (s fakeSecrets) GetSecret(ctx context.Context, organizationID string, secretName string) (*v1.Secret, error) {
+	value, ok := s[secretName]
+	if !ok {
+		return nil, errors.Errorf("secret %s not found", secretName)
+	}
+	return &v1.Secret{Name: &secretName, Secrets: v1.SecretValue{callbackSecretKey: value}}, nil
+}
+
+func (s fakeSecrets) DeleteSecret(ctx context.Context, organizationID string, secretName string) error {
+	delete(s, secretName)
+	return nil
+}
+
+func (s fakeSecrets) ListInternalSecrets(ctx context.Context, organizationID string) ([]v1.Secret, error) {
+	var secrets []v1.Secret
+	for name := range s {
+		secrets = append(secrets, v1.Secret{Name: swag.String(name)})
+	}
+	return secrets, nil
+}
+
+func addCallbackRun(t *testing.T, callbacks *Callbacks, callback *functions.RunCallback, secret string) *functions.FnRun {
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "f98d0a7f-0c1d-4020-a488-cabc501b08e0",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+		},
+		FunctionName:   "hello",
+		Output:         "hello Jon",
+		Callback:       callback,
+		CallbackStatus: functions.CallbackPending,
+	}
+	require.NoError(t, callbacks.StoreSecret(context.Background(), run, secret))
+	_, err := callbacks.Store.Add(context.Background(), run)
+	require.NoError(t, err)
+	return run
+}
+
+func getCallbackRun(t *testing.T, callbacks *Callbacks, run *functions.FnRun) *functions.FnRun {
+	stored := new(functions.FnRun)
+	require.NoError(t, callbacks.Store.Get(context.Background(), testOrgID, run.Name, entitystore.Options{}, stored))
+	return stored
+}
+
+func TestCallbacksAttemptURL(t *testing.T) {
+	attempts := 0
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		signature = r.Header.Get(CallbackSignatureHeader)
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	secrets := fakeSecrets{}
+	callbacks := NewCallbacks(helpers.MakeEntityStore(t), nil, secrets)
+	callbacks.Backoff = time.Hour
+	run := addCallbackRun(t, callbacks, &functions.RunCallback{URL: server.URL}, "s3cr3t")
+	assert.Equal(t, "s3cr3t", secrets[run.Callback.SecretName])
+	assert.NotContains(t, run.Callback.SecretName, "s3cr3t")
+
+	// a failed attempt is stored with the run and retried once the backoff expires
+	next, pending := callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.True(t, pending)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), next, time.Minute)
+	stored := getCallbackRun(t, callbacks, run)
+	assert.Equal(t, functions.CallbackPending, stored.CallbackStatus)
+	assert.Equal(t, 1, stored.Callback.Attempts)
+	assert.Equal(t, "callback returned 503 Service Unavailable", stored.Callback.Error)
+
+	_, pending = callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.True(t, pending)
+	assert.Equal(t, 1, attempts, "the retry isn't due yet")
+
+	callbacks.Backoff = 0
+	stored.Callback.NextAttempt = time.Time{}
+	_, err := callbacks.Store.Update(context.Background(), stored.Revision, stored)
+	require.NoError(t, err)
+	_, pending = callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.True(t, pending)
+	_, pending = callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.False(t, pending)
+	assert.Equal(t, 3, attempts)
+
+	var received v1.Run
+	require.NoError(t, json.Unmarshal(body, &received))
+	assert.Equal(t, signCallback("s3cr3t", body), signature)
+	assert.Equal(t, "hello Jon", received.Output)
+	assert.Equal(t, v1.StatusREADY, received.Status)
+	require.NotNil(t, received.Callback)
+	assert.Empty(t, received.Callback.Secret)
+
+	stored = getCallbackRun(t, callbacks, run)
+	assert.Equal(t, functions.CallbackDelivered, stored.CallbackStatus)
+	assert.Empty(t, stored.Callback.Error)
+	assert.Empty(t, secrets, "the secret is deleted once the callback is delivered")
+}
+
+func TestCallbacksAttemptFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	callbacks := NewCallbacks(helpers.MakeEntityStore(t), nil, fakeSecrets{})
+	callbacks.Backoff = 0
+	callbacks.Retries = 2
+	run := addCallbackRun(t, callbacks, &functions.RunCallback{URL: server.URL}, "")
+
+	for i := 0; i < 2; i++ {
+		_, pending := callbacks.attempt(context.Background(), testOrgID, run.Name)
+		assert.True(t, pending)
+	}
+	_, pending := callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.False(t, pending)
+
+	stored := getCallbackRun(t, callbacks, run)
+	assert.Equal(t, functions.CallbackFailed, stored.CallbackStatus)
+	assert.Equal(t, 3, stored.Callback.Attempts)
+}
+
+func TestCallbacksAttemptClaimed(t *testing.T) {
+	emitter := &fakeEmitter{}
+	callbacks := NewCallbacks(helpers.MakeEntityStore(t), emitter, nil)
+	run := addCallbackRun(t, callbacks, &functions.RunCallback{EventType: "run.completed"}, "")
+
+	// another process claims the attempt between the run being read and updated
+	store := &claimingStore{EntityStore: callbacks.Store}
+	callbacks.Store = store
+	next, pending := callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.True(t, pending)
+	assert.WithinDuration(t, time.Now().Add(callbackLease), next, time.Minute)
+	assert.Empty(t, emitter.emissions, "the callback is left to the process which claimed it")
+}
+
+// claimingStore updates the run before the first update, as another process claiming its callback
+type claimingStore struct {
+	entitystore.EntityStore
+	claimed bool
+}
+
+func (s *claimingStore) Update(ctx context.Context, lastRevision uint64, entity entitystore.Entity) (int64, error) {
+	if !s.claimed {
+		s.claimed = true
+		run := new(functions.FnRun)
+		if err := s.EntityStore.Get(ctx, entity.GetOrganizationID(), entity.GetName(), entitystore.Options{}, run); err != nil {
+			return 0, err
+		}
+		run.Callback.NextAttempt = time.Now().Add(callbackLease)
+		if _, err := s.EntityStore.Update(ctx, run.Revision, run); err != nil {
+			return 0, err
+		}
+	}
+	return s.EntityStore.Update(ctx, lastRevision, entity)
+}
+
+func TestCallbacksSweepSecrets(t *testing.T) {
+	secrets := fakeSecrets{}
+	callbacks := NewCallbacks(helpers.MakeEntityStore(t), nil, secrets)
+	_, err := callbacks.Store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{Name: "hello", OrganizationID: testOrgID},
+	})
+	require.NoError(t, err)
+	run := addCallbackRun(t, callbacks, &functions.RunCallback{URL: "http://callback"}, "s3cr3t")
+	assert.True(t, strings.HasPrefix(run.Callback.SecretName, secretstore.ReservedSecretPrefix))
+	orphan := callbackSecretPrefix + "deleted-run"
+	secrets[orphan] = "s3cr3t"
+	secrets[secretstore.ReservedSecretPrefix+"other"] = "value"
+
+	callbacks.SweepSecrets(context.Background())
+	assert.Len(t, secrets, 3, "a secret is only deleted once found orphaned twice")
+
+	callbacks.SweepSecrets(context.Background())
+	assert.Len(t, secrets, 3, "the sweeps are SweepPeriod apart")
+
+	callbacks.lastSweep = time.Time{}
+	callbacks.SweepSecrets(context.Background())
+	assert.NotContains(t, secrets, orphan)
+	assert.Contains(t, secrets, run.Callback.SecretName, "the secret of a pending callback is kept")
+	assert.Contains(t, secrets, secretstore.ReservedSecretPrefix+"other", "only callback secrets are swept")
+}
+
+func TestCallbacksAttemptEvent(t *testing.T) {
+	emitter := &fakeEmitter{failures: 1}
+	callbacks := NewCallbacks(helpers.MakeEntityStore(t), emitter, nil)
+	callbacks.Backoff = 0
+	run := addCallbackRun(t, callbacks, &functions.RunCallback{EventType: "run.completed"}, "")
+
+	_, pending := callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.True(t, pending)
+	_, pending = callbacks.attempt(context.Background(), testOrgID, run.Name)
+	assert.False(t, pending)
+	require.Len(t, emitter.emissions, 1)
+	ev := emitter.emissions[0].CloudEvent
+	assert.Equal(t, "run.completed", ev.EventType)
+	assert.Equal(t, run.Name, ev.EventID)
+	assert.Equal(t, "application/json", ev.ContentType)
+	var received v1.Run
+	require.NoError(t, json.Unmarshal(ev.Data, &received))
+	assert.Equal(t, "hello Jon", received.Output)
+}
+
+func TestRunEntityHandlerSyncCallbacks(t *testing.T) {
+	callbacks := NewCallbacks(helpers.MakeEntityStore(t), nil, nil)
+	h := &runEntityHandler{Store: callbacks.Store, Callbacks: callbacks}
+	run := addCallbackRun(t, callbacks, &functions.RunCallback{EventType: "run.completed"}, "")
+	delivered := &functions.FnRun{
+		BaseEntity:     entitystore.BaseEntity{Name: "delivered", OrganizationID: testOrgID, Status: entitystore.StatusREADY},
+		Callback:       &functions.RunCallback{EventType: "run.completed"},
+		CallbackStatus: functions.CallbackDelivered,
+	}
+	_, err := callbacks.Store.Add(context.Background(), delivered)
+	require.NoError(t, err)
+
+	entities, err := h.Sync(context.Background(), time.Minute)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+	assert.Equal(t, run.Name, entities[0].GetName())
+}
+
+func TestValidateCallback(t *testing.T) {
+	assert.NoError(t, validateCallback(nil))
+	assert.NoError(t, validateCallback(&v1.RunCallback{URL: "https://example.com/done", Secret: "s3cr3t"}))
+	assert.NoError(t, validateCallback(&v1.RunCallback{EventType: "run.completed"}))
+	assert.Error(t, validateCallback(&v1.RunCallback{}))
+	assert.Error(t, validateCallback(&v1.RunCallback{URL: "https://example.com/done", EventType: "run.completed"}))
+	assert.Error(t, validateCallback(&v1.RunCallback{URL: "ftp://example.com/done"}))
+	assert.Error(t, validateCallback(&v1.RunCallback{EventType: "run.completed", Secret: "s3cr3t"}))
+}
@@ -0,0 +1,158 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// maxCancelAttempts bounds the attempts to store the state of a cancelled run, or the CREATING state of a run, when
+// the run is updated concurrently
+const maxCancelAttempts = 5
+
+// Cancellations cancels the runs executed by this function manager. FaaS drivers can't interrupt a function, so a
+// cancelled run is abandoned: it is stored as cancelled and its quotas are released, while its function may still
+// complete.
+type Cancellations struct {
+	lock sync.Mutex
+	runs map[string]*cancellation
+}
+
+type cancellation struct {
+	cancel    chan struct{}
+	done      chan struct{}
+	cancelled bool
+}
+
+// NewCancellations is the constructor for Cancellations
+func NewCancellations() *Cancellations {
+	return &Cancellations{runs: make(map[string]*cancellation)}
+}
+
+func cancellationKey(run *functions.FnRun) string {
+	return run.OrganizationID + "/" + run.Name
+}
+
+// start registers the execution of run, the returned channel is closed when the run is cancelled
+func (c *Cancellations) start(run *functions.FnRun) <-chan struct{} {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e := &cancellation{cancel: make(chan struct{}), done: make(chan struct{})}
+	c.runs[cancellationKey(run)] = e
+	return e.cancel
+}
+
+// finish unregisters the execution of run once its final state is stored
+func (c *Cancellations) finish(run *functions.FnRun) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := cancellationKey(run)
+	if e, ok := c.runs[key]; ok {
+		close(e.done)
+		delete(c.runs, key)
+	}
+}
+
+// Cancel cancels run and waits until its final state is stored. It returns false when run isn't executed by this
+// function manager.
+func (c *Cancellations) Cancel(run *functions.FnRun) bool {
+	if c == nil {
+		return false
+	}
+	c.lock.Lock()
+	e, ok := c.runs[cancellationKey(run)]
+	if ok && !e.cancelled {
+		e.cancelled = true
+		close(e.cancel)
+	}
+	c.lock.Unlock()
+	if !ok {
+		return false
+	}
+	<-e.done
+	return true
+}
+
+// setCancelled records in run that it was cancelled
+func setCancelled(run *functions.FnRun) {
+	message := fmt.Sprintf("function run %s cancelled", run.Name)
+	run.Cancelled = true
+	run.Status = entitystore.StatusERROR
+	run.Reason = []string{message}
+	run.Error = &v1.InvocationError{Type: v1.ErrorTypeSystemError, Message: swag.String(message)}
+	run.FinishedTime = time.Now()
+}
+
+func (h *Handlers) cancelRun(params fnrunner.CancelRunParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
+	defer span.Finish()
+
+	for attempt := 0; attempt < maxCancelAttempts; attempt++ {
+		run := new(functions.FnRun)
+		err := h.Store.Get(ctx, params.XDispatchOrg, params.RunName.String(), entitystore.Options{}, run)
+		if err == nil && params.FunctionName != nil && run.FunctionName != *params.FunctionName {
+			err = errors.Errorf("function run %s is a run of function %s", run.Name, run.FunctionName)
+		}
+		if err == nil && !h.Invocations.Allows(params.HTTPRequest, params.XDispatchOrg, run) {
+			err = errors.Errorf("function run %s was not created with the invocation token", run.Name)
+		}
+		if err != nil {
+			log.Debugf("Error returned by h.Store.Get: %+v", err)
+			return fnrunner.NewCancelRunNotFound().WithPayload(&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("function run", params.RunName.String()),
+			})
+		}
+
+		switch {
+		case run.Status == entitystore.StatusREADY || run.Status == entitystore.StatusERROR:
+			return fnrunner.NewCancelRunOK().WithPayload(runEntityToModel(run))
+		case h.Cancellations.Cancel(run):
+			log.Infof("Function run %s cancelled", run.Name)
+			// the cancelled run is read again
+		case run.Status == entitystore.StatusINITIALIZED:
+			// the run is queued, it isn't executed once stored as cancelled
+			setCancelled(run)
+			if _, err := h.Store.Update(ctx, run.Revision, run); err != nil {
+				log.Debugf("Function run %s updated while being cancelled: %+v", run.Name, err)
+				continue
+			}
+			log.Infof("Function run %s cancelled", run.Name)
+			return fnrunner.NewCancelRunOK().WithPayload(runEntityToModel(run))
+		default:
+			return fnrunner.NewCancelRunConflict().WithPayload(&v1.Error{
+				Code:    http.StatusConflict,
+				Message: swag.String(fmt.Sprintf("function run %s is executed by another function manager", run.Name)),
+			})
+		}
+	}
+	log.Errorf("Function run %s kept being updated while being cancelled", params.RunName)
+	return fnrunner.NewCancelRunDefault(500).WithPayload(&v1.Error{
+		Code:    http.StatusInternalServerError,
+		Message: utils.ErrorMsgInternalError("function run", params.RunName.String()),
+	})
+}
@@ -0,0 +1,169 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+type runnerFunc func(fn *functions.FunctionExecution, in interface{}) (interface{}, error)
+
+func (f runnerFunc) Run(fn *functions.FunctionExecution, in interface{}) (interface{}, error) {
+	return f(fn, in)
+}
+
+func cancelRunParams(name string) fnrunner.CancelRunParams {
+	return fnrunner.CancelRunParams{
+		HTTPRequest:  httptest.NewRequest("POST", "/v1/runs/"+name+"/cancel", nil),
+		RunName:      strfmt.UUID(name),
+		XDispatchOrg: testOrgID,
+	}
+}
+
+func addCancelTestRun(t *testing.T, store entitystore.EntityStore, name string, status entitystore.Status) *functions.FnRun {
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           name,
+			Status:         status,
+			OrganizationID: testOrgID,
+		},
+		FunctionName: "hello",
+	}
+	_, err := store.Add(context.Background(), run)
+	require.NoError(t, err)
+	return run
+}
+
+func TestHandlers_cancelQueuedRun(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	cancellations := NewCancellations()
+	handlers := &Handlers{Store: store, Cancellations: cancellations}
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	_, err := store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{Name: "hello", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+		Schema:     &functions.Schema{},
+	})
+	require.NoError(t, err)
+	queued := addCancelTestRun(t, store, "f98d0a7f-0c1d-4020-a488-cabc501b08e0", entitystore.StatusINITIALIZED)
+
+	responder := api.RunnerCancelRunHandler.Handle(cancelRunParams(queued.Name), "testCookie")
+	var respBody v1.Run
+	helpers.HandlerRequest(t, responder, &respBody, 200)
+	assert.True(t, respBody.Cancelled)
+	assert.Equal(t, v1.Status(entitystore.StatusERROR), respBody.Status)
+	require.NotNil(t, respBody.Error)
+	assert.Equal(t, v1.ErrorTypeSystemError, respBody.Error.Type)
+
+	// the queued copy of the run isn't executed
+	executed := false
+	h := &runEntityHandler{
+		Store: store,
+		Runner: runnerFunc(func(fn *functions.FunctionExecution, in interface{}) (interface{}, error) {
+			executed = true
+			return nil, nil
+		}),
+		Logs:          NewLogBuffer(10, 10),
+		Cancellations: cancellations,
+	}
+	require.NoError(t, h.Add(context.Background(), queued))
+	assert.False(t, executed)
+	assert.True(t, queued.Cancelled)
+	assert.Equal(t, entitystore.StatusERROR, queued.Status)
+
+	stored := new(functions.FnRun)
+	require.NoError(t, store.Get(context.Background(), testOrgID, queued.Name, entitystore.Options{}, stored))
+	assert.True(t, stored.Cancelled)
+	assert.Equal(t, entitystore.StatusERROR, stored.Status)
+}
+
+func TestHandlers_cancelRunningRun(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	cancellations := NewCancellations()
+	handlers := &Handlers{Store: store, Cancellations: cancellations}
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	_, err := store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{Name: "hello", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+		Schema:     &functions.Schema{},
+	})
+	require.NoError(t, err)
+	running := addCancelTestRun(t, store, "f98d0a7f-0c1d-4020-a488-cabc501b08e0", entitystore.StatusINITIALIZED)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	h := &runEntityHandler{
+		Store: store,
+		Runner: runnerFunc(func(fn *functions.FunctionExecution, in interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "too late", nil
+		}),
+		Logs:          NewLogBuffer(10, 10),
+		Cancellations: cancellations,
+	}
+	done := make(chan error)
+	go func() {
+		done <- h.Add(context.Background(), running)
+	}()
+	<-started
+
+	responder := api.RunnerCancelRunHandler.Handle(cancelRunParams(running.Name), "testCookie")
+	var respBody v1.Run
+	helpers.HandlerRequest(t, responder, &respBody, 200)
+	assert.True(t, respBody.Cancelled)
+	assert.Equal(t, v1.Status(entitystore.StatusERROR), respBody.Status)
+	assert.Nil(t, respBody.Output)
+
+	// the run is abandoned while its function is still running
+	require.NoError(t, <-done)
+	assert.True(t, running.Cancelled)
+}
+
+func TestHandlers_cancelRun(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	handlers := &Handlers{Store: store, Cancellations: NewCancellations()}
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	finished := addCancelTestRun(t, store, "f98d0a7f-0c1d-4020-a488-cabc501b08e0", entitystore.StatusREADY)
+	responder := api.RunnerCancelRunHandler.Handle(cancelRunParams(finished.Name), "testCookie")
+	var respBody v1.Run
+	helpers.HandlerRequest(t, responder, &respBody, 200)
+	assert.False(t, respBody.Cancelled)
+	assert.Equal(t, v1.Status(entitystore.StatusREADY), respBody.Status)
+
+	// a run executed by another function manager can't be cancelled here
+	remote := addCancelTestRun(t, store, "0a0a0a0a-0c1d-4020-a488-cabc501b08e0", entitystore.StatusCREATING)
+	responder = api.RunnerCancelRunHandler.Handle(cancelRunParams(remote.Name), "testCookie")
+	var errBody v1.Error
+	helpers.HandlerRequest(t, responder, &errBody, 409)
+
+	params := cancelRunParams(finished.Name)
+	params.FunctionName = swag.String("other")
+	responder = api.RunnerCancelRunHandler.Handle(params, "testCookie")
+	helpers.HandlerRequest(t, responder, &errBody, 404)
+
+	responder = api.RunnerCancelRunHandler.Handle(cancelRunParams("1b1b1b1b-0c1d-4020-a488-cabc501b08e0"), "testCookie")
+	helpers.HandlerRequest(t, responder, &errBody, 404)
+}
@@ -0,0 +1,293 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package configmaps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	configmapapi "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/configmap"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/functions/injectors"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// Handlers is a base struct for config map API handlers.
+type Handlers struct {
+	store   entitystore.EntityStore
+	watcher controller.Watcher
+}
+
+// NewHandlers Creates new instance of config map handlers
+func NewHandlers(store entitystore.EntityStore, watcher controller.Watcher) *Handlers {
+	return &Handlers{
+		store:   store,
+		watcher: watcher,
+	}
+}
+
+// ConfigureHandlers configures API handlers for ConfigMap endpoints
+func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
+	a, ok := api.(*operations.FunctionManagerAPI)
+	if !ok {
+		panic("Cannot configure api")
+	}
+
+	a.ConfigMapAddConfigMapHandler = configmapapi.AddConfigMapHandlerFunc(h.addConfigMap)
+	a.ConfigMapGetConfigMapHandler = configmapapi.GetConfigMapHandlerFunc(h.getConfigMap)
+	a.ConfigMapGetConfigMapsHandler = configmapapi.GetConfigMapsHandlerFunc(h.getConfigMaps)
+	a.ConfigMapUpdateConfigMapHandler = configmapapi.UpdateConfigMapHandlerFunc(h.updateConfigMap)
+	a.ConfigMapDeleteConfigMapHandler = configmapapi.DeleteConfigMapHandlerFunc(h.deleteConfigMap)
+}
+
+func toModel(cm *functions.ConfigMap) *v1.ConfigMap {
+	var tags []*v1.Tag
+	for k, v := range cm.Tags {
+		tags = append(tags, &v1.Tag{Key: k, Value: v})
+	}
+	return &v1.ConfigMap{
+		ID:           strfmt.UUID(cm.ID),
+		Name:         swag.String(cm.Name),
+		Kind:         utils.ConfigMapKind,
+		Data:         cm.Data,
+		CreatedTime:  cm.CreatedTime.Unix(),
+		ModifiedTime: cm.ModifiedTime.Unix(),
+		Tags:         tags,
+	}
+}
+
+func fromModel(m *v1.ConfigMap, cm *functions.ConfigMap) {
+	cm.Name = *m.Name
+	cm.Data = m.Data
+	cm.Tags = map[string]string{}
+	for _, t := range m.Tags {
+		cm.Tags[t.Key] = t.Value
+	}
+}
+
+// functionsUsing lists the functions which reference a config map
+func (h *Handlers) functionsUsing(ctx context.Context, organizationID, name string) ([]*functions.Function, error) {
+	var all []*functions.Function
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	if err := h.store.List(ctx, organizationID, opts, &all); err != nil {
+		return nil, errors.Wrap(err, "store error when listing functions")
+	}
+	var using []*functions.Function
+	for _, f := range all {
+		for _, configMap := range f.ConfigMaps {
+			if configMap == name {
+				using = append(using, f)
+				break
+			}
+		}
+	}
+	return using, nil
+}
+
+// addConfigMap handles creation of new config maps
+func (h *Handlers) addConfigMap(params configmapapi.AddConfigMapParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "addConfigMap")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return configmapapi.NewAddConfigMapBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+	if err := injectors.ValidateConfig(params.Body.Data); err != nil {
+		return configmapapi.NewAddConfigMapBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
+
+	cm := &functions.ConfigMap{}
+	fromModel(params.Body, cm)
+	cm.OrganizationID = params.XDispatchOrg
+	cm.Status = entitystore.StatusREADY
+	if _, err := h.store.Add(ctx, cm); err != nil {
+		if entitystore.IsUniqueViolation(err) {
+			return configmapapi.NewAddConfigMapConflict().WithPayload(&v1.Error{
+				Code:    http.StatusConflict,
+				Message: utils.ErrorMsgAlreadyExists("config map", cm.Name),
+			})
+		}
+		log.Errorf("error when storing the config map: %+v", err)
+		return configmapapi.NewAddConfigMapDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("config map", cm.Name),
+		})
+	}
+	return configmapapi.NewAddConfigMapCreated().WithPayload(toModel(cm))
+}
+
+// getConfigMap handles retrieval of single config map
+func (h *Handlers) getConfigMap(params configmapapi.GetConfigMapParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getConfigMap")
+	defer span.Finish()
+
+	cm := functions.ConfigMap{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.ConfigMapName, entitystore.Options{}, &cm); err != nil {
+		log.Warnf("Received GET for non-existent config map %s", params.ConfigMapName)
+		log.Debugf("store error when getting config map: %+v", err)
+		return configmapapi.NewGetConfigMapNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("config map", params.ConfigMapName),
+			})
+	}
+	return configmapapi.NewGetConfigMapOK().WithPayload(toModel(&cm))
+}
+
+// getConfigMaps handles retrieval of config map list
+func (h *Handlers) getConfigMaps(params configmapapi.GetConfigMapsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getConfigMaps")
+	defer span.Finish()
+
+	var configMaps []*functions.ConfigMap
+	var err error
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %s", err)
+		return configmapapi.NewGetConfigMapsBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+
+	if err = h.store.List(ctx, params.XDispatchOrg, opts, &configMaps); err != nil {
+		log.Errorf("store error when listing config maps: %+v", err)
+		return configmapapi.NewGetConfigMapsDefault(http.StatusInternalServerError).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when getting config maps"),
+			})
+	}
+	models := []*v1.ConfigMap{}
+	for _, cm := range configMaps {
+		models = append(models, toModel(cm))
+	}
+	return configmapapi.NewGetConfigMapsOK().WithPayload(models)
+}
+
+// updateConfigMap handles changes of a config map, the functions using it are redeployed with the new environment
+func (h *Handlers) updateConfigMap(params configmapapi.UpdateConfigMapParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "updateConfigMap")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return configmapapi.NewUpdateConfigMapBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+	if err := injectors.ValidateConfig(params.Body.Data); err != nil {
+		return configmapapi.NewUpdateConfigMapBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
+
+	cm := &functions.ConfigMap{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.ConfigMapName, entitystore.Options{}, cm); err != nil {
+		log.Warnf("Received UPDATE for non-existent config map %s", params.ConfigMapName)
+		log.Debugf("store error when getting config map: %+v", err)
+		return configmapapi.NewUpdateConfigMapNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("config map", params.ConfigMapName),
+			})
+	}
+	params.Body.Name = swag.String(cm.Name)
+	fromModel(params.Body, cm)
+	if _, err := h.store.Update(ctx, cm.Revision, cm); err != nil {
+		log.Errorf("store error when updating config map %s: %+v", cm.Name, err)
+		return configmapapi.NewUpdateConfigMapDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("config map", cm.Name),
+		})
+	}
+
+	// runs pick up the new values right away, the containers of the functions need to be redeployed
+	using, err := h.functionsUsing(ctx, cm.OrganizationID, cm.Name)
+	if err != nil {
+		log.Errorf("error when redeploying the functions using config map %s: %+v", cm.Name, err)
+	}
+	for _, f := range using {
+		f.FaasID = uuid.NewV4().String()
+		f.Status = entitystore.StatusUPDATING
+		if _, err := h.store.Update(ctx, f.Revision, f); err != nil {
+			log.Errorf("store error when redeploying function %s: %+v", f.Name, err)
+			continue
+		}
+		h.watcher.OnAction(ctx, f)
+	}
+	return configmapapi.NewUpdateConfigMapOK().WithPayload(toModel(cm))
+}
+
+// deleteConfigMap handles deletion of a config map, which must not be used by any function
+func (h *Handlers) deleteConfigMap(params configmapapi.DeleteConfigMapParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "deleteConfigMap")
+	defer span.Finish()
+
+	cm := &functions.ConfigMap{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.ConfigMapName, entitystore.Options{}, cm); err != nil {
+		log.Warnf("Received DELETE for non-existent config map %s", params.ConfigMapName)
+		log.Debugf("store error when getting config map: %+v", err)
+		return configmapapi.NewDeleteConfigMapNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("config map", params.ConfigMapName),
+			})
+	}
+	using, err := h.functionsUsing(ctx, cm.OrganizationID, cm.Name)
+	if err != nil {
+		log.Errorf("error when deleting config map %s: %+v", cm.Name, err)
+		return configmapapi.NewDeleteConfigMapDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("config map", cm.Name),
+		})
+	}
+	if len(using) > 0 {
+		var names []string
+		for _, f := range using {
+			names = append(names, f.Name)
+		}
+		return configmapapi.NewDeleteConfigMapBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("config map %s is used by functions: %s", cm.Name, strings.Join(names, ", "))),
+		})
+	}
+	if err := h.store.Delete(ctx, cm.OrganizationID, cm.Name, cm); err != nil {
+		log.Errorf("store error when deleting config map %s: %+v", cm.Name, err)
+		return configmapapi.NewDeleteConfigMapDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("config map", cm.Name),
+		})
+	}
+	return configmapapi.NewDeleteConfigMapOK().WithPayload(toModel(cm))
+}
@@ -0,0 +1,135 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package configmaps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/configmap"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+const testOrgID = "testOrg"
+
+func addConfigMapEntity(t *testing.T, api *operations.FunctionManagerAPI, body *v1.ConfigMap, status int) {
+	r := httptest.NewRequest("POST", "/v1/configmap", nil)
+	params := configmap.AddConfigMapParams{
+		HTTPRequest:  r,
+		Body:         body,
+		XDispatchOrg: testOrgID,
+	}
+	responder := api.ConfigMapAddConfigMapHandler.Handle(params, "testCookie")
+	if status == http.StatusCreated {
+		var respBody v1.ConfigMap
+		helpers.HandlerRequest(t, responder, &respBody, status)
+		assert.Equal(t, *body.Name, *respBody.Name)
+		assert.Equal(t, body.Data, respBody.Data)
+		return
+	}
+	var respBody v1.Error
+	helpers.HandlerRequest(t, responder, &respBody, status)
+	assert.NotEmpty(t, respBody.Message)
+}
+
+func TestConfigMapsAddConfigMapHandler(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := NewHandlers(es, make(chan controller.WatchEvent, 1))
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	addConfigMapEntity(t, api, &v1.ConfigMap{Name: swag.String("shared"), Data: map[string]string{"REGION": "us-west"}}, http.StatusCreated)
+	addConfigMapEntity(t, api, &v1.ConfigMap{Name: swag.String("shared"), Data: map[string]string{"REGION": "us-west"}}, http.StatusConflict)
+	addConfigMapEntity(t, api, &v1.ConfigMap{Name: swag.String("invalid"), Data: map[string]string{"NOT-A-VARIABLE": "x"}}, http.StatusBadRequest)
+
+	r := httptest.NewRequest("GET", "/v1/configmap", nil)
+	get := configmap.GetConfigMapsParams{
+		HTTPRequest:  r,
+		XDispatchOrg: testOrgID,
+	}
+	getResponder := api.ConfigMapGetConfigMapsHandler.Handle(get, "testCookie")
+	var getBody []v1.ConfigMap
+	helpers.HandlerRequest(t, getResponder, &getBody, 200)
+	require.Len(t, getBody, 1)
+	assert.Equal(t, "us-west", getBody[0].Data["REGION"])
+}
+
+func TestConfigMapsUpdateConfigMapHandler(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	watcher := make(chan controller.WatchEvent, 1)
+	h := NewHandlers(es, watcher)
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	addConfigMapEntity(t, api, &v1.ConfigMap{Name: swag.String("shared"), Data: map[string]string{"REGION": "us-west"}}, http.StatusCreated)
+	f := &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "hello",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+		},
+		FaasID:     "deadbeef",
+		ConfigMaps: []string{"shared"},
+	}
+	_, err := es.Add(context.Background(), f)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("PUT", "/v1/configmap/shared", nil)
+	update := configmap.UpdateConfigMapParams{
+		HTTPRequest:   r,
+		ConfigMapName: "shared",
+		Body:          &v1.ConfigMap{Name: swag.String("shared"), Data: map[string]string{"REGION": "eu-central"}},
+		XDispatchOrg:  testOrgID,
+	}
+	updateResponder := api.ConfigMapUpdateConfigMapHandler.Handle(update, "testCookie")
+	var updateBody v1.ConfigMap
+	helpers.HandlerRequest(t, updateResponder, &updateBody, 200)
+	assert.Equal(t, "eu-central", updateBody.Data["REGION"])
+
+	// the function using the config map is redeployed
+	event := <-watcher
+	redeployed := event.Entity.(*functions.Function)
+	assert.Equal(t, "hello", redeployed.Name)
+	assert.Equal(t, entitystore.StatusUPDATING, redeployed.Status)
+	assert.NotEqual(t, "deadbeef", redeployed.FaasID)
+
+	// the config map cannot be deleted while it is in use
+	r = httptest.NewRequest("DELETE", "/v1/configmap/shared", nil)
+	del := configmap.DeleteConfigMapParams{
+		HTTPRequest:   r,
+		ConfigMapName: "shared",
+		XDispatchOrg:  testOrgID,
+	}
+	delResponder := api.ConfigMapDeleteConfigMapHandler.Handle(del, "testCookie")
+	var errBody v1.Error
+	helpers.HandlerRequest(t, delResponder, &errBody, 400)
+
+	require.NoError(t, es.Delete(context.Background(), testOrgID, "hello", redeployed))
+	delResponder = api.ConfigMapDeleteConfigMapHandler.Handle(del, "testCookie")
+	var delBody v1.ConfigMap
+	helpers.HandlerRequest(t, delResponder, &delBody, 200)
+
+	r = httptest.NewRequest("GET", "/v1/configmap/shared", nil)
+	get := configmap.GetConfigMapParams{
+		HTTPRequest:   r,
+		ConfigMapName: "shared",
+		XDispatchOrg:  testOrgID,
+	}
+	getResponder := api.ConfigMapGetConfigMapHandler.Handle(get, "testCookie")
+	var getBody v1.Error
+	helpers.HandlerRequest(t, getResponder, &getBody, 404)
+}
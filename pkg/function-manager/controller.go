@@ -18,13 +18,21 @@ import (
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/controller"
 	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/batches"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows"
+	wfentities "github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
 	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/functions/injectors"
 	"github.com/vmware/dispatch/pkg/trace"
 )
 
 // ControllerConfig is the function manager controller configuration
 type ControllerConfig struct {
 	ResyncPeriod time.Duration
+	// Workers is the number of entities processed at a time, ReservedWorkers of them only for high priority runs
+	Workers         int
+	ReservedWorkers int
 }
 
 type funcEntityHandler struct {
@@ -32,6 +40,7 @@ type funcEntityHandler struct {
 	Store        entitystore.EntityStore
 	ImgClient    ImageGetter
 	ImageBuilder functions.ImageBuilder
+	Callbacks    *Callbacks
 }
 
 // Type returns the reflect.Type of a functions.Function
@@ -70,9 +79,18 @@ func (h *funcEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (er
 	e.Status = entitystore.StatusCREATING
 	h.Store.UpdateWithError(ctx, e, nil)
 
-	e.FunctionImageURL, err = h.ImageBuilder.BuildImage(ctx, e)
+	// the function image is kept when only the configuration of the function changed
+	if hash := functions.ContentHash(e); e.FunctionImageURL == "" || e.ContentHash != hash {
+		e.FunctionImageURL, err = h.buildImage(ctx, e, hash)
+		if err != nil {
+			return errors.Wrapf(err, "Error building image for function '%s'", e.ID)
+		}
+		e.ContentHash = hash
+	}
+
+	e.Environment, err = injectors.ResolveConfig(ctx, h.Store, e.OrganizationID, e.Config, e.ConfigMaps)
 	if err != nil {
-		return errors.Wrapf(err, "Error building image for function '%s'", e.ID)
+		return errors.Wrapf(err, "Error resolving the configuration of function '%s'", e.ID)
 	}
 
 	if err := h.FaaS.Create(ctx, e); err != nil {
@@ -108,17 +126,31 @@ func (h *funcEntityHandler) Delete(ctx context.Context, obj entitystore.Entity)
 		return errors.Wrapf(err, "Driver error when deleting a FaaS function")
 	}
 
-	runs, err := getFilteredRuns(ctx, h.Store, e.OrganizationID, &e.Name, nil, nil)
+	runs, err := getFilteredRuns(ctx, h.Store, e.OrganizationID, &e.Name, nil, nil, nil)
 	if err != nil {
 		return errors.Wrapf(err, "store error listing runs for function %s", e.Name)
 	}
 	for _, r := range runs {
+		if r.CallbackStatus == functions.CallbackPending {
+			h.Callbacks.DeleteSecret(ctx, r)
+		}
 		if err := h.Store.Delete(ctx, e.OrganizationID, r.Name, r); err != nil {
 			log.Debugf("fail to delete entity because of %s", err)
 			return errors.Wrap(err, "store error when deleting function run")
 		}
 	}
 
+	builds, err := getBuilds(ctx, h.Store, e.OrganizationID, "FunctionName", e.Name)
+	if err != nil {
+		return err
+	}
+	for _, b := range builds {
+		if err := h.Store.Delete(ctx, e.OrganizationID, b.Name, b); err != nil {
+			log.Debugf("fail to delete entity because of %s", err)
+			return errors.Wrap(err, "store error when deleting function build")
+		}
+	}
+
 	log.Debugf("trying to delete entity=%s, org=%s, id=%s, status=%s\n", e.Name, e.OrganizationID, e.ID, e.Status)
 	if err := h.Store.Delete(ctx, e.OrganizationID, e.Name, e); err != nil {
 		log.Debugf("fail to delete entity because of %s", err)
@@ -179,9 +211,18 @@ func (h *funcEntityHandler) getImage(ctx context.Context, organizationID string,
 }
 
 type runEntityHandler struct {
-	FaaS   functions.FaaSDriver
-	Runner functions.Runner
-	Store  entitystore.EntityStore
+	FaaS      functions.FaaSDriver
+	Runner    functions.Runner
+	Store     entitystore.EntityStore
+	Logs      *LogBuffer
+	Quotas    *quotas.Limiter
+	Callbacks *Callbacks
+	Batches   *batches.EntityHandler
+
+	// Invocations injects the invocation token of the run in the function context, nil disables them
+	Invocations *Invocations
+	// Cancellations abandons the runs cancelled while they are executed, nil disables cancelling running runs
+	Cancellations *Cancellations
 }
 
 // Type returns the reflect.Type of a functions.FnRun
@@ -200,6 +241,30 @@ func (err *invocationError) Error() string {
 	return ""
 }
 
+// claim stores the CREATING state of run. It returns false when the run was cancelled while it was queued, run then
+// holds the stored cancelled run.
+func (h *runEntityHandler) claim(ctx context.Context, run *functions.FnRun) bool {
+	for attempt := 1; ; attempt++ {
+		_, err := h.Store.Update(ctx, run.Revision, run)
+		if err == nil {
+			return true
+		}
+		stored := new(functions.FnRun)
+		if getErr := h.Store.Get(ctx, run.OrganizationID, run.Name, entitystore.Options{}, stored); getErr != nil || attempt == maxCancelAttempts {
+			// the run is executed anyway, as its state can't be stored
+			log.Errorf("Error when updating function run %s: %+v", run.Name, err)
+			return true
+		}
+		if stored.Cancelled {
+			waitChan := run.WaitChan
+			*run = *stored
+			run.WaitChan = waitChan
+			return false
+		}
+		run.Revision = stored.Revision
+	}
+}
+
 // Add creates a function execution (run)
 func (h *runEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err error) {
 	span, ctx := trace.Trace(ctx, "")
@@ -208,12 +273,29 @@ func (h *runEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err
 	run := obj.(*functions.FnRun)
 	defer run.Done()
 
-	defer func() { h.Store.UpdateWithError(ctx, run, err) }()
+	// the run was started against its quotas by the scheduler
+	defer h.Quotas.Release(run)
+
+	// the callback is invoked, the result event published and the batch of the run advanced once the final state of
+	// the run is stored
+	defer h.Batches.RunCompleted(ctx, run)
+	f := new(functions.Function)
+	defer h.Callbacks.Notify(run)
+
+	// cancelling the run waits until its final state is stored
+	cancelled := h.Cancellations.start(run)
+	defer h.Cancellations.finish(run)
 
 	run.Status = entitystore.StatusCREATING
-	h.Store.UpdateWithError(ctx, run, nil)
+	run.TraceID = trace.ID(span)
+	if !h.claim(ctx, run) {
+		log.Infof("Function run %s was cancelled before being executed", run.Name)
+		return nil
+	}
+
+	defer h.Callbacks.PublishResult(f, run)
+	defer func() { h.Store.UpdateWithError(ctx, run, err) }()
 
-	f := new(functions.Function)
 	if err = h.Store.Get(ctx, run.OrganizationID, run.FunctionName, entitystore.Options{}, f); err != nil {
 		return errors.Wrapf(err, "Error getting function from store: '%s'", run.FunctionName)
 	}
@@ -230,7 +312,18 @@ func (h *runEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err
 
 	fctx[functions.TimeoutKey] = f.Timeout
 
-	output, err := h.Runner.Run(&functions.FunctionExecution{
+	// runtimes continue the trace of the run
+	fctx.InjectTrace(span)
+
+	if err = h.Invocations.Inject(fctx, run); err != nil {
+		return err
+	}
+
+	input, err := functions.DecodePayload(run.Input, run.InputContentType)
+	if err != nil {
+		return err
+	}
+	execution := &functions.FunctionExecution{
 		Context:        fctx,
 		OrganizationID: run.OrganizationID,
 		RunID:          run.ID,
@@ -240,13 +333,38 @@ func (h *runEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err
 			SchemaIn:  f.Schema.In,
 			SchemaOut: f.Schema.Out,
 		},
-		Cookie:   "cookie",
-		Secrets:  run.Secrets,
-		Services: run.Services,
-	}, run.Input)
+		Cookie:      "cookie",
+		Secrets:     run.Secrets,
+		Services:    run.Services,
+		Config:      f.Config,
+		ConfigMaps:  f.ConfigMaps,
+		Middlewares: f.Middlewares,
+		// logs added to the function context, by middlewares or by the driver once the function returns, are written
+		// to the buffer
+		Logs: h.Logs.Writer(run),
+	}
+	// the function is abandoned when the run is cancelled, the function context isn't read anymore then
+	type result struct {
+		output interface{}
+		err    error
+	}
+	results := make(chan result, 1)
+	go func() {
+		output, err := h.Runner.Run(execution, input)
+		results <- result{output, err}
+	}()
+	var output interface{}
+	select {
+	case r := <-results:
+		output, err = r.output, r.err
+	case <-cancelled:
+		setCancelled(run)
+		return nil
+	}
+	run.FinishedTime = time.Now()
 	logs := fctx.Logs()
 	run.Logs = &logs
-	run.Output = output
+	run.Output, run.OutputContentType = functions.EncodePayload(output)
 
 	if err != nil {
 		var stacktrace []string
@@ -278,7 +396,6 @@ func (h *runEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err
 	}
 
 	run.Status = entitystore.StatusREADY
-	run.FinishedTime = time.Now()
 
 	return
 }
@@ -289,6 +406,11 @@ func (h *runEntityHandler) Update(ctx context.Context, obj entitystore.Entity) (
 	defer span.Finish()
 
 	run := obj.(*functions.FnRun)
+	if run.Status == entitystore.StatusREADY {
+		// completed runs are only resynced to deliver their pending callback
+		h.Callbacks.Notify(run)
+		return nil
+	}
 	defer func() { h.Store.UpdateWithError(ctx, run, err) }()
 	return errors.Errorf("updating runs not supported, fn: '%s'", run.FunctionName)
 }
@@ -308,28 +430,154 @@ func (h *runEntityHandler) Sync(ctx context.Context, resyncPeriod time.Duration)
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
-	return controller.DefaultSync(ctx, h.Store, h.Type(), resyncPeriod, syncFilter(resyncPeriod))
+	runs, err := controller.DefaultSync(ctx, h.Store, h.Type(), resyncPeriod, syncFilter(resyncPeriod))
+	if err != nil {
+		return nil, err
+	}
+	if h.Callbacks == nil {
+		return runs, nil
+	}
+	go h.Callbacks.SweepSecrets(context.Background())
+	pending, err := controller.DefaultSync(ctx, h.Store, h.Type(), resyncPeriod, pendingCallbacksFilter())
+	if err != nil {
+		return nil, err
+	}
+	return append(runs, pending...), nil
 }
 
-// Error handles errors with regards to function execution entities (currently a no-op)
+// Error delivers the pending callback of a failed run
 func (h *runEntityHandler) Error(ctx context.Context, obj entitystore.Entity) error {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
-	// TODO implement me
+	h.Callbacks.Notify(obj.(*functions.FnRun))
 	return nil
 }
 
+// workflowRunTag is the tag set on function runs executed on behalf of a workflow run
+const workflowRunTag = "workflowRun"
+
+// workflowTaskRunner executes the task states of workflows as function runs
+type workflowTaskRunner struct {
+	Store entitystore.EntityStore
+	Runs  *runEntityHandler
+}
+
+// RunTask creates a run of the function and executes it synchronously
+func (r *workflowTaskRunner) RunTask(ctx context.Context, wfRun *wfentities.WorkflowRun, function string, input interface{}) (string, interface{}, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	run, err := runFunctionSync(ctx, r.Store, r.Runs, wfRun.OrganizationID, function, input, &v1.Tag{Key: workflowRunTag, Value: wfRun.Name})
+	if run == nil {
+		return "", nil, err
+	}
+	return run.Name, run.Output, err
+}
+
+// batchItemSubmitter submits the items of batches as function runs, admitted and scheduled like any other run
+type batchItemSubmitter struct {
+	Store   entitystore.EntityStore
+	Quotas  *quotas.Limiter
+	Watcher controller.Watcher
+}
+
+// SubmitItem creates the run of the item with the given name and queues it, or returns the existing run
+func (s *batchItemSubmitter) SubmitItem(ctx context.Context, b *batches.Batch, item *batches.Item, runName string) (*functions.FnRun, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	f := new(functions.Function)
+	if err := s.Store.Get(ctx, b.OrganizationID, b.FunctionName, entitystore.Options{}, f); err != nil {
+		return nil, errors.Wrapf(err, "Error getting function from store: '%s'", b.FunctionName)
+	}
+	if f.Status != entitystore.StatusREADY {
+		return nil, errors.Errorf("function %s is not READY", b.FunctionName)
+	}
+
+	run := runModelToEntity(&v1.Run{
+		Input: item.Input,
+		Tags:  []*v1.Tag{{Key: batches.RunTag, Value: b.Name}},
+	}, f)
+	run.Name = runName
+	run.OrganizationID = b.OrganizationID
+	run.Status = entitystore.StatusINITIALIZED
+	if err := s.Quotas.Admit(ctx, run); err != nil {
+		return nil, err
+	}
+	if _, err := s.Store.Add(ctx, run); err != nil {
+		s.Quotas.Release(run)
+		if !entitystore.IsUniqueViolation(err) {
+			return nil, errors.Wrapf(err, "store error when adding function run for '%s'", b.FunctionName)
+		}
+		// the run was submitted before the batch was interrupted
+		existing := new(functions.FnRun)
+		if err := s.Store.Get(ctx, b.OrganizationID, runName, entitystore.Options{}, existing); err != nil {
+			return nil, errors.Wrapf(err, "store error when getting function run %s", runName)
+		}
+		return existing, nil
+	}
+	s.Watcher.OnAction(ctx, run)
+	return run, nil
+}
+
+// runFunctionSync creates a run of the function with the tag and executes it, the run is nil if it can't be created.
+// The run is admitted against the quotas like any other run, but it isn't queued by the scheduler: it is executed on
+// the worker of the caller, e.g. a workflow run, which the scheduler already gave a worker. Queueing it while the
+// caller holds its worker would deadlock once every worker executes a workflow run. A run which doesn't fit in the
+// concurrent runs of its quotas is refused with an *quotas.ExceededError instead of waiting, like a run exceeding a
+// quota, so the retry policy of the workflow state applies.
+func runFunctionSync(ctx context.Context, store entitystore.EntityStore, runs *runEntityHandler, organizationID, function string, input interface{}, tag *v1.Tag) (*functions.FnRun, error) {
+	f := new(functions.Function)
+	if err := store.Get(ctx, organizationID, function, entitystore.Options{}, f); err != nil {
+		return nil, errors.Wrapf(err, "Error getting function from store: '%s'", function)
+	}
+	if f.Status != entitystore.StatusREADY {
+		return nil, errors.Errorf("function %s is not READY", function)
+	}
+
+	run := runModelToEntity(&v1.Run{
+		Input: input,
+		Tags:  []*v1.Tag{tag},
+	}, f)
+	run.OrganizationID = organizationID
+	run.Status = entitystore.StatusINITIALIZED
+	if err := runs.Quotas.Admit(ctx, run); err != nil {
+		return nil, err
+	}
+	if err := runs.Quotas.StartNow(run); err != nil {
+		runs.Quotas.Release(run)
+		return nil, err
+	}
+	if _, err := store.Add(ctx, run); err != nil {
+		runs.Quotas.Release(run)
+		return nil, errors.Wrapf(err, "store error when adding function run for '%s'", function)
+	}
+
+	err := runs.Add(ctx, run)
+	return run, err
+}
+
 // NewController is the constructor for the function manager controller
-func NewController(config *ControllerConfig, store entitystore.EntityStore, faas functions.FaaSDriver, runner functions.Runner, imgClient ImageGetter, imageBuilder functions.ImageBuilder) controller.Controller {
+func NewController(config *ControllerConfig, store entitystore.EntityStore, faas functions.FaaSDriver, runner functions.Runner, imgClient ImageGetter, imageBuilder functions.ImageBuilder, logs *LogBuffer, limiter *quotas.Limiter, callbacks *Callbacks, invocations *Invocations, cancellations *Cancellations) controller.Controller {
 
+	workers := config.Workers
+	if workers == 0 {
+		workers = DefaultWorkers
+	}
 	c := controller.NewController(controller.Options{
 		ResyncPeriod: config.ResyncPeriod,
-		Workers:      1000, // want more functions concurrently? add more workers // TODO configure workers
+		Workers:      workers,
 		ServiceName:  "functions",
+		Scheduler:    NewFairScheduler(workers, config.ReservedWorkers, limiter),
 	})
-	c.AddEntityHandler(&funcEntityHandler{Store: store, FaaS: faas, ImgClient: imgClient, ImageBuilder: imageBuilder})
-	c.AddEntityHandler(&runEntityHandler{Store: store, FaaS: faas, Runner: runner})
+	c.AddEntityHandler(&funcEntityHandler{Store: store, FaaS: faas, ImgClient: imgClient, ImageBuilder: imageBuilder, Callbacks: callbacks})
+	batchHandler := batches.NewEntityHandler(store, &batchItemSubmitter{Store: store, Quotas: limiter, Watcher: c.Watcher()}, c.Watcher())
+	runs := &runEntityHandler{Store: store, FaaS: faas, Runner: runner, Logs: logs, Quotas: limiter, Callbacks: callbacks, Batches: batchHandler, Invocations: invocations, Cancellations: cancellations}
+	c.AddEntityHandler(runs)
+	c.AddEntityHandler(workflows.NewEntityHandler(store))
+	c.AddEntityHandler(workflows.NewRunEntityHandler(store, &workflowTaskRunner{Store: store, Runs: runs}, c.Watcher()))
+	c.AddEntityHandler(batchHandler)
 
 	return c
 }
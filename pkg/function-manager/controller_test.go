@@ -7,15 +7,24 @@ package functionmanager
 
 import (
 	"context"
+	"errors"
+	"io"
 	"testing"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
 	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/batches"
 	"github.com/vmware/dispatch/pkg/function-manager/mocks"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	quotaentities "github.com/vmware/dispatch/pkg/function-manager/quotas/entities"
+	wfentities "github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
 	"github.com/vmware/dispatch/pkg/functions"
 	fnmocks "github.com/vmware/dispatch/pkg/functions/mocks"
 	"github.com/vmware/dispatch/pkg/functions/runner"
@@ -78,7 +87,7 @@ func TestFuncEntityHandler_Add_ImageReady(t *testing.T) {
 	faas.On("Create", mock.Anything, function).Return(nil)
 
 	imageBuilder := &fnmocks.ImageBuilder{}
-	imageBuilder.On("BuildImage", mock.Anything, mock.Anything).Return("fake-image:latest", nil)
+	imageBuilder.On("BuildImage", mock.Anything, mock.Anything, mock.Anything).Return("fake-image:latest", nil)
 
 	h := &funcEntityHandler{
 		Store:        helpers.MakeEntityStore(t),
@@ -94,6 +103,175 @@ func TestFuncEntityHandler_Add_ImageReady(t *testing.T) {
 
 	faas.AssertExpectations(t)
 	imgMgr.AssertExpectations(t)
+	assert.Equal(t, "fake-image:latest", function.FunctionImageURL)
+	assert.Equal(t, functions.ContentHash(function), function.ContentHash)
+
+	builds, err := getBuilds(context.Background(), h.Store, testOrgID, "FunctionName", "testFunction")
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	assert.Equal(t, entitystore.StatusREADY, builds[0].Status)
+	assert.Equal(t, "fake-image:latest", builds[0].FunctionImageURL)
+	assert.False(t, builds[0].Cached)
+}
+
+func TestFuncEntityHandler_Add_BuildCache(t *testing.T) {
+	imgMgr := &mocks.ImageGetter{}
+	imgMgr.On("GetImage", mock.Anything, mock.Anything, mock.Anything).Return(
+		&v1.Image{
+			DockerURL: "test/image:latest",
+			Language:  "python3",
+			Status:    v1.StatusREADY,
+		}, nil)
+	faas := &fnmocks.FaaSDriver{}
+	faas.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	imageBuilder := &fnmocks.ImageBuilder{}
+	imageBuilder.On("BuildImage", mock.Anything, mock.Anything, mock.Anything).Return("fake-image:latest", nil).Run(func(args mock.Arguments) {
+		args.Get(2).(io.Writer).Write([]byte("Step 1/2 : FROM test/image:latest\n"))
+	}).Once()
+
+	h := &funcEntityHandler{
+		Store:        helpers.MakeEntityStore(t),
+		FaaS:         faas,
+		ImgClient:    imgMgr,
+		ImageBuilder: imageBuilder,
+	}
+
+	newFunction := func(name string) *functions.Function {
+		f := &functions.Function{
+			BaseEntity: entitystore.BaseEntity{
+				Name:           name,
+				Status:         entitystore.StatusCREATING,
+				OrganizationID: testOrgID,
+			},
+			ImageName: "testImage",
+			Source:    []byte("def handle(ctx, payload): pass"),
+			Handler:   "main",
+		}
+		_, err := h.Store.Add(context.Background(), f)
+		require.NoError(t, err)
+		return f
+	}
+
+	first, second := newFunction("first"), newFunction("second")
+	require.NoError(t, h.Add(context.Background(), first))
+	require.NoError(t, h.Add(context.Background(), second))
+
+	// the second function has the same content, the image of the first one is reused
+	imageBuilder.AssertExpectations(t)
+	assert.Equal(t, "fake-image:latest", second.FunctionImageURL)
+
+	builds, err := getBuilds(context.Background(), h.Store, testOrgID, "FunctionName", "first")
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	assert.Equal(t, "Step 1/2 : FROM test/image:latest\n", builds[0].Logs)
+
+	builds, err = getBuilds(context.Background(), h.Store, testOrgID, "FunctionName", "second")
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	assert.True(t, builds[0].Cached)
+	assert.Equal(t, entitystore.StatusREADY, builds[0].Status)
+}
+
+func TestFuncEntityHandler_Add_BuildError(t *testing.T) {
+	imgMgr := &mocks.ImageGetter{}
+	imgMgr.On("GetImage", mock.Anything, mock.Anything, mock.Anything).Return(
+		&v1.Image{
+			DockerURL: "test/image:latest",
+			Language:  "python3",
+			Status:    v1.StatusREADY,
+		}, nil)
+	imageBuilder := &fnmocks.ImageBuilder{}
+	imageBuilder.On("BuildImage", mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("pip install failed")).Run(func(args mock.Arguments) {
+		args.Get(2).(io.Writer).Write([]byte("Could not find a version that satisfies the requirement\n"))
+	})
+
+	h := &funcEntityHandler{
+		Store:        helpers.MakeEntityStore(t),
+		FaaS:         &fnmocks.FaaSDriver{},
+		ImgClient:    imgMgr,
+		ImageBuilder: imageBuilder,
+	}
+	function := &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "testFunction",
+			Status:         entitystore.StatusCREATING,
+			OrganizationID: testOrgID,
+		},
+		ImageName: "testImage",
+		Handler:   "main",
+	}
+	_, err := h.Store.Add(context.Background(), function)
+	require.NoError(t, err)
+
+	assert.Error(t, h.Add(context.Background(), function))
+	assert.Equal(t, entitystore.StatusERROR, function.Status)
+
+	builds, err := getBuilds(context.Background(), h.Store, testOrgID, "FunctionName", "testFunction")
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	assert.Equal(t, entitystore.StatusERROR, builds[0].Status)
+	assert.Equal(t, entitystore.Reason{"pip install failed"}, builds[0].Reason)
+	assert.Contains(t, builds[0].Logs, "Could not find a version")
+
+	// a failed build is not reused
+	_, err = h.buildImage(context.Background(), function, functions.ContentHash(function))
+	assert.Error(t, err)
+	imageBuilder.AssertNumberOfCalls(t, "BuildImage", 2)
+}
+
+func TestFuncEntityHandler_Add_ConfigOnly(t *testing.T) {
+	imgMgr := &mocks.ImageGetter{}
+	imgMgr.On("GetImage", mock.Anything, mock.Anything, mock.Anything).Return(
+		&v1.Image{
+			DockerURL: "test/image:latest",
+			Language:  "python3",
+			Status:    v1.StatusREADY,
+		}, nil)
+	faas := &fnmocks.FaaSDriver{}
+	function := &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "testFunction",
+			Status:         entitystore.StatusUPDATING,
+			OrganizationID: testOrgID,
+		},
+		ImageName:        "testImage",
+		ImageURL:         "test/image:latest",
+		FunctionImageURL: "fake-image:latest",
+		Handler:          "main",
+		Config:           map[string]string{"REGION": "us-west"},
+		ConfigMaps:       []string{"shared"},
+	}
+	function.ContentHash = functions.ContentHash(function)
+	faas.On("Create", mock.Anything, function).Return(nil)
+
+	imageBuilder := &fnmocks.ImageBuilder{}
+
+	h := &funcEntityHandler{
+		Store:        helpers.MakeEntityStore(t),
+		FaaS:         faas,
+		ImgClient:    imgMgr,
+		ImageBuilder: imageBuilder,
+	}
+
+	_, err := h.Store.Add(context.Background(), &functions.ConfigMap{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "shared",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+		},
+		Data: map[string]string{"REGION": "eu-central", "LOG_LEVEL": "debug"},
+	})
+	require.NoError(t, err)
+	_, err = h.Store.Add(context.Background(), function)
+	require.NoError(t, err)
+
+	require.NoError(t, h.Add(context.Background(), function))
+
+	// only the configuration changed, so the function image is reused
+	imageBuilder.AssertNotCalled(t, "BuildImage", mock.Anything, mock.Anything, mock.Anything)
+	faas.AssertExpectations(t)
+	assert.Equal(t, map[string]string{"REGION": "us-west", "LOG_LEVEL": "debug"}, function.Environment)
 }
 
 func TestFuncEntityHandler_Delete(t *testing.T) {
@@ -175,9 +353,19 @@ func TestRunEntityHandler_Add(t *testing.T) {
 		FunctionName: "testFunction",
 	}
 
+	tracer, closer := jaeger.NewTracer("function-manager", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
 	functionCalled := false
+	var runTrace opentracing.SpanContext
+	release := make(chan struct{})
 	var runnable functions.Runnable = func(ctx functions.Context, in interface{}) (interface{}, error) {
 		functionCalled = true
+		runTrace = ctx.Trace()
+		ctx.AddLogs(v1.Logs{Stdout: []string{"hello"}})
+		<-release
 		return nil, nil
 	}
 	faas.On("GetRunnable", mock.Anything).Return(runnable)
@@ -189,6 +377,10 @@ func TestRunEntityHandler_Add(t *testing.T) {
 	secretInjector.On("GetMiddleware", testOrgID, mock.Anything, "cookie").Return(simw)
 	serviceInjector := &fnmocks.ServiceInjector{}
 	serviceInjector.On("GetMiddleware", testOrgID, mock.Anything, "cookie").Return(simw)
+	configInjector := &fnmocks.ConfigInjector{}
+	configInjector.On("GetMiddleware", testOrgID, mock.Anything, mock.Anything).Return(simw)
+	middlewares := &fnmocks.MiddlewareRegistry{}
+	middlewares.On("GetMiddleware", mock.Anything).Return(simw)
 
 	h := &runEntityHandler{
 		Store: helpers.MakeEntityStore(t),
@@ -198,7 +390,10 @@ func TestRunEntityHandler_Add(t *testing.T) {
 			Validator:       validator.NoOp(),
 			SecretInjector:  secretInjector,
 			ServiceInjector: serviceInjector,
+			ConfigInjector:  configInjector,
+			Middlewares:     middlewares,
 		}),
+		Logs: NewLogBuffer(10, 10),
 	}
 
 	_, err := h.Store.Add(context.Background(), function)
@@ -206,9 +401,116 @@ func TestRunEntityHandler_Add(t *testing.T) {
 	_, err = h.Store.Add(context.Background(), fnRun)
 	require.NoError(t, err)
 
-	require.NoError(t, h.Add(context.Background(), fnRun))
+	following := h.Logs.SubscribeFunction(testOrgID, "testFunction")
+	defer following.Close()
+	done := make(chan error)
+	go func() {
+		done <- h.Add(context.Background(), fnRun)
+	}()
+	// the log lines added to the context of the run are written to the buffer as they are added
+	line := <-following.Lines
+	assert.Equal(t, "hello", line.Text)
+	select {
+	case <-done:
+		t.Fatal("the run finished before its function returned")
+	default:
+	}
+	close(release)
+	require.NoError(t, <-done)
 
 	faas.AssertExpectations(t)
 	secretInjector.AssertExpectations(t)
+	configInjector.AssertExpectations(t)
 	assert.True(t, functionCalled)
+	require.NotNil(t, runTrace)
+	assert.NotEmpty(t, fnRun.TraceID)
+	assert.Equal(t, fnRun.TraceID, runTrace.(jaeger.SpanContext).TraceID().String())
+
+	lines, _ := h.Logs.RunLines(fnRun.Name)
+	assert.Equal(t, []v1.LogLine{{FunctionName: "testFunction", RunName: "testRun", Stream: "stdout", Text: "hello"}}, lines)
+}
+
+func TestBatchItemSubmitter(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	watcher := make(chan controller.WatchEvent, 2)
+	s := &batchItemSubmitter{Store: store, Watcher: watcher}
+	_, err := store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{Name: "hello", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+	})
+	require.NoError(t, err)
+
+	b := &batches.Batch{
+		BaseEntity:   entitystore.BaseEntity{Name: "f98d0a7f-0c1d-4020-a488-cabc501b08e0", OrganizationID: testOrgID},
+		FunctionName: "hello",
+	}
+	item := &batches.Item{Batch: b.Name, Input: "Jon"}
+	runName := "a3b1e1c2-0000-4000-8000-000000000001"
+	run, err := s.SubmitItem(context.Background(), b, item, runName)
+	require.NoError(t, err)
+	assert.Equal(t, runName, run.Name)
+	assert.Equal(t, b.Name, run.Tags[batches.RunTag])
+	require.Len(t, watcher, 1)
+	assert.Equal(t, runName, (<-watcher).Entity.GetName())
+
+	// submitting the item again returns its run, which isn't queued twice
+	run, err = s.SubmitItem(context.Background(), b, item, runName)
+	require.NoError(t, err)
+	assert.Equal(t, "Jon", run.Input)
+	assert.Len(t, watcher, 0)
+
+	b.FunctionName = "missing"
+	_, err = s.SubmitItem(context.Background(), b, item, "a3b1e1c2-0000-4000-8000-000000000002")
+	assert.Error(t, err)
+}
+
+func TestWorkflowTaskRunner(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	_, err := store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{Name: "hello", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+		Schema:     &functions.Schema{},
+	})
+	require.NoError(t, err)
+	_, err = store.Add(context.Background(), &quotaentities.Quota{
+		BaseEntity:        entitystore.BaseEntity{Name: "concurrency", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+		MaxConcurrentRuns: 1,
+		MaxQueuedRuns:     1,
+	})
+	require.NoError(t, err)
+	limiter := quotas.NewLimiter(store)
+
+	var running int64
+	r := &workflowTaskRunner{
+		Store: store,
+		Runs: &runEntityHandler{
+			Store: store,
+			Runner: runnerFunc(func(fn *functions.FunctionExecution, in interface{}) (interface{}, error) {
+				running, _ = limiter.Usage(testOrgID, "concurrency")
+				return "hello " + in.(string), nil
+			}),
+			Logs:   NewLogBuffer(10, 10),
+			Quotas: limiter,
+		},
+	}
+	wfRun := &wfentities.WorkflowRun{BaseEntity: entitystore.BaseEntity{Name: "wf-run", OrganizationID: testOrgID}}
+
+	// the task run is executed right away, counted as running against the quotas until it is done
+	runName, output, err := r.RunTask(context.Background(), wfRun, "hello", "Jon")
+	require.NoError(t, err)
+	assert.NotEmpty(t, runName)
+	assert.Equal(t, "hello Jon", output)
+	assert.Equal(t, int64(1), running)
+	running, queued := limiter.Usage(testOrgID, "concurrency")
+	assert.Equal(t, int64(0), running)
+	assert.Equal(t, int64(0), queued)
+
+	// a task run which doesn't fit in the concurrent runs of the quota is refused rather than queued
+	other := &functions.FnRun{BaseEntity: entitystore.BaseEntity{Name: "other", OrganizationID: testOrgID}, FunctionName: "hello"}
+	require.NoError(t, limiter.Admit(context.Background(), other))
+	require.True(t, limiter.Start(other))
+	runName, _, err = r.RunTask(context.Background(), wfRun, "hello", "Jon")
+	assert.IsType(t, &quotas.ExceededError{}, err)
+	assert.Empty(t, runName)
+	running, queued = limiter.Usage(testOrgID, "concurrency")
+	assert.Equal(t, int64(1), running)
+	assert.Equal(t, int64(0), queued)
 }
@@ -11,8 +11,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/spec"
 	"github.com/go-openapi/strfmt"
@@ -26,30 +28,56 @@ import (
 	"github.com/vmware/dispatch/pkg/entity-store"
 	dispatcherrors "github.com/vmware/dispatch/pkg/errors"
 	"github.com/vmware/dispatch/pkg/event-manager/helpers"
+	"github.com/vmware/dispatch/pkg/function-manager/batches"
+	"github.com/vmware/dispatch/pkg/function-manager/configmaps"
 	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
 	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
 	fnstore "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows"
 	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/functions/injectors"
 	"github.com/vmware/dispatch/pkg/trace"
 	"github.com/vmware/dispatch/pkg/utils"
 )
 
 // FunctionManagerFlags are configuration flags for the function manager
 var FunctionManagerFlags = struct {
-	Config           string `long:"config" description:"Path to Config file" default:"./config.dev.json"`
-	DbFile           string `long:"db-file" description:"Backend DB URL/Path" default:"./db.bolt"`
-	DbBackend        string `long:"db-backend" description:"Backend DB Name" default:"boltdb"`
-	DbUser           string `long:"db-username" description:"Backend DB Username" default:"dispatch"`
-	DbPassword       string `long:"db-password" description:"Backend DB Password" default:"dispatch"`
-	DbDatabase       string `long:"db-database" description:"Backend DB Name" default:"dispatch"`
-	ImageManager     string `long:"image-manager" description:"Image manager endpoint" default:"localhost:8002"`
-	SecretStore      string `long:"secret-store" description:"Secret store endpoint" default:"localhost:8003"`
-	ServiceManager   string `long:"service-manager" description:"Service manager endpoint" default:"localhost:8004"`
-	K8sConfig        string `long:"kubeconfig" description:"Path to kubernetes config file" default:""`
-	FileImageManager string `long:"file-image-manager" description:"Path to file containing images (useful for testing)"`
-	Tracer           string `long:"tracer" description:"Open Tracing Tracer endpoint" default:""`
+	Config            string        `long:"config" description:"Path to Config file" default:"./config.dev.json"`
+	DbFile            string        `long:"db-file" description:"Backend DB URL/Path" default:"./db.bolt"`
+	DbBackend         string        `long:"db-backend" description:"Backend DB Name" default:"boltdb"`
+	DbUser            string        `long:"db-username" description:"Backend DB Username" default:"dispatch"`
+	DbPassword        string        `long:"db-password" description:"Backend DB Password" default:"dispatch"`
+	DbDatabase        string        `long:"db-database" description:"Backend DB Name" default:"dispatch"`
+	ImageManager      string        `long:"image-manager" description:"Image manager endpoint" default:"localhost:8002"`
+	SecretStore       string        `long:"secret-store" description:"Secret store endpoint" default:"localhost:8003"`
+	ServiceManager    string        `long:"service-manager" description:"Service manager endpoint" default:"localhost:8004"`
+	EventManager      string        `long:"event-manager" description:"Event manager endpoint, to publish the callback events of runs" default:"localhost:8005"`
+	K8sConfig         string        `long:"kubeconfig" description:"Path to kubernetes config file" default:""`
+	FileImageManager  string        `long:"file-image-manager" description:"Path to file containing images (useful for testing)"`
+	Tracer            string        `long:"tracer" description:"Open Tracing Tracer endpoint" default:""`
+	LogBufferRuns     int           `long:"log-buffer-runs" description:"Number of function runs to keep logs for in memory" default:"1000"`
+	LogBufferLines    int           `long:"log-buffer-lines" description:"Number of log lines to keep in memory per function run" default:"1000"`
+	Workers           int           `long:"workers" description:"Number of function runs processed at a time" default:"1000"`
+	ReservedWorkers   int           `long:"reserved-workers" description:"Number of workers reserved for high priority runs, e.g. blocking runs of the API gateway" default:"100"`
+	IdempotencyWindow time.Duration `long:"idempotency-window" description:"Time window in which runs of a function with the same idempotency key are executed only once, 0 disables it" default:"1h"`
+	BlobStore         string        `long:"blob-store" description:"Blob store for large run inputs, outputs and logs (none, file or s3)" default:"none"`
+	BlobStoreDir      string        `long:"blob-store-dir" description:"Directory of the file blob store" default:"./blobs"`
+	S3Endpoint        string        `long:"s3-endpoint" description:"Endpoint of the S3-compatible blob store" default:""`
+	S3Bucket          string        `long:"s3-bucket" description:"Bucket of the S3-compatible blob store" default:"dispatch"`
+	S3Region          string        `long:"s3-region" description:"Region of the S3-compatible blob store" default:"us-east-1"`
+	S3AccessKey       string        `long:"s3-access-key" description:"Access key of the S3-compatible blob store" default:""`
+	S3SecretKey       string        `long:"s3-secret-key" description:"Secret key of the S3-compatible blob store" default:""`
+	PayloadThreshold  int           `long:"payload-threshold" description:"Size in bytes above which run inputs, outputs and logs are kept in the blob store" default:"65536"`
+	InvocationKey     string        `long:"invocation-token-key" description:"Path to the PEM-encoded RSA private key signing the invocation tokens of function runs, empty disables them" default:""`
+	InvocationTTL     time.Duration `long:"invocation-token-ttl" description:"Validity of the invocation tokens of function runs" default:"15m"`
+	InvocationURL     string        `long:"invocation-endpoint" description:"Dispatch API endpoint given to function runs with their invocation token" default:""`
+	HookHosts         []string      `long:"hook-host" description:"Host the http-hook middleware of functions may call, can be repeated"`
 }{}
 
+// DefaultIdempotencyWindow is the default time window in which runs with the same idempotency key are deduplicated
+const DefaultIdempotencyWindow = time.Hour
+
 func functionEntityToModel(f *functions.Function) *v1.Function {
 	var tags []*v1.Tag
 	for k, v := range f.Tags {
@@ -68,13 +96,48 @@ func functionEntityToModel(f *functions.Function) *v1.Function {
 			In:  f.Schema.In,
 			Out: f.Schema.Out,
 		},
-		Reason:   f.Reason,
-		Secrets:  f.Secrets,
-		Services: f.Services,
-		Timeout:  f.Timeout,
-		Tags:     tags,
-		Status:   v1.Status(f.Status),
+		Reason:      f.Reason,
+		Secrets:     f.Secrets,
+		Services:    f.Services,
+		Config:      f.Config,
+		ConfigMaps:  f.ConfigMaps,
+		Middlewares: middlewareEntitiesToModel(f.Middlewares),
+		OnSuccess:   resultEventEntityToModel(f.OnSuccess),
+		OnFailure:   resultEventEntityToModel(f.OnFailure),
+		Timeout:     f.Timeout,
+		Tags:        tags,
+		Status:      v1.Status(f.Status),
+	}
+}
+
+func middlewareEntitiesToModel(middlewares []functions.MiddlewareConfig) []*v1.FunctionMiddleware {
+	var m []*v1.FunctionMiddleware
+	for _, c := range middlewares {
+		m = append(m, &v1.FunctionMiddleware{Name: swag.String(c.Name), Config: c.Config})
+	}
+	return m
+}
+
+func resultEventEntityToModel(e *functions.ResultEvent) *v1.FunctionEvent {
+	if e == nil {
+		return nil
+	}
+	return &v1.FunctionEvent{EventType: swag.String(e.EventType), Source: e.Source, Data: e.Data}
+}
+
+func resultEventModelToEntity(m *v1.FunctionEvent) (*functions.ResultEvent, error) {
+	if m == nil {
+		return nil, nil
+	}
+	if m.EventType == nil || *m.EventType == "" {
+		return nil, errors.New("result event type is required")
 	}
+	for field, path := range m.Data {
+		if !validResultPath(path) {
+			return nil, errors.Errorf("invalid path %s of result event data field %s", path, field)
+		}
+	}
+	return &functions.ResultEvent{EventType: *m.EventType, Source: m.Source, Data: m.Data}, nil
 }
 
 func functionListToModel(funcs []*functions.Function) []*v1.Function {
@@ -85,6 +148,23 @@ func functionListToModel(funcs []*functions.Function) []*v1.Function {
 	return body
 }
 
+func buildEntityToModel(b *functions.FunctionBuild) *v1.FunctionBuild {
+	return &v1.FunctionBuild{
+		ID:               strfmt.UUID(b.ID),
+		Name:             b.Name,
+		Kind:             utils.FunctionBuildKind,
+		FunctionName:     b.FunctionName,
+		ContentHash:      b.ContentHash,
+		FunctionImageURL: b.FunctionImageURL,
+		Cached:           b.Cached,
+		Logs:             b.Logs,
+		Status:           v1.Status(b.Status),
+		Reason:           b.Reason,
+		CreatedTime:      b.CreatedTime.Unix(),
+		ModifiedTime:     b.ModifiedTime.Unix(),
+	}
+}
+
 func schemaModelToEntity(mSchema *v1.Schema) (*functions.Schema, error) {
 	schema := new(functions.Schema)
 	if mSchema != nil && mSchema.In != nil {
@@ -123,6 +203,43 @@ func functionModelOntoEntity(m *v1.Function, e *functions.Function) error {
 	e.Schema = schema
 	e.Secrets = m.Secrets
 	e.Services = m.Services
+	if err := injectors.ValidateConfig(m.Config); err != nil {
+		return err
+	}
+	e.Config = m.Config
+	e.ConfigMaps = m.ConfigMaps
+	e.Middlewares = nil
+	for _, mw := range m.Middlewares {
+		if mw == nil || mw.Name == nil {
+			return errors.New("middleware name is required")
+		}
+		e.Middlewares = append(e.Middlewares, functions.MiddlewareConfig{Name: *mw.Name, Config: mw.Config})
+	}
+	if e.OnSuccess, err = resultEventModelToEntity(m.OnSuccess); err != nil {
+		return err
+	}
+	if e.OnFailure, err = resultEventModelToEntity(m.OnFailure); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkMiddlewares returns an error if one of the middlewares of a function is not registered or misconfigured
+func (h *Handlers) checkMiddlewares(middlewares []functions.MiddlewareConfig) error {
+	if h.Middlewares == nil || len(middlewares) == 0 {
+		return nil
+	}
+	return h.Middlewares.Validate(middlewares)
+}
+
+// checkConfigMaps returns an error if one of the config maps of a function does not exist
+func (h *Handlers) checkConfigMaps(ctx context.Context, organizationID string, configMaps []string) error {
+	for _, name := range configMaps {
+		if err := h.Store.Get(ctx, organizationID, name, entitystore.Options{}, new(functions.ConfigMap)); err != nil {
+			log.Debugf("store error when getting config map %s: %+v", name, err)
+			return errors.Errorf("config map %s not found", name)
+		}
+	}
 	return nil
 }
 
@@ -147,6 +264,10 @@ func runModelToEntity(m *v1.Run, f *functions.Function) *functions.FnRun {
 	if m.Blocking {
 		waitChan = make(chan struct{})
 	}
+	var callbackStatus string
+	if m.Callback != nil {
+		callbackStatus = functions.CallbackPending
+	}
 	return &functions.FnRun{
 		BaseEntity: entitystore.BaseEntity{
 			Name:   uuid.NewV4().String(),
@@ -154,16 +275,21 @@ func runModelToEntity(m *v1.Run, f *functions.Function) *functions.FnRun {
 			Reason: f.Reason,
 			Tags:   tags,
 		},
-		Blocking:     m.Blocking,
-		Input:        m.Input,
-		HTTPContext:  m.HTTPContext,
-		Secrets:      secrets,
-		Services:     services,
-		FunctionName: f.Name,
-		FunctionID:   f.ID,
-		FaasID:       f.FaasID,
-		Event:        helpers.CloudEventFromAPI(m.Event),
-		WaitChan:     waitChan,
+		Blocking:         m.Blocking,
+		Input:            m.Input,
+		InputContentType: m.InputContentType,
+		HTTPContext:      m.HTTPContext,
+		IdempotencyKey:   m.IdempotencyKey,
+		Priority:         runPriority(m),
+		Callback:         callbackModelToEntity(m.Callback),
+		CallbackStatus:   callbackStatus,
+		Secrets:          secrets,
+		Services:         services,
+		FunctionName:     f.Name,
+		FunctionID:       f.ID,
+		FaasID:           f.FaasID,
+		Event:            helpers.CloudEventFromAPI(m.Event),
+		WaitChan:         waitChan,
 	}
 }
 
@@ -173,23 +299,32 @@ func runEntityToModel(f *functions.FnRun) *v1.Run {
 		tags = append(tags, &v1.Tag{Key: k, Value: v})
 	}
 	return &v1.Run{
-		ExecutedTime: f.CreatedTime.Unix(),
-		FinishedTime: f.FinishedTime.Unix(),
-		Name:         strfmt.UUID(f.Name),
-		Blocking:     f.Blocking,
-		Input:        f.Input,
-		Output:       f.Output,
-		Logs:         f.Logs,
-		Error:        f.Error,
-		Secrets:      f.Secrets,
-		HTTPContext:  f.HTTPContext,
-		FunctionName: f.FunctionName,
-		FunctionID:   f.FunctionID,
-		FaasID:       strfmt.UUID(f.FaasID),
-		Status:       v1.Status(f.Status),
-		Event:        (*v1.CloudEvent)(helpers.CloudEventToAPI(f.Event)),
-		Reason:       f.Reason,
-		Tags:         tags,
+		ExecutedTime:      f.CreatedTime.Unix(),
+		FinishedTime:      f.FinishedTime.Unix(),
+		Name:              strfmt.UUID(f.Name),
+		Blocking:          f.Blocking,
+		Input:             f.Input,
+		InputContentType:  f.InputContentType,
+		Output:            f.Output,
+		OutputContentType: f.OutputContentType,
+		Logs:              f.Logs,
+		Error:             f.Error,
+		Secrets:           f.Secrets,
+		HTTPContext:       f.HTTPContext,
+		IdempotencyKey:    f.IdempotencyKey,
+		ReplayOf:          strfmt.UUID(f.ReplayOf),
+		ParentRun:         strfmt.UUID(f.ParentRun),
+		TraceID:           f.TraceID,
+		Priority:          f.Priority,
+		Cancelled:         f.Cancelled,
+		Callback:          callbackEntityToModel(f.Callback),
+		FunctionName:      f.FunctionName,
+		FunctionID:        f.FunctionID,
+		FaasID:            strfmt.UUID(f.FaasID),
+		Status:            v1.Status(f.Status),
+		Event:             (*v1.CloudEvent)(helpers.CloudEventToAPI(f.Event)),
+		Reason:            f.Reason,
+		Tags:              tags,
 	}
 }
 
@@ -205,14 +340,33 @@ func runListToModel(runs []*functions.FnRun) []*v1.Run {
 type Handlers struct {
 	Watcher controller.Watcher
 
-	Store entitystore.EntityStore
+	Store       entitystore.EntityStore
+	Logs        *LogBuffer
+	Quotas      *quotas.Limiter
+	Middlewares functions.MiddlewareRegistry
+
+	// IdempotencyWindow is the time during which a run with an idempotency key is returned instead of creating a
+	// new run with the same key, zero disables deduplication
+	IdempotencyWindow time.Duration
+	idempotencyLock   sync.Mutex
+
+	// Invocations verifies the invocation tokens of runs created by other runs, to record their parent run
+	Invocations *Invocations
+	// Callbacks keeps the secrets of run callbacks in the secret store
+	Callbacks *Callbacks
+	// Cancellations cancels the runs executed by this function manager, nil only cancels queued runs
+	Cancellations *Cancellations
 }
 
 // NewHandlers is the constructor for the function manager API handlers
-func NewHandlers(watcher controller.Watcher, store entitystore.EntityStore) *Handlers {
+func NewHandlers(watcher controller.Watcher, store entitystore.EntityStore, logs *LogBuffer, limiter *quotas.Limiter, middlewares functions.MiddlewareRegistry, idempotencyWindow time.Duration) *Handlers {
 	return &Handlers{
-		Watcher: watcher,
-		Store:   store,
+		Watcher:           watcher,
+		Store:             store,
+		Logs:              logs,
+		Quotas:            limiter,
+		Middlewares:       middlewares,
+		IdempotencyWindow: idempotencyWindow,
 	}
 }
 
@@ -268,12 +422,23 @@ func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
 	a.Logger = log.Printf
 	a.StoreAddFunctionHandler = fnstore.AddFunctionHandlerFunc(h.addFunction)
 	a.StoreGetFunctionHandler = fnstore.GetFunctionHandlerFunc(h.getFunction)
+	a.StoreGetFunctionBuildsHandler = fnstore.GetFunctionBuildsHandlerFunc(h.getFunctionBuilds)
 	a.StoreDeleteFunctionHandler = fnstore.DeleteFunctionHandlerFunc(h.deleteFunction)
 	a.StoreGetFunctionsHandler = fnstore.GetFunctionsHandlerFunc(h.getFunctions)
 	a.StoreUpdateFunctionHandler = fnstore.UpdateFunctionHandlerFunc(h.updateFunction)
 	a.RunnerRunFunctionHandler = fnrunner.RunFunctionHandlerFunc(h.runFunction)
+	a.RunnerReplayRunHandler = fnrunner.ReplayRunHandlerFunc(h.replayRun)
+	a.RunnerCancelRunHandler = fnrunner.CancelRunHandlerFunc(h.cancelRun)
 	a.RunnerGetRunHandler = fnrunner.GetRunHandlerFunc(h.getRun)
 	a.RunnerGetRunsHandler = fnrunner.GetRunsHandlerFunc(h.getRuns)
+	a.RunnerGetRunStatsHandler = fnrunner.GetRunStatsHandlerFunc(h.getRunStats)
+	a.RunnerGetRunLogsHandler = fnrunner.GetRunLogsHandlerFunc(h.getRunLogs)
+	a.RunnerGetFunctionLogsHandler = fnrunner.GetFunctionLogsHandlerFunc(h.getFunctionLogs)
+
+	workflows.NewHandlers(h.Store, h.Watcher).ConfigureHandlers(api)
+	batches.NewHandlers(h.Store, h.Watcher).ConfigureHandlers(api)
+	quotas.NewHandlers(h.Store, h.Quotas).ConfigureHandlers(api)
+	configmaps.NewHandlers(h.Store, h.Watcher).ConfigureHandlers(api)
 }
 
 func (h *Handlers) addFunction(params fnstore.AddFunctionParams, principal interface{}) middleware.Responder {
@@ -289,6 +454,18 @@ func (h *Handlers) addFunction(params fnstore.AddFunctionParams, principal inter
 	}
 
 	e.OrganizationID = params.XDispatchOrg
+	if err := h.checkConfigMaps(ctx, e.OrganizationID, e.ConfigMaps); err != nil {
+		return fnstore.NewAddFunctionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
+	if err := h.checkMiddlewares(e.Middlewares); err != nil {
+		return fnstore.NewAddFunctionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
 	e.Status = entitystore.StatusINITIALIZED
 	e.FaasID = uuid.NewV4().String()
 	log.Debugf("trying to add entity to store")
@@ -343,6 +520,35 @@ func (h *Handlers) getFunction(params fnstore.GetFunctionParams, principal inter
 	return fnstore.NewGetFunctionOK().WithPayload(functionEntityToModel(e))
 }
 
+func (h *Handlers) getFunctionBuilds(params fnstore.GetFunctionBuildsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
+	defer span.Finish()
+
+	e := new(functions.Function)
+	if err := h.Store.Get(ctx, params.XDispatchOrg, params.FunctionName, entitystore.Options{}, e); err != nil {
+		log.Debugf("Error returned by h.Store.Get: %+v", err)
+		log.Infof("Received GET builds for non-existent function %s", params.FunctionName)
+		return fnstore.NewGetFunctionBuildsNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("function", params.FunctionName),
+		})
+	}
+
+	builds, err := getBuilds(ctx, h.Store, e.OrganizationID, "FunctionName", e.Name)
+	if err != nil {
+		log.Errorf("%+v", err)
+		return fnstore.NewGetFunctionBuildsDefault(http.StatusInternalServerError).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: swag.String("internal server error when getting function builds"),
+		})
+	}
+	body := make([]*v1.FunctionBuild, 0, len(builds))
+	for _, b := range builds {
+		body = append(body, buildEntityToModel(b))
+	}
+	return fnstore.NewGetFunctionBuildsOK().WithPayload(body)
+}
+
 func (h *Handlers) deleteFunction(params fnstore.DeleteFunctionParams, principal interface{}) middleware.Responder {
 	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
 	defer span.Finish()
@@ -441,7 +647,18 @@ func (h *Handlers) updateFunction(params fnstore.UpdateFunctionParams, principal
 			Message: swag.String(err.Error()),
 		})
 	}
-
+	if err := h.checkConfigMaps(ctx, e.OrganizationID, e.ConfigMaps); err != nil {
+		return fnstore.NewUpdateFunctionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
+	if err := h.checkMiddlewares(e.Middlewares); err != nil {
+		return fnstore.NewUpdateFunctionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(err.Error()),
+		})
+	}
 	// generating a new UUID will force the creation of a new function in the underlying FaaS
 	e.FaasID = uuid.NewV4().String()
 	e.Status = entitystore.StatusUPDATING
@@ -481,6 +698,20 @@ func (h *Handlers) runFunction(params fnrunner.RunFunctionParams, principal inte
 	}
 	log.Debugf("Execute a function with payload: %#v", *params.Body)
 
+	if _, err := functions.DecodePayload(params.Body.Input, params.Body.InputContentType); err != nil {
+		return fnrunner.NewRunFunctionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("Bad Request: %s", err)),
+		})
+	}
+
+	if err := validateCallback(params.Body.Callback); err != nil {
+		return fnrunner.NewRunFunctionBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("Bad Request: %s", err)),
+		})
+	}
+
 	opts := entitystore.Options{
 		Filter: entitystore.FilterEverything(),
 	}
@@ -512,26 +743,65 @@ func (h *Handlers) runFunction(params fnrunner.RunFunctionParams, principal inte
 
 	run := runModelToEntity(params.Body, f)
 	run.OrganizationID = params.XDispatchOrg
+	run.ParentRun = h.Invocations.ParentRun(params.HTTPRequest, params.XDispatchOrg)
 	run.Status = entitystore.StatusINITIALIZED
 
-	if _, err := h.Store.Add(ctx, run); err != nil {
-		log.Errorf("Store error when adding new function run %s: %+v", run.Name, err)
+	if params.Body.Callback != nil {
+		if err := h.Callbacks.StoreSecret(ctx, run, params.Body.Callback.Secret); err != nil {
+			log.Errorf("Error when adding new function run %s: %+v", run.Name, err)
+			return fnrunner.NewRunFunctionDefault(500).WithPayload(&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: utils.ErrorMsgInternalError("function run", run.Name),
+			})
+		}
+	}
+
+	existing, err := h.addRun(ctx, run)
+	if err != nil || existing != nil {
+		// the run isn't created, its callback secret is left unused
+		h.Callbacks.DeleteSecret(ctx, run)
+	}
+	if exceeded, ok := err.(*quotas.ExceededError); ok {
+		log.Infof("Function run of %s rejected: %s", run.FunctionName, exceeded)
+		return fnrunner.NewRunFunctionTooManyRequests().WithRetryAfter(retryAfterSeconds(exceeded.RetryAfter)).WithPayload(&v1.Error{
+			Code:    http.StatusTooManyRequests,
+			Message: swag.String(exceeded.Error()),
+		})
+	}
+	if err != nil {
+		log.Errorf("Error when adding new function run %s: %+v", run.Name, err)
 		return fnrunner.NewRunFunctionDefault(500).WithPayload(&v1.Error{
 			Code:    http.StatusInternalServerError,
 			Message: utils.ErrorMsgInternalError("function run", run.Name),
 		})
 	}
+	if existing != nil {
+		log.Infof("Function run %s already exists with idempotency key %s", existing.Name, existing.IdempotencyKey)
+		if existing.Status == entitystore.StatusREADY || existing.Status == entitystore.StatusERROR {
+			return fnrunner.NewRunFunctionOK().WithXDispatchOutputContentType(existing.OutputContentType).WithPayload(runEntityToModel(existing))
+		}
+		return fnrunner.NewRunFunctionAccepted().WithPayload(runEntityToModel(existing))
+	}
 
 	h.Watcher.OnAction(ctx, run)
 
 	if run.Blocking {
 		run.Wait()
-		return fnrunner.NewRunFunctionOK().WithPayload(runEntityToModel(run))
+		return fnrunner.NewRunFunctionOK().WithXDispatchOutputContentType(run.OutputContentType).WithPayload(runEntityToModel(run))
 	}
 
 	return fnrunner.NewRunFunctionAccepted().WithPayload(runEntityToModel(run))
 }
 
+// retryAfterSeconds rounds a delay up to whole seconds, the unit of the Retry-After header
+func retryAfterSeconds(d time.Duration) int64 {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
 func (h *Handlers) getRun(params fnrunner.GetRunParams, principal interface{}) middleware.Responder {
 	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
 	defer span.Finish()
@@ -564,6 +834,9 @@ func (h *Handlers) getRun(params fnrunner.GetRunParams, principal interface{}) m
 	}
 
 	err = h.Store.Get(ctx, params.XDispatchOrg, params.RunName.String(), opts, &run)
+	if err == nil && !h.Invocations.Allows(params.HTTPRequest, params.XDispatchOrg, &run) {
+		err = errors.Errorf("function run %s was not created with the invocation token", run.Name)
+	}
 	if err != nil || (params.FunctionName != nil && run.FunctionName != *params.FunctionName) {
 		log.Debugf("Error returned by h.Store.Get: %+v", err)
 		if params.FunctionName != nil {
@@ -579,7 +852,7 @@ func (h *Handlers) getRun(params fnrunner.GetRunParams, principal interface{}) m
 	return fnrunner.NewGetRunOK().WithPayload(runEntityToModel(&run))
 }
 
-func getFilteredRuns(ctx context.Context, store entitystore.EntityStore, orgID string, functionName *string, since *int64, tags []string) ([]*functions.FnRun, error) {
+func getFilteredRuns(ctx context.Context, store entitystore.EntityStore, orgID string, functionName *string, since *int64, tags []string, query *runQuery) ([]*functions.FnRun, error) {
 	var runs []*functions.FnRun
 	var err error
 	opts := entitystore.Options{
@@ -606,6 +879,10 @@ func getFilteredRuns(ctx context.Context, store entitystore.EntityStore, orgID s
 			})
 	}
 
+	if query != nil {
+		query.storeFilter(opts.Filter)
+	}
+
 	opts.Filter, err = utils.ParseTags(opts.Filter, tags)
 	if err != nil {
 		return nil, dispatcherrors.NewRequestError(err)
@@ -619,14 +896,90 @@ func getFilteredRuns(ctx context.Context, store entitystore.EntityStore, orgID s
 		}
 		return nil, dispatcherrors.NewServerError(err)
 	}
+	if query != nil {
+		runs = query.filter(runs)
+	}
 	return runs, nil
 }
 
+// addRun adds a new function run to the store once admitted by the quotas, an *quotas.ExceededError is returned if it
+// isn't. If a run of the same function with the same idempotency key was created within the idempotency window, the
+// run is neither admitted nor added and the existing run is returned instead.
+func (h *Handlers) addRun(ctx context.Context, run *functions.FnRun) (*functions.FnRun, error) {
+	if run.IdempotencyKey != "" && h.IdempotencyWindow > 0 {
+		// lookup and add must be atomic, otherwise concurrent requests with the same key would all create a run
+		h.idempotencyLock.Lock()
+		defer h.idempotencyLock.Unlock()
+
+		existing, err := h.findIdempotentRun(ctx, run)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+	if err := h.Quotas.Admit(ctx, run); err != nil {
+		return nil, err
+	}
+	if _, err := h.Store.Add(ctx, run); err != nil {
+		h.Quotas.Release(run)
+		return nil, errors.Wrap(err, "store error when adding function run")
+	}
+	return nil, nil
+}
+
+// findIdempotentRun returns the most recent run of the function created with the same idempotency key within the
+// idempotency window, or nil if there is none
+func (h *Handlers) findIdempotentRun(ctx context.Context, run *functions.FnRun) (*functions.FnRun, error) {
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything().Add(
+			entitystore.FilterStat{
+				Scope:   entitystore.FilterScopeExtra,
+				Subject: "FunctionName",
+				Verb:    entitystore.FilterVerbEqual,
+				Object:  run.FunctionName,
+			},
+			entitystore.FilterStat{
+				Scope:   entitystore.FilterScopeExtra,
+				Subject: "IdempotencyKey",
+				Verb:    entitystore.FilterVerbEqual,
+				Object:  run.IdempotencyKey,
+			},
+			entitystore.FilterStat{
+				Scope:   entitystore.FilterScopeField,
+				Subject: "CreatedTime",
+				Verb:    entitystore.FilterVerbAfter,
+				Object:  time.Now().Add(-h.IdempotencyWindow),
+			}),
+	}
+	var runs []*functions.FnRun
+	if err := h.Store.List(ctx, run.OrganizationID, opts, &runs); err != nil {
+		return nil, errors.Wrapf(err, "error looking up runs with idempotency key %s", run.IdempotencyKey)
+	}
+	var existing *functions.FnRun
+	for _, r := range runs {
+		if existing == nil || r.CreatedTime.After(existing.CreatedTime) {
+			existing = r
+		}
+	}
+	return existing, nil
+}
+
 func (h *Handlers) getRuns(params fnrunner.GetRunsParams, principal interface{}) middleware.Responder {
 	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
 	defer span.Finish()
 
-	runs, err := getFilteredRuns(ctx, h.Store, params.XDispatchOrg, params.FunctionName, params.Since, params.Tags)
+	query := &runQuery{
+		Until:       params.Until,
+		Status:      params.Status,
+		ErrorType:   params.ErrorType,
+		MinDuration: params.MinDuration,
+		MaxDuration: params.MaxDuration,
+		ParentRun:   h.Invocations.ParentRun(params.HTTPRequest, params.XDispatchOrg),
+	}
+	// listed runs don't include the payloads kept in the blob store, which are returned when getting a single run
+	runs, err := getFilteredRuns(withoutPayloads(ctx), h.Store, params.XDispatchOrg, params.FunctionName, params.Since, params.Tags, query)
 
 	switch err.(type) {
 	case *dispatcherrors.RequestError:
@@ -643,3 +996,121 @@ func (h *Handlers) getRuns(params fnrunner.GetRunsParams, principal interface{})
 	}
 	return fnrunner.NewGetRunsOK().WithPayload(runListToModel(runs))
 }
+
+func (h *Handlers) getRunLogs(params fnrunner.GetRunLogsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
+	defer span.Finish()
+
+	run := new(functions.FnRun)
+	err := h.Store.Get(ctx, params.XDispatchOrg, params.RunName.String(), entitystore.Options{}, run)
+	if err == nil && !h.Invocations.Allows(params.HTTPRequest, params.XDispatchOrg, run) {
+		err = errors.Errorf("function run %s was not created with the invocation token", run.Name)
+	}
+	if err != nil || (params.FunctionName != nil && run.FunctionName != *params.FunctionName) {
+		log.Debugf("Error returned by h.Store.Get: %+v", err)
+		log.Infof("Get logs failed for function run %s", params.RunName.String())
+		return fnrunner.NewGetRunLogsNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("function run", params.RunName.String()),
+		})
+	}
+
+	lines, ok := h.Logs.RunLines(run.Name)
+	if !ok {
+		// the run is no longer buffered, send the logs stored with the run
+		lines = storedLogLines(run)
+	}
+	return logStreamResponder(params.HTTPRequest.Context(), lines, nil)
+}
+
+func (h *Handlers) getFunctionLogs(params fnrunner.GetFunctionLogsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
+	defer span.Finish()
+
+	f := new(functions.Function)
+	if err := h.Store.Get(ctx, params.XDispatchOrg, params.FunctionName, entitystore.Options{}, f); err != nil {
+		log.Debugf("Error returned by h.Store.Get: %+v", err)
+		log.Infof("Get logs failed for non-existent function %s", params.FunctionName)
+		return fnrunner.NewGetFunctionLogsNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("function", params.FunctionName),
+		})
+	}
+
+	subscription := h.Logs.SubscribeFunction(params.XDispatchOrg, params.FunctionName)
+	if params.Follow == nil || !*params.Follow {
+		subscription.Close()
+	}
+	return logStreamResponder(params.HTTPRequest.Context(), subscription.Backlog, subscription)
+}
+
+func storedLogLines(run *functions.FnRun) []v1.LogLine {
+	var lines []v1.LogLine
+	if run.Logs == nil {
+		return lines
+	}
+	for _, text := range run.Logs.Stdout {
+		lines = append(lines, v1.LogLine{FunctionName: run.FunctionName, RunName: run.Name, Stream: stdoutStream, Text: text})
+	}
+	for _, text := range run.Logs.Stderr {
+		lines = append(lines, v1.LogLine{FunctionName: run.FunctionName, RunName: run.Name, Stream: stderrStream, Text: text})
+	}
+	return lines
+}
+
+// logStreamResponder writes log lines as server-sent events, one event per line named after the stream
+// the line was written to. Lines received from the subscription are sent until it is closed or the client
+// goes away.
+func logStreamResponder(ctx context.Context, backlog []v1.LogLine, subscription *LogSubscription) middleware.Responder {
+	return middleware.ResponderFunc(func(rw http.ResponseWriter, _ runtime.Producer) {
+		if subscription != nil {
+			defer subscription.Close()
+		}
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.WriteHeader(http.StatusOK)
+		flusher, _ := rw.(http.Flusher)
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		write := func(line v1.LogLine) error {
+			data, err := json.Marshal(line)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", line.Stream, data); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		}
+
+		for _, line := range backlog {
+			if err := write(line); err != nil {
+				log.Debugf("Error streaming function logs: %+v", err)
+				return
+			}
+		}
+		if subscription == nil {
+			return
+		}
+		for {
+			select {
+			case line, ok := <-subscription.Lines:
+				if !ok {
+					return
+				}
+				if err := write(line); err != nil {
+					log.Debugf("Error streaming function logs: %+v", err)
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
@@ -14,8 +14,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vmware/dispatch/pkg/controller"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
@@ -23,7 +27,10 @@ import (
 	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
 	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
 	fnstore "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	quotaentities "github.com/vmware/dispatch/pkg/function-manager/quotas/entities"
 	"github.com/vmware/dispatch/pkg/functions"
+	fnmiddleware "github.com/vmware/dispatch/pkg/functions/middleware"
 	helpers "github.com/vmware/dispatch/pkg/testing/api"
 )
 
@@ -81,6 +88,43 @@ func TestStoreAddFunctionHandler(t *testing.T) {
 	assert.Equal(t, "test", respBody.Tags[0].Value)
 }
 
+func TestStoreAddFunctionHandlerMiddlewares(t *testing.T) {
+	handlers := &Handlers{
+		Store:       helpers.MakeEntityStore(t),
+		Middlewares: fnmiddleware.New(nil),
+	}
+
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	reqBody := &v1.Function{
+		Name:   swag.String("testEntity"),
+		Source: []byte("some source"),
+		Image:  swag.String("imageID"),
+		Middlewares: []*v1.FunctionMiddleware{
+			{Name: swag.String(fnmiddleware.SizeLimitMiddleware), Config: map[string]string{"maxInput": "1024"}},
+			{Name: swag.String(fnmiddleware.LoggingMiddleware)},
+		},
+	}
+	r := httptest.NewRequest("POST", "/v1/function", nil)
+	params := fnstore.AddFunctionParams{
+		HTTPRequest:  r,
+		Body:         reqBody,
+		XDispatchOrg: testOrgID,
+	}
+	responder := api.StoreAddFunctionHandler.Handle(params, "testCookie")
+	var respBody v1.Function
+	helpers.HandlerRequest(t, responder, &respBody, 201)
+	assert.Equal(t, reqBody.Middlewares, respBody.Middlewares)
+
+	reqBody.Name = swag.String("unknownMiddleware")
+	reqBody.Middlewares = []*v1.FunctionMiddleware{{Name: swag.String("unknown")}}
+	responder = api.StoreAddFunctionHandler.Handle(params, "testCookie")
+	var errBody v1.Error
+	helpers.HandlerRequest(t, responder, &errBody, 400)
+	assert.Contains(t, *errBody.Message, "unknown middleware")
+}
+
 func TestHandlers_runFunction_notREADY(t *testing.T) {
 	store := helpers.MakeEntityStore(t)
 	watcher := make(chan controller.WatchEvent, 1)
@@ -160,6 +204,144 @@ func TestHandlers_runFunction_READY(t *testing.T) {
 	assert.Equal(t, runEntityToModel((<-watcher).Entity.(*functions.FnRun)), &respBody)
 }
 
+func TestHandlers_runFunction_idempotencyKey(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	watcher := make(chan controller.WatchEvent, 2)
+	handlers := &Handlers{
+		Watcher:           watcher,
+		Store:             store,
+		IdempotencyWindow: time.Hour,
+	}
+
+	testFuncName := "testFunction"
+
+	function := &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           testFuncName,
+			Status:         entitystore.StatusREADY,
+			OrganizationID: testOrgID,
+		},
+		// other fields are unimportant for this test
+	}
+	store.Add(context.Background(), function)
+
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	runFunction := func(key string, status int) *v1.Run {
+		r := httptest.NewRequest("POST", fmt.Sprintf("/v1/runs?functionName=%s", testFuncName), nil)
+		params := fnrunner.RunFunctionParams{
+			HTTPRequest:  r,
+			Body:         &v1.Run{IdempotencyKey: key},
+			FunctionName: &testFuncName,
+			XDispatchOrg: testOrgID,
+		}
+		responder := api.RunnerRunFunctionHandler.Handle(params, "testCookie")
+		var respBody v1.Run
+		helpers.HandlerRequest(t, responder, &respBody, status)
+		return &respBody
+	}
+
+	first := runFunction("key1", 202)
+	assert.Equal(t, "key1", first.IdempotencyKey)
+	assert.Len(t, watcher, 1)
+
+	// in progress run is returned instead of creating a new one
+	second := runFunction("key1", 202)
+	assert.Equal(t, first.Name, second.Name)
+	assert.Len(t, watcher, 1)
+
+	// finished run is returned with its result
+	run := (<-watcher).Entity.(*functions.FnRun)
+	run.Status = entitystore.StatusREADY
+	run.Output = map[string]interface{}{"hello": "world"}
+	_, err := store.Update(context.Background(), run.Revision, run)
+	assert.NoError(t, err)
+	third := runFunction("key1", 200)
+	assert.Equal(t, first.Name, third.Name)
+	assert.Equal(t, run.Output, third.Output)
+	assert.Len(t, watcher, 0)
+
+	// a different key creates a new run
+	fourth := runFunction("key2", 202)
+	assert.NotEqual(t, first.Name, fourth.Name)
+	assert.Len(t, watcher, 1)
+
+	// runs outside of the window are not deduplicated
+	handlers.IdempotencyWindow = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	fifth := runFunction("key1", 202)
+	assert.NotEqual(t, first.Name, fifth.Name)
+	assert.Len(t, watcher, 2)
+}
+
+func TestHandlers_runFunction_quotaExceeded(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	watcher := make(chan controller.WatchEvent, 2)
+	handlers := &Handlers{
+		Watcher:           watcher,
+		Store:             store,
+		Quotas:            quotas.NewLimiter(store),
+		IdempotencyWindow: time.Hour,
+	}
+
+	testFuncName := "testFunction"
+
+	function := &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           testFuncName,
+			Status:         entitystore.StatusREADY,
+			OrganizationID: testOrgID,
+		},
+		// other fields are unimportant for this test
+	}
+	store.Add(context.Background(), function)
+	quota := &quotaentities.Quota{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "one-at-a-time",
+			Status:         entitystore.StatusREADY,
+			OrganizationID: testOrgID,
+		},
+		Function:          testFuncName,
+		MaxConcurrentRuns: 1,
+	}
+	store.Add(context.Background(), quota)
+
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	runFunction := func(key string) middleware.Responder {
+		r := httptest.NewRequest("POST", fmt.Sprintf("/v1/runs?functionName=%s", testFuncName), nil)
+		params := fnrunner.RunFunctionParams{
+			HTTPRequest:  r,
+			Body:         &v1.Run{IdempotencyKey: key},
+			FunctionName: &testFuncName,
+			XDispatchOrg: testOrgID,
+		}
+		return api.RunnerRunFunctionHandler.Handle(params, "testCookie")
+	}
+
+	var run v1.Run
+	helpers.HandlerRequest(t, runFunction("key"), &run, 202)
+	assert.Len(t, watcher, 1)
+
+	var respBody v1.Error
+	resp := helpers.HandlerRequestWithResponse(t, runFunction(""), &respBody, http.StatusTooManyRequests)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.Contains(t, *respBody.Message, "one-at-a-time")
+	assert.Len(t, watcher, 1)
+
+	// a duplicate of the run is not counted against the quota
+	var duplicate v1.Run
+	helpers.HandlerRequest(t, runFunction("key"), &duplicate, 202)
+	assert.Equal(t, run.Name, duplicate.Name)
+	assert.Len(t, watcher, 1)
+
+	// the run is admitted again once the previous one is done
+	handlers.Quotas.Release((<-watcher).Entity.(*functions.FnRun))
+	helpers.HandlerRequest(t, runFunction(""), &run, 202)
+}
+
 func TestHandlers_getRuns(t *testing.T) {
 	store := helpers.MakeEntityStore(t)
 	handlers := &Handlers{
@@ -300,6 +482,55 @@ func TestStoreGetFunctionHandler(t *testing.T) {
 	assert.Equal(t, "test", getBody.Tags[0].Value)
 }
 
+func TestStoreGetFunctionBuildsHandler(t *testing.T) {
+	handlers := &Handlers{
+		Store: helpers.MakeEntityStore(t),
+	}
+
+	api := operations.NewFunctionManagerAPI(nil)
+	helpers.MakeAPI(t, handlers.ConfigureHandlers, api)
+
+	_, err := handlers.Store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "testFunction",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+		},
+	})
+	require.NoError(t, err)
+	_, err = handlers.Store.Add(context.Background(), &functions.FunctionBuild{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "build1",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusERROR,
+			Reason:         []string{"failed to build image"},
+		},
+		FunctionName: "testFunction",
+		ContentHash:  "cafe",
+		Logs:         "Step 1/2 : FROM test/image:latest\n",
+	})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/v1/function/testFunction/builds", nil)
+	get := fnstore.GetFunctionBuildsParams{
+		HTTPRequest:  r,
+		FunctionName: "testFunction",
+		XDispatchOrg: testOrgID,
+	}
+	getResponder := api.StoreGetFunctionBuildsHandler.Handle(get, "testCookie")
+	var getBody []v1.FunctionBuild
+	helpers.HandlerRequest(t, getResponder, &getBody, 200)
+	require.Len(t, getBody, 1)
+	assert.Equal(t, "build1", getBody[0].Name)
+	assert.Equal(t, v1.StatusERROR, getBody[0].Status)
+	assert.Equal(t, "Step 1/2 : FROM test/image:latest\n", getBody[0].Logs)
+
+	get.FunctionName = "missing"
+	getResponder = api.StoreGetFunctionBuildsHandler.Handle(get, "testCookie")
+	var errBody v1.Error
+	helpers.HandlerRequest(t, getResponder, &errBody, 404)
+}
+
 func Test_runModelToEntitySecret(t *testing.T) {
 	runModel0 := v1.Run{Secrets: []string{}}
 	bs, _ := json.Marshal(runModel0)
@@ -311,3 +542,93 @@ func Test_runModelToEntitySecret(t *testing.T) {
 	fnRun := runModelToEntity(&runModel, &f)
 	assert.Equal(t, secrets, fnRun.Secrets)
 }
+
+func TestHandlers_getRunLogs(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	handlers := &Handlers{
+		Store: store,
+		Logs:  NewLogBuffer(10, 10),
+	}
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	runName := "fb6d1462-9ea9-4b4a-97b1-5f0d5e3b3b5a"
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           runName,
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+		},
+		FunctionName: "testFunction",
+		Logs:         &v1.Logs{Stdout: []string{"stored"}},
+	}
+	_, err := store.Add(context.Background(), run)
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/v1/runs/"+runName+"/logs", nil)
+	params := fnrunner.GetRunLogsParams{
+		HTTPRequest:  r,
+		RunName:      strfmt.UUID(runName),
+		XDispatchOrg: testOrgID,
+	}
+
+	// the run is not buffered, logs stored with the run are sent
+	w := httptest.NewRecorder()
+	api.RunnerGetRunLogsHandler.Handle(params, "testCookie").WriteResponse(w, runtime.JSONProducer())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "event: stdout\ndata: {\"functionName\":\"testFunction\",\"runName\":\""+runName+"\",\"stream\":\"stdout\",\"text\":\"stored\"}\n\n", w.Body.String())
+
+	// the buffered lines of the run are sent
+	handlers.Logs.Write(run, v1.Logs{Stdout: []string{"buffered"}, Stderr: []string{"oops"}})
+	w = httptest.NewRecorder()
+	api.RunnerGetRunLogsHandler.Handle(params, "testCookie").WriteResponse(w, runtime.JSONProducer())
+	assert.Equal(t, "event: stdout\ndata: {\"functionName\":\"testFunction\",\"runName\":\""+runName+"\",\"stream\":\"stdout\",\"text\":\"buffered\"}\n\n"+
+		"event: stderr\ndata: {\"functionName\":\"testFunction\",\"runName\":\""+runName+"\",\"stream\":\"stderr\",\"text\":\"oops\"}\n\n", w.Body.String())
+
+	// unknown run
+	params.RunName = strfmt.UUID("2a0c9a35-3b3f-4a43-8e5b-14c9b4e1b7a2")
+	var respBody v1.Error
+	helpers.HandlerRequest(t, api.RunnerGetRunLogsHandler.Handle(params, "testCookie"), &respBody, http.StatusNotFound)
+}
+
+func TestHandlers_getFunctionLogs(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	handlers := &Handlers{
+		Store: store,
+		Logs:  NewLogBuffer(10, 10),
+	}
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	_, err := store.Add(context.Background(), &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "testFunction",
+			OrganizationID: testOrgID,
+		},
+	})
+	assert.NoError(t, err)
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "testRun",
+			OrganizationID: testOrgID,
+		},
+		FunctionName: "testFunction",
+	}
+	handlers.Logs.Write(run, v1.Logs{Stdout: []string{"hello"}})
+
+	r := httptest.NewRequest("GET", "/v1/function/testFunction/logs", nil)
+	params := fnrunner.GetFunctionLogsParams{
+		HTTPRequest:  r,
+		FunctionName: "testFunction",
+		XDispatchOrg: testOrgID,
+	}
+	w := httptest.NewRecorder()
+	api.RunnerGetFunctionLogsHandler.Handle(params, "testCookie").WriteResponse(w, runtime.JSONProducer())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "event: stdout\ndata: {\"functionName\":\"testFunction\",\"runName\":\"testRun\",\"stream\":\"stdout\",\"text\":\"hello\"}\n\n", w.Body.String())
+
+	params.FunctionName = "missing"
+	var respBody v1.Error
+	helpers.HandlerRequest(t, api.RunnerGetFunctionLogsHandler.Handle(params, "testCookie"), &respBody, http.StatusNotFound)
+}
@@ -0,0 +1,82 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/invocation"
+)
+
+// Invocations signs the invocation tokens letting function runs emit events and run other functions through the API
+// endpoint. The identity manager verifies the tokens with the public key of Key.
+type Invocations struct {
+	Key      *rsa.PrivateKey
+	Endpoint string
+	TTL      time.Duration
+}
+
+// NewInvocations is the constructor for Invocations
+func NewInvocations(key *rsa.PrivateKey, endpoint string) *Invocations {
+	return &Invocations{
+		Key:      key,
+		Endpoint: endpoint,
+		TTL:      invocation.DefaultTTL,
+	}
+}
+
+// Inject adds the endpoint and a new invocation token to the context of a run, a nil Invocations injects nothing
+func (i *Invocations) Inject(fctx functions.Context, run *functions.FnRun) error {
+	if i == nil {
+		return nil
+	}
+	token, err := invocation.Sign(invocation.NewClaims(run.OrganizationID, run.FunctionName, run.Name, i.TTL), i.Key)
+	if err != nil {
+		return err
+	}
+	fctx[functions.InvocationKey] = &functions.Invocation{
+		Endpoint:       i.Endpoint,
+		Token:          token,
+		OrganizationID: run.OrganizationID,
+		RunName:        run.Name,
+	}
+	return nil
+}
+
+// ParentRun returns the run whose invocation token authorized the request, or an empty string if the request has no
+// valid invocation token of the organization
+func (i *Invocations) ParentRun(r *http.Request, organizationID string) string {
+	if i == nil {
+		return ""
+	}
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return ""
+	}
+	claims, err := invocation.Parse(parts[1], &i.Key.PublicKey)
+	if err != nil {
+		// other bearer tokens, of service accounts, are not invocation tokens
+		log.Debugf("no invocation token in request: %s", err)
+		return ""
+	}
+	if claims.OrganizationID != organizationID {
+		return ""
+	}
+	return claims.ParentRun
+}
+
+// Allows returns false if the request has an invocation token and the run was not created with it: the runs a function
+// gets or replays are restricted to those it created, not every run of the organization
+func (i *Invocations) Allows(r *http.Request, organizationID string, run *functions.FnRun) bool {
+	parent := i.ParentRun(r, organizationID)
+	return parent == "" || run.ParentRun == parent
+}
@@ -0,0 +1,59 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/invocation"
+)
+
+func TestInvocations(t *testing.T) {
+	var disabled *Invocations
+	fctx := functions.Context{}
+	run := &functions.FnRun{
+		BaseEntity:   entitystore.BaseEntity{Name: "f98d0a7f-0c1d-4020-a488-cabc501b08e0", OrganizationID: "testOrg"},
+		FunctionName: "hello",
+	}
+	assert.NoError(t, disabled.Inject(fctx, run))
+	assert.NotContains(t, fctx, functions.InvocationKey)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	invocations := NewInvocations(key, "https://dispatch.example.com")
+	require.NoError(t, invocations.Inject(fctx, run))
+	inv := fctx[functions.InvocationKey].(*functions.Invocation)
+	assert.Equal(t, "https://dispatch.example.com", inv.Endpoint)
+	assert.Equal(t, "testOrg", inv.OrganizationID)
+	assert.Equal(t, run.Name, inv.RunName)
+
+	claims, err := invocation.Parse(inv.Token, &key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", claims.Function)
+	assert.Equal(t, run.Name, claims.ParentRun)
+
+	r := httptest.NewRequest("POST", "/v1/runs", nil)
+	assert.Empty(t, invocations.ParentRun(r, "testOrg"))
+	r.Header.Set("Authorization", "Bearer "+inv.Token)
+	assert.Equal(t, run.Name, invocations.ParentRun(r, "testOrg"))
+	assert.Empty(t, invocations.ParentRun(r, "otherOrg"))
+	assert.Empty(t, disabled.ParentRun(r, "testOrg"))
+
+	// the token only gets the runs created with it
+	child := &functions.FnRun{ParentRun: run.Name}
+	assert.True(t, invocations.Allows(r, "testOrg", child))
+	assert.False(t, invocations.Allows(r, "testOrg", run))
+	assert.True(t, disabled.Allows(r, "testOrg", run))
+	assert.True(t, invocations.Allows(httptest.NewRequest("GET", "/v1/runs", nil), "testOrg", run))
+}
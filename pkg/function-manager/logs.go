@@ -0,0 +1,238 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"container/list"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+const (
+	// DefaultLogBufferRuns is the default number of runs kept in the log buffer
+	DefaultLogBufferRuns = 1000
+	// DefaultLogBufferLines is the default number of lines kept per run in the log buffer
+	DefaultLogBufferLines = 1000
+
+	stdoutStream = "stdout"
+	stderrStream = "stderr"
+)
+
+// LogBuffer keeps the most recent log lines of function runs in memory and streams them to subscribers following
+// all runs of a function. The FaaS drivers return the logs of a function with its output, so they are written, and
+// streamed, once the function returns: a running function can't be followed. It is bounded both in the number of runs
+// (least recently written runs are evicted first) and in the number of lines kept per run.
+type LogBuffer struct {
+	sync.Mutex
+
+	maxRuns  int
+	maxLines int
+
+	runs  map[string]*runLogs
+	order *list.List
+
+	functionSubscribers map[string]map[*LogSubscription]struct{}
+}
+
+type runLogs struct {
+	organizationID string
+	functionName   string
+	lines          []v1.LogLine
+	element        *list.Element
+}
+
+// LogSubscription receives the log lines of a function written to the buffer after it was created
+type LogSubscription struct {
+	// Backlog contains the lines buffered before the subscription was created
+	Backlog []v1.LogLine
+	// Lines receives new lines, it is closed once the subscription is closed
+	Lines <-chan v1.LogLine
+
+	lines  chan v1.LogLine
+	closed bool
+	cancel func()
+}
+
+// Close stops the subscription
+func (s *LogSubscription) Close() {
+	s.cancel()
+}
+
+// NewLogBuffer creates a new log buffer keeping at most maxLines lines for each of the maxRuns most recent runs
+func NewLogBuffer(maxRuns, maxLines int) *LogBuffer {
+	if maxRuns <= 0 {
+		maxRuns = DefaultLogBufferRuns
+	}
+	if maxLines <= 0 {
+		maxLines = DefaultLogBufferLines
+	}
+	return &LogBuffer{
+		maxRuns:             maxRuns,
+		maxLines:            maxLines,
+		runs:                make(map[string]*runLogs),
+		order:               list.New(),
+		functionSubscribers: make(map[string]map[*LogSubscription]struct{}),
+	}
+}
+
+func functionKey(organizationID, functionName string) string {
+	return organizationID + "/" + functionName
+}
+
+// getRun returns the buffered logs of a run, creating them if needed. Must be called with the lock held.
+func (b *LogBuffer) getRun(organizationID, functionName, runName string) *runLogs {
+	r, ok := b.runs[runName]
+	if ok {
+		b.order.MoveToFront(r.element)
+		return r
+	}
+	r = &runLogs{
+		organizationID: organizationID,
+		functionName:   functionName,
+	}
+	r.element = b.order.PushFront(runName)
+	b.runs[runName] = r
+	for b.order.Len() > b.maxRuns {
+		b.evict(b.order.Back())
+	}
+	return r
+}
+
+func (b *LogBuffer) evict(e *list.Element) {
+	runName := e.Value.(string)
+	b.order.Remove(e)
+	delete(b.runs, runName)
+}
+
+func (b *LogBuffer) newSubscription(backlog []v1.LogLine, unsubscribe func()) *LogSubscription {
+	lines := make(chan v1.LogLine, b.maxLines)
+	s := &LogSubscription{
+		Backlog: backlog,
+		Lines:   lines,
+		lines:   lines,
+	}
+	s.cancel = func() {
+		b.Lock()
+		defer b.Unlock()
+		unsubscribe()
+		b.closeSubscription(s)
+	}
+	return s
+}
+
+func (b *LogBuffer) closeSubscription(s *LogSubscription) {
+	if !s.closed {
+		s.closed = true
+		close(s.lines)
+	}
+}
+
+func (b *LogBuffer) send(s *LogSubscription, line v1.LogLine) {
+	if s.closed {
+		return
+	}
+	select {
+	case s.lines <- line:
+	default:
+		log.Debugf("log subscriber is too slow, dropping log line of run %s", line.RunName)
+	}
+}
+
+// Write appends the logs of a run to the buffer and sends them to the subscribers
+func (b *LogBuffer) Write(run *functions.FnRun, logs v1.Logs) {
+	if b == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+
+	r := b.getRun(run.OrganizationID, run.FunctionName, run.Name)
+	fnSubscribers := b.functionSubscribers[functionKey(run.OrganizationID, run.FunctionName)]
+	write := func(stream string, lines []string) {
+		for _, text := range lines {
+			line := v1.LogLine{
+				FunctionName: run.FunctionName,
+				RunName:      run.Name,
+				Stream:       stream,
+				Text:         text,
+			}
+			r.lines = append(r.lines, line)
+			for s := range fnSubscribers {
+				b.send(s, line)
+			}
+		}
+	}
+	write(stdoutStream, logs.Stdout)
+	write(stderrStream, logs.Stderr)
+	if len(r.lines) > b.maxLines {
+		r.lines = append([]v1.LogLine(nil), r.lines[len(r.lines)-b.maxLines:]...)
+	}
+}
+
+// runLogWriter writes the logs added to the context of a run to the buffer
+type runLogWriter struct {
+	buffer *LogBuffer
+	run    *functions.FnRun
+}
+
+func (w *runLogWriter) WriteLogs(logs v1.Logs) {
+	w.buffer.Write(w.run, logs)
+}
+
+// Writer returns the writer of the logs of a run, nil if there is no buffer
+func (b *LogBuffer) Writer(run *functions.FnRun) functions.LogWriter {
+	if b == nil {
+		return nil
+	}
+	return &runLogWriter{buffer: b, run: run}
+}
+
+// RunLines returns the buffered log lines of a run, false if the run isn't buffered
+func (b *LogBuffer) RunLines(runName string) ([]v1.LogLine, bool) {
+	if b == nil {
+		return nil, false
+	}
+	b.Lock()
+	defer b.Unlock()
+
+	r, ok := b.runs[runName]
+	if !ok {
+		return nil, false
+	}
+	return append([]v1.LogLine(nil), r.lines...), true
+}
+
+// SubscribeFunction follows the logs of all runs of a function
+func (b *LogBuffer) SubscribeFunction(organizationID, functionName string) *LogSubscription {
+	b.Lock()
+	defer b.Unlock()
+
+	var backlog []v1.LogLine
+	// runs are ordered from the most recent, the backlog is sent oldest first
+	for e := b.order.Back(); e != nil; e = e.Prev() {
+		r := b.runs[e.Value.(string)]
+		if r.organizationID == organizationID && r.functionName == functionName {
+			backlog = append(backlog, r.lines...)
+		}
+	}
+	key := functionKey(organizationID, functionName)
+	if _, ok := b.functionSubscribers[key]; !ok {
+		b.functionSubscribers[key] = make(map[*LogSubscription]struct{})
+	}
+	var s *LogSubscription
+	s = b.newSubscription(backlog, func() {
+		delete(b.functionSubscribers[key], s)
+		if len(b.functionSubscribers[key]) == 0 {
+			delete(b.functionSubscribers, key)
+		}
+	})
+	b.functionSubscribers[key][s] = struct{}{}
+	return s
+}
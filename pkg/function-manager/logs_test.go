@@ -0,0 +1,90 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package functionmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+func testRun(functionName, runName string) *functions.FnRun {
+	return &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           runName,
+			OrganizationID: testOrgID,
+		},
+		FunctionName: functionName,
+	}
+}
+
+func texts(lines []v1.LogLine) []string {
+	var t []string
+	for _, l := range lines {
+		t = append(t, l.Text)
+	}
+	return t
+}
+
+func TestLogBufferRunLines(t *testing.T) {
+	b := NewLogBuffer(10, 10)
+	run := testRun("fn", "run1")
+
+	_, ok := b.RunLines("run1")
+	assert.False(t, ok)
+
+	b.Write(run, v1.Logs{Stdout: []string{"first"}})
+	b.Write(run, v1.Logs{Stdout: []string{"second"}, Stderr: []string{"oops"}})
+	lines, ok := b.RunLines("run1")
+	assert.True(t, ok)
+	assert.Equal(t, []v1.LogLine{
+		{FunctionName: "fn", RunName: "run1", Stream: "stdout", Text: "first"},
+		{FunctionName: "fn", RunName: "run1", Stream: "stdout", Text: "second"},
+		{FunctionName: "fn", RunName: "run1", Stream: "stderr", Text: "oops"},
+	}, lines)
+}
+
+func TestLogBufferFollowFunction(t *testing.T) {
+	b := NewLogBuffer(10, 10)
+	b.Write(testRun("fn", "run1"), v1.Logs{Stdout: []string{"one"}})
+	b.Write(testRun("other", "run2"), v1.Logs{Stdout: []string{"two"}})
+
+	s := b.SubscribeFunction(testOrgID, "fn")
+	assert.Equal(t, []string{"one"}, texts(s.Backlog))
+
+	b.Write(testRun("other", "run2"), v1.Logs{Stdout: []string{"ignored"}})
+	b.Write(testRun("fn", "run3"), v1.Logs{Stdout: []string{"three"}})
+	line := <-s.Lines
+	assert.Equal(t, "three", line.Text)
+	assert.Equal(t, "run3", line.RunName)
+
+	s.Close()
+	_, open := <-s.Lines
+	assert.False(t, open)
+	assert.Empty(t, b.functionSubscribers)
+}
+
+func TestLogBufferBounds(t *testing.T) {
+	b := NewLogBuffer(2, 3)
+	run1 := testRun("fn", "run1")
+	b.Write(run1, v1.Logs{Stdout: []string{"1", "2", "3", "4"}})
+	lines, _ := b.RunLines("run1")
+	assert.Equal(t, []string{"2", "3", "4"}, texts(lines))
+
+	b.Write(testRun("fn", "run2"), v1.Logs{Stdout: []string{"a"}})
+	b.Write(testRun("fn", "run3"), v1.Logs{Stdout: []string{"b"}})
+
+	// run1 is the least recently written run and got evicted
+	_, ok := b.RunLines("run1")
+	assert.False(t, ok)
+	_, ok = b.RunLines("run2")
+	assert.True(t, ok)
+	_, ok = b.RunLines("run3")
+	assert.True(t, ok)
+}
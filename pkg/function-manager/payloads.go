@@ -0,0 +1,246 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/blobstore"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+// DefaultPayloadThreshold is the default size in bytes above which run inputs, outputs and logs are kept in the blob store
+const DefaultPayloadThreshold = 64 * 1024
+
+// payloadStore is an entity store keeping large run inputs, outputs and logs in a blob store, the runs stored in the
+// entity store only keep references to them, which are resolved when runs are read
+type payloadStore struct {
+	entitystore.EntityStore
+	blobs     blobstore.Store
+	threshold int
+}
+
+// NewPayloadStore wraps an entity store to keep run inputs, outputs and logs larger than threshold bytes in a blob store
+func NewPayloadStore(store entitystore.EntityStore, blobs blobstore.Store, threshold int) entitystore.EntityStore {
+	return &payloadStore{EntityStore: store, blobs: blobs, threshold: threshold}
+}
+
+func payloadKey(organizationID, runName, payload string) string {
+	return path.Join(organizationID, "runs", runName, payload)
+}
+
+// offload returns a copy of the run with the large payloads replaced by references to the blob store. The references
+// are recorded in run too, with the digests of the payloads, so a payload which didn't change since it was stored isn't
+// stored again when the run is updated.
+func (s *payloadStore) offload(ctx context.Context, run *functions.FnRun) (*functions.FnRun, error) {
+	stored := *run
+	for _, p := range []struct {
+		name  string
+		value interface{}
+		clear func()
+		ref   *string
+	}{
+		{"input", run.Input, func() { stored.Input = nil }, &run.InputRef},
+		{"output", run.Output, func() { stored.Output = nil }, &run.OutputRef},
+		{"logs", run.Logs, func() { stored.Logs = nil }, &run.LogsRef},
+	} {
+		data, err := json.Marshal(p.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error encoding %s of run %s", p.name, run.Name)
+		}
+		if len(data) <= s.threshold {
+			// a null payload with a reference wasn't resolved, e.g. a run listed without its payloads
+			if string(data) != "null" && *p.ref != "" {
+				*p.ref = ""
+				delete(run.PayloadDigests, p.name)
+			}
+			continue
+		}
+		key := payloadKey(run.OrganizationID, run.Name, p.name)
+		digest := fmt.Sprintf("%x", sha256.Sum256(data))
+		if *p.ref != key || run.PayloadDigests[p.name] != digest {
+			if err := s.blobs.Put(ctx, key, data); err != nil {
+				return nil, errors.Wrapf(err, "blob store error when storing %s of run %s", p.name, run.Name)
+			}
+			if run.PayloadDigests == nil {
+				run.PayloadDigests = make(map[string]string)
+			}
+			run.PayloadDigests[p.name] = digest
+			*p.ref = key
+		}
+		p.clear()
+	}
+	stored.InputRef, stored.OutputRef, stored.LogsRef = run.InputRef, run.OutputRef, run.LogsRef
+	stored.PayloadDigests = run.PayloadDigests
+	return &stored, nil
+}
+
+// resolve loads the payloads the references of the run to the blob store refer to, the references are kept so the
+// payloads aren't stored again unless they change
+func (s *payloadStore) resolve(ctx context.Context, run *functions.FnRun) error {
+	for _, p := range []struct {
+		name  string
+		ref   *string
+		value interface{}
+	}{
+		{"input", &run.InputRef, &run.Input},
+		{"output", &run.OutputRef, &run.Output},
+		{"logs", &run.LogsRef, &run.Logs},
+	} {
+		if *p.ref == "" {
+			continue
+		}
+		data, err := s.blobs.Get(ctx, *p.ref)
+		if err != nil {
+			return errors.Wrapf(err, "blob store error when reading %s of run %s", p.name, run.Name)
+		}
+		if err := json.Unmarshal(data, p.value); err != nil {
+			return errors.Wrapf(err, "error decoding %s of run %s", p.name, run.Name)
+		}
+	}
+	return nil
+}
+
+func (s *payloadStore) Add(ctx context.Context, entity entitystore.Entity) (string, error) {
+	run, ok := entity.(*functions.FnRun)
+	if !ok {
+		return s.EntityStore.Add(ctx, entity)
+	}
+	stored, err := s.offload(ctx, run)
+	if err != nil {
+		return "", err
+	}
+	id, err := s.EntityStore.Add(ctx, stored)
+	run.BaseEntity = stored.BaseEntity
+	return id, err
+}
+
+func (s *payloadStore) Update(ctx context.Context, lastRevision uint64, entity entitystore.Entity) (int64, error) {
+	run, ok := entity.(*functions.FnRun)
+	if !ok {
+		return s.EntityStore.Update(ctx, lastRevision, entity)
+	}
+	stored, err := s.offload(ctx, run)
+	if err != nil {
+		return 0, err
+	}
+	revision, err := s.EntityStore.Update(ctx, lastRevision, stored)
+	run.BaseEntity = stored.BaseEntity
+	return revision, err
+}
+
+func (s *payloadStore) UpdateWithError(ctx context.Context, entity entitystore.Entity, err error) {
+	run, ok := entity.(*functions.FnRun)
+	if !ok {
+		s.EntityStore.UpdateWithError(ctx, entity, err)
+		return
+	}
+	stored, offloadErr := s.offload(ctx, run)
+	if offloadErr != nil {
+		// keep the run without its payloads rather than losing its status
+		copied := *run
+		copied.Input, copied.Output, copied.Logs = nil, nil, nil
+		stored = &copied
+		if err == nil {
+			err = offloadErr
+		}
+	}
+	s.EntityStore.UpdateWithError(ctx, stored, err)
+	run.BaseEntity = stored.BaseEntity
+}
+
+func (s *payloadStore) SoftDelete(ctx context.Context, entity entitystore.Entity) error {
+	run, ok := entity.(*functions.FnRun)
+	if !ok {
+		return s.EntityStore.SoftDelete(ctx, entity)
+	}
+	stored, err := s.offload(ctx, run)
+	if err != nil {
+		return err
+	}
+	err = s.EntityStore.SoftDelete(ctx, stored)
+	run.BaseEntity = stored.BaseEntity
+	return err
+}
+
+func (s *payloadStore) Get(ctx context.Context, organizationID string, key string, opts entitystore.Options, entity entitystore.Entity) error {
+	if err := s.EntityStore.Get(ctx, organizationID, key, opts, entity); err != nil {
+		return err
+	}
+	if run, ok := entity.(*functions.FnRun); ok {
+		return s.resolve(ctx, run)
+	}
+	return nil
+}
+
+func (s *payloadStore) Find(ctx context.Context, organizationID string, key string, opts entitystore.Options, entity entitystore.Entity) (bool, error) {
+	found, err := s.EntityStore.Find(ctx, organizationID, key, opts, entity)
+	if !found || err != nil {
+		return found, err
+	}
+	if run, ok := entity.(*functions.FnRun); ok {
+		return true, s.resolve(ctx, run)
+	}
+	return true, nil
+}
+
+// skipPayloadsKey is the context key of withoutPayloads
+type skipPayloadsKey struct{}
+
+// withoutPayloads returns a context in which the runs listed from a payload store are not resolved: their payloads kept
+// in the blob store are left out, for listings which don't need them
+func withoutPayloads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipPayloadsKey{}, true)
+}
+
+func (s *payloadStore) resolveList(ctx context.Context, entities interface{}) error {
+	runs, ok := entities.(*[]*functions.FnRun)
+	if !ok || ctx.Value(skipPayloadsKey{}) != nil {
+		return nil
+	}
+	for _, run := range *runs {
+		if err := s.resolve(ctx, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *payloadStore) List(ctx context.Context, organizationID string, opts entitystore.Options, entities interface{}) error {
+	if err := s.EntityStore.List(ctx, organizationID, opts, entities); err != nil {
+		return err
+	}
+	return s.resolveList(ctx, entities)
+}
+
+func (s *payloadStore) ListGlobal(ctx context.Context, opts entitystore.Options, entities interface{}) error {
+	if err := s.EntityStore.ListGlobal(ctx, opts, entities); err != nil {
+		return err
+	}
+	return s.resolveList(ctx, entities)
+}
+
+func (s *payloadStore) Delete(ctx context.Context, organizationID string, id string, entity entitystore.Entity) error {
+	if err := s.EntityStore.Delete(ctx, organizationID, id, entity); err != nil {
+		return err
+	}
+	if _, ok := entity.(*functions.FnRun); !ok {
+		return nil
+	}
+	for _, name := range []string{"input", "output", "logs"} {
+		if err := s.blobs.Delete(ctx, payloadKey(organizationID, id, name)); err != nil {
+			return errors.Wrapf(err, "blob store error when deleting %s of run %s", name, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/blobstore"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+// countingBlobs counts the blobs stored
+type countingBlobs struct {
+	blobstore.Store
+	puts int
+}
+
+func (b *countingBlobs) Put(ctx context.Context, key string, data []byte) error {
+	b.puts++
+	return b.Store.Put(ctx, key, data)
+}
+
+func TestPayloadStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	blobs, err := blobstore.NewFileStore(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	inner := helpers.MakeEntityStore(t)
+	es := NewPayloadStore(inner, blobs, 100)
+
+	large := strings.Repeat("x", 200)
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run1",
+			OrganizationID: testOrgID,
+		},
+		FunctionName: "hello",
+		Input:        map[string]interface{}{"data": large},
+	}
+	_, err = es.Add(ctx, run)
+	require.NoError(t, err)
+	assert.NotEmpty(t, run.ID)
+	assert.Equal(t, large, run.Input.(map[string]interface{})["data"])
+
+	run.Output = "small"
+	run.Logs = &v1.Logs{Stdout: []string{large}}
+	_, err = es.Update(ctx, run.Revision, run)
+	require.NoError(t, err)
+
+	// the entity store only keeps references to the large payloads
+	stored := &functions.FnRun{}
+	require.NoError(t, inner.Get(ctx, testOrgID, "run1", entitystore.Options{}, stored))
+	assert.Nil(t, stored.Input)
+	assert.Equal(t, "small", stored.Output)
+	assert.Nil(t, stored.Logs)
+	assert.Equal(t, "testOrg/runs/run1/input", stored.InputRef)
+	assert.Empty(t, stored.OutputRef)
+	assert.Equal(t, "testOrg/runs/run1/logs", stored.LogsRef)
+
+	resolved := &functions.FnRun{}
+	require.NoError(t, es.Get(ctx, testOrgID, "run1", entitystore.Options{}, resolved))
+	assert.Equal(t, large, resolved.Input.(map[string]interface{})["data"])
+	assert.Equal(t, "small", resolved.Output)
+	assert.Equal(t, []string{large}, resolved.Logs.Stdout)
+	assert.Equal(t, "testOrg/runs/run1/input", resolved.InputRef)
+	assert.Equal(t, "testOrg/runs/run1/logs", resolved.LogsRef)
+
+	// unchanged payloads aren't stored again
+	puts := &countingBlobs{Store: blobs}
+	es.(*payloadStore).blobs = puts
+	resolved.Status = entitystore.StatusREADY
+	_, err = es.Update(ctx, resolved.Revision, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, 0, puts.puts)
+	resolved.Logs = &v1.Logs{Stdout: []string{large, large}}
+	_, err = es.Update(ctx, resolved.Revision, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts.puts)
+
+	// a payload which became small is kept with the run again
+	resolved.Logs = &v1.Logs{Stdout: []string{"small"}}
+	_, err = es.Update(ctx, resolved.Revision, resolved)
+	require.NoError(t, err)
+	stored = &functions.FnRun{}
+	require.NoError(t, inner.Get(ctx, testOrgID, "run1", entitystore.Options{}, stored))
+	assert.Empty(t, stored.LogsRef)
+	assert.Equal(t, []string{"small"}, stored.Logs.Stdout)
+	es.(*payloadStore).blobs = blobs
+	resolved.Logs = &v1.Logs{Stdout: []string{large}}
+	_, err = es.Update(ctx, resolved.Revision, resolved)
+	require.NoError(t, err)
+
+	var runs []*functions.FnRun
+	require.NoError(t, es.List(ctx, testOrgID, entitystore.Options{}, &runs))
+	require.Len(t, runs, 1)
+	assert.Equal(t, []string{large}, runs[0].Logs.Stdout)
+
+	// listings which don't need the payloads don't read the blob store
+	runs = nil
+	require.NoError(t, es.List(withoutPayloads(ctx), testOrgID, entitystore.Options{}, &runs))
+	require.Len(t, runs, 1)
+	assert.Nil(t, runs[0].Logs)
+	assert.Equal(t, "small", runs[0].Output)
+
+	require.NoError(t, es.Delete(ctx, testOrgID, "run1", &functions.FnRun{}))
+	_, err = blobs.Get(ctx, "testOrg/runs/run1/input")
+	assert.Equal(t, blobstore.ErrNotFound, err)
+}
@@ -0,0 +1,62 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package entities
+
+import (
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// NO TESTS
+
+// Quota struct represents the limits on the function runs of an organization, or of one of its functions
+type Quota struct {
+	entitystore.BaseEntity
+	Function          string  `json:"function,omitempty"`
+	RunsPerSecond     float64 `json:"runsPerSecond,omitempty"`
+	MaxConcurrentRuns int64   `json:"maxConcurrentRuns,omitempty"`
+	MaxQueuedRuns     int64   `json:"maxQueuedRuns,omitempty"`
+}
+
+// ToModel converts quota to swagger model
+func (q *Quota) ToModel() *v1.Quota {
+	var tags []*v1.Tag
+	for k, v := range q.Tags {
+		tags = append(tags, &v1.Tag{Key: k, Value: v})
+	}
+	m := v1.Quota{
+		Name:              swag.String(q.Name),
+		Kind:              utils.QuotaKind,
+		ID:                strfmt.UUID(q.ID),
+		Function:          q.Function,
+		RunsPerSecond:     q.RunsPerSecond,
+		MaxConcurrentRuns: q.MaxConcurrentRuns,
+		MaxQueuedRuns:     q.MaxQueuedRuns,
+		CreatedTime:       q.CreatedTime.Unix(),
+		ModifiedTime:      q.ModifiedTime.Unix(),
+		Tags:              tags,
+	}
+	return &m
+}
+
+// FromModel builds quota based on swagger model
+func (q *Quota) FromModel(m *v1.Quota, orgID string) {
+	tags := make(map[string]string)
+	for _, t := range m.Tags {
+		tags[t.Key] = t.Value
+	}
+	q.BaseEntity.OrganizationID = orgID
+	q.BaseEntity.Name = *m.Name
+	q.BaseEntity.Tags = tags
+	q.Function = m.Function
+	q.RunsPerSecond = m.RunsPerSecond
+	q.MaxConcurrentRuns = m.MaxConcurrentRuns
+	q.MaxQueuedRuns = m.MaxQueuedRuns
+}
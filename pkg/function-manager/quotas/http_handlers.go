@@ -0,0 +1,233 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package quotas
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	quotaapi "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/quota"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas/entities"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// Handlers is a base struct for quota API handlers.
+type Handlers struct {
+	store   entitystore.EntityStore
+	limiter *Limiter
+}
+
+// NewHandlers Creates new instance of quota handlers
+func NewHandlers(store entitystore.EntityStore, limiter *Limiter) *Handlers {
+	return &Handlers{
+		store:   store,
+		limiter: limiter,
+	}
+}
+
+// ConfigureHandlers configures API handlers for Quota endpoints
+func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
+	a, ok := api.(*operations.FunctionManagerAPI)
+	if !ok {
+		panic("Cannot configure api")
+	}
+
+	a.QuotaAddQuotaHandler = quotaapi.AddQuotaHandlerFunc(h.addQuota)
+	a.QuotaGetQuotaHandler = quotaapi.GetQuotaHandlerFunc(h.getQuota)
+	a.QuotaGetQuotasHandler = quotaapi.GetQuotasHandlerFunc(h.getQuotas)
+	a.QuotaUpdateQuotaHandler = quotaapi.UpdateQuotaHandlerFunc(h.updateQuota)
+	a.QuotaDeleteQuotaHandler = quotaapi.DeleteQuotaHandlerFunc(h.deleteQuota)
+}
+
+// Validate validates the limits of a quota
+func Validate(q *entities.Quota) error {
+	if q.RunsPerSecond < 0 || q.MaxConcurrentRuns < 0 || q.MaxQueuedRuns < 0 {
+		return errors.New("limits must not be negative")
+	}
+	if q.RunsPerSecond == 0 && q.MaxConcurrentRuns == 0 {
+		return errors.New("at least one of runs per second and max concurrent runs is required")
+	}
+	if q.MaxQueuedRuns > 0 && q.MaxConcurrentRuns == 0 {
+		// runs only queue for the concurrent runs of a quota
+		return errors.New("max queued runs requires max concurrent runs")
+	}
+	return nil
+}
+
+// toModel converts a quota to swagger model, with its current usage
+func (h *Handlers) toModel(q *entities.Quota) *v1.Quota {
+	m := q.ToModel()
+	m.ConcurrentRuns, m.QueuedRuns = h.limiter.Usage(q.OrganizationID, q.Name)
+	return m
+}
+
+// addQuota handles creation of new quotas
+func (h *Handlers) addQuota(params quotaapi.AddQuotaParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "addQuota")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return quotaapi.NewAddQuotaBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+
+	q := &entities.Quota{}
+	q.FromModel(params.Body, params.XDispatchOrg)
+	if err := Validate(q); err != nil {
+		return quotaapi.NewAddQuotaBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("invalid quota: %s", err)),
+		})
+	}
+	q.Status = entitystore.StatusREADY
+	if _, err := h.store.Add(ctx, q); err != nil {
+		if entitystore.IsUniqueViolation(err) {
+			return quotaapi.NewAddQuotaConflict().WithPayload(&v1.Error{
+				Code:    http.StatusConflict,
+				Message: utils.ErrorMsgAlreadyExists("quota", q.Name),
+			})
+		}
+		log.Errorf("error when storing the quota: %+v", err)
+		return quotaapi.NewAddQuotaDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("quota", q.Name),
+		})
+	}
+	h.limiter.Invalidate(q.OrganizationID)
+	return quotaapi.NewAddQuotaCreated().WithPayload(h.toModel(q))
+}
+
+// getQuota handles retrieval of single quota
+func (h *Handlers) getQuota(params quotaapi.GetQuotaParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getQuota")
+	defer span.Finish()
+
+	q := entities.Quota{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.QuotaName, entitystore.Options{}, &q); err != nil {
+		log.Warnf("Received GET for non-existent quota %s", params.QuotaName)
+		log.Debugf("store error when getting quota: %+v", err)
+		return quotaapi.NewGetQuotaNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("quota", params.QuotaName),
+			})
+	}
+	return quotaapi.NewGetQuotaOK().WithPayload(h.toModel(&q))
+}
+
+// getQuotas handles retrieval of quota list
+func (h *Handlers) getQuotas(params quotaapi.GetQuotasParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getQuotas")
+	defer span.Finish()
+
+	var quotas []*entities.Quota
+	var err error
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %s", err)
+		return quotaapi.NewGetQuotasBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+
+	if err = h.store.List(ctx, params.XDispatchOrg, opts, &quotas); err != nil {
+		log.Errorf("store error when listing quotas: %+v", err)
+		return quotaapi.NewGetQuotasDefault(http.StatusInternalServerError).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when getting quotas"),
+			})
+	}
+	quotaModels := []*v1.Quota{}
+	for _, q := range quotas {
+		quotaModels = append(quotaModels, h.toModel(q))
+	}
+	return quotaapi.NewGetQuotasOK().WithPayload(quotaModels)
+}
+
+// updateQuota handles changes of the limits of a quota
+func (h *Handlers) updateQuota(params quotaapi.UpdateQuotaParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "updateQuota")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return quotaapi.NewUpdateQuotaBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+
+	q := &entities.Quota{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.QuotaName, entitystore.Options{}, q); err != nil {
+		log.Warnf("Received UPDATE for non-existent quota %s", params.QuotaName)
+		log.Debugf("store error when getting quota: %+v", err)
+		return quotaapi.NewUpdateQuotaNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("quota", params.QuotaName),
+			})
+	}
+	params.Body.Name = swag.String(q.Name)
+	q.FromModel(params.Body, q.OrganizationID)
+	if err := Validate(q); err != nil {
+		return quotaapi.NewUpdateQuotaBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("invalid quota: %s", err)),
+		})
+	}
+	if _, err := h.store.Update(ctx, q.Revision, q); err != nil {
+		log.Errorf("store error when updating quota %s: %+v", q.Name, err)
+		return quotaapi.NewUpdateQuotaDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("quota", q.Name),
+		})
+	}
+	h.limiter.Invalidate(q.OrganizationID)
+	return quotaapi.NewUpdateQuotaOK().WithPayload(h.toModel(q))
+}
+
+// deleteQuota handles deletion of a quota
+func (h *Handlers) deleteQuota(params quotaapi.DeleteQuotaParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "deleteQuota")
+	defer span.Finish()
+
+	q := &entities.Quota{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.QuotaName, entitystore.Options{}, q); err != nil {
+		log.Warnf("Received DELETE for non-existent quota %s", params.QuotaName)
+		log.Debugf("store error when getting quota: %+v", err)
+		return quotaapi.NewDeleteQuotaNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("quota", params.QuotaName),
+			})
+	}
+	if err := h.store.Delete(ctx, q.OrganizationID, q.Name, q); err != nil {
+		log.Errorf("store error when deleting quota %s: %+v", q.Name, err)
+		return quotaapi.NewDeleteQuotaDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("quota", q.Name),
+		})
+	}
+	h.limiter.Invalidate(q.OrganizationID)
+	return quotaapi.NewDeleteQuotaOK().WithPayload(h.toModel(q))
+}
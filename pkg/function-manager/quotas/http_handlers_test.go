@@ -0,0 +1,111 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package quotas
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/quota"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func addQuotaEntity(t *testing.T, api *operations.FunctionManagerAPI, body *v1.Quota, status int) {
+	r := httptest.NewRequest("POST", "/v1/quota", nil)
+	params := quota.AddQuotaParams{
+		HTTPRequest:  r,
+		Body:         body,
+		XDispatchOrg: testOrgID,
+	}
+	responder := api.QuotaAddQuotaHandler.Handle(params, "testCookie")
+	if status == http.StatusCreated {
+		var respBody v1.Quota
+		helpers.HandlerRequest(t, responder, &respBody, status)
+		assert.Equal(t, *body.Name, *respBody.Name)
+		return
+	}
+	var respBody v1.Error
+	helpers.HandlerRequest(t, responder, &respBody, status)
+	assert.NotEmpty(t, respBody.Message)
+}
+
+func TestQuotasAddQuotaHandler(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := NewHandlers(es, NewLimiter(es))
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	addQuotaEntity(t, api, &v1.Quota{Name: swag.String("myquota"), RunsPerSecond: 10}, http.StatusCreated)
+	addQuotaEntity(t, api, &v1.Quota{Name: swag.String("myquota"), RunsPerSecond: 10}, http.StatusConflict)
+	addQuotaEntity(t, api, &v1.Quota{Name: swag.String("invalid"), MaxQueuedRuns: 10}, http.StatusBadRequest)
+
+	r := httptest.NewRequest("GET", "/v1/quota", nil)
+	get := quota.GetQuotasParams{
+		HTTPRequest:  r,
+		XDispatchOrg: testOrgID,
+	}
+	getResponder := api.QuotaGetQuotasHandler.Handle(get, "testCookie")
+	var getBody []v1.Quota
+	helpers.HandlerRequest(t, getResponder, &getBody, 200)
+	assert.Len(t, getBody, 1)
+	assert.Equal(t, float64(10), getBody[0].RunsPerSecond)
+}
+
+func TestQuotasUpdateQuotaHandler(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	limiter := NewLimiter(es)
+	h := NewHandlers(es, limiter)
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	addQuotaEntity(t, api, &v1.Quota{Name: swag.String("myquota"), MaxConcurrentRuns: 1}, http.StatusCreated)
+	require.NoError(t, limiter.Admit(context.Background(), newRun("run1", "hello")))
+	assert.Error(t, limiter.Admit(context.Background(), newRun("run2", "hello")))
+
+	r := httptest.NewRequest("PUT", "/v1/quota/myquota", nil)
+	update := quota.UpdateQuotaParams{
+		HTTPRequest:  r,
+		QuotaName:    "myquota",
+		Body:         &v1.Quota{Name: swag.String("myquota"), MaxConcurrentRuns: 2},
+		XDispatchOrg: testOrgID,
+	}
+	updateResponder := api.QuotaUpdateQuotaHandler.Handle(update, "testCookie")
+	var updateBody v1.Quota
+	helpers.HandlerRequest(t, updateResponder, &updateBody, 200)
+	assert.Equal(t, int64(2), updateBody.MaxConcurrentRuns)
+	assert.Equal(t, int64(1), updateBody.QueuedRuns)
+
+	// the new limits are enforced right away
+	assert.NoError(t, limiter.Admit(context.Background(), newRun("run2", "hello")))
+
+	r = httptest.NewRequest("DELETE", "/v1/quota/myquota", nil)
+	del := quota.DeleteQuotaParams{
+		HTTPRequest:  r,
+		QuotaName:    "myquota",
+		XDispatchOrg: testOrgID,
+	}
+	delResponder := api.QuotaDeleteQuotaHandler.Handle(del, "testCookie")
+	var delBody v1.Quota
+	helpers.HandlerRequest(t, delResponder, &delBody, 200)
+
+	r = httptest.NewRequest("GET", "/v1/quota/myquota", nil)
+	get := quota.GetQuotaParams{
+		HTTPRequest:  r,
+		QuotaName:    "myquota",
+		XDispatchOrg: testOrgID,
+	}
+	getResponder := api.QuotaGetQuotaHandler.Handle(get, "testCookie")
+	var getBody v1.Error
+	helpers.HandlerRequest(t, getResponder, &getBody, 404)
+}
@@ -0,0 +1,295 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas/entities"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+)
+
+// cacheTTL is how long the quotas of an organization are cached, quotas changed through another replica are picked
+// up after at most this period
+const cacheTTL = 10 * time.Second
+
+// ExceededError is returned when a run is rejected by a quota
+type ExceededError struct {
+	Quota  string
+	Reason string
+	// RetryAfter is how long to wait before the run may be admitted
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota %s exceeded: %s", e.Quota, e.Reason)
+}
+
+type cachedQuotas struct {
+	quotas  []*entities.Quota
+	expires time.Time
+}
+
+// usage tracks the runs counted against a quota
+type usage struct {
+	tokens  float64
+	last    time.Time
+	queued  int64
+	running int64
+}
+
+// refill adds the tokens earned since the last refill to the bucket, which holds at most a second worth of runs
+func (u *usage) refill(rate float64, now time.Time) {
+	burst := math.Max(1, rate)
+	if u.last.IsZero() {
+		u.tokens = burst
+	} else {
+		u.tokens = math.Min(burst, u.tokens+now.Sub(u.last).Seconds()*rate)
+	}
+	u.last = now
+}
+
+// admission is an admitted run, with the quotas and usages it is counted against
+type admission struct {
+	quotas  []string
+	usages  []*usage
+	limits  []int64
+	started bool
+}
+
+// full returns the index of a quota without room for the run to start, or -1 if the run can start
+func (a *admission) full() int {
+	for i, u := range a.usages {
+		if a.limits[i] > 0 && u.running >= a.limits[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// Limiter enforces the quotas of function runs. Usage is tracked in memory, each function manager replica
+// enforcing the quotas for the runs it receives. A nil Limiter enforces no quota.
+type Limiter struct {
+	store entitystore.EntityStore
+	now   func() time.Time
+
+	sync.Mutex
+	quotas map[string]*cachedQuotas
+	usages map[string]*usage
+	runs   map[string]*admission
+}
+
+// NewLimiter creates a new limiter of the runs, enforcing the quotas of store
+func NewLimiter(store entitystore.EntityStore) *Limiter {
+	return &Limiter{
+		store:  store,
+		now:    time.Now,
+		quotas: make(map[string]*cachedQuotas),
+		usages: make(map[string]*usage),
+		runs:   make(map[string]*admission),
+	}
+}
+
+func runKey(run *functions.FnRun) string {
+	return run.OrganizationID + "/" + run.Name
+}
+
+func quotaKey(organizationID, name string) string {
+	return organizationID + "/" + name
+}
+
+// Admit counts a new run against the quotas of its organization and function, it returns an *ExceededError if
+// one of them is exceeded. An admitted run must be released once finished (or if it is not executed after all).
+func (l *Limiter) Admit(ctx context.Context, run *functions.FnRun) error {
+	if l == nil {
+		return nil
+	}
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	quotas, err := l.quotasFor(ctx, run.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	a := &admission{}
+	var rates []float64
+	for _, q := range quotas {
+		if q.Function != "" && q.Function != run.FunctionName {
+			continue
+		}
+		u := l.usageOf(run.OrganizationID, q.Name)
+		if q.MaxConcurrentRuns > 0 && u.queued+u.running >= q.MaxConcurrentRuns+q.MaxQueuedRuns {
+			return &ExceededError{
+				Quota:      q.Name,
+				Reason:     fmt.Sprintf("%d runs running and %d queued", u.running, u.queued),
+				RetryAfter: time.Second,
+			}
+		}
+		if q.RunsPerSecond > 0 {
+			u.refill(q.RunsPerSecond, now)
+			if u.tokens < 1 {
+				return &ExceededError{
+					Quota:      q.Name,
+					Reason:     fmt.Sprintf("more than %g runs per second", q.RunsPerSecond),
+					RetryAfter: time.Duration((1 - u.tokens) / q.RunsPerSecond * float64(time.Second)),
+				}
+			}
+		}
+		a.quotas = append(a.quotas, q.Name)
+		a.usages = append(a.usages, u)
+		a.limits = append(a.limits, q.MaxConcurrentRuns)
+		rates = append(rates, q.RunsPerSecond)
+	}
+
+	// every quota has room for the run, count it
+	for i, u := range a.usages {
+		if rates[i] > 0 {
+			u.tokens--
+		}
+		u.queued++
+	}
+	l.runs[runKey(run)] = a
+	return nil
+}
+
+// Start counts the run as running if it fits in the concurrent runs of its quotas, it returns false if the run must
+// stay queued until another run is released. Runs which were not admitted (e.g. runs resumed after a restart) start
+// right away. Start never blocks, queued runs are held by the scheduler of the controller rather than by its workers.
+func (l *Limiter) Start(run *functions.FnRun) bool {
+	if l == nil {
+		return true
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	return l.start(run) == nil
+}
+
+// StartNow counts an admitted run as running for runs which can't be queued, e.g. the task runs of workflows, it
+// returns an *ExceededError if the run doesn't fit in the concurrent runs of its quotas
+func (l *Limiter) StartNow(run *functions.FnRun) error {
+	if l == nil {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	return l.start(run)
+}
+
+func (l *Limiter) start(run *functions.FnRun) error {
+	a, ok := l.runs[runKey(run)]
+	if !ok || a.started {
+		return nil
+	}
+	if i := a.full(); i >= 0 {
+		return &ExceededError{
+			Quota:      a.quotas[i],
+			Reason:     fmt.Sprintf("%d runs running", a.usages[i].running),
+			RetryAfter: time.Second,
+		}
+	}
+	for _, u := range a.usages {
+		u.queued--
+		u.running++
+	}
+	a.started = true
+	return nil
+}
+
+// Release stops counting an admitted run against its quotas
+func (l *Limiter) Release(run *functions.FnRun) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	key := runKey(run)
+	a, ok := l.runs[key]
+	if !ok {
+		return
+	}
+	for _, u := range a.usages {
+		if a.started {
+			u.running--
+		} else {
+			u.queued--
+		}
+	}
+	delete(l.runs, key)
+}
+
+// Usage returns the number of runs running and queued counted against a quota
+func (l *Limiter) Usage(organizationID, name string) (running int64, queued int64) {
+	if l == nil {
+		return 0, 0
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	if u, ok := l.usages[quotaKey(organizationID, name)]; ok {
+		return u.running, u.queued
+	}
+	return 0, 0
+}
+
+// Invalidate drops the cached quotas of an organization, so changes are enforced right away
+func (l *Limiter) Invalidate(organizationID string) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.quotas, organizationID)
+}
+
+// usageOf returns the usage of a quota, it must be called with the lock held
+func (l *Limiter) usageOf(organizationID, name string) *usage {
+	key := quotaKey(organizationID, name)
+	u, ok := l.usages[key]
+	if !ok {
+		u = &usage{}
+		l.usages[key] = u
+	}
+	return u
+}
+
+func (l *Limiter) quotasFor(ctx context.Context, organizationID string) ([]*entities.Quota, error) {
+	l.Lock()
+	cached, ok := l.quotas[organizationID]
+	l.Unlock()
+	if ok && l.now().Before(cached.expires) {
+		return cached.quotas, nil
+	}
+
+	var quotas []*entities.Quota
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	if err := l.store.List(ctx, organizationID, opts, &quotas); err != nil {
+		return nil, errors.Wrapf(err, "store error when listing quotas")
+	}
+
+	l.Lock()
+	l.quotas[organizationID] = &cachedQuotas{quotas: quotas, expires: l.now().Add(cacheTTL)}
+	l.Unlock()
+	return quotas, nil
+}
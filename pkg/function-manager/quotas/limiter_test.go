@@ -0,0 +1,180 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package quotas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas/entities"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+const testOrgID = "testOrg"
+
+func addQuota(t *testing.T, es entitystore.EntityStore, quota *entities.Quota) {
+	quota.OrganizationID = testOrgID
+	quota.Status = entitystore.StatusREADY
+	_, err := es.Add(context.Background(), quota)
+	require.NoError(t, err)
+}
+
+func newRun(name, function string) *functions.FnRun {
+	return &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           name,
+			OrganizationID: testOrgID,
+		},
+		FunctionName: function,
+	}
+}
+
+func TestLimiterRunsPerSecond(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	addQuota(t, es, &entities.Quota{
+		BaseEntity:    entitystore.BaseEntity{Name: "rate"},
+		RunsPerSecond: 2,
+	})
+	now := time.Now()
+	l := NewLimiter(es)
+	l.now = func() time.Time { return now }
+
+	assert.NoError(t, l.Admit(context.Background(), newRun("run1", "hello")))
+	assert.NoError(t, l.Admit(context.Background(), newRun("run2", "hello")))
+
+	err := l.Admit(context.Background(), newRun("run3", "hello"))
+	require.IsType(t, &ExceededError{}, err)
+	exceeded := err.(*ExceededError)
+	assert.Equal(t, "rate", exceeded.Quota)
+	assert.Equal(t, 500*time.Millisecond, exceeded.RetryAfter)
+
+	// the bucket is refilled over time
+	now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, l.Admit(context.Background(), newRun("run3", "hello")))
+}
+
+func TestLimiterConcurrentRuns(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	addQuota(t, es, &entities.Quota{
+		BaseEntity:        entitystore.BaseEntity{Name: "concurrency"},
+		MaxConcurrentRuns: 1,
+		MaxQueuedRuns:     1,
+	})
+	l := NewLimiter(es)
+
+	run1, run2, run3 := newRun("run1", "hello"), newRun("run2", "hello"), newRun("run3", "hello")
+	require.NoError(t, l.Admit(context.Background(), run1))
+	require.NoError(t, l.Admit(context.Background(), run2))
+	assert.IsType(t, &ExceededError{}, l.Admit(context.Background(), run3))
+
+	assert.True(t, l.Start(run1))
+	running, queued := l.Usage(testOrgID, "concurrency")
+	assert.Equal(t, int64(1), running)
+	assert.Equal(t, int64(1), queued)
+
+	// the queued run waits for the running one to finish
+	assert.False(t, l.Start(run2))
+	l.Release(run1)
+	assert.True(t, l.Start(run2))
+
+	running, queued = l.Usage(testOrgID, "concurrency")
+	assert.Equal(t, int64(1), running)
+	assert.Equal(t, int64(0), queued)
+	assert.NoError(t, l.Admit(context.Background(), run3))
+
+	l.Release(run2)
+	l.Release(run3)
+	running, queued = l.Usage(testOrgID, "concurrency")
+	assert.Equal(t, int64(0), running)
+	assert.Equal(t, int64(0), queued)
+}
+
+func TestLimiterStartNow(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	addQuota(t, es, &entities.Quota{
+		BaseEntity:        entitystore.BaseEntity{Name: "concurrency"},
+		MaxConcurrentRuns: 1,
+		MaxQueuedRuns:     1,
+	})
+	l := NewLimiter(es)
+
+	run1, run2 := newRun("run1", "hello"), newRun("run2", "hello")
+	require.NoError(t, l.Admit(context.Background(), run1))
+	require.NoError(t, l.StartNow(run1))
+	require.NoError(t, l.Admit(context.Background(), run2))
+
+	// a run which can't be queued is refused while the quota is full
+	err := l.StartNow(run2)
+	require.IsType(t, &ExceededError{}, err)
+	assert.Equal(t, "concurrency", err.(*ExceededError).Quota)
+	l.Release(run1)
+	assert.NoError(t, l.StartNow(run2))
+
+	running, queued := l.Usage(testOrgID, "concurrency")
+	assert.Equal(t, int64(1), running)
+	assert.Equal(t, int64(0), queued)
+}
+
+func TestLimiterFunctionQuota(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	addQuota(t, es, &entities.Quota{
+		BaseEntity:        entitystore.BaseEntity{Name: "hello-only"},
+		Function:          "hello",
+		MaxConcurrentRuns: 1,
+	})
+	l := NewLimiter(es)
+
+	require.NoError(t, l.Admit(context.Background(), newRun("run1", "hello")))
+	assert.Error(t, l.Admit(context.Background(), newRun("run2", "hello")))
+	assert.NoError(t, l.Admit(context.Background(), newRun("run3", "goodbye")))
+}
+
+func TestLimiterInvalidate(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	l := NewLimiter(es)
+	require.NoError(t, l.Admit(context.Background(), newRun("run1", "hello")))
+
+	// quotas are cached until invalidated
+	addQuota(t, es, &entities.Quota{
+		BaseEntity:        entitystore.BaseEntity{Name: "concurrency"},
+		MaxConcurrentRuns: 1,
+	})
+	require.NoError(t, l.Admit(context.Background(), newRun("run2", "hello")))
+	require.NoError(t, l.Admit(context.Background(), newRun("run3", "hello")))
+	l.Invalidate(testOrgID)
+	require.NoError(t, l.Admit(context.Background(), newRun("run4", "hello")))
+	assert.Error(t, l.Admit(context.Background(), newRun("run5", "hello")))
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	run := newRun("run1", "hello")
+	assert.NoError(t, l.Admit(context.Background(), run))
+	assert.True(t, l.Start(run))
+	assert.NoError(t, l.StartNow(run))
+	l.Release(run)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(&entities.Quota{RunsPerSecond: 0.5}))
+	assert.NoError(t, Validate(&entities.Quota{MaxConcurrentRuns: 2, MaxQueuedRuns: 10}))
+
+	for _, quota := range []*entities.Quota{
+		{},
+		{MaxQueuedRuns: 10},
+		{RunsPerSecond: 0.5, MaxQueuedRuns: 10},
+		{RunsPerSecond: -1},
+		{MaxConcurrentRuns: 2, MaxQueuedRuns: -1},
+	} {
+		assert.Error(t, Validate(quota), "%+v", quota)
+	}
+}
@@ -0,0 +1,151 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// replayRunEntity creates a run of the function f with the input, event, HTTP context, secrets and services of the
+// original run. The secrets and services of f are added to the original ones when f is another function.
+func replayRunEntity(original *functions.FnRun, f *functions.Function, blocking bool) *functions.FnRun {
+	tags := make(map[string]string)
+	for k, v := range original.Tags {
+		tags[k] = v
+	}
+	var waitChan chan struct{}
+	if blocking {
+		waitChan = make(chan struct{})
+	}
+	return &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:   uuid.NewV4().String(),
+			Status: f.Status,
+			Reason: f.Reason,
+			Tags:   tags,
+		},
+		Blocking:         blocking,
+		Input:            original.Input,
+		InputContentType: original.InputContentType,
+		HTTPContext:      original.HTTPContext,
+		Event:            original.Event,
+		Secrets:          union(original.Secrets, f.Secrets),
+		Services:         union(original.Services, f.Services),
+		FunctionName:     f.Name,
+		FunctionID:       f.ID,
+		FaasID:           f.FaasID,
+		ReplayOf:         original.Name,
+		WaitChan:         waitChan,
+	}
+}
+
+// union returns the values of a followed by the values of b missing in a
+func union(a, b []string) []string {
+	values := append([]string(nil), a...)
+	for _, v := range b {
+		found := false
+		for _, existing := range a {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (h *Handlers) replayRun(params fnrunner.ReplayRunParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
+	defer span.Finish()
+
+	original := new(functions.FnRun)
+	err := h.Store.Get(ctx, params.XDispatchOrg, params.RunName.String(), entitystore.Options{}, original)
+	if err == nil && !h.Invocations.Allows(params.HTTPRequest, params.XDispatchOrg, original) {
+		err = errors.Errorf("function run %s was not created with the invocation token", original.Name)
+	}
+	if err != nil {
+		log.Debugf("Error returned by h.Store.Get: %+v", err)
+		return fnrunner.NewReplayRunNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("function run", params.RunName.String()),
+		})
+	}
+
+	functionName := original.FunctionName
+	if params.FunctionName != nil {
+		functionName = *params.FunctionName
+	}
+	f := new(functions.Function)
+	if err := h.Store.Get(ctx, params.XDispatchOrg, functionName, entitystore.Options{}, f); err != nil {
+		log.Debugf("Error returned by h.Store.Get: %+v", err)
+		return fnrunner.NewReplayRunNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("function", functionName),
+		})
+	}
+	if f.Status != entitystore.StatusREADY {
+		return fnrunner.NewReplayRunNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: swag.String(fmt.Sprintf("function %s is not READY", functionName)),
+		})
+	}
+
+	run := replayRunEntity(original, f, swag.BoolValue(params.Blocking))
+	run.OrganizationID = params.XDispatchOrg
+	run.Status = entitystore.StatusINITIALIZED
+
+	if err := h.Quotas.Admit(ctx, run); err != nil {
+		if exceeded, ok := err.(*quotas.ExceededError); ok {
+			log.Infof("Replay of function run %s rejected: %s", original.Name, exceeded)
+			return fnrunner.NewReplayRunTooManyRequests().WithRetryAfter(retryAfterSeconds(exceeded.RetryAfter)).WithPayload(&v1.Error{
+				Code:    http.StatusTooManyRequests,
+				Message: swag.String(exceeded.Error()),
+			})
+		}
+		log.Errorf("Error when checking the quotas of function run %s: %+v", run.Name, err)
+		return fnrunner.NewReplayRunDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("function run", run.Name),
+		})
+	}
+
+	if _, err := h.Store.Add(ctx, run); err != nil {
+		h.Quotas.Release(run)
+		log.Errorf("Store error when adding new function run %s: %+v", run.Name, err)
+		return fnrunner.NewReplayRunDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("function run", run.Name),
+		})
+	}
+	log.Infof("Function run %s replays run %s", run.Name, original.Name)
+
+	h.Watcher.OnAction(ctx, run)
+
+	if run.Blocking {
+		run.Wait()
+		return fnrunner.NewReplayRunOK().WithPayload(runEntityToModel(run))
+	}
+
+	return fnrunner.NewReplayRunAccepted().WithPayload(runEntityToModel(run))
+}
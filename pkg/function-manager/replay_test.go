@@ -0,0 +1,112 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func TestHandlers_replayRun(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	watcher := make(chan controller.WatchEvent, 2)
+	handlers := &Handlers{
+		Watcher: watcher,
+		Store:   store,
+	}
+
+	for _, f := range []*functions.Function{
+		{
+			BaseEntity: entitystore.BaseEntity{Name: "hello", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+			Secrets:    []string{"greeting"},
+		},
+		{
+			BaseEntity: entitystore.BaseEntity{Name: "hello-fixed", Status: entitystore.StatusREADY, OrganizationID: testOrgID},
+			Secrets:    []string{"greeting", "fix"},
+		},
+	} {
+		_, err := store.Add(context.Background(), f)
+		require.NoError(t, err)
+	}
+	original := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "f98d0a7f-0c1d-4020-a488-cabc501b08e0",
+			Status:         entitystore.StatusERROR,
+			OrganizationID: testOrgID,
+			Tags:           map[string]string{"role": "test"},
+		},
+		FunctionName:   "hello",
+		Input:          map[string]interface{}{"name": "Jon"},
+		HTTPContext:    map[string]interface{}{"method": "POST"},
+		Event:          &events.CloudEvent{EventType: "user.created", EventID: "1"},
+		Secrets:        []string{"greeting", "token"},
+		Services:       []string{"db"},
+		IdempotencyKey: "key",
+	}
+	_, err := store.Add(context.Background(), original)
+	require.NoError(t, err)
+
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	r := httptest.NewRequest("POST", "/v1/runs/f98d0a7f-0c1d-4020-a488-cabc501b08e0/replay", nil)
+	params := fnrunner.ReplayRunParams{
+		HTTPRequest:  r,
+		RunName:      strfmt.UUID(original.Name),
+		XDispatchOrg: testOrgID,
+	}
+	responder := api.RunnerReplayRunHandler.Handle(params, "testCookie")
+	var respBody v1.Run
+	helpers.HandlerRequest(t, responder, &respBody, 202)
+
+	replay := (<-watcher).Entity.(*functions.FnRun)
+	assert.NotEqual(t, original.Name, replay.Name)
+	assert.Equal(t, "hello", replay.FunctionName)
+	assert.Equal(t, original.Name, replay.ReplayOf)
+	assert.Equal(t, original.Input, replay.Input)
+	assert.Equal(t, original.HTTPContext, replay.HTTPContext)
+	require.NotNil(t, replay.Event)
+	assert.Equal(t, original.Event.EventType, replay.Event.EventType)
+	assert.Equal(t, original.Event.EventID, replay.Event.EventID)
+	assert.Equal(t, []string{"greeting", "token"}, replay.Secrets)
+	assert.Equal(t, []string{"db"}, replay.Services)
+	assert.Equal(t, original.Tags, replay.Tags)
+	assert.Empty(t, replay.IdempotencyKey)
+	assert.Equal(t, strfmt.UUID(original.Name), respBody.ReplayOf)
+
+	otherFunction := "hello-fixed"
+	params.FunctionName = &otherFunction
+	responder = api.RunnerReplayRunHandler.Handle(params, "testCookie")
+	helpers.HandlerRequest(t, responder, &respBody, 202)
+	replay = (<-watcher).Entity.(*functions.FnRun)
+	assert.Equal(t, "hello-fixed", replay.FunctionName)
+	assert.Equal(t, []string{"greeting", "token", "fix"}, replay.Secrets)
+
+	missingFunction := "missing"
+	params.FunctionName = &missingFunction
+	responder = api.RunnerReplayRunHandler.Handle(params, "testCookie")
+	var errBody v1.Error
+	helpers.HandlerRequest(t, responder, &errBody, 404)
+
+	params.FunctionName = nil
+	params.RunName = "0a0a0a0a-0c1d-4020-a488-cabc501b08e0"
+	responder = api.RunnerReplayRunHandler.Handle(params, "testCookie")
+	helpers.HandlerRequest(t, responder, &errBody, 404)
+}
@@ -0,0 +1,141 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/event-manager/helpers"
+	"github.com/vmware/dispatch/pkg/events"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// RunExtension is the extension of result events holding the name of the run
+const RunExtension = "dispatchRun"
+
+// resultPaths are the fields of the run the data of result events is mapped from
+var resultPaths = map[string]bool{
+	"name":         true,
+	"functionName": true,
+	"input":        true,
+	"output":       true,
+	"error":        true,
+}
+
+// validResultPath returns true if the path starts with one of the fields of the run result events are mapped from
+func validResultPath(path string) bool {
+	return resultPaths[strings.SplitN(path, ".", 2)[0]]
+}
+
+// PublishResult publishes in the background the event configured on the function for the result of a completed run:
+// OnSuccess when it succeeded, OnFailure when it failed. Unlike callbacks, the publication isn't stored with the run:
+// it is retried in memory only, so the event is lost if the function manager stops before it is published.
+func (c *Callbacks) PublishResult(f *functions.Function, run *functions.FnRun) {
+	if c == nil {
+		return
+	}
+	config := f.OnSuccess
+	if run.Status != entitystore.StatusREADY {
+		config = f.OnFailure
+	}
+	if config == nil {
+		return
+	}
+	ev, err := resultEvent(f.Name, config, run)
+	if err != nil {
+		log.Errorf("error creating the result event of function run %s: %+v", run.Name, err)
+		return
+	}
+	emission := &v1.Emission{CloudEvent: *helpers.CloudEventToAPI(ev)}
+	go c.retry("result event of function run "+run.Name, func() error {
+		if c.Events == nil {
+			return errors.New("no event manager to publish the event")
+		}
+		_, err := c.Events.EmitEvent(context.Background(), run.OrganizationID, emission)
+		return err
+	})
+}
+
+// resultEvent returns the event with the result of the run, its data being the output or error of the run unless the
+// configuration maps its fields
+func resultEvent(functionName string, config *functions.ResultEvent, run *functions.FnRun) (*events.CloudEvent, error) {
+	ev := events.NewCloudEventWithDefaults(config.EventType)
+	// the ID is derived from the run, so an event published twice for the same run is recorded and delivered once
+	ev.EventID = "run-" + run.Name
+	ev.Source = config.Source
+	if ev.Source == "" {
+		ev.Source = "dispatch/functions/" + functionName
+	}
+	ev.Extensions = events.CloudEventExtensions{RunExtension: run.Name}
+	ev.ContentType = "application/json"
+
+	var data interface{}
+	switch {
+	case len(config.Data) > 0:
+		doc, err := runDocument(run)
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]interface{}{}
+		for field, path := range config.Data {
+			if value, ok := utils.LookupPath(doc, path); ok {
+				fields[field] = value
+			}
+		}
+		data = fields
+	case run.Status != entitystore.StatusREADY:
+		data = run.Error
+		if run.Error == nil {
+			// the run failed before running the function
+			message := strings.Join(run.Reason, "; ")
+			data = &v1.InvocationError{Message: &message, Type: v1.ErrorTypeSystemError}
+		}
+	case !functions.IsJSONContentType(run.OutputContentType):
+		// binary outputs are published as they are
+		payload, err := functions.DecodePayload(run.Output, run.OutputContentType)
+		if err != nil {
+			return nil, err
+		}
+		ev.ContentType = run.OutputContentType
+		ev.Data = payload.(*functions.Payload).Data
+		return &ev, nil
+	default:
+		data = run.Output
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding the event data")
+	}
+	ev.Data = body
+	return &ev, nil
+}
+
+// runDocument returns the fields of the run the data of result events is mapped from, as decoded JSON
+func runDocument(run *functions.FnRun) (map[string]interface{}, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"name":         run.Name,
+		"functionName": run.FunctionName,
+		"input":        run.Input,
+		"output":       run.Output,
+		"error":        run.Error,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding the run")
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, errors.Wrap(err, "error decoding the run")
+	}
+	return doc, nil
+}
@@ -0,0 +1,86 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+func TestResultEvent(t *testing.T) {
+	run := &functions.FnRun{
+		BaseEntity:   entitystore.BaseEntity{Name: "f98d0a7f-0c1d-4020-a488-cabc501b08e0", Status: entitystore.StatusREADY},
+		FunctionName: "hello",
+		Input:        map[string]interface{}{"name": "Jon"},
+		Output:       map[string]interface{}{"myField": "Hello, Jon"},
+	}
+
+	ev, err := resultEvent("hello", &functions.ResultEvent{EventType: "hello.done"}, run)
+	require.NoError(t, err)
+	assert.Equal(t, "hello.done", ev.EventType)
+	assert.Equal(t, "dispatch/functions/hello", ev.Source)
+	assert.Equal(t, "run-"+run.Name, ev.EventID)
+	assert.Equal(t, run.Name, ev.Extensions[RunExtension])
+	assert.Equal(t, "application/json", ev.ContentType)
+	assert.JSONEq(t, `{"myField":"Hello, Jon"}`, string(ev.Data))
+
+	// the event of a run has the same ID every time it is created
+	again, err := resultEvent("hello", &functions.ResultEvent{EventType: "hello.done"}, run)
+	require.NoError(t, err)
+	assert.Equal(t, ev.EventID, again.EventID)
+
+	config := &functions.ResultEvent{
+		EventType: "hello.done",
+		Source:    "greetings",
+		Data:      map[string]string{"greeting": "output.myField", "who": "input.name", "missing": "output.other"},
+	}
+	ev, err = resultEvent("hello", config, run)
+	require.NoError(t, err)
+	assert.Equal(t, "greetings", ev.Source)
+	assert.JSONEq(t, `{"greeting":"Hello, Jon","who":"Jon"}`, string(ev.Data))
+
+	run.Output, run.OutputContentType = functions.EncodePayload(&functions.Payload{ContentType: "text/plain", Data: []byte("Hello, Jon")})
+	ev, err = resultEvent("hello", &functions.ResultEvent{EventType: "hello.done"}, run)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", ev.ContentType)
+	assert.Equal(t, "Hello, Jon", string(ev.Data))
+
+	message := "function failed"
+	run.Status = entitystore.StatusERROR
+	run.Error = &v1.InvocationError{Message: &message, Type: v1.ErrorTypeFunctionError}
+	ev, err = resultEvent("hello", &functions.ResultEvent{EventType: "hello.failed"}, run)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message":"function failed","stacktrace":null,"type":"FunctionError"}`, string(ev.Data))
+
+	run.Error = nil
+	run.Reason = []string{"function hello is not READY"}
+	ev, err = resultEvent("hello", &functions.ResultEvent{EventType: "hello.failed"}, run)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message":"function hello is not READY","stacktrace":null,"type":"SystemError"}`, string(ev.Data))
+}
+
+func TestResultEventModelToEntity(t *testing.T) {
+	e, err := resultEventModelToEntity(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = resultEventModelToEntity(&v1.FunctionEvent{EventType: swag.String("hello.done"), Data: map[string]string{"who": "input.name"}})
+	require.NoError(t, err)
+	assert.Equal(t, "hello.done", e.EventType)
+	assert.Equal(t, map[string]string{"who": "input.name"}, resultEventEntityToModel(e).Data)
+
+	_, err = resultEventModelToEntity(&v1.FunctionEvent{})
+	assert.Error(t, err)
+	_, err = resultEventModelToEntity(&v1.FunctionEvent{EventType: swag.String("hello.done"), Data: map[string]string{"who": "secrets.name"}})
+	assert.Error(t, err)
+}
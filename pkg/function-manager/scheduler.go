@@ -0,0 +1,242 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"sync"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+const (
+	// DefaultWorkers is the default number of entities, mostly function runs, processed at a time
+	DefaultWorkers = 1000
+	// DefaultReservedWorkers is the default number of workers only high priority runs are processed by
+	DefaultReservedWorkers = 100
+)
+
+// priorities are the priority classes of function runs, highest first
+var priorities = []string{v1.RunPriorityHigh, v1.RunPriorityNormal, v1.RunPriorityLow}
+
+// runPriority returns the priority of a run: the one requested, high for blocking runs, low for runs of events and
+// normal otherwise
+func runPriority(m *v1.Run) string {
+	switch {
+	case m.Priority != "":
+		return m.Priority
+	case m.Blocking:
+		return v1.RunPriorityHigh
+	case m.Event != nil:
+		return v1.RunPriorityLow
+	}
+	return v1.RunPriorityNormal
+}
+
+// FairScheduler schedules the entities of the function manager controller. Function runs are processed by priority,
+// round robin between the organizations and then between the functions of each organization, so a burst of runs of one
+// function doesn't delay the runs of the others. Some workers are reserved for high priority runs, so blocking runs of
+// the API gateway are processed even when all other workers are taken by event-driven runs. Other entities are
+// processed as normal priority runs. Runs which don't fit in the concurrent runs allowed by their quotas stay queued,
+// without taking a worker, until a run of the quotas is done. A run pushed again while it is queued or processed, e.g.
+// by a resync of the controller, is dropped.
+type FairScheduler struct {
+	workers  int
+	reserved int
+	quotas   *quotas.Limiter
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues map[string]*fairQueue
+	// running counts the watch events being processed, by priority
+	running map[string]int
+	// runs are the runs queued or being processed
+	runs   map[string]bool
+	closed bool
+}
+
+// NewFairScheduler is the constructor for FairScheduler, processing up to workers entities at a time, reserved of them
+// only for high priority runs, and starting runs against the concurrency quotas of limiter
+func NewFairScheduler(workers, reserved int, limiter *quotas.Limiter) *FairScheduler {
+	if reserved >= workers {
+		reserved = workers - 1
+	}
+	if reserved < 0 {
+		reserved = 0
+	}
+	s := &FairScheduler{
+		workers:  workers,
+		reserved: reserved,
+		quotas:   limiter,
+		queues:   map[string]*fairQueue{},
+		running:  map[string]int{},
+		runs:     map[string]bool{},
+	}
+	s.cond = sync.NewCond(&s.mu)
+	for _, p := range priorities {
+		s.queues[p] = newFairQueue()
+	}
+	return s
+}
+
+// Push queues a watch event, unless it is a run already queued or being processed
+func (s *FairScheduler) Push(event controller.WatchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	function := ""
+	if run, ok := event.Entity.(*functions.FnRun); ok {
+		if s.runs[scheduledRunKey(run)] {
+			return
+		}
+		s.runs[scheduledRunKey(run)] = true
+		function = run.FunctionName
+	}
+	s.queues[s.priority(event)].push(event.Entity.GetOrganizationID(), function, event)
+	s.cond.Broadcast()
+}
+
+// Next blocks until a queued watch event can be processed and returns it, or returns false once closed
+func (s *FairScheduler) Next() (controller.WatchEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		if s.closed {
+			return controller.WatchEvent{}, false
+		}
+		for _, p := range priorities {
+			if s.queues[p].len == 0 || !s.available(p) {
+				continue
+			}
+			if event, ok := s.queues[p].pop(s.start); ok {
+				s.running[p]++
+				return event, true
+			}
+		}
+		s.cond.Wait()
+	}
+}
+
+// start returns true if the watch event can be processed now, counting runs as running against their quotas
+func (s *FairScheduler) start(event controller.WatchEvent) bool {
+	if run, ok := event.Entity.(*functions.FnRun); ok {
+		return s.quotas.Start(run)
+	}
+	return true
+}
+
+// Done releases the worker of a processed watch event, the quotas of a processed run are released by then
+func (s *FairScheduler) Done(event controller.WatchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[s.priority(event)]--
+	if run, ok := event.Entity.(*functions.FnRun); ok {
+		delete(s.runs, scheduledRunKey(run))
+	}
+	s.cond.Broadcast()
+}
+
+// Close stops the scheduler, dropping the queued watch events
+func (s *FairScheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+func scheduledRunKey(run *functions.FnRun) string {
+	return run.OrganizationID + "/" + run.Name
+}
+
+// priority returns the priority class of a watch event
+func (s *FairScheduler) priority(event controller.WatchEvent) string {
+	if run, ok := event.Entity.(*functions.FnRun); ok {
+		if _, ok := s.queues[run.Priority]; ok {
+			return run.Priority
+		}
+	}
+	return v1.RunPriorityNormal
+}
+
+// available returns true if a worker is available for a watch event of the priority: any worker for high priority
+// runs, a worker not reserved for them otherwise
+func (s *FairScheduler) available(priority string) bool {
+	total := 0
+	for _, n := range s.running {
+		total += n
+	}
+	if total >= s.workers {
+		return false
+	}
+	return priority == v1.RunPriorityHigh || total-s.running[v1.RunPriorityHigh] < s.workers-s.reserved
+}
+
+// fairQueue queues the watch events of a priority, round robin between the organizations and then between the
+// functions of each organization
+type fairQueue struct {
+	organizations []string
+	queues        map[string]*organizationQueue
+	len           int
+}
+
+// organizationQueue queues the watch events of an organization, round robin between the functions
+type organizationQueue struct {
+	functions []string
+	events    map[string][]controller.WatchEvent
+}
+
+func newFairQueue() *fairQueue {
+	return &fairQueue{queues: map[string]*organizationQueue{}}
+}
+
+func (q *fairQueue) push(organizationID, function string, event controller.WatchEvent) {
+	o, ok := q.queues[organizationID]
+	if !ok {
+		o = &organizationQueue{events: map[string][]controller.WatchEvent{}}
+		q.queues[organizationID] = o
+		q.organizations = append(q.organizations, organizationID)
+	}
+	if _, ok := o.events[function]; !ok {
+		o.functions = append(o.functions, function)
+	}
+	o.events[function] = append(o.events[function], event)
+	q.len++
+}
+
+// pop returns the next watch event of the queue which can start, or false if none can. The first watch event of
+// every function is tried in round robin order, those which can't start keep their place. The organization and function
+// of the returned event go to the back of their round robin.
+func (q *fairQueue) pop(start func(controller.WatchEvent) bool) (controller.WatchEvent, bool) {
+	for i, organizationID := range q.organizations {
+		o := q.queues[organizationID]
+		for j, function := range o.functions {
+			events := o.events[function]
+			event := events[0]
+			if !start(event) {
+				continue
+			}
+
+			o.functions = append(o.functions[:j:j], o.functions[j+1:]...)
+			if len(events) > 1 {
+				o.events[function] = events[1:]
+				o.functions = append(o.functions, function)
+			} else {
+				delete(o.events, function)
+			}
+
+			q.organizations = append(q.organizations[:i:i], q.organizations[i+1:]...)
+			if len(o.functions) > 0 {
+				q.organizations = append(q.organizations, organizationID)
+			} else {
+				delete(q.queues, organizationID)
+			}
+			q.len--
+			return event, true
+		}
+	}
+	return controller.WatchEvent{}, false
+}
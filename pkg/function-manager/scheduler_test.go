@@ -0,0 +1,261 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/quotas"
+	quotaentities "github.com/vmware/dispatch/pkg/function-manager/quotas/entities"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func runEvent(name, organizationID, function, priority string) controller.WatchEvent {
+	return controller.WatchEvent{Entity: &functions.FnRun{
+		BaseEntity:   entitystore.BaseEntity{Name: name, OrganizationID: organizationID},
+		FunctionName: function,
+		Priority:     priority,
+	}}
+}
+
+func next(t *testing.T, s *FairScheduler) string {
+	event, ok := s.Next()
+	require.True(t, ok)
+	return event.Entity.GetName()
+}
+
+func TestRunPriority(t *testing.T) {
+	assert.Equal(t, v1.RunPriorityNormal, runPriority(&v1.Run{}))
+	assert.Equal(t, v1.RunPriorityHigh, runPriority(&v1.Run{Blocking: true}))
+	assert.Equal(t, v1.RunPriorityLow, runPriority(&v1.Run{Event: &v1.CloudEvent{}}))
+	assert.Equal(t, v1.RunPriorityLow, runPriority(&v1.Run{Blocking: true, Priority: v1.RunPriorityLow}))
+}
+
+func TestFairSchedulerPriority(t *testing.T) {
+	s := NewFairScheduler(10, 0, nil)
+	s.Push(runEvent("low", "org", "hello", v1.RunPriorityLow))
+	s.Push(runEvent("normal", "org", "hello", ""))
+	s.Push(runEvent("high", "org", "hello", v1.RunPriorityHigh))
+
+	assert.Equal(t, "high", next(t, s))
+	assert.Equal(t, "normal", next(t, s))
+	assert.Equal(t, "low", next(t, s))
+}
+
+func TestFairSchedulerRoundRobin(t *testing.T) {
+	s := NewFairScheduler(10, 0, nil)
+	s.Push(runEvent("org1-hello-1", "org1", "hello", ""))
+	s.Push(runEvent("org1-hello-2", "org1", "hello", ""))
+	s.Push(runEvent("org1-hello-3", "org1", "hello", ""))
+	s.Push(runEvent("org1-bye-1", "org1", "bye", ""))
+	s.Push(runEvent("org2-hello-1", "org2", "hello", ""))
+
+	assert.Equal(t, "org1-hello-1", next(t, s))
+	assert.Equal(t, "org2-hello-1", next(t, s))
+	assert.Equal(t, "org1-bye-1", next(t, s))
+	assert.Equal(t, "org1-hello-2", next(t, s))
+	assert.Equal(t, "org1-hello-3", next(t, s))
+}
+
+func TestFairSchedulerReserved(t *testing.T) {
+	s := NewFairScheduler(2, 1, nil)
+	s.Push(runEvent("normal-1", "org", "hello", ""))
+	s.Push(runEvent("normal-2", "org", "hello", ""))
+	first, ok := s.Next()
+	require.True(t, ok)
+	assert.Equal(t, "normal-1", first.Entity.GetName())
+
+	// the only other worker is reserved for high priority runs
+	s.Push(runEvent("high", "org", "hello", v1.RunPriorityHigh))
+	assert.Equal(t, "high", next(t, s))
+
+	nexts := make(chan string)
+	go func() {
+		event, _ := s.Next()
+		nexts <- event.Entity.GetName()
+	}()
+	select {
+	case name := <-nexts:
+		t.Fatalf("%s processed while all workers are taken", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.Done(first)
+	assert.Equal(t, "normal-2", <-nexts)
+
+	s.Close()
+	_, ok = s.Next()
+	assert.False(t, ok)
+}
+
+func TestFairSchedulerQuotas(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	_, err := store.Add(context.Background(), &quotaentities.Quota{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "one-at-a-time",
+			Status:         entitystore.StatusREADY,
+			OrganizationID: "org",
+		},
+		Function:          "hello",
+		MaxConcurrentRuns: 1,
+		MaxQueuedRuns:     1,
+	})
+	require.NoError(t, err)
+	limiter := quotas.NewLimiter(store)
+	s := NewFairScheduler(10, 0, limiter)
+
+	events := []controller.WatchEvent{
+		runEvent("hello-1", "org", "hello", ""),
+		runEvent("hello-2", "org", "hello", ""),
+		runEvent("bye-1", "org", "bye", ""),
+	}
+	for _, event := range events {
+		require.NoError(t, limiter.Admit(context.Background(), event.Entity.(*functions.FnRun)))
+		s.Push(event)
+	}
+
+	// the second run of hello stays queued without taking a worker, other runs are processed meanwhile
+	first, ok := s.Next()
+	require.True(t, ok)
+	assert.Equal(t, "hello-1", first.Entity.GetName())
+	assert.Equal(t, "bye-1", next(t, s))
+
+	nexts := make(chan string)
+	go func() {
+		event, _ := s.Next()
+		nexts <- event.Entity.GetName()
+	}()
+	select {
+	case name := <-nexts:
+		t.Fatalf("%s processed while its quota is full", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+	limiter.Release(first.Entity.(*functions.FnRun))
+	s.Done(first)
+	assert.Equal(t, "hello-2", <-nexts)
+}
+
+// countingRunHandler counts the runs processed, blocking the processing of the runs in hold, and resyncs the runs of
+// resync
+type countingRunHandler struct {
+	limiter *quotas.Limiter
+	hold    map[string]chan struct{}
+
+	mu     sync.Mutex
+	resync []entitystore.Entity
+	added  map[string]int
+	done   chan string
+}
+
+func (h *countingRunHandler) Type() reflect.Type {
+	return reflect.TypeOf(&functions.FnRun{})
+}
+
+func (h *countingRunHandler) Add(ctx context.Context, obj entitystore.Entity) error {
+	run := obj.(*functions.FnRun)
+	defer h.limiter.Release(run)
+	h.mu.Lock()
+	h.added[run.Name]++
+	h.mu.Unlock()
+	if hold, ok := h.hold[run.Name]; ok {
+		<-hold
+	}
+	h.done <- run.Name
+	return nil
+}
+
+func (h *countingRunHandler) Update(ctx context.Context, obj entitystore.Entity) error { return nil }
+
+func (h *countingRunHandler) Delete(ctx context.Context, obj entitystore.Entity) error { return nil }
+
+func (h *countingRunHandler) Error(ctx context.Context, obj entitystore.Entity) error { return nil }
+
+func (h *countingRunHandler) Sync(ctx context.Context, resyncPeriod time.Duration) ([]entitystore.Entity, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var entities []entitystore.Entity
+	for _, e := range h.resync {
+		run := *e.(*functions.FnRun)
+		entities = append(entities, &run)
+	}
+	return entities, nil
+}
+
+func TestFairSchedulerResyncQueuedRun(t *testing.T) {
+	ctx := context.Background()
+	store := helpers.MakeEntityStore(t)
+	_, err := store.Add(ctx, &quotaentities.Quota{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "one-at-a-time",
+			Status:         entitystore.StatusREADY,
+			OrganizationID: "org",
+		},
+		Function:          "hello",
+		MaxConcurrentRuns: 1,
+		MaxQueuedRuns:     1,
+	})
+	require.NoError(t, err)
+	limiter := quotas.NewLimiter(store)
+
+	hold := make(chan struct{})
+	handler := &countingRunHandler{
+		limiter: limiter,
+		hold:    map[string]chan struct{}{"hello-1": hold},
+		added:   map[string]int{},
+		done:    make(chan string, 10),
+	}
+	resyncPeriod := 20 * time.Millisecond
+	c := controller.NewController(controller.Options{
+		ResyncPeriod: resyncPeriod,
+		Workers:      10,
+		Scheduler:    NewFairScheduler(10, 0, limiter),
+	})
+	c.AddEntityHandler(handler)
+	c.Start()
+	defer c.Shutdown()
+	watcher := c.Watcher()
+
+	var runs []*functions.FnRun
+	for _, name := range []string{"hello-1", "hello-2"} {
+		run := runEvent(name, "org", "hello", "").Entity.(*functions.FnRun)
+		run.Status = entitystore.StatusINITIALIZED
+		require.NoError(t, limiter.Admit(ctx, run))
+		watcher.OnAction(ctx, run)
+		runs = append(runs, run)
+	}
+
+	// the second run stays queued by its quota for several resync periods, and is resynced meanwhile
+	handler.mu.Lock()
+	handler.resync = []entitystore.Entity{runs[1]}
+	handler.mu.Unlock()
+	time.Sleep(5 * resyncPeriod)
+	handler.mu.Lock()
+	assert.Equal(t, 0, handler.added["hello-2"])
+	handler.resync = nil
+	handler.mu.Unlock()
+
+	close(hold)
+	assert.Equal(t, "hello-1", <-handler.done)
+	assert.Equal(t, "hello-2", <-handler.done)
+	select {
+	case name := <-handler.done:
+		t.Fatalf("%s processed twice", name)
+	case <-time.After(5 * resyncPeriod):
+	}
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	assert.Equal(t, 1, handler.added["hello-2"])
+}
@@ -0,0 +1,174 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	dispatcherrors "github.com/vmware/dispatch/pkg/errors"
+	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+)
+
+// runQuery filters listed runs, on the entity store where possible and on the listed runs otherwise
+type runQuery struct {
+	Until       *int64
+	Status      *string
+	ErrorType   *string
+	MinDuration *int64
+	MaxDuration *int64
+	// ParentRun restricts the runs to those created by the run, for requests with an invocation token
+	ParentRun string
+}
+
+// runDuration returns how long a run took, false if it has not finished
+func runDuration(run *functions.FnRun) (time.Duration, bool) {
+	if run.FinishedTime.IsZero() {
+		return 0, false
+	}
+	return run.FinishedTime.Sub(run.CreatedTime), true
+}
+
+// storeFilter adds the criteria the entity store can filter on to filter
+func (q *runQuery) storeFilter(filter entitystore.Filter) {
+	if q.Until != nil {
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "ModifiedTime",
+			Verb:    entitystore.FilterVerbBefore,
+			Object:  time.Unix(*q.Until, 0),
+		})
+	}
+	if q.Status != nil {
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  entitystore.Status(*q.Status),
+		})
+	}
+	if q.ParentRun != "" {
+		filter.Add(entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeExtra,
+			Subject: "ParentRun",
+			Verb:    entitystore.FilterVerbEqual,
+			Object:  q.ParentRun,
+		})
+	}
+}
+
+// match returns whether a listed run matches the criteria the entity store can't filter on
+func (q *runQuery) match(run *functions.FnRun) bool {
+	if q.ErrorType != nil && (run.Error == nil || string(run.Error.Type) != *q.ErrorType) {
+		return false
+	}
+	if q.MinDuration != nil || q.MaxDuration != nil {
+		d, finished := runDuration(run)
+		if !finished {
+			return false
+		}
+		if q.MinDuration != nil && d < time.Duration(*q.MinDuration)*time.Millisecond {
+			return false
+		}
+		if q.MaxDuration != nil && d > time.Duration(*q.MaxDuration)*time.Millisecond {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *runQuery) filter(runs []*functions.FnRun) []*functions.FnRun {
+	var filtered []*functions.FnRun
+	for _, run := range runs {
+		if q.match(run) {
+			filtered = append(filtered, run)
+		}
+	}
+	return filtered
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// runStats aggregates runs per function, sorted by function name
+func runStats(runs []*functions.FnRun) []*v1.RunStats {
+	byFunction := make(map[string][]*functions.FnRun)
+	var names []string
+	for _, run := range runs {
+		if _, ok := byFunction[run.FunctionName]; !ok {
+			names = append(names, run.FunctionName)
+		}
+		byFunction[run.FunctionName] = append(byFunction[run.FunctionName], run)
+	}
+	sort.Strings(names)
+
+	stats := []*v1.RunStats{}
+	for _, name := range names {
+		s := &v1.RunStats{FunctionName: name}
+		var durations []time.Duration
+		for _, run := range byFunction[name] {
+			s.Count++
+			if run.Status == entitystore.StatusERROR {
+				s.ErrorCount++
+			}
+			if d, finished := runDuration(run); finished {
+				durations = append(durations, d)
+			}
+		}
+		s.ErrorRate = float64(s.ErrorCount) / float64(s.Count)
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		s.P50Duration = int64(percentile(durations, 50) / time.Millisecond)
+		s.P95Duration = int64(percentile(durations, 95) / time.Millisecond)
+		s.P99Duration = int64(percentile(durations, 99) / time.Millisecond)
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+func (h *Handlers) getRunStats(params fnrunner.GetRunStatsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "")
+	defer span.Finish()
+
+	// the stats don't need the payloads of the runs
+	query := &runQuery{
+		Until:     params.Until,
+		ParentRun: h.Invocations.ParentRun(params.HTTPRequest, params.XDispatchOrg),
+	}
+	runs, err := getFilteredRuns(withoutPayloads(ctx), h.Store, params.XDispatchOrg, params.FunctionName, params.Since, params.Tags, query)
+
+	switch err.(type) {
+	case *dispatcherrors.RequestError:
+		return fnrunner.NewGetRunStatsBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	case *dispatcherrors.ServerError:
+		return fnrunner.NewGetRunStatsDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: swag.String("error when listing function runs"),
+		})
+	}
+	return fnrunner.NewGetRunStatsOK().WithPayload(runStats(query.filter(runs)))
+}
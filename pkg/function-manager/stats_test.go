@@ -0,0 +1,122 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functionmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	fnrunner "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/runner"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+// addFinishedRun adds a run of the function which took duration and failed with errorType if not empty
+func addFinishedRun(t *testing.T, store entitystore.EntityStore, functionName string, duration time.Duration, errorType v1.ErrorType) {
+	run := &functions.FnRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           fmt.Sprintf("%s-%d", functionName, duration),
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusREADY,
+		},
+		FunctionName: functionName,
+	}
+	if errorType != "" {
+		run.Status = entitystore.StatusERROR
+		run.Error = &v1.InvocationError{Message: swag.String("failed"), Type: errorType}
+	}
+	_, err := store.Add(context.Background(), run)
+	require.NoError(t, err)
+	run.FinishedTime = run.CreatedTime.Add(duration)
+	_, err = store.Update(context.Background(), run.Revision, run)
+	require.NoError(t, err)
+}
+
+func TestHandlers_getRunsQuery(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	handlers := &Handlers{
+		Store: store,
+	}
+	addFinishedRun(t, store, "hello", 10*time.Millisecond, "")
+	addFinishedRun(t, store, "hello", 200*time.Millisecond, v1.ErrorTypeFunctionError)
+	addFinishedRun(t, store, "hello", 3*time.Second, v1.ErrorTypeSystemError)
+
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	for _, tc := range []struct {
+		params fnrunner.GetRunsParams
+		runs   []string
+	}{
+		{fnrunner.GetRunsParams{Status: swag.String("ERROR")}, []string{"hello-200000000", "hello-3000000000"}},
+		{fnrunner.GetRunsParams{ErrorType: swag.String("SystemError")}, []string{"hello-3000000000"}},
+		{fnrunner.GetRunsParams{MinDuration: swag.Int64(100)}, []string{"hello-200000000", "hello-3000000000"}},
+		{fnrunner.GetRunsParams{MinDuration: swag.Int64(100), MaxDuration: swag.Int64(1000)}, []string{"hello-200000000"}},
+		{fnrunner.GetRunsParams{Until: swag.Int64(time.Now().Add(-time.Hour).Unix())}, nil},
+	} {
+		params := tc.params
+		params.HTTPRequest = httptest.NewRequest("GET", "/v1/runs", nil)
+		params.XDispatchOrg = testOrgID
+		responder := api.RunnerGetRunsHandler.Handle(params, "testcookie")
+		var respBody []v1.Run
+		helpers.HandlerRequest(t, responder, &respBody, 200)
+		var names []string
+		for _, run := range respBody {
+			names = append(names, run.Name.String())
+		}
+		sort.Strings(names)
+		assert.Equal(t, tc.runs, names)
+	}
+}
+
+func TestHandlers_getRunStats(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	handlers := &Handlers{
+		Store: store,
+	}
+	for i := 1; i <= 100; i++ {
+		var errorType v1.ErrorType
+		if i%4 == 0 {
+			errorType = v1.ErrorTypeFunctionError
+		}
+		addFinishedRun(t, store, "hello", time.Duration(i)*time.Millisecond, errorType)
+	}
+	addFinishedRun(t, store, "bye", time.Second, "")
+
+	api := operations.NewFunctionManagerAPI(nil)
+	handlers.ConfigureHandlers(api)
+
+	params := fnrunner.GetRunStatsParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/runs/stats", nil),
+		XDispatchOrg: testOrgID,
+	}
+	responder := api.RunnerGetRunStatsHandler.Handle(params, "testcookie")
+	var respBody []v1.RunStats
+	helpers.HandlerRequest(t, responder, &respBody, 200)
+
+	require.Len(t, respBody, 2)
+	assert.Equal(t, v1.RunStats{FunctionName: "bye", Count: 1, P50Duration: 1000, P95Duration: 1000, P99Duration: 1000}, respBody[0])
+	assert.Equal(t, v1.RunStats{
+		FunctionName: "hello",
+		Count:        100,
+		ErrorCount:   25,
+		ErrorRate:    0.25,
+		P50Duration:  50,
+		P95Duration:  95,
+		P99Duration:  99,
+	}, respBody[1])
+}
@@ -0,0 +1,438 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// maxTransitions bounds the number of states a single branch may go through, so that a loop between choice
+// states cannot run forever
+const maxTransitions = 1000
+
+// TaskRunner runs the function of a task state
+type TaskRunner interface {
+	// RunTask executes the function with the given input on behalf of the workflow run, it returns the name of
+	// the function run and its output
+	RunTask(ctx context.Context, run *entities.WorkflowRun, function string, input interface{}) (string, interface{}, error)
+}
+
+// waitError is returned by the engine when the workflow run must wait, for a wait state or before retrying a task,
+// the run is resumed by executing it again once the time has come
+type waitError struct {
+	until time.Time
+}
+
+func (e *waitError) Error() string {
+	return fmt.Sprintf("waiting until %s", e.until.Format(time.RFC3339))
+}
+
+// waitingUntil returns when a run waiting with the error must be resumed
+func waitingUntil(err error) (time.Time, bool) {
+	if w, ok := err.(*waitError); ok {
+		return w.until, true
+	}
+	return time.Time{}, false
+}
+
+// recordError is returned by the engine when a step can't be recorded in the workflow run. It is never caught by a
+// state, as executing the workflow further could execute states again once the run is resumed.
+type recordError struct {
+	error
+}
+
+func isRecordError(err error) bool {
+	_, ok := errors.Cause(err).(*recordError)
+	return ok
+}
+
+// Engine executes workflows as state machines, persisting every step of the execution in the workflow run
+type Engine struct {
+	store  entitystore.EntityStore
+	runner TaskRunner
+}
+
+// NewEngine creates a new workflow engine
+func NewEngine(store entitystore.EntityStore, runner TaskRunner) *Engine {
+	return &Engine{
+		store:  store,
+		runner: runner,
+	}
+}
+
+// execution holds the state of a single execution of a workflow run
+type execution struct {
+	run *entities.WorkflowRun
+
+	// stepsLock guards the steps of the run, which are updated concurrently by parallel branches
+	stepsLock sync.Mutex
+	// recorded holds the steps recorded by previous executions of the run by branch, they are replayed in order
+	// instead of executing their states again
+	recorded map[string][]*v1.WorkflowStep
+}
+
+// Execute runs the workflow with the input of the run and returns the output of the last state. A run which was
+// interrupted or had to wait is resumed by executing it again: the steps it already finished are replayed from the
+// run. A *waitError is returned when the run has to wait.
+func (e *Engine) Execute(ctx context.Context, workflow *entities.Workflow, run *entities.WorkflowRun) (interface{}, error) {
+	x := &execution{
+		run:      run,
+		recorded: make(map[string][]*v1.WorkflowStep),
+	}
+	for _, step := range run.Steps {
+		x.recorded[step.Branch] = append(x.recorded[step.Branch], step)
+	}
+	return e.execute(ctx, x, "", workflow.StartAt, workflow.States, run.Input)
+}
+
+func (e *Engine) execute(ctx context.Context, x *execution, branch string, startAt string, states []*v1.WorkflowState, input interface{}) (interface{}, error) {
+	byName := make(map[string]*v1.WorkflowState)
+	for _, s := range states {
+		byName[*s.Name] = s
+	}
+
+	current := startAt
+	for i := 0; i < maxTransitions; i++ {
+		state, ok := byName[current]
+		if !ok {
+			return nil, errors.Errorf("state %s not found", current)
+		}
+		output, next, err := e.executeState(ctx, x, branch, state, input)
+		if err != nil {
+			if _, waiting := waitingUntil(err); waiting || isRecordError(err) || state.Catch == "" || *state.Type == v1.WorkflowStateTypeFail {
+				return nil, err
+			}
+			log.Debugf("workflow run %s: state %s failed, continuing with %s: %s", x.run.Name, *state.Name, state.Catch, err)
+			output = map[string]interface{}{
+				"error": err.Error(),
+				"input": input,
+			}
+			next = state.Catch
+		}
+		if next == "" {
+			return output, nil
+		}
+		current = next
+		input = output
+	}
+	return nil, errors.Errorf("exceeded maximum number of %d state transitions", maxTransitions)
+}
+
+// executeState executes a single state, and returns its output and the name of the next state (empty if the
+// state ends the branch)
+func (e *Engine) executeState(ctx context.Context, x *execution, branch string, state *v1.WorkflowState, input interface{}) (interface{}, string, error) {
+	next := state.Next
+	if state.End || *state.Type == v1.WorkflowStateTypeSucceed || *state.Type == v1.WorkflowStateTypeFail {
+		next = ""
+	}
+
+	if *state.Type == v1.WorkflowStateTypeTask {
+		output, err := e.executeTask(ctx, x, branch, state, input)
+		return output, next, err
+	}
+
+	step, finished, err := e.startStep(ctx, x, branch, state, input, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	if finished {
+		if *state.Type == v1.WorkflowStateTypeChoice {
+			choice, err := evaluateChoices(state, input)
+			return step.Output, choice, err
+		}
+		return step.Output, next, stepError(step)
+	}
+
+	switch *state.Type {
+	case v1.WorkflowStateTypeParallel:
+		output, err := e.executeParallel(ctx, x, branch, state, input)
+		if _, waiting := waitingUntil(err); waiting {
+			return nil, "", err
+		}
+		return output, next, e.finishStep(ctx, x, step, output, err)
+
+	case v1.WorkflowStateTypeChoice:
+		choice, err := evaluateChoices(state, input)
+		return input, choice, e.finishStep(ctx, x, step, input, err)
+
+	case v1.WorkflowStateTypeWait:
+		until := time.Unix(step.StartedTime, 0).Add(time.Duration(state.Seconds) * time.Second)
+		if time.Now().Before(until) {
+			return nil, "", &waitError{until: until}
+		}
+		return input, next, e.finishStep(ctx, x, step, input, nil)
+
+	case v1.WorkflowStateTypeSucceed:
+		return input, next, e.finishStep(ctx, x, step, input, nil)
+
+	case v1.WorkflowStateTypeFail:
+		err := errors.New(state.Error)
+		if state.Error == "" {
+			err = errors.Errorf("workflow failed in state %s", *state.Name)
+		}
+		return nil, next, e.finishStep(ctx, x, step, nil, err)
+	}
+	return nil, "", errors.Errorf("unknown type %s of state %s", *state.Type, *state.Name)
+}
+
+func (e *Engine) executeTask(ctx context.Context, x *execution, branch string, state *v1.WorkflowState, input interface{}) (interface{}, error) {
+	attempts := int64(1)
+	interval := time.Second
+	backoff := 2.0
+	if state.Retry != nil {
+		if state.Retry.MaxAttempts > 0 {
+			attempts = state.Retry.MaxAttempts
+		}
+		if state.Retry.IntervalSeconds > 0 {
+			interval = time.Duration(state.Retry.IntervalSeconds) * time.Second
+		}
+		if state.Retry.BackoffRate >= 1 {
+			backoff = state.Retry.BackoffRate
+		}
+	}
+
+	var step *v1.WorkflowStep
+	for attempt := int64(1); attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			delay := time.Duration(float64(interval) * math.Pow(backoff, float64(attempt-2)))
+			if until := time.Unix(step.FinishedTime, 0).Add(delay); time.Now().Before(until) && !e.recorded(x, branch, state, attempt) {
+				return nil, &waitError{until: until}
+			}
+		}
+		var finished bool
+		var err error
+		if step, finished, err = e.startStep(ctx, x, branch, state, input, attempt); err != nil {
+			return nil, err
+		}
+		if !finished {
+			fnRun, output, err := e.runner.RunTask(ctx, x.run, state.Function, input)
+			x.stepsLock.Lock()
+			step.FunctionRun = fnRun
+			x.stepsLock.Unlock()
+			if err = e.finishStep(ctx, x, step, output, err); isRecordError(err) {
+				return nil, err
+			}
+		}
+		if step.Status == v1.StatusREADY {
+			return step.Output, nil
+		}
+		log.Debugf("workflow run %s: attempt %d of state %s failed: %s", x.run.Name, attempt, *state.Name, step.Error)
+	}
+	return nil, stepError(step)
+}
+
+func (e *Engine) executeParallel(ctx context.Context, x *execution, branch string, state *v1.WorkflowState, input interface{}) (interface{}, error) {
+	outputs := make([]interface{}, len(state.Branches))
+	errs := make([]error, len(state.Branches))
+
+	var wg sync.WaitGroup
+	for i, b := range state.Branches {
+		wg.Add(1)
+		go func(i int, b *v1.WorkflowBranch) {
+			defer wg.Done()
+			name := fmt.Sprintf("%s[%d]", *state.Name, i)
+			if branch != "" {
+				name = branch + "/" + name
+			}
+			outputs[i], errs[i] = e.execute(ctx, x, name, *b.StartAt, b.States, input)
+		}(i, b)
+	}
+	wg.Wait()
+
+	// the run waits for the earliest waiting branch, unless another branch failed
+	var wait *waitError
+	for i, err := range errs {
+		if until, waiting := waitingUntil(err); waiting {
+			if wait == nil || until.Before(wait.until) {
+				wait = err.(*waitError)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "branch %d of state %s failed", i, *state.Name)
+		}
+	}
+	if wait != nil {
+		return nil, wait
+	}
+	return outputs, nil
+}
+
+// recorded returns whether the next recorded step of the branch is the given attempt of the state
+func (e *Engine) recorded(x *execution, branch string, state *v1.WorkflowState, attempt int64) bool {
+	x.stepsLock.Lock()
+	defer x.stepsLock.Unlock()
+	steps := x.recorded[branch]
+	return len(steps) > 0 && steps[0].State == *state.Name && steps[0].Attempt == attempt
+}
+
+// startStep records the start of a state execution in the workflow run. The step recorded by a previous execution
+// is returned instead if there is one, finished is true if its state doesn't need to be executed again.
+func (e *Engine) startStep(ctx context.Context, x *execution, branch string, state *v1.WorkflowState, input interface{}, attempt int64) (step *v1.WorkflowStep, finished bool, err error) {
+	x.stepsLock.Lock()
+	defer x.stepsLock.Unlock()
+	if steps := x.recorded[branch]; len(steps) > 0 {
+		if steps[0].State == *state.Name && steps[0].Attempt == attempt {
+			x.recorded[branch] = steps[1:]
+			return steps[0], steps[0].Status != v1.StatusCREATING, nil
+		}
+		// the workflow changed since the steps were recorded, the rest of the branch is executed again
+		log.Warnf("workflow run %s: state %s doesn't match the recorded step %s", x.run.Name, *state.Name, steps[0].State)
+		delete(x.recorded, branch)
+	}
+	step = &v1.WorkflowStep{
+		State:       *state.Name,
+		Type:        *state.Type,
+		Branch:      branch,
+		Attempt:     attempt,
+		Input:       input,
+		Status:      v1.StatusCREATING,
+		StartedTime: time.Now().Unix(),
+	}
+	x.run.Steps = append(x.run.Steps, step)
+	if err := e.record(ctx, x); err != nil {
+		return nil, false, err
+	}
+	return step, false, nil
+}
+
+// finishStep records the result of a state execution in the workflow run, it returns the error of the execution, or
+// a *recordError if the result can't be recorded
+func (e *Engine) finishStep(ctx context.Context, x *execution, step *v1.WorkflowStep, output interface{}, err error) error {
+	x.stepsLock.Lock()
+	defer x.stepsLock.Unlock()
+	step.FinishedTime = time.Now().Unix()
+	step.Output = output
+	step.Status = v1.StatusREADY
+	if err != nil {
+		step.Status = v1.StatusERROR
+		step.Error = err.Error()
+	}
+	if recordErr := e.record(ctx, x); recordErr != nil {
+		return recordErr
+	}
+	return err
+}
+
+// record stores the steps of the workflow run, the caller holding the steps lock
+func (e *Engine) record(ctx context.Context, x *execution) error {
+	if _, err := e.store.Update(ctx, x.run.Revision, x.run); err != nil {
+		return &recordError{errors.Wrapf(err, "error recording the steps of workflow run %s", x.run.Name)}
+	}
+	return nil
+}
+
+// stepError returns the error of a finished step, nil if it succeeded
+func stepError(step *v1.WorkflowStep) error {
+	if step.Status != v1.StatusERROR {
+		return nil
+	}
+	return errors.New(step.Error)
+}
+
+// evaluateChoices returns the next state of the first matching choice, or the default state
+func evaluateChoices(state *v1.WorkflowState, input interface{}) (string, error) {
+	for _, c := range state.Choices {
+		value, ok := utils.LookupPath(input, *c.Variable)
+		if !ok {
+			continue
+		}
+		matched, err := utils.Compare(value, *c.Operator, c.Value)
+		if err != nil {
+			return "", errors.Wrapf(err, "error evaluating %s in state %s", *c.Variable, *state.Name)
+		}
+		if matched {
+			return *c.Next, nil
+		}
+	}
+	if state.Default == "" {
+		return "", errors.Errorf("no choice matched in state %s", *state.Name)
+	}
+	return state.Default, nil
+}
+
+// Validate checks that the states of the workflow form a valid state machine
+func Validate(workflow *entities.Workflow) error {
+	return validateStates(workflow.StartAt, workflow.States)
+}
+
+func validateStates(startAt string, states []*v1.WorkflowState) error {
+	if len(states) == 0 {
+		return errors.New("no states defined")
+	}
+	names := make(map[string]bool)
+	for _, s := range states {
+		if names[*s.Name] {
+			return errors.Errorf("duplicate state %s", *s.Name)
+		}
+		names[*s.Name] = true
+	}
+	if !names[startAt] {
+		return errors.Errorf("start state %s not found", startAt)
+	}
+
+	transition := func(state *v1.WorkflowState, field, next string) error {
+		if next != "" && !names[next] {
+			return errors.Errorf("%s state %s of state %s not found", field, next, *state.Name)
+		}
+		return nil
+	}
+
+	for _, s := range states {
+		if err := transition(s, "next", s.Next); err != nil {
+			return err
+		}
+		if err := transition(s, "catch", s.Catch); err != nil {
+			return err
+		}
+		switch *s.Type {
+		case v1.WorkflowStateTypeTask:
+			if s.Function == "" {
+				return errors.Errorf("task state %s has no function", *s.Name)
+			}
+		case v1.WorkflowStateTypeParallel:
+			if len(s.Branches) == 0 {
+				return errors.Errorf("parallel state %s has no branches", *s.Name)
+			}
+			for i, b := range s.Branches {
+				if err := validateStates(*b.StartAt, b.States); err != nil {
+					return errors.Wrapf(err, "branch %d of state %s", i, *s.Name)
+				}
+			}
+		case v1.WorkflowStateTypeChoice:
+			if len(s.Choices) == 0 {
+				return errors.Errorf("choice state %s has no choices", *s.Name)
+			}
+			for _, c := range s.Choices {
+				if err := transition(s, "choice", *c.Next); err != nil {
+					return err
+				}
+			}
+			if err := transition(s, "default", s.Default); err != nil {
+				return err
+			}
+			continue
+		case v1.WorkflowStateTypeSucceed, v1.WorkflowStateTypeFail:
+			continue
+		}
+		if s.Next == "" && !s.End {
+			return errors.Errorf("state %s has neither next state nor end", *s.Name)
+		}
+	}
+	return nil
+}
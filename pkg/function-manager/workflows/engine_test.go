@@ -0,0 +1,331 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package workflows
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+// fakeRunner runs tasks with plain go functions, keyed by function name
+type fakeRunner struct {
+	sync.Mutex
+	functions map[string]func(input interface{}) (interface{}, error)
+	calls     map[string]int
+}
+
+func (r *fakeRunner) RunTask(ctx context.Context, run *entities.WorkflowRun, function string, input interface{}) (string, interface{}, error) {
+	r.Lock()
+	if r.calls == nil {
+		r.calls = make(map[string]int)
+	}
+	r.calls[function]++
+	r.Unlock()
+	output, err := r.functions[function](input)
+	return function + "-run", output, err
+}
+
+func state(name, typ string) *v1.WorkflowState {
+	return &v1.WorkflowState{Name: swag.String(name), Type: swag.String(typ)}
+}
+
+func task(name, function, next string) *v1.WorkflowState {
+	s := state(name, v1.WorkflowStateTypeTask)
+	s.Function = function
+	s.Next = next
+	s.End = next == ""
+	return s
+}
+
+func executeWorkflow(t *testing.T, runner TaskRunner, w *entities.Workflow, input interface{}) (*entities.WorkflowRun, interface{}, error) {
+	es := helpers.MakeEntityStore(t)
+	run := &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run",
+			OrganizationID: testOrgID,
+		},
+		WorkflowName: w.Name,
+		Input:        input,
+	}
+	_, err := es.Add(context.Background(), run)
+	require.NoError(t, err)
+	require.NoError(t, Validate(w))
+
+	// waiting runs are executed again once the wait is over, like the run entity handler does
+	engine := NewEngine(es, runner)
+	for {
+		output, err := engine.Execute(context.Background(), w, run)
+		until, waiting := waitingUntil(err)
+		if !waiting {
+			return run, output, err
+		}
+		time.Sleep(time.Until(until))
+	}
+}
+
+func TestEngineSequence(t *testing.T) {
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"double": func(input interface{}) (interface{}, error) {
+			return map[string]interface{}{"value": input.(map[string]interface{})["value"].(float64) * 2}, nil
+		},
+	}}
+	w := &entities.Workflow{
+		StartAt: "first",
+		States: []*v1.WorkflowState{
+			task("first", "double", "second"),
+			task("second", "double", ""),
+		},
+	}
+	run, output, err := executeWorkflow(t, runner, w, map[string]interface{}{"value": 1.0})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"value": 4.0}, output)
+	require.Len(t, run.Steps, 2)
+	assert.Equal(t, "first", run.Steps[0].State)
+	assert.Equal(t, "double-run", run.Steps[0].FunctionRun)
+	assert.Equal(t, v1.StatusREADY, run.Steps[1].Status)
+	assert.Equal(t, map[string]interface{}{"value": 2.0}, run.Steps[1].Input)
+}
+
+func TestEngineChoice(t *testing.T) {
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"small": func(input interface{}) (interface{}, error) { return "small", nil },
+		"large": func(input interface{}) (interface{}, error) { return "large", nil },
+	}}
+	choice := state("check", v1.WorkflowStateTypeChoice)
+	choice.Choices = []*v1.WorkflowChoice{
+		{Variable: swag.String("order.total"), Operator: swag.String(">="), Value: 100, Next: swag.String("large")},
+	}
+	choice.Default = "small"
+	w := &entities.Workflow{
+		StartAt: "check",
+		States: []*v1.WorkflowState{
+			choice,
+			task("small", "small", ""),
+			task("large", "large", ""),
+		},
+	}
+
+	_, output, err := executeWorkflow(t, runner, w, map[string]interface{}{"order": map[string]interface{}{"total": 150.0}})
+	require.NoError(t, err)
+	assert.Equal(t, "large", output)
+
+	_, output, err = executeWorkflow(t, runner, w, map[string]interface{}{"order": map[string]interface{}{"total": 10.0}})
+	require.NoError(t, err)
+	assert.Equal(t, "small", output)
+}
+
+func TestEngineParallel(t *testing.T) {
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"a": func(input interface{}) (interface{}, error) { return "a", nil },
+		"b": func(input interface{}) (interface{}, error) { return "b", nil },
+	}}
+	parallel := state("both", v1.WorkflowStateTypeParallel)
+	parallel.Branches = []*v1.WorkflowBranch{
+		{StartAt: swag.String("a"), States: []*v1.WorkflowState{task("a", "a", "")}},
+		{StartAt: swag.String("b"), States: []*v1.WorkflowState{task("b", "b", "")}},
+	}
+	parallel.End = true
+	w := &entities.Workflow{
+		StartAt: "both",
+		States:  []*v1.WorkflowState{parallel},
+	}
+
+	run, output, err := executeWorkflow(t, runner, w, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, output)
+	require.Len(t, run.Steps, 3)
+	branches := map[string]bool{}
+	for _, s := range run.Steps[1:] {
+		branches[s.Branch] = true
+	}
+	assert.Equal(t, map[string]bool{"both[0]": true, "both[1]": true}, branches)
+}
+
+func TestEngineRetryAndCatch(t *testing.T) {
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"flaky":   func(input interface{}) (interface{}, error) { return nil, errors.New("boom") },
+		"recover": func(input interface{}) (interface{}, error) { return input.(map[string]interface{})["error"], nil },
+	}}
+	flaky := task("flaky", "flaky", "")
+	flaky.Retry = &v1.WorkflowRetry{MaxAttempts: 2, IntervalSeconds: 0, BackoffRate: 1}
+	flaky.Catch = "recover"
+	w := &entities.Workflow{
+		StartAt: "flaky",
+		States:  []*v1.WorkflowState{flaky, task("recover", "recover", "")},
+	}
+
+	run, output, err := executeWorkflow(t, runner, w, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "boom", output)
+	assert.Equal(t, 2, runner.calls["flaky"])
+	require.Len(t, run.Steps, 3)
+	assert.Equal(t, int64(2), run.Steps[1].Attempt)
+	assert.Equal(t, v1.StatusERROR, run.Steps[1].Status)
+	assert.Equal(t, "boom", run.Steps[1].Error)
+}
+
+func TestEngineFail(t *testing.T) {
+	fail := state("fail", v1.WorkflowStateTypeFail)
+	fail.Error = "invalid order"
+	w := &entities.Workflow{
+		StartAt: "wait",
+		States: []*v1.WorkflowState{
+			{Name: swag.String("wait"), Type: swag.String(v1.WorkflowStateTypeWait), Next: "fail"},
+			fail,
+		},
+	}
+
+	run, _, err := executeWorkflow(t, &fakeRunner{}, w, nil)
+	assert.EqualError(t, err, "invalid order")
+	require.Len(t, run.Steps, 2)
+	assert.Equal(t, v1.StatusERROR, run.Steps[1].Status)
+}
+
+// failingStore fails the updates of the workflow run
+type failingStore struct {
+	entitystore.EntityStore
+}
+
+func (s *failingStore) Update(ctx context.Context, lastRevision uint64, entity entitystore.Entity) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func TestEngineRecordError(t *testing.T) {
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"hello": func(input interface{}) (interface{}, error) { return "hello", nil },
+	}}
+	hello := task("hello", "hello", "")
+	hello.Catch = "hello"
+	w := &entities.Workflow{StartAt: "hello", States: []*v1.WorkflowState{hello}}
+	run := &entities.WorkflowRun{BaseEntity: entitystore.BaseEntity{Name: "run", OrganizationID: testOrgID}}
+
+	// a step which can't be recorded isn't executed, and the error isn't caught
+	engine := NewEngine(&failingStore{helpers.MakeEntityStore(t)}, runner)
+	_, err := engine.Execute(context.Background(), w, run)
+	assert.Error(t, err)
+	assert.True(t, isRecordError(err))
+	assert.Equal(t, 0, runner.calls["hello"])
+}
+
+func TestValidate(t *testing.T) {
+	w := &entities.Workflow{
+		StartAt: "missing",
+		States:  []*v1.WorkflowState{task("first", "f", "")},
+	}
+	assert.Error(t, Validate(w))
+
+	w = &entities.Workflow{
+		StartAt: "first",
+		States:  []*v1.WorkflowState{task("first", "f", "second")},
+	}
+	assert.Error(t, Validate(w))
+
+	w = &entities.Workflow{
+		StartAt: "first",
+		States:  []*v1.WorkflowState{task("first", "", "")},
+	}
+	assert.Error(t, Validate(w))
+
+	w = &entities.Workflow{
+		StartAt: "first",
+		States:  []*v1.WorkflowState{task("first", "f", "")},
+	}
+	assert.NoError(t, Validate(w))
+}
+
+func TestEngineWait(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	wait := state("wait", v1.WorkflowStateTypeWait)
+	wait.Seconds = 60
+	wait.End = true
+	w := &entities.Workflow{
+		StartAt: "wait",
+		States:  []*v1.WorkflowState{wait},
+	}
+	run := &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run",
+			OrganizationID: testOrgID,
+		},
+		Input: "input",
+	}
+	_, err := es.Add(context.Background(), run)
+	require.NoError(t, err)
+
+	// the engine returns instead of blocking until the end of the wait
+	engine := NewEngine(es, &fakeRunner{})
+	_, err = engine.Execute(context.Background(), w, run)
+	until, waiting := waitingUntil(err)
+	require.True(t, waiting)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), until, 2*time.Second)
+	require.Len(t, run.Steps, 1)
+	assert.Equal(t, v1.StatusCREATING, run.Steps[0].Status)
+
+	_, err = engine.Execute(context.Background(), w, run)
+	_, waiting = waitingUntil(err)
+	assert.True(t, waiting)
+	assert.Len(t, run.Steps, 1)
+
+	// the wait is over
+	run.Steps[0].StartedTime -= 60
+	output, err := engine.Execute(context.Background(), w, run)
+	require.NoError(t, err)
+	assert.Equal(t, "input", output)
+	require.Len(t, run.Steps, 1)
+	assert.Equal(t, v1.StatusREADY, run.Steps[0].Status)
+}
+
+func TestEngineResume(t *testing.T) {
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"double": func(input interface{}) (interface{}, error) {
+			return map[string]interface{}{"value": input.(map[string]interface{})["value"].(float64) * 2}, nil
+		},
+	}}
+	w := &entities.Workflow{
+		StartAt: "first",
+		States: []*v1.WorkflowState{
+			task("first", "double", "second"),
+			task("second", "double", "third"),
+			task("third", "double", ""),
+		},
+	}
+	es := helpers.MakeEntityStore(t)
+	// the run was interrupted while executing the second state
+	run := &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusCREATING,
+		},
+		Input: map[string]interface{}{"value": 1.0},
+		Steps: []*v1.WorkflowStep{
+			{State: "first", Type: v1.WorkflowStateTypeTask, Attempt: 1, Status: v1.StatusREADY, Output: map[string]interface{}{"value": 2.0}},
+			{State: "second", Type: v1.WorkflowStateTypeTask, Attempt: 1, Status: v1.StatusCREATING},
+		},
+	}
+	_, err := es.Add(context.Background(), run)
+	require.NoError(t, err)
+
+	output, err := NewEngine(es, runner).Execute(context.Background(), w, run)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"value": 8.0}, output)
+	assert.Equal(t, 2, runner.calls["double"])
+	require.Len(t, run.Steps, 3)
+	assert.Equal(t, v1.StatusREADY, run.Steps[1].Status)
+	assert.Equal(t, "third", run.Steps[2].State)
+}
@@ -0,0 +1,108 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package entities
+
+import (
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// NO TESTS
+
+// Workflow struct represents a state machine composing functions
+type Workflow struct {
+	entitystore.BaseEntity
+	StartAt string              `json:"startAt"`
+	States  []*v1.WorkflowState `json:"states"`
+}
+
+// ToModel converts workflow to swagger model
+func (w *Workflow) ToModel() *v1.Workflow {
+	var tags []*v1.Tag
+	for k, v := range w.Tags {
+		tags = append(tags, &v1.Tag{Key: k, Value: v})
+	}
+	m := v1.Workflow{
+		Name:         swag.String(w.Name),
+		Kind:         utils.WorkflowKind,
+		ID:           strfmt.UUID(w.ID),
+		StartAt:      swag.String(w.StartAt),
+		States:       w.States,
+		Status:       v1.Status(w.Status),
+		Reason:       w.Reason,
+		CreatedTime:  w.CreatedTime.Unix(),
+		ModifiedTime: w.ModifiedTime.Unix(),
+		Tags:         tags,
+	}
+	return &m
+}
+
+// FromModel builds workflow based on swagger model
+func (w *Workflow) FromModel(m *v1.Workflow, orgID string) {
+	tags := make(map[string]string)
+	for _, t := range m.Tags {
+		tags[t.Key] = t.Value
+	}
+	w.BaseEntity.OrganizationID = orgID
+	w.BaseEntity.Name = *m.Name
+	w.BaseEntity.Status = entitystore.Status(m.Status)
+	w.BaseEntity.Tags = tags
+	w.StartAt = *m.StartAt
+	w.States = m.States
+}
+
+// WorkflowRun struct represents a single execution of a workflow, recording every step of the state machine
+type WorkflowRun struct {
+	entitystore.BaseEntity
+	WorkflowName string             `json:"workflowName"`
+	Input        interface{}        `json:"input,omitempty"`
+	Output       interface{}        `json:"output,omitempty"`
+	Error        string             `json:"error,omitempty"`
+	Steps        []*v1.WorkflowStep `json:"steps,omitempty"`
+	FinishedTime time.Time          `json:"finishedTime,omitempty"`
+}
+
+// ToModel converts workflow run to swagger model
+func (r *WorkflowRun) ToModel() *v1.WorkflowRun {
+	tags := []*v1.Tag{}
+	for k, v := range r.Tags {
+		tags = append(tags, &v1.Tag{Key: k, Value: v})
+	}
+	var finished int64
+	if !r.FinishedTime.IsZero() {
+		finished = r.FinishedTime.Unix()
+	}
+	return &v1.WorkflowRun{
+		Name:         strfmt.UUID(r.Name),
+		WorkflowName: r.WorkflowName,
+		ExecutedTime: r.CreatedTime.Unix(),
+		FinishedTime: finished,
+		Input:        r.Input,
+		Output:       r.Output,
+		Error:        r.Error,
+		Steps:        r.Steps,
+		Status:       v1.Status(r.Status),
+		Reason:       r.Reason,
+		Tags:         tags,
+	}
+}
+
+// FromModel builds workflow run based on swagger model
+func (r *WorkflowRun) FromModel(m *v1.WorkflowRun, orgID string) {
+	tags := make(map[string]string)
+	for _, t := range m.Tags {
+		tags[t.Key] = t.Value
+	}
+	r.BaseEntity.OrganizationID = orgID
+	r.BaseEntity.Tags = tags
+	r.Input = m.Input
+}
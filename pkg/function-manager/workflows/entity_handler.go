@@ -0,0 +1,289 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package workflows
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	ewrapper "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
+	"github.com/vmware/dispatch/pkg/trace"
+)
+
+// Only return entities in INITIALIZED, UPDATING or DELETING status
+func syncFilter(resyncPeriod time.Duration) entitystore.Filter {
+	now := time.Now().Add(-resyncPeriod)
+	return entitystore.FilterEverything().Add(
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "ModifiedTime",
+			Verb:    entitystore.FilterVerbBefore,
+			Object:  now,
+		},
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbIn,
+			Object: []entitystore.Status{
+				entitystore.StatusINITIALIZED, entitystore.StatusUPDATING, entitystore.StatusDELETING,
+			},
+		})
+}
+
+// Only return workflow runs in INITIALIZED or CREATING status, CREATING runs were interrupted or are waiting
+func runSyncFilter(resyncPeriod time.Duration) entitystore.Filter {
+	now := time.Now().Add(-resyncPeriod)
+	return entitystore.FilterEverything().Add(
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "ModifiedTime",
+			Verb:    entitystore.FilterVerbBefore,
+			Object:  now,
+		},
+		entitystore.FilterStat{
+			Scope:   entitystore.FilterScopeField,
+			Subject: "Status",
+			Verb:    entitystore.FilterVerbIn,
+			Object: []entitystore.Status{
+				entitystore.StatusINITIALIZED, entitystore.StatusCREATING,
+			},
+		})
+}
+
+// EntityHandler handles Workflow entity operations
+type EntityHandler struct {
+	store entitystore.EntityStore
+}
+
+// NewEntityHandler returns new instance of EntityHandler
+func NewEntityHandler(store entitystore.EntityStore) *EntityHandler {
+	return &EntityHandler{
+		store: store,
+	}
+}
+
+// Type returns entity handler type
+func (h *EntityHandler) Type() reflect.Type {
+	return reflect.TypeOf(&entities.Workflow{})
+}
+
+// Add handles adding new workflow entity
+func (h *EntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	w := obj.(*entities.Workflow)
+	defer func() { h.store.UpdateWithError(ctx, w, err) }()
+
+	if err := Validate(w); err != nil {
+		return ewrapper.Wrapf(err, "invalid workflow %s", w.Name)
+	}
+
+	w.Status = entitystore.StatusREADY
+
+	log.Infof("workflow %s is ready", w.Name)
+
+	return nil
+}
+
+// Update handles workflow entity update
+func (h *EntityHandler) Update(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	return h.Add(ctx, obj)
+}
+
+// Delete handles workflow entity deletion, runs of the workflow are deleted as well
+func (h *EntityHandler) Delete(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	w := obj.(*entities.Workflow)
+
+	runs, err := getFilteredRuns(ctx, h.store, w.OrganizationID, &w.Name, nil)
+	if err != nil {
+		return ewrapper.Wrapf(err, "store error listing runs for workflow %s", w.Name)
+	}
+	for _, r := range runs {
+		if err := h.store.Delete(ctx, w.OrganizationID, r.Name, r); err != nil {
+			return ewrapper.Wrap(err, "store error when deleting workflow run")
+		}
+	}
+
+	// hard deletion
+	if err := h.store.Delete(ctx, w.OrganizationID, w.Name, w); err != nil {
+		return ewrapper.Wrap(err, "store error when deleting workflow")
+	}
+	log.Infof("workflow %s deleted from the entity store", w.Name)
+	return nil
+}
+
+// Sync returns the list of workflow entities which must be resolved
+func (h *EntityHandler) Sync(ctx context.Context, resyncPeriod time.Duration) ([]entitystore.Entity, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	return controller.DefaultSync(ctx, h.store, h.Type(), resyncPeriod, syncFilter(resyncPeriod))
+}
+
+// Error handles error state
+func (h *EntityHandler) Error(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	log.Errorf("handleError func not implemented yet")
+	return nil
+}
+
+// RunEntityHandler handles WorkflowRun entity operations, executing workflows
+type RunEntityHandler struct {
+	store   entitystore.EntityStore
+	engine  *Engine
+	watcher controller.Watcher
+
+	// active holds the IDs of the runs being executed or waiting to be resumed, so that resyncing doesn't execute
+	// them twice
+	active     map[string]bool
+	activeLock sync.Mutex
+}
+
+// NewRunEntityHandler returns new instance of RunEntityHandler running tasks with the given runner, waiting runs are
+// resumed through the watcher
+func NewRunEntityHandler(store entitystore.EntityStore, runner TaskRunner, watcher controller.Watcher) *RunEntityHandler {
+	return &RunEntityHandler{
+		store:   store,
+		engine:  NewEngine(store, runner),
+		watcher: watcher,
+		active:  make(map[string]bool),
+	}
+}
+
+// Type returns entity handler type
+func (h *RunEntityHandler) Type() reflect.Type {
+	return reflect.TypeOf(&entities.WorkflowRun{})
+}
+
+// Add executes the workflow of a new workflow run, or resumes an interrupted or waiting one
+func (h *RunEntityHandler) Add(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	run := obj.(*entities.WorkflowRun)
+	if !h.activate(run) {
+		log.Debugf("workflow run %s is already executing", run.Name)
+		return nil
+	}
+	defer func() {
+		if until, waiting := waitingUntil(err); waiting {
+			log.Debugf("workflow run %s is waiting until %s", run.Name, until)
+			h.store.UpdateWithError(ctx, run, nil)
+			h.resumeAt(run, until)
+			err = nil
+			return
+		}
+		h.deactivate(run)
+		run.FinishedTime = time.Now()
+		if err != nil {
+			run.Error = err.Error()
+		}
+		h.store.UpdateWithError(ctx, run, err)
+	}()
+
+	run.Status = entitystore.StatusCREATING
+	h.store.UpdateWithError(ctx, run, nil)
+
+	w := new(entities.Workflow)
+	if err = h.store.Get(ctx, run.OrganizationID, run.WorkflowName, entitystore.Options{}, w); err != nil {
+		return ewrapper.Wrapf(err, "error getting workflow from store: '%s'", run.WorkflowName)
+	}
+
+	run.Output, err = h.engine.Execute(ctx, w, run)
+	if _, waiting := waitingUntil(err); waiting {
+		return err
+	}
+	if err != nil {
+		return ewrapper.Wrapf(err, "error running workflow: %s", run.WorkflowName)
+	}
+
+	run.Status = entitystore.StatusREADY
+
+	return nil
+}
+
+// activate marks the run as executing, it returns false if it already is
+func (h *RunEntityHandler) activate(run *entities.WorkflowRun) bool {
+	h.activeLock.Lock()
+	defer h.activeLock.Unlock()
+	if h.active[run.ID] {
+		return false
+	}
+	h.active[run.ID] = true
+	return true
+}
+
+func (h *RunEntityHandler) deactivate(run *entities.WorkflowRun) {
+	h.activeLock.Lock()
+	defer h.activeLock.Unlock()
+	delete(h.active, run.ID)
+}
+
+// resumeAt hands the waiting run back to the controller at the given time, instead of holding a worker meanwhile
+func (h *RunEntityHandler) resumeAt(run *entities.WorkflowRun, until time.Time) {
+	time.AfterFunc(time.Until(until), func() {
+		h.deactivate(run)
+		resumed := new(entities.WorkflowRun)
+		if err := h.store.Get(context.Background(), run.OrganizationID, run.Name, entitystore.Options{}, resumed); err != nil {
+			log.Errorf("error getting waiting workflow run %s from store: %+v", run.Name, err)
+			return
+		}
+		h.watcher.OnAction(context.Background(), resumed)
+	})
+}
+
+// Update updates a workflow run
+func (h *RunEntityHandler) Update(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	run := obj.(*entities.WorkflowRun)
+	defer func() { h.store.UpdateWithError(ctx, run, err) }()
+	return ewrapper.Errorf("updating workflow runs not supported, workflow: '%s'", run.WorkflowName)
+}
+
+// Delete deletes a workflow run
+func (h *RunEntityHandler) Delete(ctx context.Context, obj entitystore.Entity) (err error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	run := obj.(*entities.WorkflowRun)
+	defer func() { h.store.UpdateWithError(ctx, run, err) }()
+	return ewrapper.Errorf("deleting workflow runs not supported, workflow: '%s'", run.WorkflowName)
+}
+
+// Sync returns the list of workflow run entities which must be executed or resumed
+func (h *RunEntityHandler) Sync(ctx context.Context, resyncPeriod time.Duration) ([]entitystore.Entity, error) {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	return controller.DefaultSync(ctx, h.store, h.Type(), resyncPeriod, runSyncFilter(resyncPeriod))
+}
+
+// Error handles error state
+func (h *RunEntityHandler) Error(ctx context.Context, obj entitystore.Entity) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	log.Errorf("handleError func not implemented yet")
+	return nil
+}
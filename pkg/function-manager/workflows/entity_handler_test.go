@@ -0,0 +1,167 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package workflows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func getWorkflowEntity(t *testing.T, es entitystore.EntityStore, name string) (*entities.Workflow, error) {
+	w := new(entities.Workflow)
+	err := es.Get(context.Background(), testOrgID, name, entitystore.Options{}, w)
+	return w, err
+}
+
+func addWorkflow(t *testing.T, es entitystore.EntityStore, states ...*v1.WorkflowState) *entities.Workflow {
+	w := &entities.Workflow{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "workflow",
+			Status:         entitystore.StatusINITIALIZED,
+			OrganizationID: testOrgID,
+		},
+		StartAt: *states[0].Name,
+		States:  states,
+	}
+	_, err := es.Add(context.Background(), w)
+	require.NoError(t, err)
+	return w
+}
+
+func TestWorkflowAdd(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	w := addWorkflow(t, es, task("first", "hello", ""))
+
+	assert.NoError(t, NewEntityHandler(es).Add(context.Background(), w))
+	w, err := getWorkflowEntity(t, es, "workflow")
+	require.NoError(t, err)
+	assert.Equal(t, entitystore.StatusREADY, w.Status)
+}
+
+func TestWorkflowDelete(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	w := addWorkflow(t, es, task("first", "hello", ""))
+	run := &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run",
+			OrganizationID: testOrgID,
+		},
+		WorkflowName: "workflow",
+	}
+	_, err := es.Add(context.Background(), run)
+	require.NoError(t, err)
+
+	assert.NoError(t, NewEntityHandler(es).Delete(context.Background(), w))
+	_, err = getWorkflowEntity(t, es, "workflow")
+	assert.Error(t, err)
+	var runs []*entities.WorkflowRun
+	require.NoError(t, es.List(context.Background(), testOrgID, entitystore.Options{}, &runs))
+	assert.Len(t, runs, 0)
+}
+
+func TestWorkflowRunAdd(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	addWorkflow(t, es, task("first", "hello", ""))
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"hello": func(input interface{}) (interface{}, error) { return "hello " + input.(string), nil },
+	}}
+	handler := NewRunEntityHandler(es, runner, nil)
+
+	run := &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run",
+			OrganizationID: testOrgID,
+		},
+		WorkflowName: "workflow",
+		Input:        "world",
+	}
+	_, err := es.Add(context.Background(), run)
+	require.NoError(t, err)
+
+	assert.NoError(t, handler.Add(context.Background(), run))
+	assert.Equal(t, entitystore.StatusREADY, run.Status)
+	assert.Equal(t, "hello world", run.Output)
+	assert.False(t, run.FinishedTime.IsZero())
+
+	runner.functions["hello"] = func(input interface{}) (interface{}, error) { return nil, errors.New("failed") }
+	run = &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "failing",
+			OrganizationID: testOrgID,
+		},
+		WorkflowName: "workflow",
+	}
+	_, err = es.Add(context.Background(), run)
+	require.NoError(t, err)
+
+	assert.Error(t, handler.Add(context.Background(), run))
+	stored := new(entities.WorkflowRun)
+	require.NoError(t, es.Get(context.Background(), testOrgID, "failing", entitystore.Options{}, stored))
+	assert.Equal(t, entitystore.StatusERROR, stored.Status)
+	assert.Contains(t, stored.Error, "failed")
+	assert.Len(t, stored.Steps, 1)
+}
+
+func TestWorkflowRunWait(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	wait := state("wait", v1.WorkflowStateTypeWait)
+	wait.Seconds = 1
+	wait.Next = "first"
+	addWorkflow(t, es, wait, task("first", "hello", ""))
+	runner := &fakeRunner{functions: map[string]func(interface{}) (interface{}, error){
+		"hello": func(input interface{}) (interface{}, error) { return "hello", nil },
+	}}
+	watcher := make(chan controller.WatchEvent, 1)
+	handler := NewRunEntityHandler(es, runner, watcher)
+
+	run := &entities.WorkflowRun{
+		BaseEntity: entitystore.BaseEntity{
+			Name:           "run",
+			OrganizationID: testOrgID,
+			Status:         entitystore.StatusINITIALIZED,
+		},
+		WorkflowName: "workflow",
+	}
+	_, err := es.Add(context.Background(), run)
+	require.NoError(t, err)
+
+	// the run waits without holding the worker
+	require.NoError(t, handler.Add(context.Background(), run))
+	assert.Equal(t, entitystore.StatusCREATING, run.Status)
+	assert.Equal(t, 0, runner.calls["hello"])
+
+	// waiting runs are not executed twice when resyncing
+	synced, err := handler.Sync(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, synced, 1)
+	require.NoError(t, handler.Add(context.Background(), synced[0]))
+	assert.Equal(t, 0, runner.calls["hello"])
+
+	// the run is handed back to the controller once the wait is over
+	var event controller.WatchEvent
+	select {
+	case event = <-watcher:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiting run was not resumed")
+	}
+	resumed := event.Entity.(*entities.WorkflowRun)
+	require.NoError(t, handler.Add(context.Background(), resumed))
+	assert.Equal(t, entitystore.StatusREADY, resumed.Status)
+	assert.Equal(t, "hello", resumed.Output)
+	assert.Equal(t, 1, runner.calls["hello"])
+	require.Len(t, resumed.Steps, 2)
+}
@@ -0,0 +1,291 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/controller"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	workflowapi "github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/workflow"
+	"github.com/vmware/dispatch/pkg/function-manager/workflows/entities"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+// Handlers is a base struct for workflow API handlers.
+type Handlers struct {
+	store   entitystore.EntityStore
+	watcher controller.Watcher
+}
+
+// NewHandlers Creates new instance of workflow handlers
+func NewHandlers(store entitystore.EntityStore, watcher controller.Watcher) *Handlers {
+	return &Handlers{
+		watcher: watcher,
+		store:   store,
+	}
+}
+
+// ConfigureHandlers configures API handlers for Workflow endpoints
+func (h *Handlers) ConfigureHandlers(api middleware.RoutableAPI) {
+	a, ok := api.(*operations.FunctionManagerAPI)
+	if !ok {
+		panic("Cannot configure api")
+	}
+
+	a.WorkflowAddWorkflowHandler = workflowapi.AddWorkflowHandlerFunc(h.addWorkflow)
+	a.WorkflowGetWorkflowHandler = workflowapi.GetWorkflowHandlerFunc(h.getWorkflow)
+	a.WorkflowGetWorkflowsHandler = workflowapi.GetWorkflowsHandlerFunc(h.getWorkflows)
+	a.WorkflowDeleteWorkflowHandler = workflowapi.DeleteWorkflowHandlerFunc(h.deleteWorkflow)
+	a.WorkflowRunWorkflowHandler = workflowapi.RunWorkflowHandlerFunc(h.runWorkflow)
+	a.WorkflowGetWorkflowRunHandler = workflowapi.GetWorkflowRunHandlerFunc(h.getWorkflowRun)
+	a.WorkflowGetWorkflowRunsHandler = workflowapi.GetWorkflowRunsHandlerFunc(h.getWorkflowRuns)
+}
+
+// addWorkflow handles creation of new workflows
+func (h *Handlers) addWorkflow(params workflowapi.AddWorkflowParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "addWorkflow")
+	defer span.Finish()
+
+	if err := params.Body.Validate(strfmt.Default); err != nil {
+		return workflowapi.NewAddWorkflowBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("error validating the payload: %s", err)),
+		})
+	}
+
+	w := &entities.Workflow{}
+	w.FromModel(params.Body, params.XDispatchOrg)
+	if err := Validate(w); err != nil {
+		return workflowapi.NewAddWorkflowBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("invalid workflow: %s", err)),
+		})
+	}
+	w.Status = entitystore.StatusINITIALIZED
+	_, err := h.store.Add(ctx, w)
+	if err != nil {
+		if entitystore.IsUniqueViolation(err) {
+			return workflowapi.NewAddWorkflowConflict().WithPayload(&v1.Error{
+				Code:    http.StatusConflict,
+				Message: utils.ErrorMsgAlreadyExists("workflow", w.Name),
+			})
+		}
+		log.Errorf("error when storing the workflow: %+v", err)
+		return workflowapi.NewAddWorkflowDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("workflow", w.Name),
+		})
+	}
+	h.watcher.OnAction(ctx, w)
+	return workflowapi.NewAddWorkflowCreated().WithPayload(w.ToModel())
+}
+
+// getWorkflow handles retrieval of single workflow
+func (h *Handlers) getWorkflow(params workflowapi.GetWorkflowParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getWorkflow")
+	defer span.Finish()
+
+	w := entities.Workflow{}
+	err := h.store.Get(ctx, params.XDispatchOrg, params.WorkflowName, entitystore.Options{}, &w)
+	if err != nil {
+		log.Warnf("Received GET for non-existent workflow %s", params.WorkflowName)
+		log.Debugf("store error when getting workflow: %+v", err)
+		return workflowapi.NewGetWorkflowNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("workflow", params.WorkflowName),
+			})
+	}
+	return workflowapi.NewGetWorkflowOK().WithPayload(w.ToModel())
+}
+
+// getWorkflows handles retrieval of workflow list
+func (h *Handlers) getWorkflows(params workflowapi.GetWorkflowsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getWorkflows")
+	defer span.Finish()
+
+	var workflows []*entities.Workflow
+	var err error
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+	opts.Filter, err = utils.ParseTags(opts.Filter, params.Tags)
+	if err != nil {
+		log.Errorf("error parsing tags: %s", err)
+		return workflowapi.NewGetWorkflowsBadRequest().WithPayload(
+			&v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: swag.String(err.Error()),
+			})
+	}
+
+	err = h.store.List(ctx, params.XDispatchOrg, opts, &workflows)
+	if err != nil {
+		log.Errorf("store error when listing workflows: %+v", err)
+		return workflowapi.NewGetWorkflowsDefault(http.StatusInternalServerError).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when getting workflows"),
+			})
+	}
+	workflowModels := []*v1.Workflow{}
+	for _, w := range workflows {
+		workflowModels = append(workflowModels, w.ToModel())
+	}
+	return workflowapi.NewGetWorkflowsOK().WithPayload(workflowModels)
+}
+
+// deleteWorkflow handles deletion of a workflow
+func (h *Handlers) deleteWorkflow(params workflowapi.DeleteWorkflowParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "deleteWorkflow")
+	defer span.Finish()
+
+	w := &entities.Workflow{}
+	err := h.store.Get(ctx, params.XDispatchOrg, params.WorkflowName, entitystore.Options{}, w)
+	if err != nil {
+		log.Warnf("Received DELETE for non-existent workflow %s", params.WorkflowName)
+		log.Debugf("store error when getting workflow: %+v", err)
+		return workflowapi.NewDeleteWorkflowNotFound().WithPayload(
+			&v1.Error{
+				Code:    http.StatusNotFound,
+				Message: utils.ErrorMsgNotFound("workflow", params.WorkflowName),
+			})
+	}
+	if w.Status == entitystore.StatusDELETING {
+		log.Warnf("Attempting to delete workflow %s which already is in DELETING state", w.Name)
+		return workflowapi.NewDeleteWorkflowBadRequest().WithPayload(&v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: swag.String(fmt.Sprintf("Unable to delete workflow %s: workflow is already being deleted", w.Name)),
+		})
+	}
+	w.Status = entitystore.StatusDELETING
+	if _, err = h.store.Update(ctx, w.Revision, w); err != nil {
+		log.Errorf("store error when deleting a workflow %s: %+v", w.Name, err)
+		return workflowapi.NewDeleteWorkflowDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("workflow", w.Name),
+		})
+	}
+	h.watcher.OnAction(ctx, w)
+	return workflowapi.NewDeleteWorkflowOK().WithPayload(w.ToModel())
+}
+
+// runWorkflow handles starting a new execution of a workflow
+func (h *Handlers) runWorkflow(params workflowapi.RunWorkflowParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "runWorkflow")
+	defer span.Finish()
+
+	w := &entities.Workflow{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.WorkflowName, entitystore.Options{}, w); err != nil {
+		log.Debugf("store error when getting workflow: %+v", err)
+		return workflowapi.NewRunWorkflowNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("workflow", params.WorkflowName),
+		})
+	}
+	if w.Status != entitystore.StatusREADY {
+		return workflowapi.NewRunWorkflowNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: swag.String(fmt.Sprintf("workflow %s is not READY", params.WorkflowName)),
+		})
+	}
+
+	run := &entities.WorkflowRun{}
+	if params.Body != nil {
+		run.FromModel(params.Body, params.XDispatchOrg)
+	}
+	run.OrganizationID = params.XDispatchOrg
+	run.Name = uuid.NewV4().String()
+	run.WorkflowName = w.Name
+	run.Status = entitystore.StatusINITIALIZED
+
+	if _, err := h.store.Add(ctx, run); err != nil {
+		log.Errorf("store error when adding new workflow run %s: %+v", run.Name, err)
+		return workflowapi.NewRunWorkflowDefault(500).WithPayload(&v1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: utils.ErrorMsgInternalError("workflow run", run.Name),
+		})
+	}
+	h.watcher.OnAction(ctx, run)
+	return workflowapi.NewRunWorkflowAccepted().WithPayload(run.ToModel())
+}
+
+// getWorkflowRun handles retrieval of a single workflow run
+func (h *Handlers) getWorkflowRun(params workflowapi.GetWorkflowRunParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getWorkflowRun")
+	defer span.Finish()
+
+	run := entities.WorkflowRun{}
+	if err := h.store.Get(ctx, params.XDispatchOrg, params.WorkflowRunName.String(), entitystore.Options{}, &run); err != nil {
+		log.Debugf("store error when getting workflow run: %+v", err)
+		return workflowapi.NewGetWorkflowRunNotFound().WithPayload(&v1.Error{
+			Code:    http.StatusNotFound,
+			Message: utils.ErrorMsgNotFound("workflow run", params.WorkflowRunName.String()),
+		})
+	}
+	return workflowapi.NewGetWorkflowRunOK().WithPayload(run.ToModel())
+}
+
+// getWorkflowRuns handles retrieval of workflow run list
+func (h *Handlers) getWorkflowRuns(params workflowapi.GetWorkflowRunsParams, principal interface{}) middleware.Responder {
+	span, ctx := trace.Trace(params.HTTPRequest.Context(), "getWorkflowRuns")
+	defer span.Finish()
+
+	runs, err := getFilteredRuns(ctx, h.store, params.XDispatchOrg, params.WorkflowName, nil)
+	if err != nil {
+		log.Errorf("store error when listing workflow runs: %+v", err)
+		return workflowapi.NewGetWorkflowRunsDefault(http.StatusInternalServerError).WithPayload(
+			&v1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: swag.String("internal server error when getting workflow runs"),
+			})
+	}
+	runModels := []*v1.WorkflowRun{}
+	for _, r := range runs {
+		runModels = append(runModels, r.ToModel())
+	}
+	return workflowapi.NewGetWorkflowRunsOK().WithPayload(runModels)
+}
+
+func getFilteredRuns(ctx context.Context, store entitystore.EntityStore, orgID string, workflowName *string, tags []string) ([]*entities.WorkflowRun, error) {
+	var runs []*entities.WorkflowRun
+	var err error
+	opts := entitystore.Options{
+		Filter: entitystore.FilterEverything(),
+	}
+
+	if workflowName != nil {
+		opts.Filter.Add(
+			entitystore.FilterStat{
+				Scope:   entitystore.FilterScopeExtra,
+				Subject: "WorkflowName",
+				Verb:    entitystore.FilterVerbEqual,
+				Object:  *workflowName,
+			})
+	}
+
+	opts.Filter, err = utils.ParseTags(opts.Filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = store.List(ctx, orgID, opts, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,129 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package workflows
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/function-manager/gen/restapi/operations/workflow"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+const testOrgID = "testOrg"
+
+func addWorkflowEntity(t *testing.T, api *operations.FunctionManagerAPI, name string, states []*v1.WorkflowState, status int) {
+	reqBody := &v1.Workflow{
+		Name:    swag.String(name),
+		StartAt: states[0].Name,
+		States:  states,
+	}
+	r := httptest.NewRequest("POST", "/v1/workflow", nil)
+	params := workflow.AddWorkflowParams{
+		HTTPRequest:  r,
+		Body:         reqBody,
+		XDispatchOrg: testOrgID,
+	}
+	responder := api.WorkflowAddWorkflowHandler.Handle(params, "testCookie")
+	if status == http.StatusCreated {
+		var respBody v1.Workflow
+		helpers.HandlerRequest(t, responder, &respBody, status)
+		assert.Equal(t, name, *respBody.Name)
+		return
+	}
+	var respBody v1.Error
+	helpers.HandlerRequest(t, responder, &respBody, status)
+	assert.NotEmpty(t, respBody.Message)
+}
+
+func TestWorkflowsAddWorkflowHandler(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := Handlers{es, nil}
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	addWorkflowEntity(t, api, "myworkflow", []*v1.WorkflowState{task("first", "hello", "")}, http.StatusCreated)
+	addWorkflowEntity(t, api, "myworkflow", []*v1.WorkflowState{task("first", "hello", "")}, http.StatusConflict)
+	addWorkflowEntity(t, api, "invalid", []*v1.WorkflowState{task("first", "hello", "missing")}, http.StatusBadRequest)
+
+	r := httptest.NewRequest("GET", "/v1/workflow", nil)
+	get := workflow.GetWorkflowsParams{
+		HTTPRequest:  r,
+		XDispatchOrg: testOrgID,
+	}
+	getResponder := api.WorkflowGetWorkflowsHandler.Handle(get, "testCookie")
+	var getBody []v1.Workflow
+	helpers.HandlerRequest(t, getResponder, &getBody, 200)
+	assert.Len(t, getBody, 1)
+	assert.Equal(t, "first", *getBody[0].StartAt)
+}
+
+func TestWorkflowsRunWorkflowHandler(t *testing.T) {
+	api := operations.NewFunctionManagerAPI(nil)
+	es := helpers.MakeEntityStore(t)
+	h := Handlers{es, nil}
+	helpers.MakeAPI(t, h.ConfigureHandlers, api)
+
+	addWorkflowEntity(t, api, "myworkflow", []*v1.WorkflowState{task("first", "hello", "")}, http.StatusCreated)
+
+	r := httptest.NewRequest("POST", "/v1/workflow/myworkflow/runs", nil)
+	run := workflow.RunWorkflowParams{
+		HTTPRequest:  r,
+		WorkflowName: "myworkflow",
+		Body:         &v1.WorkflowRun{Input: "hello"},
+		XDispatchOrg: testOrgID,
+	}
+	// the workflow has not been marked READY by the controller yet
+	runResponder := api.WorkflowRunWorkflowHandler.Handle(run, "testCookie")
+	var errorBody v1.Error
+	helpers.HandlerRequest(t, runResponder, &errorBody, 404)
+
+	getWorkflow := workflow.GetWorkflowParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/workflow/myworkflow", nil),
+		WorkflowName: "myworkflow",
+		XDispatchOrg: testOrgID,
+	}
+	var wf v1.Workflow
+	helpers.HandlerRequest(t, api.WorkflowGetWorkflowHandler.Handle(getWorkflow, "testCookie"), &wf, 200)
+
+	w, _ := getWorkflowEntity(t, es, "myworkflow")
+	assert.NoError(t, NewEntityHandler(es).Add(r.Context(), w))
+
+	runResponder = api.WorkflowRunWorkflowHandler.Handle(run, "testCookie")
+	var runBody v1.WorkflowRun
+	helpers.HandlerRequest(t, runResponder, &runBody, 202)
+	assert.Equal(t, "myworkflow", runBody.WorkflowName)
+	assert.Equal(t, "hello", runBody.Input)
+	assert.NotEmpty(t, runBody.Name)
+
+	getRun := workflow.GetWorkflowRunParams{
+		HTTPRequest:     httptest.NewRequest("GET", "/v1/workflowruns/"+runBody.Name.String(), nil),
+		WorkflowRunName: runBody.Name,
+		XDispatchOrg:    testOrgID,
+	}
+	var getRunBody v1.WorkflowRun
+	helpers.HandlerRequest(t, api.WorkflowGetWorkflowRunHandler.Handle(getRun, "testCookie"), &getRunBody, 200)
+	assert.Equal(t, runBody.Name, getRunBody.Name)
+
+	getRuns := workflow.GetWorkflowRunsParams{
+		HTTPRequest:  httptest.NewRequest("GET", "/v1/workflowruns", nil),
+		WorkflowName: swag.String("other"),
+		XDispatchOrg: testOrgID,
+	}
+	var getRunsBody []v1.WorkflowRun
+	helpers.HandlerRequest(t, api.WorkflowGetWorkflowRunsHandler.Handle(getRuns, "testCookie"), &getRunsBody, 200)
+	assert.Len(t, getRunsBody, 0)
+
+	getRuns.WorkflowName = swag.String("myworkflow")
+	helpers.HandlerRequest(t, api.WorkflowGetWorkflowRunsHandler.Handle(getRuns, "testCookie"), &getRunsBody, 200)
+	assert.Len(t, getRunsBody, 1)
+}
@@ -9,6 +9,9 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -79,7 +82,7 @@ func (ib *DockerImageBuilder) copyFunctionTemplate(tmpDir string, image string)
 }
 
 // BuildImage packages a function into a docker image.  It also adds any FaaS specfic image layers
-func (ib *DockerImageBuilder) BuildImage(ctx context.Context, f *Function) (string, error) {
+func (ib *DockerImageBuilder) BuildImage(ctx context.Context, f *Function, output io.Writer) (string, error) {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
@@ -112,10 +115,21 @@ func (ib *DockerImageBuilder) BuildImage(ctx context.Context, f *Function) (stri
 		"IMAGE":   swag.String(f.ImageURL),
 		"HANDLER": swag.String(f.Handler),
 	}
-	err = images.BuildAndPushFromDir(ctx, ib.docker, tmpDir, name, ib.RegistryAuth, ib.PushImages, buildArgs)
+	err = images.BuildAndPushFromDir(ctx, ib.docker, tmpDir, name, ib.RegistryAuth, ib.PushImages, buildArgs, output)
 	return name, err
 }
 
+// ContentHash returns the hash of what the function image is built from: the source, the image and the handler
+func ContentHash(f *Function) string {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(f.ImageURL), []byte(f.Handler), f.Source} {
+		// length prefixed, so that moving bytes between parts changes the hash
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func writeSourceDir(destDir string, f *Function) error {
 	r, err := tarStream(f.Source)
 	if err != nil {
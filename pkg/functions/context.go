@@ -7,9 +7,11 @@ package functions
 
 import (
 	"bufio"
+	"encoding/json"
 	"io"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/opentracing/opentracing-go"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/vmware/dispatch/pkg/api/v1"
@@ -22,8 +24,58 @@ const (
 	ErrorKey       = "error"
 	HTTPContextKey = "httpContext"
 	TimeoutKey     = "timeout"
+	InvocationKey  = "dispatch"
+	TraceKey       = "trace"
+
+	// logWriterKey holds the LogWriter of the run, it isn't sent to the function
+	logWriterKey = "logWriter"
 )
 
+// Invocation lets a function run emit events and run other functions of its organization, by calling the API
+// endpoint with the token as bearer token
+type Invocation struct {
+	Endpoint       string `json:"endpoint"`
+	Token          string `json:"token"`
+	OrganizationID string `json:"organizationId"`
+	RunName        string `json:"runName"`
+}
+
+// InjectTrace adds the span context of the run to the context, as an OpenTracing text map, so runtimes can continue
+// the trace
+func (ctx Context) InjectTrace(span opentracing.Span) {
+	carrier := opentracing.TextMapCarrier{}
+	if err := span.Tracer().Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+		log.Debugf("unable to inject the span context of the run: %s", err)
+		return
+	}
+	if len(carrier) > 0 {
+		ctx[TraceKey] = map[string]string(carrier)
+	}
+}
+
+// Trace returns the span context of the run, nil if the context has none
+func (ctx Context) Trace() opentracing.SpanContext {
+	carrier := opentracing.TextMapCarrier{}
+	switch t := ctx[TraceKey].(type) {
+	case map[string]string:
+		carrier = t
+	case map[string]interface{}:
+		for k, v := range t {
+			if s, ok := v.(string); ok {
+				carrier[k] = s
+			}
+		}
+	}
+	if len(carrier) == 0 {
+		return nil
+	}
+	spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, carrier)
+	if err != nil {
+		return nil
+	}
+	return spanCtx
+}
+
 // Logs returns the logs as a list of strings
 func (ctx Context) Logs() v1.Logs {
 	log.Debugf(`Logs from ctx["logs"]: %#v`, ctx[LogsKey])
@@ -72,13 +124,35 @@ func (ctx Context) ReadLogs(stderrReader io.Reader, stdoutReader io.Reader) {
 	}
 }
 
-// AddLogs adds the logs into the context
+// AddLogs adds the logs into the context, and writes them to the log writer of the context if any
 func (ctx Context) AddLogs(logs v1.Logs) {
 	log.Debugf("adding logs: %#v", logs)
 	l := ctx.Logs()
 	l.Stderr = append(l.Stderr, logs.Stderr...)
 	l.Stdout = append(l.Stdout, logs.Stdout...)
 	ctx[LogsKey] = l
+	if w, ok := ctx[logWriterKey].(LogWriter); ok {
+		w.WriteLogs(logs)
+	}
+}
+
+// SetLogWriter makes AddLogs write the logs added to the context to w as well
+func (ctx Context) SetLogWriter(w LogWriter) {
+	ctx[logWriterKey] = w
+}
+
+// MarshalJSON encodes the context as sent to the function, without its log writer
+func (ctx Context) MarshalJSON() ([]byte, error) {
+	if _, ok := ctx[logWriterKey]; !ok {
+		return json.Marshal(map[string]interface{}(ctx))
+	}
+	copied := make(map[string]interface{}, len(ctx))
+	for k, v := range ctx {
+		if k != logWriterKey {
+			copied[k] = v
+		}
+	}
+	return json.Marshal(copied)
 }
 
 func readLogs(reader io.Reader) []string {
@@ -6,6 +6,7 @@ package functions
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -111,3 +112,26 @@ func TestContext_GetErrorNil(t *testing.T) {
 	ctx := Context{}
 	assert.Nil(t, ctx.GetError())
 }
+
+type testLogWriter struct {
+	logs []v1.Logs
+}
+
+func (w *testLogWriter) WriteLogs(logs v1.Logs) {
+	w.logs = append(w.logs, logs)
+}
+
+func TestContext_LogWriter(t *testing.T) {
+	ctx := Context{EventKey: "event"}
+	w := &testLogWriter{}
+	ctx.SetLogWriter(w)
+	ctx.AddLogs(v1.Logs{Stdout: []string{"one"}})
+	ctx.AddLogs(v1.Logs{Stderr: []string{"two"}})
+	assert.Equal(t, []v1.Logs{{Stdout: []string{"one"}}, {Stderr: []string{"two"}}}, w.logs)
+	assert.Equal(t, v1.Logs{Stdout: []string{"one"}, Stderr: []string{"two"}}, ctx.Logs())
+
+	// the writer isn't sent to the function
+	b, err := json.Marshal(ctx)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"event": "event", "logs": {"stdout": ["one"], "stderr": ["two"]}}`, string(b))
+}
@@ -6,12 +6,12 @@
 package docker
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -90,6 +90,7 @@ func (d *Driver) Create(ctx context.Context, f *functions.Function) error {
 
 	resp, err := d.docker.ContainerCreate(ctx, &container.Config{
 		Image:        f.FunctionImageURL,
+		Env:          environment(f),
 		ExposedPorts: nat.PortSet{functionAPIPort: {}},
 		Labels: map[string]string{
 			labelFunctionID:       f.ID,
@@ -159,6 +160,16 @@ func (d *Driver) Create(ctx context.Context, f *functions.Function) error {
 	})
 }
 
+// environment returns the configuration of the function as container environment variables
+func environment(f *functions.Function) []string {
+	var env []string
+	for key, value := range f.Environment {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
 // Delete deletes the function container.
 func (d *Driver) Delete(ctx context.Context, f *functions.Function) error {
 	span, ctx := trace.Trace(ctx, "")
@@ -183,6 +194,7 @@ func (d *Driver) deleteContainers(ctx context.Context, f *functions.Function, de
 		return errors.Wrapf(err, "error when finding containers for function ID %s", f.ID)
 	}
 
+	var removed []types.Container
 	for _, c := range containers {
 		if c.Labels[labelFunctionRevision] == f.FaasID && !deleteActive {
 			continue
@@ -194,6 +206,44 @@ func (d *Driver) deleteContainers(ctx context.Context, f *functions.Function, de
 		if err != nil {
 			return errors.Wrapf(err, "error when deleting container %s for function %s", c.ID, f.ID)
 		}
+		removed = append(removed, c)
+	}
+	// a function redeployed with a new configuration only runs its active revision on the image of the old ones
+	active := f.FunctionImageURL
+	if deleteActive {
+		active = ""
+	}
+	if err := d.deleteImages(ctx, f, removed, active); err != nil {
+		return err
+	}
+
+	// Clear cache if active is also to be deleted
+	if _, ok := d.containerCache.Load(f.ID); ok && deleteActive {
+		d.containerCache.Delete(f.ID)
+	}
+
+	return nil
+}
+
+// deleteImages deletes the images of the removed containers of a function, except the active image of the function.
+// Function images are shared through the build cache, e.g. by functions with the same sources, so the images still
+// used by other containers are kept as well.
+func (d *Driver) deleteImages(ctx context.Context, f *functions.Function, removed []types.Container, active string) error {
+	if len(removed) == 0 {
+		return nil
+	}
+	inUse, err := d.imagesInUse(ctx)
+	if err != nil {
+		return err
+	}
+	if active != "" {
+		inUse[active] = true
+	}
+	for _, c := range removed {
+		if inUse[c.Image] || inUse[c.ImageID] {
+			log.Debugf("Keeping image %s still used by other containers", c.Image)
+			continue
+		}
 		log.Debugf("Deleting image %s", c.Image)
 		deleted, err := d.docker.ImageRemove(ctx, c.Image, types.ImageRemoveOptions{
 			Force:         true,
@@ -202,19 +252,34 @@ func (d *Driver) deleteContainers(ctx context.Context, f *functions.Function, de
 		if err != nil {
 			return errors.Wrapf(err, "error when deleting function image %s for container %s and function %s", c.Image, c.ID, f.ID)
 		}
+		// the image of several removed containers is deleted once
+		inUse[c.Image] = true
 		if log.GetLevel() == log.DebugLevel {
 			for _, image := range deleted {
 				log.Debugf("Deleted image: %+v", image)
 			}
 		}
 	}
+	return nil
+}
 
-	// Clear cache if active is also to be deleted
-	if _, ok := d.containerCache.Load(f.ID); ok && deleteActive {
-		d.containerCache.Delete(f.ID)
+// imagesInUse returns the names and IDs of the images of the function containers
+func (d *Driver) imagesInUse(ctx context.Context) (map[string]bool, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", labelFunctionID)
+	containers, err := d.docker.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error when finding function containers")
 	}
-
-	return nil
+	inUse := make(map[string]bool)
+	for _, c := range containers {
+		inUse[c.Image] = true
+		inUse[c.ImageID] = true
+	}
+	return inUse, nil
 }
 
 func (d *Driver) findActiveContainer(ctx context.Context, functionID, functionRevision string) (*dockerContainer, error) {
@@ -261,7 +326,6 @@ func (d *Driver) findActiveContainer(ctx context.Context, functionID, functionRe
 // GetRunnable creates runnable representation of the function
 func (d *Driver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
 	return func(ctx functions.Context, in interface{}) (interface{}, error) {
-		bytesIn, _ := json.Marshal(functions.Message{Context: ctx, Payload: in})
 		var c dockerContainer
 
 		ci, ok := d.containerCache.Load(e.FunctionID)
@@ -281,7 +345,11 @@ func (d *Driver) GetRunnable(e *functions.FunctionExecution) functions.Runnable
 		}
 
 		postURL := "http://" + d.ExternalHost + ":" + c.Port + "/"
-		res, err := http.Post(postURL, jsonContentType, bytes.NewReader(bytesIn))
+		req, err := functions.NewMessageRequest(postURL, ctx, in)
+		if err != nil {
+			return nil, &systemError{err}
+		}
+		res, err := http.DefaultClient.Do(req)
 		if err != nil {
 			log.Errorf("Error when sending POST request to %s: %+v", postURL, err)
 			return nil, &systemError{errors.Wrapf(err, "request to function container on %s failed", postURL)}
@@ -301,7 +369,11 @@ func (d *Driver) GetRunnable(e *functions.FunctionExecution) functions.Runnable
 			}
 			ctx.AddLogs(out.Context.Logs())
 			ctx.SetError(out.Context.GetError())
-			return out.Payload, nil
+			output, err := out.Output()
+			if err != nil {
+				return nil, &systemError{err}
+			}
+			return output, nil
 
 		default:
 			bytesOut, err := ioutil.ReadAll(res.Body)
@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -42,6 +43,7 @@ func TestDriverCreate(t *testing.T) {
 			Name: "hello",
 			ID:   "deadbeef",
 		},
+		Environment: map[string]string{"REGION": "us-west", "LOG_LEVEL": "debug"},
 	}
 	dockerMock := &mocks.DockerClient{}
 	d := New(dockerMock)
@@ -49,7 +51,9 @@ func TestDriverCreate(t *testing.T) {
 	server, port := startHTTPServer()
 	defer server.Close()
 
-	dockerMock.On("ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+	dockerMock.On("ContainerCreate", mock.Anything, mock.MatchedBy(func(config *container.Config) bool {
+		return assert.ObjectsAreEqual([]string{"LOG_LEVEL=debug", "REGION=us-west"}, config.Env)
+	}), mock.Anything, mock.Anything, mock.Anything).Return(
 		container.ContainerCreateCreatedBody{}, error(nil),
 	)
 
@@ -184,3 +188,96 @@ func TestOfDriverDelete(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func TestDriverDeleteSharedImage(t *testing.T) {
+	f := functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name: "hello",
+			ID:   "deadbeef",
+		},
+	}
+	functionContainers := mock.MatchedBy(func(opts types.ContainerListOptions) bool { return !opts.All })
+	allContainers := mock.MatchedBy(func(opts types.ContainerListOptions) bool { return opts.All })
+	removed := types.Container{ID: "hello-container", Image: "function-image", ImageID: "sha256:cafe"}
+
+	// another function built from the same sources runs on the cached image
+	dockerMock := &mocks.DockerClient{}
+	dockerMock.On("ContainerList", mock.Anything, functionContainers).Return([]types.Container{removed}, nil)
+	dockerMock.On("ContainerList", mock.Anything, allContainers).Return([]types.Container{
+		{ID: "bye-container", Image: "function-image", ImageID: "sha256:cafe"},
+	}, nil)
+	dockerMock.On("ContainerRemove", mock.Anything, "hello-container", mock.Anything).Return(nil)
+	assert.NoError(t, New(dockerMock).Delete(context.Background(), &f))
+	dockerMock.AssertNotCalled(t, "ImageRemove", mock.Anything, mock.Anything, mock.Anything)
+
+	// the image is deleted with the last container using it
+	dockerMock = &mocks.DockerClient{}
+	dockerMock.On("ContainerList", mock.Anything, functionContainers).Return([]types.Container{removed}, nil)
+	dockerMock.On("ContainerList", mock.Anything, allContainers).Return([]types.Container{}, nil)
+	dockerMock.On("ContainerRemove", mock.Anything, "hello-container", mock.Anything).Return(nil)
+	dockerMock.On("ImageRemove", mock.Anything, "function-image", mock.Anything).Return([]types.ImageDelete{}, nil)
+	assert.NoError(t, New(dockerMock).Delete(context.Background(), &f))
+	dockerMock.AssertCalled(t, "ImageRemove", mock.Anything, "function-image", mock.Anything)
+}
+
+func TestDriverCreateConfigOnly(t *testing.T) {
+	// the configuration of the function changed, its new revision runs on the image of the old one
+	f := functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name: "hello",
+			ID:   "deadbeef",
+		},
+		FaasID:           "revision-2",
+		FunctionImageURL: "function-image",
+		Environment:      map[string]string{"REGION": "us-east"},
+	}
+	dockerMock := &mocks.DockerClient{}
+	d := New(dockerMock)
+	d.RetryTimeout = 0
+	server, port := startHTTPServer()
+	defer server.Close()
+
+	dockerMock.On("ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		container.ContainerCreateCreatedBody{ID: "revision-2-container"}, error(nil),
+	)
+	dockerMock.On("ContainerStart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	dockerMock.On("ContainerInspect", mock.Anything, mock.Anything).Return(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			State: &types.ContainerState{
+				Running: true,
+			},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			NetworkSettingsBase: types.NetworkSettingsBase{
+				Ports: nat.PortMap{
+					functionAPIPort: []nat.PortBinding{{
+						HostIP:   "0.0.0.0",
+						HostPort: port,
+					}},
+				},
+			},
+		},
+	}, nil)
+	dockerMock.On("ContainerList", mock.Anything, mock.MatchedBy(func(opts types.ContainerListOptions) bool {
+		return !opts.All
+	})).Return([]types.Container{{
+		ID:     "revision-1-container",
+		Image:  "function-image",
+		Labels: map[string]string{labelFunctionID: "deadbeef", labelFunctionRevision: "revision-1"},
+	}}, nil)
+	// the container of the new revision isn't listed yet
+	dockerMock.On("ContainerList", mock.Anything, mock.MatchedBy(func(opts types.ContainerListOptions) bool {
+		return opts.All
+	})).Return([]types.Container{}, nil)
+	removed := make(chan string, 1)
+	dockerMock.On("ContainerRemove", mock.Anything, "revision-1-container", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		removed <- args.String(1)
+	})
+	dockerMock.On("ImageRemove", mock.Anything, mock.Anything, mock.Anything).Return([]types.ImageDelete{}, nil)
+
+	assert.NoError(t, d.Create(context.Background(), &f))
+	assert.Equal(t, "revision-1-container", <-removed)
+	// the old revision is cleaned up in the background
+	time.Sleep(50 * time.Millisecond)
+	dockerMock.AssertNotCalled(t, "ImageRemove", mock.Anything, "function-image", mock.Anything)
+}
@@ -26,10 +26,51 @@ type Function struct {
 	ImageName        string   `json:"image"`
 	ImageURL         string   `json:"imageURL"`
 	FunctionImageURL string   `json:"functionImageURL"`
+	ContentHash      string   `json:"contentHash,omitempty"`
 	Schema           *Schema  `json:"schema,omitempty"`
 	Secrets          []string `json:"secrets,omitempty"`
 	Services         []string `json:"services,omitempty"`
 	Timeout          int64    `json:"timeout,omitempty"`
+
+	Config     map[string]string `json:"config,omitempty"`
+	ConfigMaps []string          `json:"configMaps,omitempty"`
+	// Environment is the configuration resolved when the function was last deployed
+	Environment map[string]string `json:"environment,omitempty"`
+
+	Middlewares []MiddlewareConfig `json:"middlewares,omitempty"`
+
+	OnSuccess *ResultEvent `json:"onSuccess,omitempty"`
+	OnFailure *ResultEvent `json:"onFailure,omitempty"`
+}
+
+// MiddlewareConfig names a registered middleware a function opts into, and its configuration
+type MiddlewareConfig struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// ResultEvent configures the event published with the result of the runs of a function
+type ResultEvent struct {
+	EventType string `json:"eventType"`
+	Source    string `json:"source,omitempty"`
+	// Data maps the fields of the event data to dot-separated paths in the run
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// FunctionBuild struct represents a build of the image of a function
+type FunctionBuild struct {
+	entitystore.BaseEntity
+	FunctionName     string `json:"functionName"`
+	ContentHash      string `json:"contentHash"`
+	FunctionImageURL string `json:"functionImageURL"`
+	Cached           bool   `json:"cached"`
+	Logs             string `json:"logs,omitempty"`
+}
+
+// ConfigMap struct represents configuration key-values shared by functions
+type ConfigMap struct {
+	entitystore.BaseEntity
+	Data map[string]string `json:"data,omitempty"`
 }
 
 // Schema struct stores input and output validation schemas
@@ -41,23 +82,65 @@ type Schema struct {
 // FnRun struct represents single function run
 type FnRun struct {
 	entitystore.BaseEntity
-	FunctionName string                 `json:"functionName"`
-	FunctionID   string                 `json:"functionID"`
-	FaasID       string                 `json:"faasId"`
-	Blocking     bool                   `json:"blocking"`
-	Input        interface{}            `json:"input,omitempty"`
-	Output       interface{}            `json:"output,omitempty"`
-	Secrets      []string               `json:"secrets,omitempty"`
-	Services     []string               `json:"services,omitempty"`
-	HTTPContext  map[string]interface{} `json:"httpContext,omitempty"`
-	Event        *events.CloudEvent     `json:"event,omitempty"`
-	Logs         *v1.Logs               `json:"logs,omitempty"`
-	Error        *v1.InvocationError    `json:"error,omitempty"`
-	FinishedTime time.Time              `json:"finishedTime,omitempty"`
+	FunctionName   string                 `json:"functionName"`
+	FunctionID     string                 `json:"functionID"`
+	FaasID         string                 `json:"faasId"`
+	Blocking       bool                   `json:"blocking"`
+	Input          interface{}            `json:"input,omitempty"`
+	Output         interface{}            `json:"output,omitempty"`
+	Secrets        []string               `json:"secrets,omitempty"`
+	Services       []string               `json:"services,omitempty"`
+	HTTPContext    map[string]interface{} `json:"httpContext,omitempty"`
+	IdempotencyKey string                 `json:"idempotencyKey,omitempty"`
+	ReplayOf       string                 `json:"replayOf,omitempty"`
+	ParentRun      string                 `json:"parentRun,omitempty"`
+	TraceID        string                 `json:"traceId,omitempty"`
+	Priority       string                 `json:"priority,omitempty"`
+	Cancelled      bool                   `json:"cancelled,omitempty"`
+	Callback       *RunCallback           `json:"callback,omitempty"`
+	CallbackStatus string                 `json:"callbackStatus,omitempty"`
+	Event          *events.CloudEvent     `json:"event,omitempty"`
+	Logs           *v1.Logs               `json:"logs,omitempty"`
+	Error          *v1.InvocationError    `json:"error,omitempty"`
+	FinishedTime   time.Time              `json:"finishedTime,omitempty"`
+
+	// InputContentType and OutputContentType are set for binary and text payloads, which are base64-encoded strings
+	InputContentType  string `json:"inputContentType,omitempty"`
+	OutputContentType string `json:"outputContentType,omitempty"`
+
+	// InputRef, OutputRef and LogsRef are the blob store keys of the input, output and logs when they are too large
+	// to be kept with the run
+	InputRef  string `json:"inputRef,omitempty"`
+	OutputRef string `json:"outputRef,omitempty"`
+	LogsRef   string `json:"logsRef,omitempty"`
+	// PayloadDigests are the SHA-256 digests of the payloads kept in the blob store, by payload
+	PayloadDigests map[string]string `json:"payloadDigests,omitempty"`
 
 	WaitChan chan struct{} `json:"-"`
 }
 
+// The status of the callback of a run, pending until it is delivered or its retries are exhausted
+const (
+	CallbackPending   = "pending"
+	CallbackDelivered = "delivered"
+	CallbackFailed    = "failed"
+)
+
+// RunCallback is invoked with the run when it completes, either by POSTing it to URL or by publishing it as an event
+// of type EventType
+type RunCallback struct {
+	URL       string `json:"url,omitempty"`
+	EventType string `json:"eventType,omitempty"`
+	// SecretName is the secret of the secret store holding the key signing the request to URL, the key itself is never
+	// stored with the run
+	SecretName string `json:"secretName,omitempty"`
+
+	// Attempts is the number of failed attempts, the next one being due at NextAttempt
+	Attempts    int       `json:"attempts,omitempty"`
+	NextAttempt time.Time `json:"nextAttempt,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
 // Wait waits for function execution to finish
 func (r *FnRun) Wait() {
 	if r.WaitChan != nil {
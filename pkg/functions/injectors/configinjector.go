@@ -0,0 +1,78 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package injectors
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+// configKeyPattern matches the configuration keys, which are also used as environment variable names
+var configKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type configInjector struct {
+	store entitystore.EntityStore
+}
+
+// NewConfigInjector create a new config injector
+func NewConfigInjector(store entitystore.EntityStore) functions.ConfigInjector {
+	return &configInjector{
+		store: store,
+	}
+}
+
+// ValidateConfig checks that the configuration keys can be used as environment variable names
+func ValidateConfig(config map[string]string) error {
+	for key := range config {
+		if !configKeyPattern.MatchString(key) {
+			return errors.Errorf("invalid config key %q, keys must match %s", key, configKeyPattern)
+		}
+	}
+	return nil
+}
+
+// ResolveConfig merges the data of the config maps, in order, and the config. Later values override earlier ones,
+// the config of the function overriding all config maps.
+func ResolveConfig(ctx context.Context, store entitystore.EntityStore, organizationID string, config map[string]string, configMaps []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for _, name := range configMaps {
+		cm := new(functions.ConfigMap)
+		if err := store.Get(ctx, organizationID, name, entitystore.Options{}, cm); err != nil {
+			return nil, errors.Wrapf(err, "failed to get config map %s", name)
+		}
+		for key, value := range cm.Data {
+			resolved[key] = value
+		}
+	}
+	for key, value := range config {
+		resolved[key] = value
+	}
+	return resolved, nil
+}
+
+func (i *configInjector) GetMiddleware(organizationID string, config map[string]string, configMaps []string) functions.Middleware {
+	return func(f functions.Runnable) functions.Runnable {
+		return func(ctx functions.Context, in interface{}) (interface{}, error) {
+			resolved, err := ResolveConfig(context.Background(), i.store, organizationID, config, configMaps)
+			if err != nil {
+				log.Errorf("error when resolving config maps %+v", err)
+				return nil, &injectorError{errors.Wrap(err, "error when resolving config maps")}
+			}
+			ctx["config"] = resolved
+			out, err := f(ctx, in)
+			if err != nil {
+				return nil, err
+			}
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,56 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package injectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+	helpers "github.com/vmware/dispatch/pkg/testing/api"
+)
+
+func TestInjectConfig(t *testing.T) {
+	store := helpers.MakeEntityStore(t)
+	for _, cm := range []*functions.ConfigMap{
+		{
+			BaseEntity: entitystore.BaseEntity{Name: "defaults", OrganizationID: "testOrg"},
+			Data:       map[string]string{"LOG_LEVEL": "info", "REGION": "us-west"},
+		},
+		{
+			BaseEntity: entitystore.BaseEntity{Name: "overrides", OrganizationID: "testOrg"},
+			Data:       map[string]string{"LOG_LEVEL": "debug"},
+		},
+	} {
+		_, err := store.Add(context.Background(), cm)
+		require.NoError(t, err)
+	}
+
+	injector := NewConfigInjector(store)
+
+	printConfigFn := func(ctx functions.Context, _ interface{}) (interface{}, error) {
+		return ctx["config"], nil
+	}
+
+	ctx := functions.Context{}
+	config := map[string]string{"REGION": "eu-central"}
+	output, err := injector.GetMiddleware("testOrg", config, []string{"defaults", "overrides"})(printConfigFn)(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "REGION": "eu-central"}, output)
+
+	_, err = injector.GetMiddleware("testOrg", nil, []string{"missing"})(printConfigFn)(functions.Context{}, nil)
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.InputError)(nil), err)
+}
+
+func TestValidateConfig(t *testing.T) {
+	assert.NoError(t, ValidateConfig(map[string]string{"LOG_LEVEL": "debug", "_private": "", "v2": "x"}))
+	assert.Error(t, ValidateConfig(map[string]string{"2FAST": "x"}))
+	assert.Error(t, ValidateConfig(map[string]string{"LOG-LEVEL": "x"}))
+}
@@ -6,7 +6,6 @@
 package kubeless
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -168,12 +167,11 @@ func (d *kubelessDriver) Delete(ctx context.Context, f *functions.Function) erro
 	return nil
 }
 
-func (d *kubelessDriver) doHTTPReq(faasID string, body []byte) ([]byte, error) {
-	req, err := http.NewRequest("POST", fmt.Sprintf("http://%s.%s.svc.cluster.local:8080", getID(faasID), d.fnNs), bytes.NewReader(body))
+func (d *kubelessDriver) doHTTPReq(faasID string, ctx functions.Context, in interface{}) ([]byte, error) {
+	req, err := functions.NewMessageRequest(fmt.Sprintf("http://%s.%s.svc.cluster.local:8080", getID(faasID), d.fnNs), ctx, in)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to create request %v", err)
 	}
-	req.Header.Add("Content-Type", jsonContentType)
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -192,8 +190,7 @@ func (d *kubelessDriver) doHTTPReq(faasID string, body []byte) ([]byte, error) {
 
 func (d *kubelessDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
 	return func(ctx functions.Context, in interface{}) (interface{}, error) {
-		bytesIn, _ := json.Marshal(functions.Message{Context: ctx, Payload: in})
-		res, err := d.doHTTPReq(e.FaasID, bytesIn)
+		res, err := d.doHTTPReq(e.FaasID, ctx, in)
 		if err != nil {
 			return nil, err
 		}
@@ -203,6 +200,10 @@ func (d *kubelessDriver) GetRunnable(e *functions.FunctionExecution) functions.R
 		}
 		ctx.AddLogs(out.Context.Logs())
 		ctx.SetError(out.Context.GetError())
-		return out.Payload, nil
+		output, err := out.Output()
+		if err != nil {
+			return nil, &systemError{err}
+		}
+		return output, nil
 	}
 }
@@ -0,0 +1,431 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	typedappsv1beta1 "k8s.io/client-go/kubernetes/typed/apps/v1beta1"
+	typedautoscalingv1 "k8s.io/client-go/kubernetes/typed/autoscaling/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/vmware/dispatch/pkg/config"
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/trace"
+	"github.com/vmware/dispatch/pkg/utils"
+)
+
+const (
+	jsonContentType     = "application/json"
+	functionAPIPort     = 8080
+	healthcheckEndpoint = "/healthz"
+
+	labelApp        = "app"
+	labelFunctionID = "dispatch-function-id"
+	labelFaasID     = "dispatch-faas-id"
+	appName         = "dispatch-function"
+
+	defaultCreateTimeout = 60 // seconds
+	defaultReplicas      = 1
+)
+
+// Config contains the Kubernetes driver configuration
+type Config struct {
+	K8sConfig           string
+	FuncNamespace       string
+	FuncDefaultLimits   *config.FunctionResources
+	FuncDefaultRequests *config.FunctionResources
+	CreateTimeout       *int
+	ImagePullSecret     string
+	// Replicas is the number of pods started for each function revision.
+	Replicas int32
+	// MaxReplicas enables horizontal pod autoscaling of function pods up to MaxReplicas, if greater than Replicas.
+	MaxReplicas int32
+	// TargetCPUUtilization is the average CPU utilization (in percent) the autoscaler aims at.
+	TargetCPUUtilization *int32
+}
+
+type k8sDriver struct {
+	deployments typedappsv1beta1.DeploymentInterface
+	services    typedcorev1.ServiceInterface
+	autoscalers typedautoscalingv1.HorizontalPodAutoscalerInterface
+	fnNs        string
+
+	httpClient *http.Client
+	// serviceURL returns the URL function invocations for the given FaaS ID are sent to
+	serviceURL func(faasID string) string
+
+	createTimeout   int
+	imagePullSecret string
+
+	replicas             int32
+	maxReplicas          int32
+	targetCPUUtilization *int32
+
+	resources corev1.ResourceRequirements
+}
+
+type systemError struct {
+	Err error `json:"err"`
+}
+
+func (err *systemError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *systemError) AsSystemErrorObject() interface{} {
+	return err
+}
+
+func (err *systemError) StackTrace() errors.StackTrace {
+	if e, ok := err.Err.(functions.StackTracer); ok {
+		return e.StackTrace()
+	}
+
+	return nil
+}
+
+// New creates a new Kubernetes driver. The driver does not depend on any FaaS platform: each function revision
+// is deployed as a Deployment and a Service in the functions namespace.
+func New(config *Config) (functions.FaaSDriver, error) {
+	k8sConf, err := kubeClientConfig(config.K8sConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "error configuring k8s API client")
+	}
+	k8sClient, err := kubernetes.NewForConfig(k8sConf)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating k8s API client")
+	}
+
+	return newDriver(config, k8sClient), nil
+}
+
+func newDriver(config *Config, k8sClient kubernetes.Interface) *k8sDriver {
+	fnNs := config.FuncNamespace
+	if fnNs == "" {
+		fnNs = "default"
+	}
+
+	d := &k8sDriver{
+		// Use AppsV1beta1 until we remove support for Kubernetes 1.7
+		deployments: k8sClient.AppsV1beta1().Deployments(fnNs),
+		services:    k8sClient.CoreV1().Services(fnNs),
+		autoscalers: k8sClient.AutoscalingV1().HorizontalPodAutoscalers(fnNs),
+		fnNs:        fnNs,
+		httpClient:  http.DefaultClient,
+		serviceURL: func(faasID string) string {
+			return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d/", getID(faasID), fnNs, functionAPIPort)
+		},
+		createTimeout:        defaultCreateTimeout,
+		imagePullSecret:      config.ImagePullSecret,
+		replicas:             defaultReplicas,
+		maxReplicas:          config.MaxReplicas,
+		targetCPUUtilization: config.TargetCPUUtilization,
+	}
+	if config.CreateTimeout != nil {
+		d.createTimeout = *config.CreateTimeout
+	}
+	if config.Replicas > 0 {
+		d.replicas = config.Replicas
+	}
+	if config.FuncDefaultLimits != nil {
+		d.resources.Limits = resourceList(config.FuncDefaultLimits)
+	}
+	if config.FuncDefaultRequests != nil {
+		d.resources.Requests = resourceList(config.FuncDefaultRequests)
+	}
+
+	return d
+}
+
+func kubeClientConfig(kubeConfPath string) (*rest.Config, error) {
+	if kubeConfPath != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeConfPath)
+	}
+	return rest.InClusterConfig()
+}
+
+func resourceList(r *config.FunctionResources) corev1.ResourceList {
+	l := corev1.ResourceList{}
+	if r.CPU != "" {
+		l[corev1.ResourceCPU] = resource.MustParse(r.CPU)
+	}
+	if r.Memory != "" {
+		l[corev1.ResourceMemory] = resource.MustParse(r.Memory)
+	}
+	return l
+}
+
+func getID(id string) string {
+	return fmt.Sprintf("k8s-%s", id)
+}
+
+func labels(f *functions.Function) map[string]string {
+	return map[string]string{
+		labelApp:        appName,
+		labelFunctionID: f.ID,
+		labelFaasID:     f.FaasID,
+	}
+}
+
+// envVars returns the configuration of the function as container environment variables
+func envVars(f *functions.Function) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for name, value := range f.Environment {
+		env = append(env, corev1.EnvVar{Name: name, Value: value})
+	}
+	sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
+	return env
+}
+
+func (d *k8sDriver) deployment(f *functions.Function) *appsv1beta1.Deployment {
+	name := getID(f.FaasID)
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:      "function",
+				Image:     f.FunctionImageURL,
+				Env:       envVars(f),
+				Ports:     []corev1.ContainerPort{{ContainerPort: functionAPIPort, Protocol: corev1.ProtocolTCP}},
+				Resources: d.resources,
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path: healthcheckEndpoint,
+							Port: intstr.FromInt(functionAPIPort),
+						},
+					},
+				},
+			},
+		},
+	}
+	if d.imagePullSecret != "" {
+		podSpec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: d.imagePullSecret}}
+	}
+	replicas := d.replicas
+	return &appsv1beta1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels(f),
+		},
+		Spec: appsv1beta1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{labelFaasID: f.FaasID},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels(f),
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+func (d *k8sDriver) service(f *functions.Function) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   getID(f.FaasID),
+			Labels: labels(f),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{labelFaasID: f.FaasID},
+			Ports: []corev1.ServicePort{
+				{
+					Port:       functionAPIPort,
+					TargetPort: intstr.FromInt(functionAPIPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+func (d *k8sDriver) autoscaler(f *functions.Function) *autoscalingv1.HorizontalPodAutoscaler {
+	minReplicas := d.replicas
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   getID(f.FaasID),
+			Labels: labels(f),
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1beta1",
+				Kind:       "Deployment",
+				Name:       getID(f.FaasID),
+			},
+			MinReplicas:                    &minReplicas,
+			MaxReplicas:                    d.maxReplicas,
+			TargetCPUUtilizationPercentage: d.targetCPUUtilization,
+		},
+	}
+}
+
+// Create creates a deployment and a service for the function revision, and removes resources of other revisions
+// of the same function once the new revision is available.
+func (d *k8sDriver) Create(ctx context.Context, f *functions.Function) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	name := getID(f.FaasID)
+
+	if _, err := d.deployments.Create(d.deployment(f)); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "error creating deployment for function '%s'", f.Name)
+	}
+	if _, err := d.services.Create(d.service(f)); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "error creating service for function '%s'", f.Name)
+	}
+	if d.maxReplicas > d.replicas {
+		if _, err := d.autoscalers.Create(d.autoscaler(f)); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "error creating autoscaler for function '%s'", f.Name)
+		}
+	}
+
+	// make sure the function has started
+	err := utils.Backoff(time.Duration(d.createTimeout)*time.Second, func() error {
+		deployment, err := d.deployments.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to read function deployment status: '%s'", name)
+		}
+
+		if deployment.Status.AvailableReplicas > 0 {
+			return nil
+		}
+
+		return errors.Errorf("function deployment not available: '%s'", name)
+	})
+	if err != nil {
+		return err
+	}
+
+	// clear any revisions that could have been created before (e.g. before update)
+	return d.deleteRevisions(f, false)
+}
+
+// Delete deletes the resources of all revisions of the function.
+func (d *k8sDriver) Delete(ctx context.Context, f *functions.Function) error {
+	span, ctx := trace.Trace(ctx, "")
+	defer span.Finish()
+
+	return d.deleteRevisions(f, true)
+}
+
+func (d *k8sDriver) deleteRevisions(f *functions.Function, deleteActive bool) error {
+	listOpts := metav1.ListOptions{LabelSelector: labelFunctionID + "=" + f.ID}
+	propagation := metav1.DeletePropagationForeground
+	deleteOpts := &metav1.DeleteOptions{PropagationPolicy: &propagation}
+
+	skip := func(l map[string]string) bool {
+		return l[labelFaasID] == f.FaasID && !deleteActive
+	}
+
+	autoscalers, err := d.autoscalers.List(listOpts)
+	if err != nil {
+		return errors.Wrapf(err, "error listing autoscalers for function '%s'", f.Name)
+	}
+	for _, a := range autoscalers.Items {
+		if skip(a.Labels) {
+			continue
+		}
+		log.Debugf("Deleting autoscaler %s", a.Name)
+		if err := d.autoscalers.Delete(a.Name, deleteOpts); err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error deleting autoscaler %s for function '%s'", a.Name, f.Name)
+		}
+	}
+
+	services, err := d.services.List(listOpts)
+	if err != nil {
+		return errors.Wrapf(err, "error listing services for function '%s'", f.Name)
+	}
+	for _, s := range services.Items {
+		if skip(s.Labels) {
+			continue
+		}
+		log.Debugf("Deleting service %s", s.Name)
+		if err := d.services.Delete(s.Name, deleteOpts); err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error deleting service %s for function '%s'", s.Name, f.Name)
+		}
+	}
+
+	deployments, err := d.deployments.List(listOpts)
+	if err != nil {
+		return errors.Wrapf(err, "error listing deployments for function '%s'", f.Name)
+	}
+	for _, dep := range deployments.Items {
+		if skip(dep.Labels) {
+			continue
+		}
+		log.Debugf("Deleting deployment %s", dep.Name)
+		if err := d.deployments.Delete(dep.Name, deleteOpts); err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error deleting deployment %s for function '%s'", dep.Name, f.Name)
+		}
+	}
+
+	return nil
+}
+
+// GetRunnable creates runnable representation of the function
+func (d *k8sDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
+	return func(ctx functions.Context, in interface{}) (interface{}, error) {
+		postURL := d.serviceURL(e.FaasID)
+		req, err := functions.NewMessageRequest(postURL, ctx, in)
+		if err != nil {
+			return nil, &systemError{err}
+		}
+		res, err := d.httpClient.Do(req)
+		if err != nil {
+			log.Errorf("Error when sending POST request to %s: %+v", postURL, err)
+			return nil, &systemError{errors.Wrapf(err, "request to function service on %s failed", postURL)}
+		}
+		defer res.Body.Close()
+
+		log.Debugf("kubernetes.run.%s: status code: %v", e.FunctionID, res.StatusCode)
+		switch res.StatusCode {
+		case 200:
+			resBytes, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				return nil, &systemError{errors.Errorf("cannot read result from function service on URL: %s %s", postURL, err)}
+			}
+			var out functions.Message
+			if err := json.Unmarshal(resBytes, &out); err != nil {
+				return nil, &systemError{errors.Errorf("cannot JSON-parse result from function service: %s %s", err, string(resBytes))}
+			}
+			ctx.AddLogs(out.Context.Logs())
+			ctx.SetError(out.Context.GetError())
+			output, err := out.Output()
+			if err != nil {
+				return nil, &systemError{err}
+			}
+			return output, nil
+
+		default:
+			bytesOut, err := ioutil.ReadAll(res.Body)
+			if err == nil {
+				return nil, &systemError{errors.Errorf("Server returned unexpected status code: %d - %s", res.StatusCode, string(bytesOut))}
+			}
+			return nil, &systemError{errors.Wrapf(err, "Error performing request, status: %v", res.StatusCode)}
+		}
+	}
+}
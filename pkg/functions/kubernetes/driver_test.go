@@ -0,0 +1,183 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/config"
+	"github.com/vmware/dispatch/pkg/entity-store"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+func fakeClientset() *k8sFake.Clientset {
+	clientSet := k8sFake.NewSimpleClientset()
+	// the fake clientset has no controllers, pretend every deployment becomes available once created
+	clientSet.PrependReactor("create", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		deployment := action.(k8sTesting.CreateAction).GetObject().(*appsv1beta1.Deployment)
+		deployment.Status.AvailableReplicas = *deployment.Spec.Replicas
+		return false, nil, nil
+	})
+	return clientSet
+}
+
+func testFunction(faasID string) *functions.Function {
+	return &functions.Function{
+		BaseEntity: entitystore.BaseEntity{
+			Name: "hello",
+			ID:   "deadbeef",
+		},
+		FaasID:           faasID,
+		FunctionImageURL: "fake-image:latest",
+	}
+}
+
+func TestDriverCreate(t *testing.T) {
+	clientSet := fakeClientset()
+	d := newDriver(&Config{
+		FuncNamespace:     "fakeNS",
+		ImagePullSecret:   "pull-secret",
+		Replicas:          2,
+		FuncDefaultLimits: &config.FunctionResources{CPU: "500m", Memory: "256Mi"},
+	}, clientSet)
+
+	f := testFunction("cafe")
+	f.Environment = map[string]string{"REGION": "us-west", "LOG_LEVEL": "debug"}
+	require.NoError(t, d.Create(context.Background(), f))
+
+	deployment, err := clientSet.AppsV1beta1().Deployments("fakeNS").Get(getID(f.FaasID), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	assert.Equal(t, f.ID, deployment.Labels[labelFunctionID])
+	assert.Equal(t, f.FaasID, deployment.Labels[labelFaasID])
+	assert.Equal(t, f.FaasID, deployment.Spec.Selector.MatchLabels[labelFaasID])
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "fake-image:latest", container.Image)
+	assert.Equal(t, "500m", container.Resources.Limits.Cpu().String())
+	assert.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "REGION", Value: "us-west"}}, container.Env)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "pull-secret"}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	service, err := clientSet.CoreV1().Services("fakeNS").Get(getID(f.FaasID), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{labelFaasID: f.FaasID}, service.Spec.Selector)
+	assert.Equal(t, int32(functionAPIPort), service.Spec.Ports[0].Port)
+
+	autoscalers, err := clientSet.AutoscalingV1().HorizontalPodAutoscalers("fakeNS").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, autoscalers.Items)
+}
+
+func TestDriverCreateAutoscaler(t *testing.T) {
+	clientSet := fakeClientset()
+	targetCPU := int32(70)
+	d := newDriver(&Config{
+		FuncNamespace:        "fakeNS",
+		MaxReplicas:          5,
+		TargetCPUUtilization: &targetCPU,
+	}, clientSet)
+
+	f := testFunction("cafe")
+	require.NoError(t, d.Create(context.Background(), f))
+
+	hpa, err := clientSet.AutoscalingV1().HorizontalPodAutoscalers("fakeNS").Get(getID(f.FaasID), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+	assert.Equal(t, int32(70), *hpa.Spec.TargetCPUUtilizationPercentage)
+	assert.Equal(t, getID(f.FaasID), hpa.Spec.ScaleTargetRef.Name)
+}
+
+func TestDriverUpdateAndDelete(t *testing.T) {
+	clientSet := fakeClientset()
+	d := newDriver(&Config{FuncNamespace: "fakeNS", MaxReplicas: 3}, clientSet)
+
+	old := testFunction("cafe")
+	require.NoError(t, d.Create(context.Background(), old))
+
+	updated := testFunction("babe")
+	require.NoError(t, d.Create(context.Background(), updated))
+
+	deployments, err := clientSet.AppsV1beta1().Deployments("fakeNS").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, deployments.Items, 1)
+	assert.Equal(t, getID(updated.FaasID), deployments.Items[0].Name)
+
+	services, err := clientSet.CoreV1().Services("fakeNS").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, services.Items, 1)
+	assert.Equal(t, getID(updated.FaasID), services.Items[0].Name)
+
+	require.NoError(t, d.Delete(context.Background(), updated))
+
+	deployments, err = clientSet.AppsV1beta1().Deployments("fakeNS").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, deployments.Items)
+	services, err = clientSet.CoreV1().Services("fakeNS").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, services.Items)
+	autoscalers, err := clientSet.AutoscalingV1().HorizontalPodAutoscalers("fakeNS").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, autoscalers.Items)
+}
+
+func TestDriverGetRunnable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var in functions.Message
+		json.Unmarshal(body, &in)
+		payload := in.Payload.(map[string]interface{})
+		out, _ := json.Marshal(functions.Message{
+			Context: functions.Context{functions.LogsKey: v1.Logs{Stdout: []string{"log log log"}}},
+			Payload: map[string]interface{}{"myField": "Hello, " + payload["name"].(string)},
+		})
+		w.Header().Set("Content-Type", jsonContentType)
+		w.Write(out)
+	}))
+	defer server.Close()
+
+	d := newDriver(&Config{FuncNamespace: "fakeNS"}, k8sFake.NewSimpleClientset())
+	d.httpClient = server.Client()
+	d.serviceURL = func(faasID string) string {
+		assert.Equal(t, "cafe", faasID)
+		return server.URL
+	}
+
+	ctx := functions.Context{}
+	r, err := d.GetRunnable(&functions.FunctionExecution{FunctionID: "deadbeef", FaasID: "cafe"})(ctx, map[string]interface{}{"name": "Me"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"myField": "Hello, Me"}, r)
+	assert.Equal(t, []string{"log log log"}, ctx.Logs().Stdout)
+}
+
+func TestDriverGetRunnableError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	d := newDriver(&Config{}, k8sFake.NewSimpleClientset())
+	d.httpClient = server.Client()
+	d.serviceURL = func(string) string { return server.URL }
+
+	_, err := d.GetRunnable(&functions.FunctionExecution{FunctionID: "deadbeef", FaasID: "cafe"})(functions.Context{}, nil)
+	require.Error(t, err)
+	_, ok := err.(functions.SystemError)
+	assert.True(t, ok)
+}
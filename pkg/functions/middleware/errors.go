@@ -0,0 +1,72 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+type inputError struct {
+	Err error `json:"err"`
+}
+
+func (err *inputError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *inputError) AsInputErrorObject() interface{} {
+	return err
+}
+
+func (err *inputError) StackTrace() errors.StackTrace {
+	if e, ok := err.Err.(functions.StackTracer); ok {
+		return e.StackTrace()
+	}
+
+	return nil
+}
+
+type outputError struct {
+	Err error `json:"err"`
+}
+
+func (err *outputError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *outputError) AsFunctionErrorObject() interface{} {
+	return err
+}
+
+func (err *outputError) StackTrace() errors.StackTrace {
+	if e, ok := err.Err.(functions.StackTracer); ok {
+		return e.StackTrace()
+	}
+
+	return nil
+}
+
+type systemError struct {
+	Err error `json:"err"`
+}
+
+func (err *systemError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *systemError) AsSystemErrorObject() interface{} {
+	return err
+}
+
+func (err *systemError) StackTrace() errors.StackTrace {
+	if e, ok := err.Err.(functions.StackTracer); ok {
+		return e.StackTrace()
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+const (
+	defaultHookTimeout = 10 * time.Second
+	// maxHookRejectionBody is the number of bytes of the response of a rejecting hook kept in the error of the run
+	maxHookRejectionBody = 1024
+)
+
+// hookContextKeys are the keys of the function context sent to hooks. The other keys, e.g. the invocation token of the
+// run and the secrets, services and configuration injected in the context, never leave dispatch.
+var hookContextKeys = []string{functions.EventKey, functions.HTTPContextKey, functions.TimeoutKey, functions.TraceKey}
+
+// newHTTPHook returns the factory of hooks POSTing the input to the "pre" URL and the output to the "post" URL, as a
+// function message with the hookContextKeys of the context, and replacing them with the payload of the message in the
+// response. A 204 response leaves the payload unchanged, a 4xx response of the pre hook rejects the input. Only the
+// allowedHosts set by the operator can be called, so that functions can't reach internal services through dispatch.
+func newHTTPHook(allowedHosts []string) Factory {
+	return func(config map[string]string) (functions.Middleware, error) {
+		pre, post := config["pre"], config["post"]
+		if pre == "" && post == "" {
+			return nil, errors.New("pre or post is required")
+		}
+		for _, u := range []string{pre, post} {
+			if u == "" {
+				continue
+			}
+			parsed, err := url.Parse(u)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+				return nil, errors.Errorf("invalid hook URL %q", u)
+			}
+			if !hostAllowed(allowedHosts, parsed.Hostname()) {
+				return nil, errors.Errorf("hook host %s is not allowed", parsed.Hostname())
+			}
+		}
+		timeout, err := durationConfig(config, "timeout", defaultHookTimeout)
+		if err != nil {
+			return nil, err
+		}
+		client := &http.Client{
+			Timeout: timeout,
+			// a redirect could lead to a host which is not allowed
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		return func(f functions.Runnable) functions.Runnable {
+			return func(ctx functions.Context, in interface{}) (interface{}, error) {
+				if pre != "" {
+					var err error
+					if in, err = callHook(client, pre, ctx, in); err != nil {
+						if _, ok := err.(*hookRejection); ok {
+							return nil, &inputError{err}
+						}
+						return nil, &systemError{errors.Wrap(err, "pre hook failed")}
+					}
+				}
+				out, err := f(ctx, in)
+				if err != nil {
+					return nil, err
+				}
+				if post != "" {
+					if out, err = callHook(client, post, ctx, out); err != nil {
+						return nil, &systemError{errors.Wrap(err, "post hook failed")}
+					}
+				}
+				return out, nil
+			}
+		}, nil
+	}
+}
+
+// hostAllowed returns true if the host is one of the allowed hosts
+func hostAllowed(allowedHosts []string, host string) bool {
+	for _, allowed := range allowedHosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// hookRejection is returned when a hook answers with a client error
+type hookRejection struct {
+	status int
+	body   string
+}
+
+func (r *hookRejection) Error() string {
+	body := r.body
+	if len(body) > maxHookRejectionBody {
+		body = body[:maxHookRejectionBody] + "..."
+	}
+	return "hook rejected the payload: " + http.StatusText(r.status) + ": " + body
+}
+
+func callHook(client *http.Client, u string, ctx functions.Context, v interface{}) (interface{}, error) {
+	req, err := functions.NewMessageRequest(u, hookContext(ctx), v)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating hook request")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading hook response")
+	}
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return v, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, &hookRejection{resp.StatusCode, string(respBody)}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, errors.Errorf("hook returned %s", resp.Status)
+	}
+	var msg functions.Message
+	if err := json.Unmarshal(respBody, &msg); err != nil {
+		return nil, errors.Wrap(err, "hook response is not a function message")
+	}
+	return msg.Output()
+}
+
+// hookContext returns the hookContextKeys of the function context
+func hookContext(ctx functions.Context) functions.Context {
+	hctx := functions.Context{}
+	for _, k := range hookContextKeys {
+		if v, ok := ctx[k]; ok {
+			hctx[k] = v
+		}
+	}
+	return hctx
+}
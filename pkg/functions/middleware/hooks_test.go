@@ -0,0 +1,119 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+// testHookHosts allows the hooks of the test servers
+var testHookHosts = []string{"127.0.0.1"}
+
+func TestHTTPHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg functions.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		payload, _ := msg.Payload.(map[string]interface{})
+		switch r.URL.Path {
+		case "/pre":
+			if payload["token"] != "valid" {
+				http.Error(w, "invalid token", http.StatusForbidden)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"payload": map[string]interface{}{"user": "jon"}})
+		case "/post":
+			payload["audited"] = true
+			json.NewEncoder(w).Encode(msg)
+		case "/reject":
+			http.Error(w, strings.Repeat("invalid ", 1000), http.StatusBadRequest)
+		case "/redirect":
+			http.Redirect(w, r, "/pre", http.StatusTemporaryRedirect)
+		case "/noop":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	m, err := newHTTPHook(testHookHosts)(map[string]string{"pre": server.URL + "/pre", "post": server.URL + "/post"})
+	require.NoError(t, err)
+	out, err := m(echo)(functions.Context{}, map[string]interface{}{"token": "valid"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"user": "jon", "audited": true}, out)
+
+	_, err = m(echo)(functions.Context{}, map[string]interface{}{"token": "invalid"})
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.InputError)(nil), err)
+
+	m, err = newHTTPHook(testHookHosts)(map[string]string{"pre": server.URL + "/noop", "post": server.URL + "/broken"})
+	require.NoError(t, err)
+	_, err = m(echo)(functions.Context{}, map[string]interface{}{"token": "valid"})
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.SystemError)(nil), err)
+
+	m, err = newHTTPHook(testHookHosts)(map[string]string{"pre": server.URL + "/reject"})
+	require.NoError(t, err)
+	_, err = m(echo)(functions.Context{}, map[string]interface{}{})
+	assert.Implements(t, (*functions.InputError)(nil), err)
+	assert.True(t, len(err.Error()) < 2*maxHookRejectionBody, "the body of the rejection is truncated")
+
+	// redirects are not followed, as they could lead to a host which is not allowed
+	m, err = newHTTPHook(testHookHosts)(map[string]string{"pre": server.URL + "/redirect"})
+	require.NoError(t, err)
+	_, err = m(echo)(functions.Context{}, map[string]interface{}{"token": "valid"})
+	assert.Implements(t, (*functions.SystemError)(nil), err)
+}
+
+func TestHTTPHookHosts(t *testing.T) {
+	_, err := newHTTPHook(nil)(map[string]string{"pre": "http://127.0.0.1/pre"})
+	assert.Error(t, err)
+	_, err = newHTTPHook([]string{"hooks.example.com"})(map[string]string{"post": "http://169.254.169.254/latest"})
+	assert.Error(t, err)
+	_, err = newHTTPHook([]string{"hooks.example.com"})(map[string]string{"pre": "https://HOOKS.example.com:8443/pre"})
+	assert.NoError(t, err)
+}
+
+func TestHTTPHookContext(t *testing.T) {
+	var contexts []functions.Context
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg functions.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		contexts = append(contexts, msg.Context)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	m, err := newHTTPHook(testHookHosts)(map[string]string{"pre": server.URL + "/pre", "post": server.URL + "/post"})
+	require.NoError(t, err)
+	ctx := functions.Context{
+		functions.InvocationKey: map[string]interface{}{"token": "invocation-token"},
+		functions.EventKey:      map[string]interface{}{"eventID": "event-id"},
+	}
+	// the injectors inside the hooks add the secrets, services and config of the function to the context
+	inject := func(ctx functions.Context, in interface{}) (interface{}, error) {
+		ctx["secrets"] = map[string]interface{}{"password": "secret"}
+		ctx["serviceBindings"] = map[string]interface{}{"db": "binding"}
+		ctx["config"] = map[string]interface{}{"URL": "config"}
+		return in, nil
+	}
+	_, err = m(inject)(ctx, map[string]interface{}{"name": "jon"})
+	require.NoError(t, err)
+
+	require.Len(t, contexts, 2)
+	for _, hctx := range contexts {
+		assert.Equal(t, functions.Context{functions.EventKey: map[string]interface{}{"eventID": "event-id"}}, hctx)
+	}
+}
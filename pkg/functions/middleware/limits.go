@@ -0,0 +1,109 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+// newSizeLimit rejects inputs larger than "maxInput" and outputs larger than "maxOutput" bytes. The size of a JSON
+// value is the size of its encoding, the size of a binary or text payload the size of its data.
+func newSizeLimit(config map[string]string) (functions.Middleware, error) {
+	maxInput, err := sizeConfig(config, "maxInput")
+	if err != nil {
+		return nil, err
+	}
+	maxOutput, err := sizeConfig(config, "maxOutput")
+	if err != nil {
+		return nil, err
+	}
+	if maxInput == 0 && maxOutput == 0 {
+		return nil, errors.New("maxInput or maxOutput is required")
+	}
+	return func(f functions.Runnable) functions.Runnable {
+		return func(ctx functions.Context, in interface{}) (interface{}, error) {
+			if maxInput > 0 {
+				if size := payloadSize(in); size > maxInput {
+					return nil, &inputError{errors.Errorf("input of %d bytes exceeds the limit of %d bytes", size, maxInput)}
+				}
+			}
+			out, err := f(ctx, in)
+			if err != nil {
+				return nil, err
+			}
+			if maxOutput > 0 {
+				if size := payloadSize(out); size > maxOutput {
+					return nil, &outputError{errors.Errorf("output of %d bytes exceeds the limit of %d bytes", size, maxOutput)}
+				}
+			}
+			return out, nil
+		}
+	}, nil
+}
+
+func payloadSize(v interface{}) int {
+	if p, ok := v.(*functions.Payload); ok {
+		return len(p.Data)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// newLogging adds the input and output size, the duration and the error of each run to its logs, and the input and
+// output themselves if "payloads" is true.
+func newLogging(config map[string]string) (functions.Middleware, error) {
+	payloads := false
+	switch config["payloads"] {
+	case "", "false":
+	case "true":
+		payloads = true
+	default:
+		return nil, errors.Errorf("payloads must be true or false, got %q", config["payloads"])
+	}
+	return func(f functions.Runnable) functions.Runnable {
+		return func(ctx functions.Context, in interface{}) (interface{}, error) {
+			var lines []string
+			lines = append(lines, fmt.Sprintf("[logging] input: %d bytes", payloadSize(in)))
+			if payloads {
+				lines = append(lines, fmt.Sprintf("[logging] input: %s", describe(in)))
+			}
+			start := time.Now()
+			out, err := f(ctx, in)
+			duration := time.Since(start)
+			if err != nil {
+				lines = append(lines, fmt.Sprintf("[logging] error after %s: %s", duration, err))
+			} else {
+				lines = append(lines, fmt.Sprintf("[logging] output: %d bytes in %s", payloadSize(out), duration))
+				if payloads {
+					lines = append(lines, fmt.Sprintf("[logging] output: %s", describe(out)))
+				}
+			}
+			log.Debugf("function run: %v", lines)
+			// the logs are added after the run, the driver sets the logs of the function in the context
+			ctx.AddLogs(v1.Logs{Stdout: lines})
+			return out, err
+		}
+	}, nil
+}
+
+func describe(v interface{}) string {
+	if p, ok := v.(*functions.Payload); ok {
+		return fmt.Sprintf("%s payload", p.ContentType)
+	}
+	s, _ := toJSON(v)
+	return s
+}
@@ -0,0 +1,59 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+func TestSizeLimit(t *testing.T) {
+	m, err := newSizeLimit(map[string]string{"maxInput": "10", "maxOutput": "4"})
+	require.NoError(t, err)
+
+	out, err := m(echo)(functions.Context{}, "ab")
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", out)
+
+	_, err = m(echo)(functions.Context{}, "abcdefghijk")
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.InputError)(nil), err)
+
+	_, err = m(echo)(functions.Context{}, "abcd")
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.FunctionError)(nil), err)
+
+	_, err = m(echo)(functions.Context{}, &functions.Payload{ContentType: "image/png", Data: make([]byte, 11)})
+	assert.Implements(t, (*functions.InputError)(nil), err)
+}
+
+func TestLogging(t *testing.T) {
+	m, err := newLogging(map[string]string{"payloads": "true"})
+	require.NoError(t, err)
+
+	ctx := functions.Context{}
+	_, err = m(echo)(ctx, map[string]interface{}{"name": "Jon"})
+	assert.NoError(t, err)
+	logs := ctx.Logs().Stdout
+	require.Len(t, logs, 4)
+	assert.Equal(t, "[logging] input: 14 bytes", logs[0])
+	assert.Equal(t, `[logging] input: {"name":"Jon"}`, logs[1])
+	assert.Contains(t, logs[2], "[logging] output: 14 bytes in ")
+	assert.Equal(t, `[logging] output: {"name":"Jon"}`, logs[3])
+
+	failing := func(ctx functions.Context, in interface{}) (interface{}, error) {
+		return nil, errors.New("failed")
+	}
+	ctx = functions.Context{}
+	_, err = m(failing)(ctx, nil)
+	assert.Error(t, err)
+	assert.Contains(t, ctx.Logs().Stdout[2], "failed")
+}
@@ -0,0 +1,132 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/functions"
+	"github.com/vmware/dispatch/pkg/functions/runner"
+)
+
+// Names of the built-in middlewares
+const (
+	TemplateMiddleware  = "template"
+	RedactMiddleware    = "redact"
+	SizeLimitMiddleware = "size-limit"
+	LoggingMiddleware   = "logging"
+	HTTPHookMiddleware  = "http-hook"
+)
+
+// Factory creates a middleware from its configuration, returning an error if the configuration is invalid
+type Factory func(config map[string]string) (functions.Middleware, error)
+
+// Registry holds the named middlewares functions can opt into
+type Registry struct {
+	sync.RWMutex
+	factories map[string]Factory
+}
+
+// New creates a middleware registry with the built-in middlewares, the http-hook middleware calling only the hooks of
+// hookHosts
+func New(hookHosts []string) *Registry {
+	r := &Registry{
+		factories: make(map[string]Factory),
+	}
+	r.Register(TemplateMiddleware, newTemplate)
+	r.Register(RedactMiddleware, newRedact)
+	r.Register(SizeLimitMiddleware, newSizeLimit)
+	r.Register(LoggingMiddleware, newLogging)
+	r.Register(HTTPHookMiddleware, newHTTPHook(hookHosts))
+	return r
+}
+
+// Register adds a named middleware, replacing the middleware already registered with the same name
+func (r *Registry) Register(name string, factory Factory) {
+	r.Lock()
+	defer r.Unlock()
+	r.factories[name] = factory
+}
+
+// Names returns the sorted names of the registered middlewares
+func (r *Registry) Names() []string {
+	r.RLock()
+	defer r.RUnlock()
+	var names []string
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Registry) build(middlewares []functions.MiddlewareConfig) ([]functions.Middleware, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var ms []functions.Middleware
+	for _, c := range middlewares {
+		factory, ok := r.factories[c.Name]
+		if !ok {
+			return nil, errors.Errorf("unknown middleware %q", c.Name)
+		}
+		m, err := factory(c.Config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration of middleware %s", c.Name)
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// Validate returns an error if a middleware is not registered or its configuration is invalid
+func (r *Registry) Validate(middlewares []functions.MiddlewareConfig) error {
+	_, err := r.build(middlewares)
+	return err
+}
+
+// GetMiddleware composes the middlewares in order, the first one being the outermost
+func (r *Registry) GetMiddleware(middlewares []functions.MiddlewareConfig) functions.Middleware {
+	ms, err := r.build(middlewares)
+	if err != nil {
+		return func(f functions.Runnable) functions.Runnable {
+			return func(ctx functions.Context, in interface{}) (interface{}, error) {
+				return nil, &systemError{err}
+			}
+		}
+	}
+	return runner.Compose(ms...)
+}
+
+// sizeConfig parses an optional size in bytes, 0 if the key is not set
+func sizeConfig(config map[string]string, key string) (int, error) {
+	value, ok := config[key]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, errors.Errorf("%s must be a number of bytes, got %q", key, value)
+	}
+	return size, nil
+}
+
+// durationConfig parses an optional duration, def if the key is not set
+func durationConfig(config map[string]string, key string, def time.Duration) (time.Duration, error) {
+	value, ok := config[key]
+	if !ok || value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("%s must be a positive duration (e.g. 5s), got %q", key, value)
+	}
+	return d, nil
+}
@@ -0,0 +1,68 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+func echo(ctx functions.Context, in interface{}) (interface{}, error) {
+	return in, nil
+}
+
+func appendMiddleware(s string) Factory {
+	return func(config map[string]string) (functions.Middleware, error) {
+		return func(f functions.Runnable) functions.Runnable {
+			return func(ctx functions.Context, in interface{}) (interface{}, error) {
+				out, err := f(ctx, in.(string)+s+config["in"])
+				if err != nil {
+					return nil, err
+				}
+				return out.(string) + s + config["out"], nil
+			}
+		}, nil
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := New(nil)
+	assert.Equal(t, []string{"http-hook", "logging", "redact", "size-limit", "template"}, r.Names())
+
+	r.Register("a", appendMiddleware("a"))
+	r.Register("b", appendMiddleware("b"))
+	middlewares := []functions.MiddlewareConfig{
+		{Name: "a", Config: map[string]string{"in": "1", "out": "2"}},
+		{Name: "b"},
+	}
+	assert.NoError(t, r.Validate(middlewares))
+	out, err := r.GetMiddleware(middlewares)(echo)(functions.Context{}, ">")
+	assert.NoError(t, err)
+	assert.Equal(t, ">a1bba2", out)
+
+	out, err = r.GetMiddleware(nil)(echo)(functions.Context{}, ">")
+	assert.NoError(t, err)
+	assert.Equal(t, ">", out)
+}
+
+func TestRegistryInvalid(t *testing.T) {
+	r := New(nil)
+	unknown := []functions.MiddlewareConfig{{Name: "unknown"}}
+	assert.Error(t, r.Validate(unknown))
+	_, err := r.GetMiddleware(unknown)(echo)(functions.Context{}, nil)
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.SystemError)(nil), err)
+
+	assert.Error(t, r.Validate([]functions.MiddlewareConfig{{Name: SizeLimitMiddleware}}))
+	assert.Error(t, r.Validate([]functions.MiddlewareConfig{{Name: SizeLimitMiddleware, Config: map[string]string{"maxInput": "1k"}}}))
+	assert.Error(t, r.Validate([]functions.MiddlewareConfig{{Name: TemplateMiddleware, Config: map[string]string{"template": "{{"}}}))
+	assert.Error(t, r.Validate([]functions.MiddlewareConfig{{Name: HTTPHookMiddleware, Config: map[string]string{"pre": "ftp://hook"}}}))
+	assert.Error(t, r.Validate([]functions.MiddlewareConfig{{Name: RedactMiddleware}}))
+	assert.Error(t, r.Validate([]functions.MiddlewareConfig{{Name: LoggingMiddleware, Config: map[string]string{"payloads": "yes"}}}))
+}
@@ -0,0 +1,103 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+const defaultReplacement = "[REDACTED]"
+
+// newTemplate transforms the input with the text/template in "template", which must render JSON. The template is
+// executed with the input as data and can use the json function to encode a value.
+func newTemplate(config map[string]string) (functions.Middleware, error) {
+	text := config["template"]
+	if text == "" {
+		return nil, errors.New("template is required")
+	}
+	tmpl, err := template.New("input").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing template")
+	}
+	return func(f functions.Runnable) functions.Runnable {
+		return func(ctx functions.Context, in interface{}) (interface{}, error) {
+			// binary and text payloads are not JSON and are passed as is
+			if _, ok := in.(*functions.Payload); ok {
+				return f(ctx, in)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, in); err != nil {
+				return nil, &inputError{errors.Wrap(err, "error executing input template")}
+			}
+			var transformed interface{}
+			if err := json.Unmarshal(buf.Bytes(), &transformed); err != nil {
+				return nil, &inputError{errors.Wrap(err, "input template did not render JSON")}
+			}
+			return f(ctx, transformed)
+		}
+	}, nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// newRedact replaces the fields of the output listed in "fields", comma-separated paths such as user.password, with
+// "replacement" ([REDACTED] by default). A path goes through every element of the arrays it meets.
+func newRedact(config map[string]string) (functions.Middleware, error) {
+	var paths [][]string
+	for _, field := range strings.Split(config["fields"], ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			paths = append(paths, strings.Split(field, "."))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("fields is required")
+	}
+	replacement, ok := config["replacement"]
+	if !ok {
+		replacement = defaultReplacement
+	}
+	return func(f functions.Runnable) functions.Runnable {
+		return func(ctx functions.Context, in interface{}) (interface{}, error) {
+			out, err := f(ctx, in)
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range paths {
+				redact(out, path, replacement)
+			}
+			return out, nil
+		}
+	}, nil
+}
+
+func redact(v interface{}, path []string, replacement string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		value, ok := v[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			v[path[0]] = replacement
+			return
+		}
+		redact(value, path[1:], replacement)
+	case []interface{}:
+		for _, item := range v {
+			redact(item, path, replacement)
+		}
+	}
+}
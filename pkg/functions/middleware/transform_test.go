@@ -0,0 +1,66 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/dispatch/pkg/functions"
+)
+
+func TestTemplate(t *testing.T) {
+	m, err := newTemplate(map[string]string{"template": `{"name": {{json .user.name}}, "greeting": "hello"}`})
+	require.NoError(t, err)
+
+	in := map[string]interface{}{"user": map[string]interface{}{"name": "Jon"}}
+	out, err := m(echo)(functions.Context{}, in)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "Jon", "greeting": "hello"}, out)
+
+	m, err = newTemplate(map[string]string{"template": `{{.name}}`})
+	require.NoError(t, err)
+	_, err = m(echo)(functions.Context{}, map[string]interface{}{"name": "not json"})
+	assert.Error(t, err)
+	assert.Implements(t, (*functions.InputError)(nil), err)
+
+	payload := &functions.Payload{ContentType: "text/plain", Data: []byte("hello")}
+	out, err = m(echo)(functions.Context{}, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, out)
+}
+
+func TestRedact(t *testing.T) {
+	m, err := newRedact(map[string]string{"fields": "password, users.token,missing.field"})
+	require.NoError(t, err)
+
+	output := func(ctx functions.Context, in interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"password": "secret",
+			"users": []interface{}{
+				map[string]interface{}{"name": "a", "token": "t1"},
+				map[string]interface{}{"name": "b"},
+			},
+		}, nil
+	}
+	out, err := m(output)(functions.Context{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"password": "[REDACTED]",
+		"users": []interface{}{
+			map[string]interface{}{"name": "a", "token": "[REDACTED]"},
+			map[string]interface{}{"name": "b"},
+		},
+	}, out)
+
+	m, err = newRedact(map[string]string{"fields": "password", "replacement": ""})
+	require.NoError(t, err)
+	out, err = m(output)(functions.Context{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.(map[string]interface{})["password"])
+}
@@ -0,0 +1,29 @@
+// Code generated by mockery v1.0.0
+
+// CLOSE THIS FILE AS QUICKLY AS POSSIBLE
+
+package mocks
+
+import functions "github.com/vmware/dispatch/pkg/functions"
+import mock "github.com/stretchr/testify/mock"
+
+// ConfigInjector is an autogenerated mock type for the ConfigInjector type
+type ConfigInjector struct {
+	mock.Mock
+}
+
+// GetMiddleware provides a mock function with given fields: organizationID, config, configMaps
+func (_m *ConfigInjector) GetMiddleware(organizationID string, config map[string]string, configMaps []string) functions.Middleware {
+	ret := _m.Called(organizationID, config, configMaps)
+
+	var r0 functions.Middleware
+	if rf, ok := ret.Get(0).(func(string, map[string]string, []string) functions.Middleware); ok {
+		r0 = rf(organizationID, config, configMaps)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(functions.Middleware)
+		}
+	}
+
+	return r0
+}
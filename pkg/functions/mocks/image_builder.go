@@ -6,6 +6,7 @@ package mocks
 
 import context "context"
 import functions "github.com/vmware/dispatch/pkg/functions"
+import io "io"
 import mock "github.com/stretchr/testify/mock"
 
 // ImageBuilder is an autogenerated mock type for the ImageBuilder type
@@ -13,20 +14,20 @@ type ImageBuilder struct {
 	mock.Mock
 }
 
-// BuildImage provides a mock function with given fields: ctx, f
-func (_m *ImageBuilder) BuildImage(ctx context.Context, f *functions.Function) (string, error) {
-	ret := _m.Called(ctx, f)
+// BuildImage provides a mock function with given fields: ctx, f, output
+func (_m *ImageBuilder) BuildImage(ctx context.Context, f *functions.Function, output io.Writer) (string, error) {
+	ret := _m.Called(ctx, f, output)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, *functions.Function) string); ok {
-		r0 = rf(ctx, f)
+	if rf, ok := ret.Get(0).(func(context.Context, *functions.Function, io.Writer) string); ok {
+		r0 = rf(ctx, f, output)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *functions.Function) error); ok {
-		r1 = rf(ctx, f)
+	if rf, ok := ret.Get(1).(func(context.Context, *functions.Function, io.Writer) error); ok {
+		r1 = rf(ctx, f, output)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,43 @@
+// Code generated by mockery v1.0.0
+
+// CLOSE THIS FILE AS QUICKLY AS POSSIBLE
+
+package mocks
+
+import functions "github.com/vmware/dispatch/pkg/functions"
+import mock "github.com/stretchr/testify/mock"
+
+// MiddlewareRegistry is an autogenerated mock type for the MiddlewareRegistry type
+type MiddlewareRegistry struct {
+	mock.Mock
+}
+
+// GetMiddleware provides a mock function with given fields: middlewares
+func (_m *MiddlewareRegistry) GetMiddleware(middlewares []functions.MiddlewareConfig) functions.Middleware {
+	ret := _m.Called(middlewares)
+
+	var r0 functions.Middleware
+	if rf, ok := ret.Get(0).(func([]functions.MiddlewareConfig) functions.Middleware); ok {
+		r0 = rf(middlewares)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(functions.Middleware)
+		}
+	}
+
+	return r0
+}
+
+// Validate provides a mock function with given fields: middlewares
+func (_m *MiddlewareRegistry) Validate(middlewares []functions.MiddlewareConfig) error {
+	ret := _m.Called(middlewares)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]functions.MiddlewareConfig) error); ok {
+		r0 = rf(middlewares)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
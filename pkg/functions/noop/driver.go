@@ -60,6 +60,10 @@ const xStderrHeader = "X-Stderr"
 // GetRunnable returns a functions.Runnable
 func (d *noopDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
 	return func(ctx functions.Context, in interface{}) (interface{}, error) {
+		// binary and text inputs are echoed as is
+		if p, ok := in.(*functions.Payload); ok {
+			return p, nil
+		}
 		return ctxAndIn{Context: ctx, Input: in}, nil
 	}
 }
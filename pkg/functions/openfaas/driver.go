@@ -221,9 +221,12 @@ const xStderrHeader = "X-Stderr"
 
 func (d *ofDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
 	return func(ctx functions.Context, in interface{}) (interface{}, error) {
-		bytesIn, _ := json.Marshal(functions.Message{Context: ctx, Payload: in})
 		postURL := d.gateway + "/function/" + getID(e.FaasID)
-		res, err := d.httpClient.Post(postURL, jsonContentType, bytes.NewReader(bytesIn))
+		req, err := functions.NewMessageRequest(postURL, ctx, in)
+		if err != nil {
+			return nil, &systemError{err}
+		}
+		res, err := d.httpClient.Do(req)
 		if err != nil {
 			log.Errorf("Error when sending POST request to %s: %+v", postURL, err)
 			return nil, &systemError{errors.Wrapf(err, "request to OpenFaaS on %s failed", d.gateway)}
@@ -243,7 +246,11 @@ func (d *ofDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnabl
 			}
 			ctx.AddLogs(out.Context.Logs())
 			ctx.SetError(out.Context.GetError())
-			return out.Payload, nil
+			output, err := out.Output()
+			if err != nil {
+				return nil, &systemError{err}
+			}
+			return output, nil
 
 		default:
 			bytesOut, err := ioutil.ReadAll(res.Body)
@@ -83,14 +83,25 @@ func (d *wskDriver) Delete(ctx context.Context, f *functions.Function) error {
 type ctxAndIn struct {
 	Context functions.Context `json:"context"`
 	Input   interface{}       `json:"input"`
+	// ContentType is set for binary and text inputs, which are base64-encoded strings
+	ContentType string `json:"contentType,omitempty"`
 }
 
 func (d *wskDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
 	return func(ctx functions.Context, in interface{}) (interface{}, error) {
-		result, _, err := d.client.Actions.Invoke(e.FunctionID, ctxAndIn{Context: ctx, Input: in}, true, true)
+		input, contentType := functions.EncodePayload(in)
+		result, _, err := d.client.Actions.Invoke(e.FunctionID, ctxAndIn{Context: ctx, Input: input, ContentType: contentType}, true, true)
 		if err != nil {
 			return nil, &systemError{errors.Wrapf(err, "openwhisk: error invoking function: '%s', runID: '%s'", e.FunctionID, e.RunID)} // TODO err should be JSON-serializable and usable (e.g. invalid arg vs runtime error)
 		}
+		// binary and text outputs are returned as a base64-encoded payload with its content type
+		if contentType, ok := result["contentType"].(string); ok {
+			output, err := functions.DecodePayload(result["payload"], contentType)
+			if err != nil {
+				return nil, &systemError{err}
+			}
+			return output, nil
+		}
 		return result, nil
 	}
 }
@@ -0,0 +1,92 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functions
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Payload is a binary or text function input or output, e.g. an uploaded image or CSV file
+type Payload struct {
+	ContentType string
+	Data        []byte
+}
+
+// IsJSONContentType returns true if a payload of the content type is JSON, including JSON-based media types such as
+// application/cloudevents+json, an empty content type is JSON
+func IsJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")
+}
+
+// EncodePayload returns the JSON representation of a function input or output with its content type: the data of a
+// *Payload is base64-encoded, any other value is JSON and returned as is with an empty content type.
+func EncodePayload(v interface{}) (interface{}, string) {
+	if p, ok := v.(*Payload); ok {
+		return base64.StdEncoding.EncodeToString(p.Data), p.ContentType
+	}
+	return v, ""
+}
+
+// DecodePayload reverses EncodePayload, returning a *Payload unless the content type is JSON
+func DecodePayload(v interface{}, contentType string) (interface{}, error) {
+	if IsJSONContentType(contentType) {
+		return v, nil
+	}
+	encoded, ok := v.(string)
+	if !ok && v != nil {
+		return nil, errors.Errorf("%s payload must be a base64-encoded string", contentType)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s payload", contentType)
+	}
+	return &Payload{ContentType: contentType, Data: data}, nil
+}
+
+// NewMessage creates the message invoking a function with the input
+func NewMessage(ctx Context, in interface{}) Message {
+	payload, contentType := EncodePayload(in)
+	return Message{Context: ctx, Payload: payload, ContentType: contentType}
+}
+
+// NewMessageRequest creates the POST request invoking a function container with the input, its headers carrying the
+// span context of the run for runtimes reading it from the request rather than the function context
+func NewMessageRequest(url string, ctx Context, in interface{}) (*http.Request, error) {
+	body, err := json.Marshal(NewMessage(ctx, in))
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding the function message")
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating the request to %s", url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if spanCtx := ctx.Trace(); spanCtx != nil {
+		opentracing.GlobalTracer().Inject(spanCtx, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	}
+	return req, nil
+}
+
+// Output returns the payload of a message returned by a function
+func (m *Message) Output() (interface{}, error) {
+	return DecodePayload(m.Payload, m.ContentType)
+}
@@ -0,0 +1,103 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package functions
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsJSONContentType(t *testing.T) {
+	testCases := []struct {
+		In  string
+		Out bool
+	}{
+		{"", true},
+		{"multipart/form-data; boundary=---------------------------974767299852498929531610575", false},
+		{"text/html; charset=utf-8", false},
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"application/cloudevents+json", true},
+	}
+
+	for _, c := range testCases {
+		assert.Equal(t, c.Out, IsJSONContentType(c.In), c.In)
+	}
+}
+
+func TestMessagePayload(t *testing.T) {
+	image := &Payload{ContentType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}}
+	bs, err := json.Marshal(NewMessage(Context{}, image))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"context":{},"payload":"iVBORw==","contentType":"image/png"}`, string(bs))
+
+	var m Message
+	require.NoError(t, json.Unmarshal(bs, &m))
+	output, err := m.Output()
+	require.NoError(t, err)
+	assert.Equal(t, image, output)
+
+	bs, err = json.Marshal(NewMessage(Context{}, map[string]interface{}{"name": "Jon"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"context":{},"payload":{"name":"Jon"}}`, string(bs))
+}
+
+func TestDecodePayload(t *testing.T) {
+	v, err := DecodePayload(map[string]interface{}{"name": "Jon"}, "application/json; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "Jon"}, v)
+
+	v, err = DecodePayload("YSxiCjEsMgo=", "text/csv")
+	require.NoError(t, err)
+	assert.Equal(t, &Payload{ContentType: "text/csv", Data: []byte("a,b\n1,2\n")}, v)
+
+	_, err = DecodePayload(map[string]interface{}{}, "text/csv")
+	assert.Error(t, err)
+	_, err = DecodePayload("not base64!", "text/csv")
+	assert.Error(t, err)
+}
+
+func TestNewMessageRequest(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+	span := tracer.StartSpan("run")
+	defer span.Finish()
+
+	ctx := Context{}
+	ctx.InjectTrace(span)
+	assert.Equal(t, span.Context(), ctx.Trace())
+
+	// the context of a message returned by a runtime is decoded JSON
+	bs, err := json.Marshal(ctx)
+	require.NoError(t, err)
+	ctx = Context{}
+	require.NoError(t, json.Unmarshal(bs, &ctx))
+	assert.Equal(t, span.Context(), ctx.Trace())
+
+	req, err := NewMessageRequest("http://hello:8080", ctx, map[string]interface{}{"name": "Jon"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	spanCtx, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	require.NoError(t, err)
+	assert.Equal(t, span.Context(), spanCtx)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	var m Message
+	require.NoError(t, json.Unmarshal(body, &m))
+	assert.Equal(t, map[string]interface{}{"name": "Jon"}, m.Payload)
+
+	assert.Nil(t, Context{}.Trace())
+	req, err = NewMessageRequest("http://hello:8080", Context{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("Mockpfx-Ids-Traceid"))
+}
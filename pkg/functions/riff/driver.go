@@ -104,7 +104,7 @@ func (d *riffDriver) Delete(ctx context.Context, f *functions.Function) error {
 func (d *riffDriver) GetRunnable(e *functions.FunctionExecution) functions.Runnable {
 	return func(ctx functions.Context, in interface{}) (interface{}, error) {
 
-		bytesIn, _ := json.Marshal(functions.Message{Context: ctx, Payload: in})
+		bytesIn, _ := json.Marshal(functions.NewMessage(ctx, in))
 		topic := fnID(e.FaasID)
 
 		log.Debugf("Posting to topic '%s': '%s'", topic, string(bytesIn))
@@ -120,7 +120,11 @@ func (d *riffDriver) GetRunnable(e *functions.FunctionExecution) functions.Runna
 		}
 		ctx.AddLogs(out.Context.Logs())
 		ctx.SetError(out.Context.GetError())
-		return out.Payload, nil
+		output, err := out.Output()
+		if err != nil {
+			return nil, &systemError{err}
+		}
+		return output, nil
 	}
 }
 
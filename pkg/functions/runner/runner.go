@@ -15,6 +15,8 @@ type Config struct {
 	Validator       functions.Validator
 	SecretInjector  functions.SecretInjector
 	ServiceInjector functions.ServiceInjector
+	ConfigInjector  functions.ConfigInjector
+	Middlewares     functions.MiddlewareRegistry
 }
 
 type impl struct {
@@ -27,11 +29,20 @@ func New(config *Config) functions.Runner {
 }
 
 func (r *impl) Run(fn *functions.FunctionExecution, in interface{}) (interface{}, error) {
+	if fn.Logs != nil {
+		// drivers and middlewares add the logs to the context as they are produced
+		fn.Context.SetLogWriter(fn.Logs)
+	}
 	f := r.Faas.GetRunnable(fn)
 	m := Compose(
+		// the middlewares of the function run outside the validator, so that the schemas apply to the input they
+		// transformed and to the output before they transform it, and before secrets, services and config are
+		// injected in the context
+		r.Middlewares.GetMiddleware(fn.Middlewares),
 		r.Validator.GetMiddleware(fn.Schemas),
 		r.SecretInjector.GetMiddleware(fn.OrganizationID, fn.Secrets, fn.Cookie),
 		r.ServiceInjector.GetMiddleware(fn.OrganizationID, fn.Services, fn.Cookie),
+		r.ConfigInjector.GetMiddleware(fn.OrganizationID, fn.Config, fn.ConfigMaps),
 	)
 	return m(f)(fn.Context, in)
 }
@@ -24,6 +24,7 @@ const (
 	f0            = "f0"
 	m1            = "m1"
 	m2            = "m2"
+	custom        = "custom"
 	argsStr       = "args"
 	traceInStr    = "trace-in"
 	traceOutStr   = "trace-out"
@@ -34,6 +35,8 @@ func TestRun(t *testing.T) {
 	v := &mocks.Validator{}
 	secretInjector := &mocks.SecretInjector{}
 	serviceInjector := &mocks.ServiceInjector{}
+	configInjector := &mocks.ConfigInjector{}
+	middlewares := &mocks.MiddlewareRegistry{}
 	testSchemas := &functions.Schemas{SchemaIn: testSchemaIn, SchemaOut: testSchemaOut}
 	fe := &functions.FunctionExecution{
 		Context:        functions.Context{},
@@ -49,8 +52,10 @@ func TestRun(t *testing.T) {
 	v.On("GetMiddleware", testSchemas).Return(functions.Middleware(mw0(validation)))
 	secretInjector.On("GetMiddleware", "testOrg", []string{}, "cookie").Return(functions.Middleware(mw0(injection)))
 	serviceInjector.On("GetMiddleware", "testOrg", []string{}, "cookie").Return(functions.Middleware(mw0(injection)))
+	configInjector.On("GetMiddleware", "testOrg", map[string]string(nil), []string(nil)).Return(functions.Middleware(mw0(injection)))
+	middlewares.On("GetMiddleware", []functions.MiddlewareConfig(nil)).Return(functions.Middleware(mw0(custom)))
 
-	testRunner := New(&Config{faas, v, secretInjector, serviceInjector})
+	testRunner := New(&Config{faas, v, secretInjector, serviceInjector, configInjector, middlewares})
 
 	fn := &functions.FunctionExecution{
 		Context:        functions.Context{},
@@ -65,11 +70,12 @@ func TestRun(t *testing.T) {
 	result, err := testRunner.Run(fn, args)
 	faas.AssertExpectations(t)
 	v.AssertExpectations(t)
+	middlewares.AssertExpectations(t)
 	assert.Nil(t, err)
 	expected := map[string]interface{}{
 		argsStr:     args,
-		traceInStr:  []string{validation, injection, injection, f0},
-		traceOutStr: []string{f0, injection, injection, validation},
+		traceInStr:  []string{custom, validation, injection, injection, injection, f0},
+		traceOutStr: []string{f0, injection, injection, injection, validation, custom},
 	}
 	assert.Equal(t, expected, result)
 }
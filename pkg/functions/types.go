@@ -7,8 +7,11 @@ package functions
 
 import (
 	"context"
+	"io"
 
 	"github.com/pkg/errors"
+
+	"github.com/vmware/dispatch/pkg/api/v1"
 )
 
 // NO TESTS
@@ -20,6 +23,8 @@ type Context map[string]interface{}
 type Message struct {
 	Context Context     `json:"context"`
 	Payload interface{} `json:"payload"`
+	// ContentType is set for binary and text payloads, which are base64-encoded strings
+	ContentType string `json:"contentType,omitempty"`
 }
 
 // Runnable is a runnable representation of a function
@@ -45,10 +50,21 @@ type FunctionExecution struct {
 	FunctionID string
 	FaasID     string
 
-	Schemas  *Schemas
-	Secrets  []string
-	Services []string
-	Cookie   string
+	Schemas     *Schemas
+	Secrets     []string
+	Services    []string
+	Config      map[string]string
+	ConfigMaps  []string
+	Middlewares []MiddlewareConfig
+	Cookie      string
+
+	// Logs receives the logs of the run as soon as they are added to the context, nil if not needed
+	Logs LogWriter
+}
+
+// LogWriter receives the logs added to the context of a run, the drivers add the logs of a function once it returns
+type LogWriter interface {
+	WriteLogs(logs v1.Logs)
 }
 
 //go:generate mockery -name FaaSDriver -case underscore -dir . -note "CLOSE THIS FILE AS QUICKLY AS POSSIBLE"
@@ -74,9 +90,9 @@ type FaaSDriver interface {
 
 // ImageBuilder builds a docker image for a serverless function.
 type ImageBuilder interface {
-	// BuildImage builds a function image and pushes it to the docker registry.
+	// BuildImage builds a function image and pushes it to the docker registry, writing the build output to output.
 	// Returns image full name.
-	BuildImage(ctx context.Context, f *Function) (string, error)
+	BuildImage(ctx context.Context, f *Function, output io.Writer) (string, error)
 }
 
 // Runner knows how to execute a function
@@ -103,6 +119,24 @@ type ServiceInjector interface {
 	GetMiddleware(organizationID string, services []string, cookie string) Middleware
 }
 
+//go:generate mockery -name ConfigInjector -case underscore -dir . -note "CLOSE THIS FILE AS QUICKLY AS POSSIBLE"
+
+// ConfigInjector injects configuration into function execution
+type ConfigInjector interface {
+	GetMiddleware(organizationID string, config map[string]string, configMaps []string) Middleware
+}
+
+//go:generate mockery -name MiddlewareRegistry -case underscore -dir . -note "CLOSE THIS FILE AS QUICKLY AS POSSIBLE"
+
+// MiddlewareRegistry builds the registered middlewares functions opt into
+type MiddlewareRegistry interface {
+	// Validate returns an error if a middleware is not registered or its configuration is invalid
+	Validate(middlewares []MiddlewareConfig) error
+
+	// GetMiddleware composes the middlewares in order, the first one being the outermost
+	GetMiddleware(middlewares []MiddlewareConfig) Middleware
+}
+
 // InputError represents user/input error
 type InputError interface {
 	AsInputErrorObject() interface{}
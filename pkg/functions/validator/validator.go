@@ -66,8 +66,10 @@ func (err *outputError) StackTrace() errors.StackTrace {
 func (*schemaValidator) GetMiddleware(schemas *functions.Schemas) functions.Middleware {
 	return func(f functions.Runnable) functions.Runnable {
 		return func(ctx functions.Context, input interface{}) (interface{}, error) {
+			// binary and text payloads are not JSON, schemas do not apply to them
+			_, binary := input.(*functions.Payload)
 			if schema, ok := schemas.SchemaIn.(*spec.Schema); ok {
-				if schema != nil {
+				if schema != nil && !binary {
 					if err := validate.AgainstSchema(schema, input, strfmt.Default); err != nil {
 						return nil, &inputError{errors.Wrap(err, "Input invalid against schema")}
 					}
@@ -79,8 +81,9 @@ func (*schemaValidator) GetMiddleware(schemas *functions.Schemas) functions.Midd
 			if err != nil {
 				return nil, err
 			}
+			_, binary = output.(*functions.Payload)
 			if schema, ok := schemas.SchemaOut.(*spec.Schema); ok {
-				if schema != nil {
+				if schema != nil && !binary {
 					if err := validate.AgainstSchema(schema, output, strfmt.Default); err != nil {
 						return nil, &outputError{errors.Wrap(err, "Output invalid against schema")}
 					}
@@ -121,6 +121,21 @@ func TestImpl_GetMiddleware(t *testing.T) {
 	}
 }
 
+func TestBinaryPayloadNotValidated(t *testing.T) {
+	schemas := &functions.Schemas{
+		SchemaIn:  &spec.Schema{SchemaProps: spec.SchemaProps{Required: []string{"inputField"}}},
+		SchemaOut: &spec.Schema{SchemaProps: spec.SchemaProps{Required: []string{"outputField"}}},
+	}
+	identity := func(ctx functions.Context, input interface{}) (interface{}, error) {
+		return input, nil
+	}
+	input := &functions.Payload{ContentType: "text/csv", Data: []byte("a,b\n1,2\n")}
+
+	output, err := New().GetMiddleware(schemas)(identity)(functions.Context{}, input)
+	require.NoError(t, err)
+	assert.Equal(t, input, output)
+}
+
 func TestNumberNoPanic(t *testing.T) {
 	var schemaJSON = `
 {
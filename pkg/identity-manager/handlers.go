@@ -32,6 +32,7 @@ import (
 	orgOperations "github.com/vmware/dispatch/pkg/identity-manager/gen/restapi/operations/organization"
 	policyOperations "github.com/vmware/dispatch/pkg/identity-manager/gen/restapi/operations/policy"
 	svcAccountOperations "github.com/vmware/dispatch/pkg/identity-manager/gen/restapi/operations/serviceaccount"
+	"github.com/vmware/dispatch/pkg/invocation"
 	"github.com/vmware/dispatch/pkg/trace"
 )
 
@@ -49,6 +50,7 @@ var IdentityManagerFlags = struct {
 	OAuth2ProxyAuthURL   string `long:"oauth2-proxy-auth-url" description:"The localhost url for oauth2proxy service's auth endpoint'" default:"http://localhost:4180/v1/iam/oauth2/auth"`
 	ServiceAccountDomain string `long:"service-account-domain" description:"The default domain name to use for service accounts" default:"svc.dispatch.local"`
 	Tracer               string `long:"tracer" description:"Open Tracing Tracer endpoint" default:""`
+	InvocationPublicKey  string `long:"invocation-token-public-key" description:"Path to the PEM-encoded RSA public key verifying the invocation tokens of function runs, empty rejects them" default:""`
 }{}
 
 const (
@@ -99,6 +101,9 @@ type Handlers struct {
 	watcher  controller.Watcher
 	store    entitystore.EntityStore
 	enforcer *casbin.SyncedEnforcer
+
+	// InvocationKey verifies the invocation tokens of function runs, nil rejects them
+	InvocationKey *rsa.PublicKey
 }
 
 // NewHandlers create a new Policy Manager Handler
@@ -199,6 +204,10 @@ func (h *Handlers) getAuthAccountFromToken(token string) (*authAccount, error) {
 		return nil, errors.New("missing issuer claim in unvalidated token")
 	}
 
+	if unverifiedIssuer == invocation.Issuer {
+		return h.getInvocationAccount(token)
+	}
+
 	var account *authAccount
 	var pubBase64Encoded string
 	// Get Public Key from secret if bootstrap mode is enabled
@@ -255,6 +264,23 @@ func (h *Handlers) getAuthAccountFromToken(token string) (*authAccount, error) {
 
 }
 
+// getInvocationAccount validates the invocation token of a function run, signed by the function manager
+func (h *Handlers) getInvocationAccount(token string) (*authAccount, error) {
+	if h.InvocationKey == nil {
+		return nil, errors.New("invocation tokens are not enabled")
+	}
+	claims, err := invocation.Parse(token, h.InvocationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &authAccount{
+		organizationID: claims.OrganizationID,
+		subject:        claims.Subject,
+		kind:           subjectFunction,
+		invocation:     claims,
+	}, nil
+}
+
 func (h *Handlers) validateJWTToken(token string, pubKey *rsa.PublicKey) error {
 
 	_, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
@@ -363,6 +389,19 @@ func (h *Handlers) auth(params operations.AuthParams, principal interface{}) mid
 		return operations.NewAuthAccepted().WithXDispatchOrg(bootstrapOrg)
 	}
 
+	// Invocation tokens of function runs are restricted to the organization and scopes of the token, no policy applies
+	if account.kind == subjectFunction {
+		if params.XDispatchOrg != nil && *params.XDispatchOrg != account.organizationID {
+			log.Debugf("Invocation token of %s used for organization %s", account.subject, *params.XDispatchOrg)
+			return operations.NewAuthForbidden()
+		}
+		if !reqAttrs.isResourceRequest || !account.invocation.Allows(reqAttrs.resource, string(reqAttrs.action)) {
+			log.Debugf("Invocation token of %s does not allow %s %s", account.subject, reqAttrs.action, params.HTTPRequest.Header.Get(HTTPHeaderReqURI))
+			return operations.NewAuthForbidden()
+		}
+		return operations.NewAuthAccepted().WithXDispatchOrg(account.organizationID)
+	}
+
 	// For User accounts, orgID can be missing after authentication, it just means the upstream IDP is not multi-tenant or
 	// Dispatch isn't configured with the claims that identify tenancy. Proceed with checking policies against user-
 	// specified org-id in XDispatchOrg Header.
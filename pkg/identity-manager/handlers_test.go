@@ -25,6 +25,7 @@ import (
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/entity-store"
 	"github.com/vmware/dispatch/pkg/identity-manager/gen/restapi/operations"
+	"github.com/vmware/dispatch/pkg/invocation"
 	helpers "github.com/vmware/dispatch/pkg/testing/api"
 )
 
@@ -802,3 +803,70 @@ func TestAuthenticateCookieMissingEmailHeader(t *testing.T) {
 	assert.Nil(t, principal)
 	assert.EqualError(t, err, "authentication failed: missing X-Auth-Request-Email header in response from oauth2proxy")
 }
+
+func createTestInvocationToken(organizationID string) string {
+	pvtKey, _ := invocation.LoadPrivateKey("testdata/test_key")
+	token, _ := invocation.Sign(invocation.NewClaims(organizationID, "hello", "f98d0a7f-0c1d-4020-a488-cabc501b08e0", time.Minute), pvtKey)
+	return token
+}
+
+func TestParseInvocationToken(t *testing.T) {
+	es := helpers.MakeEntityStore(t)
+	enforcer := SetupEnforcer(es)
+	h := NewHandlers(nil, es, enforcer)
+
+	token := createTestInvocationToken(testOrgA)
+	_, err := h.getAuthAccountFromToken(token)
+	assert.EqualError(t, err, "invocation tokens are not enabled")
+
+	h.InvocationKey, err = invocation.LoadPublicKey("testdata/test_key.pub")
+	assert.NoError(t, err)
+	account, err := h.getAuthAccountFromToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "function:hello", account.subject)
+	assert.Equal(t, testOrgA, account.organizationID)
+	assert.Equal(t, subjectFunction, account.kind)
+
+	h.InvocationKey, err = invocation.LoadPublicKey("testdata/test_key2.pub")
+	assert.NoError(t, err)
+	_, err = h.getAuthAccountFromToken(token)
+	assert.Error(t, err)
+}
+
+func TestAuthInvocationToken(t *testing.T) {
+	api := setupTestAPI(t, true)
+	account := &authAccount{
+		subject:        "function:hello",
+		organizationID: testOrgA,
+		kind:           subjectFunction,
+		invocation:     invocation.NewClaims(testOrgA, "hello", "f98d0a7f-0c1d-4020-a488-cabc501b08e0", time.Minute),
+	}
+
+	for _, test := range []struct {
+		method string
+		path   string
+		org    *string
+		status int
+	}{
+		{"POST", "/v1/event/", nil, http.StatusAccepted},
+		{"POST", "/v1/runs", &testOrgA, http.StatusAccepted},
+		{"GET", "/v1/runs/f98d0a7f-0c1d-4020-a488-cabc501b08e0", nil, http.StatusAccepted},
+		{"POST", "/v1/runs", &testOrgB, http.StatusForbidden},
+		{"POST", "/v1/function", nil, http.StatusForbidden},
+		{"GET", "/v1/secret/db", nil, http.StatusForbidden},
+		{"GET", "/echo", nil, http.StatusForbidden},
+	} {
+		request := httptest.NewRequest("GET", "/auth", nil)
+		request.Header.Add(HTTPHeaderReqURI, test.path)
+		request.Header.Add(HTTPHeaderOrigMethod, test.method)
+		params := operations.AuthParams{
+			HTTPRequest:  request,
+			XDispatchOrg: test.org,
+		}
+		responder := api.AuthHandler.Handle(params, account)
+		resp := helpers.HandlerRequestWithResponse(t, responder, nil, test.status)
+		if test.status == http.StatusAccepted {
+			assert.Equal(t, testOrgA, resp.Header.Get("X-Dispatch-Org"), "%s %s", test.method, test.path)
+		}
+	}
+}
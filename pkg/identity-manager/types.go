@@ -5,12 +5,17 @@
 
 package identitymanager
 
+import (
+	"github.com/vmware/dispatch/pkg/invocation"
+)
+
 // NO TESTS
 
 const (
 	subjectUser          subjectKind = "user"
 	subjectSvcAccount    subjectKind = "serviceAccount"
 	subjectBootstrapUser subjectKind = "bootstrapUser"
+	subjectFunction      subjectKind = "function"
 )
 
 type subjectKind string
@@ -27,4 +32,6 @@ type authAccount struct {
 	organizationID string
 	subject        string
 	kind           subjectKind
+	// invocation are the claims of the invocation token of a function run, for subjectFunction only
+	invocation *invocation.Claims
 }
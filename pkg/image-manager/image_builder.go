@@ -344,7 +344,7 @@ func (b *ImageBuilder) imageCreate(ctx context.Context, image *Image, baseImage
 		"SYSTEM_PACKAGES_FILE": swag.String(systemPackagesFile),
 		"PACKAGES_FILE":        swag.String(packagesFile),
 	}
-	err = images.BuildAndPushFromDir(ctx, b.dockerClient, tmpDir, dockerURL, b.registryAuth, b.PushImages, buildArgs)
+	err = images.BuildAndPushFromDir(ctx, b.dockerClient, tmpDir, dockerURL, b.registryAuth, b.PushImages, buildArgs, nil)
 	if err != nil {
 		return err
 	}
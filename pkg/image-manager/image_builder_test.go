@@ -207,6 +207,6 @@ func TestBuild(t *testing.T) {
 		"PACKAGES_FILE":        swag.String(packagesFile),
 	}
 
-	err = images.Build(context.Background(), b.dockerClient, tmpDir, image.DockerURL, buildArgs)
+	err = images.Build(context.Background(), b.dockerClient, tmpDir, image.DockerURL, buildArgs, nil)
 	require.NoError(t, err)
 }
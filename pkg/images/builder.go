@@ -28,6 +28,11 @@ import (
 
 // DockerError scans for errors in docker commands
 func DockerError(r io.ReadCloser, err error) error {
+	return DockerOutput(r, err, nil)
+}
+
+// DockerOutput scans for errors in docker commands, writing the output of the command to w if not nil
+func DockerOutput(r io.ReadCloser, err error, w io.Writer) error {
 	if err != nil {
 		return err
 	}
@@ -44,6 +49,9 @@ func DockerError(r io.ReadCloser, err error) error {
 			return errors.Wrapf(err, "failed to parse docker response: %s", s.Text())
 		}
 		if result.Error != nil {
+			if w != nil {
+				io.WriteString(w, *result.Error+"\n")
+			}
 			return errors.New(*result.Error + "\n" + sb.String())
 		}
 
@@ -59,17 +67,21 @@ func DockerError(r io.ReadCloser, err error) error {
 				sb.Reset()
 			}
 			sb.WriteString(*msg.Stream)
+			if w != nil {
+				io.WriteString(w, *msg.Stream)
+			}
 		}
 	}
 	return nil
 }
 
-// BuildAndPushFromDir will tar up a docker image, build it, and push it
-func BuildAndPushFromDir(ctx context.Context, client docker.ImageAPIClient, dir, name, registryAuth string, push bool, buildArgs map[string]*string) error {
+// BuildAndPushFromDir will tar up a docker image, build it, and push it. The build output is written to output if not
+// nil.
+func BuildAndPushFromDir(ctx context.Context, client docker.ImageAPIClient, dir, name, registryAuth string, push bool, buildArgs map[string]*string, output io.Writer) error {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
-	if err := Build(ctx, client, dir, name, buildArgs); err != nil {
+	if err := Build(ctx, client, dir, name, buildArgs, output); err != nil {
 		return err
 	}
 	if !push {
@@ -78,8 +90,8 @@ func BuildAndPushFromDir(ctx context.Context, client docker.ImageAPIClient, dir,
 	return Push(ctx, client, name, registryAuth)
 }
 
-// Build a docker image
-func Build(ctx context.Context, client docker.ImageAPIClient, dir, name string, buildArgs map[string]*string) error {
+// Build a docker image, writing the build output to output if not nil
+func Build(ctx context.Context, client docker.ImageAPIClient, dir, name string, buildArgs map[string]*string, output io.Writer) error {
 	span, ctx := trace.Trace(ctx, "")
 	defer span.Finish()
 
@@ -102,7 +114,7 @@ func Build(ctx context.Context, client docker.ImageAPIClient, dir, name string,
 		Remove:      true,
 		ForceRemove: true,
 	})
-	return errors.Wrapf(DockerError(r.Body, err), "failed to build image '%s'", name)
+	return errors.Wrapf(DockerOutput(r.Body, err, output), "failed to build image '%s'", name)
 }
 
 // Push a docker image
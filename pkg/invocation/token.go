@@ -0,0 +1,138 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package invocation
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// Issuer is the issuer of invocation tokens, the function manager signs them for each function run
+const Issuer = "dispatch/function-manager"
+
+// DefaultTTL is the default validity of invocation tokens
+const DefaultTTL = 15 * time.Minute
+
+// Scopes are of the form <resource>:<action>, matching the resources and actions of the identity manager policies
+const (
+	ScopeEmitEvents   = "event:create"
+	ScopeRunFunctions = "runs:create"
+	ScopeGetRuns      = "runs:get"
+)
+
+// DefaultScopes are the scopes of the invocation tokens of function runs: emit events and run other functions, getting
+// the result of the runs. The function manager narrows ScopeGetRuns to the runs created with the token, a function
+// doesn't get the other runs of its organization
+var DefaultScopes = []string{ScopeEmitEvents, ScopeRunFunctions, ScopeGetRuns}
+
+// Claims are the claims of an invocation token
+type Claims struct {
+	jwt.StandardClaims
+
+	// OrganizationID is the organization the token is restricted to
+	OrganizationID string `json:"org"`
+	// Function is the name of the function the token was issued to
+	Function string `json:"function"`
+	// ParentRun is the name of the run the token was issued to, the parent of the runs created with it
+	ParentRun string `json:"parentRun"`
+	// Scopes are the API requests allowed with the token
+	Scopes []string `json:"scopes"`
+}
+
+// NewClaims returns the claims of the token of a function run, valid for ttl with the default scopes
+func NewClaims(organizationID, function, runName string, ttl time.Duration) *Claims {
+	now := time.Now()
+	return &Claims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    Issuer,
+			Subject:   fmt.Sprintf("function:%s", function),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
+		},
+		OrganizationID: organizationID,
+		Function:       function,
+		ParentRun:      runName,
+		Scopes:         DefaultScopes,
+	}
+}
+
+// Allows returns true if the scopes of the token allow the action on the resource
+func (c *Claims) Allows(resource, action string) bool {
+	scope := fmt.Sprintf("%s:%s", resource, action)
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid checks the expiration and issuer of the claims
+func (c *Claims) Valid() error {
+	if err := c.StandardClaims.Valid(); err != nil {
+		return err
+	}
+	if c.Issuer != Issuer {
+		return errors.Errorf("unexpected issuer %s", c.Issuer)
+	}
+	return nil
+}
+
+// Sign returns the token of the claims, signed with RS256
+func Sign(claims *Claims, key *rsa.PrivateKey) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		return "", errors.Wrap(err, "error signing invocation token")
+	}
+	return token, nil
+}
+
+// Parse validates the signature, expiration and issuer of a token and returns its claims
+func Parse(token string, key *rsa.PublicKey) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		// only accept the signing method of invocation tokens, never the one chosen by the token
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid invocation token")
+	}
+	return claims, nil
+}
+
+// LoadPrivateKey reads the PEM-encoded RSA private key signing invocation tokens
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading invocation token key %s", path)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing invocation token key %s", path)
+	}
+	return key, nil
+}
+
+// LoadPublicKey reads the PEM-encoded RSA public key verifying invocation tokens
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading invocation token public key %s", path)
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing invocation token public key %s", path)
+	}
+	return key, nil
+}
@@ -0,0 +1,58 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package invocation
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignParse(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	token, err := Sign(NewClaims("testOrg", "hello", "f98d0a7f-0c1d-4020-a488-cabc501b08e0", time.Minute), key)
+	require.NoError(t, err)
+
+	claims, err := Parse(token, &key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "testOrg", claims.OrganizationID)
+	assert.Equal(t, "hello", claims.Function)
+	assert.Equal(t, "function:hello", claims.Subject)
+	assert.Equal(t, "f98d0a7f-0c1d-4020-a488-cabc501b08e0", claims.ParentRun)
+	assert.True(t, claims.Allows("event", "create"))
+	assert.True(t, claims.Allows("runs", "create"))
+	assert.False(t, claims.Allows("function", "create"))
+	assert.False(t, claims.Allows("secret", "get"))
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = Parse(token, &other.PublicKey)
+	assert.Error(t, err)
+
+	expired, err := Sign(NewClaims("testOrg", "hello", "", -time.Minute), key)
+	require.NoError(t, err)
+	_, err = Parse(expired, &key.PublicKey)
+	assert.Error(t, err)
+
+	claims = NewClaims("testOrg", "hello", "", time.Minute)
+	claims.Issuer = "testOrg/someone"
+	wrongIssuer, err := Sign(claims, key)
+	require.NoError(t, err)
+	_, err = Parse(wrongIssuer, &key.PublicKey)
+	assert.Error(t, err)
+
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, NewClaims("testOrg", "hello", "", time.Minute)).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = Parse(hmac, &key.PublicKey)
+	assert.Error(t, err)
+}
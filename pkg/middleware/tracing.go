@@ -33,6 +33,13 @@ func (w *statusCodeTracker) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+// Flush sends any buffered data to the client, so streamed responses are not held back by the middleware
+func (w *statusCodeTracker) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 type mwOptions struct {
 	opNameFunc func(r *http.Request) string
 }
@@ -9,6 +9,10 @@ import (
 	entitystore "github.com/vmware/dispatch/pkg/entity-store"
 )
 
+// ReservedSecretPrefix starts the names of the secrets dispatch keeps for itself, e.g. the secrets signing the
+// callbacks of function runs, which are left out of the secrets listed to users
+const ReservedSecretPrefix = "dispatch-internal-"
+
 // SecretEntity is the secret entity type
 type SecretEntity struct {
 	entitystore.BaseEntity
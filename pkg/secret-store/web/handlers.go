@@ -9,6 +9,7 @@ package web
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/swag"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/vmware/dispatch/pkg/api/v1"
 	"github.com/vmware/dispatch/pkg/entity-store"
+	secretstore "github.com/vmware/dispatch/pkg/secret-store"
 	"github.com/vmware/dispatch/pkg/secret-store/gen/restapi/operations"
 	"github.com/vmware/dispatch/pkg/secret-store/gen/restapi/operations/secret"
 	"github.com/vmware/dispatch/pkg/secret-store/service"
@@ -124,7 +126,15 @@ func (h *Handlers) getSecrets(params secret.GetSecretsParams, principal interfac
 		})
 	}
 
-	return secret.NewGetSecretsOK().WithPayload(vmwSecrets)
+	// the reserved secrets are listed on their own
+	internal := swag.BoolValue(params.Internal)
+	secrets := []*v1.Secret{}
+	for _, s := range vmwSecrets {
+		if strings.HasPrefix(swag.StringValue(s.Name), secretstore.ReservedSecretPrefix) == internal {
+			secrets = append(secrets, s)
+		}
+	}
+	return secret.NewGetSecretsOK().WithPayload(secrets)
 }
 
 func (h *Handlers) getSecret(params secret.GetSecretParams, principal interface{}) middleware.Responder {
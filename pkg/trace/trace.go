@@ -14,6 +14,7 @@ import (
 	"strconv"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
 )
 
 // begin a span from this stack frame less the skip.
@@ -48,3 +49,20 @@ func Trace(ctx context.Context, operationName string) (opentracing.Span, context
 func Tracef(ctx context.Context, format string, a ...interface{}) (opentracing.Span, context.Context) {
 	return Trace(ctx, fmt.Sprintf(format, a...))
 }
+
+// ID returns the ID of the trace of the span, or an empty string if the tracer has no trace IDs (tracing is disabled)
+func ID(span opentracing.Span) string {
+	if span == nil {
+		return ""
+	}
+	if sc, ok := span.Context().(jaeger.SpanContext); ok && sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// FollowsFrom begins a span following from the span context, e.g. received with an event, instead of the span of ctx
+func FollowsFrom(ctx context.Context, operationName string, spanCtx opentracing.SpanContext) (opentracing.Span, context.Context) {
+	span := opentracing.StartSpan(operationName, opentracing.FollowsFrom(spanCtx))
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
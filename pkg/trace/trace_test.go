@@ -12,6 +12,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
 )
 
 func TestTrace(t *testing.T) {
@@ -49,3 +50,29 @@ func TestTraceCustomOpName(t *testing.T) {
 	assert.Equal(t, "caller", fields[1].Key)
 	assert.Equal(t, "github.com/vmware/dispatch/pkg/trace.TestTraceCustomOpName", fields[1].ValueString)
 }
+
+func TestID(t *testing.T) {
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close()
+	span := tracer.StartSpan("testOperation")
+	defer span.Finish()
+	assert.Equal(t, span.Context().(jaeger.SpanContext).TraceID().String(), ID(span))
+	assert.NotEmpty(t, ID(span))
+
+	assert.Empty(t, ID(opentracing.NoopTracer{}.StartSpan("testOperation")))
+	assert.Empty(t, ID(nil))
+}
+
+func TestFollowsFrom(t *testing.T) {
+	mockTracer := &mocktracer.MockTracer{}
+	opentracing.SetGlobalTracer(mockTracer)
+	emission := mockTracer.StartSpan("emission")
+	emission.Finish()
+	span, ctx := FollowsFrom(context.Background(), "handler", emission.Context())
+	span.Finish()
+	assert.Equal(t, span, opentracing.SpanFromContext(ctx))
+	spans := mockTracer.FinishedSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext.TraceID, spans[1].SpanContext.TraceID)
+	assert.Equal(t, spans[0].SpanContext.SpanID, spans[1].ParentID)
+}
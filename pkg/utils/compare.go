@@ -0,0 +1,103 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package utils
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LookupPath returns the value of a dot-separated path (e.g. "result.status") in the input
+func LookupPath(input interface{}, path string) (interface{}, bool) {
+	value := input
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Compare compares a value with the expected one. == and != compare any values, numbers as numbers whatever their
+// type, <, <=, > and >= compare numbers or strings.
+func Compare(value interface{}, operator string, expected interface{}) (bool, error) {
+	switch operator {
+	case "==":
+		return equal(value, expected), nil
+	case "!=":
+		return !equal(value, expected), nil
+	}
+
+	var cmp int
+	if a, ok := toFloat(value); ok {
+		b, ok := toFloat(expected)
+		if !ok {
+			return false, errors.Errorf("cannot compare number with %v", expected)
+		}
+		cmp = compareFloats(a, b)
+	} else if a, ok := value.(string); ok {
+		b, ok := expected.(string)
+		if !ok {
+			return false, errors.Errorf("cannot compare string with %v", expected)
+		}
+		cmp = strings.Compare(a, b)
+	} else {
+		return false, errors.Errorf("operator %s is not supported for %v", operator, value)
+	}
+
+	switch operator {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, errors.Errorf("unknown operator %s", operator)
+}
+
+func equal(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,54 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupPath(t *testing.T) {
+	input := map[string]interface{}{
+		"vm": map[string]interface{}{"powerState": "off"},
+	}
+	value, ok := LookupPath(input, "vm.powerState")
+	assert.True(t, ok)
+	assert.Equal(t, "off", value)
+
+	_, ok = LookupPath(input, "vm.name")
+	assert.False(t, ok)
+	_, ok = LookupPath(input, "vm.powerState.on")
+	assert.False(t, ok)
+}
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		operator string
+		expected interface{}
+		matched  bool
+	}{
+		{"off", "==", "off", true},
+		{"off", "!=", "off", false},
+		{float64(2), "==", 2, true},
+		{int64(2), "<", float64(2.5), true},
+		{"b", ">=", "a", true},
+		{nil, "==", nil, true},
+	}
+	for _, c := range cases {
+		matched, err := Compare(c.value, c.operator, c.expected)
+		assert.NoError(t, err)
+		assert.Equal(t, c.matched, matched, "%v %s %v", c.value, c.operator, c.expected)
+	}
+
+	_, err := Compare("a", "<", 1)
+	assert.Error(t, err)
+	_, err = Compare(true, ">", false)
+	assert.Error(t, err)
+	_, err = Compare(1, "~", 1)
+	assert.Error(t, err)
+}
@@ -52,3 +52,18 @@ const ServiceAccountKind = "ServiceAccount"
 
 // OrganizationKind a constant representing the kind of the Organization Model
 const OrganizationKind = "Organization"
+
+// WorkflowKind a constant representing the kind of the Workflow Model
+const WorkflowKind = "Workflow"
+
+// ScheduleKind a constant representing the kind of the Schedule Model
+const ScheduleKind = "Schedule"
+
+// QuotaKind a constant representing the kind of the Quota Model
+const QuotaKind = "Quota"
+
+// ConfigMapKind a constant representing the kind of the ConfigMap Model
+const ConfigMapKind = "ConfigMap"
+
+// FunctionBuildKind a constant representing the kind of the FunctionBuild Model
+const FunctionBuildKind = "FunctionBuild"
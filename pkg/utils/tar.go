@@ -7,10 +7,14 @@ package utils
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bufio"
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -27,8 +31,62 @@ func IsDir(path string) (bool, error) {
 	return f.IsDir(), nil
 }
 
+// IgnoreFile is the name of the file listing the paths left out of a source directory
+const IgnoreFile = ".dispatchignore"
+
 // Tar writes the tar stream of the source to w.
 func Tar(source string, w io.Writer) error {
+	return tarFiltered(source, w, nil)
+}
+
+// ignorePatterns reads the ignore file at the root of dir, one glob pattern per line, '#' starting a comment
+func ignorePatterns(dir string) ([]string, error) {
+	file, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ignore file in '%s'", dir)
+	}
+	defer file.Close()
+
+	patterns := []string{IgnoreFile}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern '%s' in ignore file", line)
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// ignored matches the slash separated relative path against the patterns. Patterns without a slash match the file
+// name at any depth, patterns ending with a slash only match directories.
+func ignored(patterns []string, rel string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if !isDir {
+				continue
+			}
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		name := rel
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(strings.TrimPrefix(pattern, "/"), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func tarFiltered(source string, w io.Writer, patterns []string) error {
 	source = filepath.Clean(source)
 	prefix := source + "/"
 
@@ -52,6 +110,14 @@ func Tar(source string, w io.Writer) error {
 				return nil
 			}
 
+			if ignored(patterns, filepath.ToSlash(strings.TrimPrefix(path, prefix)), info.IsDir()) {
+				log.Debugf("tar: ignoring: %s", path)
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			header, err := tar.FileInfoHeader(info, info.Name())
 			if err != nil {
 				return err
@@ -92,8 +158,122 @@ func TarGzBytes(source string) ([]byte, error) {
 	return bs.Bytes(), nil
 }
 
+// SourceTarGzBytes produces the tar.gz of a function source, which is either a single file, a directory (leaving out
+// the paths listed in its ignore file) or a .tar, .tar.gz, .tgz or .zip archive of the source tree.
+func SourceTarGzBytes(source string) ([]byte, error) {
+	isDir, err := IsDir(source)
+	if err != nil {
+		return nil, err
+	}
+	if !isDir && !IsArchive(source) {
+		return TarGzBytes(source)
+	}
+
+	bs := &bytes.Buffer{}
+	gw := gzip.NewWriter(bs)
+	switch {
+	case isDir:
+		var patterns []string
+		if patterns, err = ignorePatterns(source); err == nil {
+			err = tarFiltered(source, gw, patterns)
+		}
+	case strings.HasSuffix(source, ".zip"):
+		err = zipToTar(source, gw)
+	default:
+		err = copyTar(source, gw)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to tar source '%s'", source)
+	}
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close gzip writer")
+	}
+	return bs.Bytes(), nil
+}
+
+// IsArchive determines if path is an archive of a source tree, based on its extension
+func IsArchive(path string) bool {
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyTar copies the entries of a (possibly gzipped) tar archive to w, checking it can be read
+func copyTar(source string, w io.Writer) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if !strings.HasSuffix(source, ".tar") {
+		gr, err := gzip.NewReader(file)
+		if err != nil {
+			return errors.Wrap(err, "failed to read gzip stream")
+		}
+		defer gr.Close()
+		r = gr
+	}
+	tarReader := tar.NewReader(r)
+	tarball := tar.NewWriter(w)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return tarball.Close()
+		} else if err != nil {
+			return err
+		}
+		if err := tarball.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tarball, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// zipToTar writes the entries of a zip archive to w as a tar stream
+func zipToTar(source string, w io.Writer) error {
+	zipReader, err := zip.OpenReader(source)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+
+	tarball := tar.NewWriter(w)
+	for _, entry := range zipReader.File {
+		info := entry.FileInfo()
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = "./" + strings.TrimSuffix(entry.Name, "/")
+		if err := tarball.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tarball, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return tarball.Close()
+}
+
 // Untar the tar stream r into the dst dir stripping prefix from file paths
 func Untar(dst, prefix string, r io.Reader) error {
+	dst = filepath.Clean(dst)
 	tarReader := tar.NewReader(r)
 	for {
 		header, err := tarReader.Next()
@@ -104,6 +284,9 @@ func Untar(dst, prefix string, r io.Reader) error {
 		}
 
 		path := filepath.Join(dst, strings.TrimPrefix(header.Name, prefix))
+		if path != dst && !strings.HasPrefix(path, dst+string(filepath.Separator)) {
+			return fmt.Errorf("tar entry '%s' is outside of the destination directory", header.Name)
+		}
 		info := header.FileInfo()
 		if info.IsDir() {
 			if err = os.MkdirAll(path, info.Mode()); err != nil {
@@ -111,16 +294,38 @@ func Untar(dst, prefix string, r io.Reader) error {
 			}
 			continue
 		}
-
-		file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
-		if err != nil {
+		// archives don't always have entries for the directories of their files
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 			return err
 		}
-		defer file.Close()
-		_, err = io.Copy(file, tarReader)
-		if err != nil {
+		if header.Typeflag == tar.TypeSymlink {
+			target := filepath.Join(filepath.Dir(path), header.Linkname)
+			if filepath.IsAbs(header.Linkname) || !strings.HasPrefix(target, dst+string(filepath.Separator)) {
+				return fmt.Errorf("tar entry '%s' links outside of the destination directory", header.Name)
+			}
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			log.Debugf("untar: skipping %s", header.Name)
+			continue
+		}
+
+		if err := writeFile(path, info.Mode(), tarReader); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func writeFile(path string, mode os.FileMode, r io.Reader) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, r)
+	return err
+}
@@ -0,0 +1,130 @@
+///////////////////////////////////////////////////////////////////////
+// Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+///////////////////////////////////////////////////////////////////////
+
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func untarGz(t *testing.T, bs []byte) string {
+	dst, err := ioutil.TempDir("", "untar")
+	require.NoError(t, err)
+	gr, err := gzip.NewReader(bytes.NewReader(bs))
+	require.NoError(t, err)
+	require.NoError(t, Untar(dst, "/", gr))
+	return dst
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestSourceTarGzBytesDir(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+	writeFiles(t, src, map[string]string{
+		"report/main.py":            "import report.util",
+		"report/util.py":            "pass",
+		"report/util.pyc":           "compiled",
+		"node_modules/left/pad.js":  "",
+		"tests/test_main.py":        "pass",
+		"tests/fixtures/report.csv": "a,b",
+		IgnoreFile:                  "# build artifacts\n*.pyc\nnode_modules/\n/tests/fixtures\n",
+	})
+
+	bs, err := SourceTarGzBytes(src)
+	require.NoError(t, err)
+	dst := untarGz(t, bs)
+	defer os.RemoveAll(dst)
+
+	assert.Equal(t, "import report.util", readFile(t, filepath.Join(dst, "report", "main.py")))
+	assert.Equal(t, "pass", readFile(t, filepath.Join(dst, "tests", "test_main.py")))
+	for _, name := range []string{"report/util.pyc", "node_modules", "tests/fixtures", IgnoreFile} {
+		_, err := os.Stat(filepath.Join(dst, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+}
+
+func TestSourceTarGzBytesZip(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+
+	archive := filepath.Join(src, "report.zip")
+	file, err := os.Create(archive)
+	require.NoError(t, err)
+	zw := zip.NewWriter(file)
+	w, err := zw.Create("report/main.py")
+	require.NoError(t, err)
+	w.Write([]byte("import report.util"))
+	require.NoError(t, zw.Close())
+	require.NoError(t, file.Close())
+
+	bs, err := SourceTarGzBytes(archive)
+	require.NoError(t, err)
+	dst := untarGz(t, bs)
+	defer os.RemoveAll(dst)
+
+	assert.Equal(t, "import report.util", readFile(t, filepath.Join(dst, "report", "main.py")))
+}
+
+func TestSourceTarGzBytesTar(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+	writeFiles(t, src, map[string]string{"project/report/main.py": "pass"})
+
+	archive := filepath.Join(src, "report.tar")
+	file, err := os.Create(archive)
+	require.NoError(t, err)
+	require.NoError(t, Tar(filepath.Join(src, "project"), file))
+	require.NoError(t, file.Close())
+
+	bs, err := SourceTarGzBytes(archive)
+	require.NoError(t, err)
+	dst := untarGz(t, bs)
+	defer os.RemoveAll(dst)
+
+	assert.Equal(t, "pass", readFile(t, filepath.Join(dst, "report", "main.py")))
+}
+
+func TestUntarOutsideDestination(t *testing.T) {
+	dst, err := ioutil.TempDir("", "untar")
+	require.NoError(t, err)
+	defer os.RemoveAll(dst)
+
+	for _, header := range []*tar.Header{
+		{Name: "../evil.py", Mode: 0644, Typeflag: tar.TypeReg},
+		{Name: "./link", Linkname: "../../etc/passwd", Typeflag: tar.TypeSymlink},
+	} {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		require.NoError(t, tw.WriteHeader(header))
+		require.NoError(t, tw.Close())
+		assert.Error(t, Untar(dst, "/", buf), header.Name)
+	}
+}